@@ -6,6 +6,7 @@ import (
 	"date-apps-be/infrastructure/database"
 	"date-apps-be/internal/api/http/router"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -71,8 +72,17 @@ func run(log *zap.Logger) error {
 
 	cc := container.NewHandlerComponent(sharedComponent)
 
+	// Start background jobs, they stop when the server shuts down
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer func() {
+		stopWorker()
+		cc.Worker.Wait()
+	}()
+	cc.Worker.Start(workerCtx)
+
 	log.Info("Initializing the web server ...")
 	e := echo.New()
+	e.IPExtractor = ipExtractor(conf.TrustedProxies)
 	e.Pre(middleware.RemoveTrailingSlash())
 	e.Use(middleware.Recover())
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -153,3 +163,22 @@ func (rv *requestValidator) Validate(i interface{}) (err error) {
 	_, err = govalidator.ValidateStruct(i)
 	return
 }
+
+// ipExtractor reads the client IP from X-Forwarded-For only when the request comes from a trusted proxy,
+// otherwise anyone could pick the IP the login limits count against.
+func ipExtractor(trustedProxies []*net.IPNet) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		options = append(options, echo.TrustIPRange(proxy))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
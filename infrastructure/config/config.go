@@ -6,6 +6,9 @@ import (
 	"encoding/base64"
 	"fmt"
 	"log"
+	"net"
+	"os"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/joho/godotenv"
@@ -16,19 +19,93 @@ type Config struct {
 	Environment string
 	HttpPort    string
 	CORSOrigins []string
+	// TrustedProxies are the networks of the load balancers allowed to set X-Forwarded-For,
+	// without them the client IP is the address of the connection
+	TrustedProxies []*net.IPNet
 
 	// API Key
 	APIKey string
 
 	// JWT
-	JWTExpiration      int
-	JWTRS256PrivateKey *rsa.PrivateKey
-	JWTRS256PubKey     *rsa.PublicKey
+	JWTExpiration          int
+	RefreshTokenExpiration int
+	JWTRS256PrivateKey     *rsa.PrivateKey
+	JWTRS256PubKey         *rsa.PublicKey
+	// JWTKeyID is the kid of the signing key pair above
+	JWTKeyID string
+	// JWTVerificationKeys are public keys still accepted for verification, keyed by kid
+	JWTVerificationKeys  map[string]*rsa.PublicKey
+	TokenRevocationStore string
+
+	// SMS
+	SMSSender string
+
+	// Email verification
+	AppBaseURL                  string
+	EmailVerificationSecret     []byte
+	EmailVerificationExpiration int
+	RequireEmailVerification    bool
+
+	// PasswordResetURL is the page that receives the reset token
+	PasswordResetURL string
+
+	// MFAEncryptionKey is the AES-256 key used to encrypt TOTP secrets at rest
+	MFAEncryptionKey []byte
+
+	// DataExportSecret signs the download links of personal data exports
+	DataExportSecret []byte
+
+	// BlobStoreDir is the directory of the local blob store
+	BlobStoreDir string
+
+	// AccountDeletionGracePeriod in minutes is how long a deleted account can be restored before it is purged
+	AccountDeletionGracePeriod int
+
+	// OIDCProviders are the identity providers accepted by /login/oidc
+	OIDCProviders []*OIDCProvider
+
+	// ServiceClients are the shared secrets of the backends allowed to call /internal, keyed by client ID
+	ServiceClients     map[string][]byte
+	ServiceReplayStore string
+
+	Mail *Mail
 
 	DBMaster *DB
 	DBSlave  *DB
 }
 
+// Mail config model
+type Mail struct {
+	Mailer       string
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// OIDCProvider config model
+type OIDCProvider struct {
+	Name string
+	// Issuers are the accepted iss values of ID tokens
+	Issuers []string
+	// ClientIDs are the accepted aud values, usually one per app platform
+	ClientIDs []string
+	JWKSURL   string
+}
+
+// oidcDefaultIssuers are the issuers accepted for a provider when OIDC_<NAME>_ISSUERS is not set.
+var oidcDefaultIssuers = map[string][]string{
+	"google": {"https://accounts.google.com", "accounts.google.com"},
+	"apple":  {"https://appleid.apple.com"},
+}
+
+// oidcDefaultJWKSURLs are the key sets of a provider when OIDC_<NAME>_JWKS_URL is not set.
+var oidcDefaultJWKSURLs = map[string]string{
+	"google": "https://www.googleapis.com/oauth2/v3/certs",
+	"apple":  "https://appleid.apple.com/auth/keys",
+}
+
 // DB config model
 type DB struct {
 	ConnectionString string
@@ -41,6 +118,8 @@ type configEnv struct {
 	Port        string   `envconfig:"APP_PORT" default:"8080"`
 	Env         string   `envconfig:"APP_ENV" default:"local"`
 	CORSOrigins []string `envconfig:"CORS_ORIGINS"`
+	// TrustedProxies is a list of CIDR of the load balancers in front of the app
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
 
 	// Database config
 	DBMasterMaxIdle int    `envconfig:"DBMASTERMAXIDLECONN"`
@@ -62,6 +141,60 @@ type configEnv struct {
 	JWTRS256PrivateKey string `envconfig:"JWT_RS256_PRIVATE_KEY" required:"true"`
 	JWTRS256PubKey     string `envconfig:"JWT_RS256_PUBLIC_KEY" required:"true"`
 	JWTExpiration      int    `envconfig:"JWT_EXPIRATION" required:"true"`
+	JWTKeyID           string `envconfig:"JWT_KEY_ID" default:"default"`
+	// JWTVerificationKeys is a list of kid:base64 PEM public key,
+	// keep the previous signing key here while rotating so issued tokens stay valid
+	JWTVerificationKeys []string `envconfig:"JWT_VERIFICATION_KEYS"`
+	// RefreshTokenExpiration in minutes, default is 30 days
+	RefreshTokenExpiration int `envconfig:"REFRESH_TOKEN_EXPIRATION" default:"43200"`
+	// TokenRevocationStore is either mysql or memory, memory only works with a single instance
+	TokenRevocationStore string `envconfig:"TOKEN_REVOCATION_STORE" default:"mysql"`
+
+	// SMS
+	// SMSSender selects how SMS are delivered, log only writes them to the application log
+	SMSSender string `envconfig:"SMS_SENDER" default:"log"`
+
+	// Email verification
+	// AppBaseURL is used to build links sent to users
+	AppBaseURL              string `envconfig:"APP_BASE_URL" default:"http://localhost:8080"`
+	EmailVerificationSecret string `envconfig:"EMAIL_VERIFICATION_SECRET" required:"true"`
+	// EmailVerificationExpiration in minutes, default is 1 day
+	EmailVerificationExpiration int `envconfig:"EMAIL_VERIFICATION_EXPIRATION" default:"1440"`
+	// RequireEmailVerification hides discovery until the user verified the email
+	RequireEmailVerification bool `envconfig:"REQUIRE_EMAIL_VERIFICATION" default:"false"`
+
+	// PasswordResetURL is the frontend page the reset token is sent to
+	PasswordResetURL string `envconfig:"PASSWORD_RESET_URL" default:"http://localhost:3000/reset-password"`
+
+	// MFAEncryptionKey is a base64 encoded 32 bytes key, changing it invalidates every enrolled authenticator
+	MFAEncryptionKey string `envconfig:"MFA_ENCRYPTION_KEY" required:"true"`
+
+	// DataExportSecret signs the download links of personal data exports
+	DataExportSecret string `envconfig:"DATA_EXPORT_SECRET" required:"true"`
+
+	// BlobStoreDir is where uploaded and generated files are kept, share it between instances
+	BlobStoreDir string `envconfig:"BLOB_STORE_DIR" default:"./storage"`
+
+	// AccountDeletionGracePeriod in minutes, default is 30 days
+	AccountDeletionGracePeriod int `envconfig:"ACCOUNT_DELETION_GRACE_PERIOD" default:"43200"`
+
+	// OIDCProviders is the list of enabled identity providers e.g. google,apple,
+	// each provider is configured by OIDC_<NAME>_CLIENT_IDS, OIDC_<NAME>_ISSUERS and OIDC_<NAME>_JWKS_URL
+	OIDCProviders []string `envconfig:"OIDC_PROVIDERS"`
+
+	// ServiceClients is a list of client_id:base64 secret of the backends allowed to call /internal
+	ServiceClients []string `envconfig:"SERVICE_CLIENTS"`
+	// ServiceReplayStore is either mysql or memory, memory only works with a single instance
+	ServiceReplayStore string `envconfig:"SERVICE_REPLAY_STORE" default:"mysql"`
+
+	// Mail
+	// Mailer is either log or smtp, log only writes emails to the application log
+	Mailer       string `envconfig:"MAILER" default:"log"`
+	MailFrom     string `envconfig:"MAIL_FROM" default:"no-reply@date-apps.local"`
+	SMTPHost     string `envconfig:"SMTP_HOST"`
+	SMTPPort     string `envconfig:"SMTP_PORT" default:"587"`
+	SMTPUsername string `envconfig:"SMTP_USERNAME"`
+	SMTPPassword string `envconfig:"SMTP_PASSWORD"`
 }
 
 var appConfig *Config
@@ -79,17 +212,58 @@ func Init() {
 	appConfig.Environment = cfg.Env
 	appConfig.Environment = cfg.Port
 	appConfig.CORSOrigins = cfg.CORSOrigins
+	appConfig.TrustedProxies = getTrustedProxies(cfg)
 
 	appConfig.HttpPort = cfg.Port
 	jwtPrivateKey, jwtPubKey := getJWTConfig(cfg)
 
 	appConfig.JWTRS256PrivateKey = jwtPrivateKey
 	appConfig.JWTRS256PubKey = jwtPubKey
+	appConfig.JWTKeyID = cfg.JWTKeyID
+	appConfig.JWTVerificationKeys = getJWTVerificationKeys(cfg)
 	appConfig.JWTExpiration = cfg.JWTExpiration
+	appConfig.RefreshTokenExpiration = cfg.RefreshTokenExpiration
+	appConfig.TokenRevocationStore = cfg.TokenRevocationStore
+	appConfig.SMSSender = cfg.SMSSender
+
+	appConfig.AppBaseURL = strings.TrimRight(cfg.AppBaseURL, "/")
+	appConfig.EmailVerificationSecret = getSigningSecret("EMAIL_VERIFICATION_SECRET", cfg.EmailVerificationSecret)
+	appConfig.EmailVerificationExpiration = cfg.EmailVerificationExpiration
+	appConfig.RequireEmailVerification = cfg.RequireEmailVerification
+	appConfig.PasswordResetURL = cfg.PasswordResetURL
+	appConfig.MFAEncryptionKey = getMFAEncryptionKey(cfg)
+	appConfig.DataExportSecret = getSigningSecret("DATA_EXPORT_SECRET", cfg.DataExportSecret)
+	appConfig.BlobStoreDir = cfg.BlobStoreDir
+	appConfig.AccountDeletionGracePeriod = cfg.AccountDeletionGracePeriod
+	appConfig.OIDCProviders = getOIDCProviders(cfg)
+	appConfig.ServiceClients = getServiceClients(cfg)
+	appConfig.ServiceReplayStore = cfg.ServiceReplayStore
+	appConfig.Mail = &Mail{
+		Mailer:       cfg.Mailer,
+		From:         cfg.MailFrom,
+		SMTPHost:     cfg.SMTPHost,
+		SMTPPort:     cfg.SMTPPort,
+		SMTPUsername: cfg.SMTPUsername,
+		SMTPPassword: cfg.SMTPPassword,
+	}
 
 	initDB(&cfg)
 }
 
+func getTrustedProxies(cfg configEnv) []*net.IPNet {
+	proxies := make([]*net.IPNet, 0, len(cfg.TrustedProxies))
+	for _, cidr := range cfg.TrustedProxies {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			log.Fatalf("Failed to load trusted proxy %q, %+v\n", cidr, err)
+		}
+
+		proxies = append(proxies, ipNet)
+	}
+
+	return proxies
+}
+
 func getJWTConfig(cfg configEnv) (*rsa.PrivateKey, *rsa.PublicKey) {
 	// Decode base64 RS256 JWT Secret
 	jwtPrivateKeyPEM, err := base64.StdEncoding.DecodeString(cfg.JWTRS256PrivateKey)
@@ -113,6 +287,111 @@ func getJWTConfig(cfg configEnv) (*rsa.PrivateKey, *rsa.PublicKey) {
 	return jwtPrivateKey, jwtPubKey
 }
 
+func getJWTVerificationKeys(cfg configEnv) map[string]*rsa.PublicKey {
+	keys := map[string]*rsa.PublicKey{}
+	for _, key := range cfg.JWTVerificationKeys {
+		kid, encodedPEM, ok := strings.Cut(key, ":")
+		if !ok || kid == "" {
+			log.Fatalf("Failed to load jwt verification key, expected kid:base64 PEM\n")
+		}
+
+		pubKeyPEM, err := base64.StdEncoding.DecodeString(encodedPEM)
+		if err != nil {
+			log.Fatalf("Failed to load jwt verification key %q, %+v\n", kid, err)
+		}
+		pubKey, err := jwt.ParseRSAPublicKeyFromPEM(pubKeyPEM)
+		if err != nil {
+			log.Fatalf("Failed to load jwt verification key %q, %+v\n", kid, err)
+		}
+
+		keys[kid] = pubKey
+	}
+
+	return keys
+}
+
+func getMFAEncryptionKey(cfg configEnv) []byte {
+	key, err := base64.StdEncoding.DecodeString(cfg.MFAEncryptionKey)
+	if err != nil {
+		log.Fatalf("Failed to load mfa encryption key, %+v\n", err)
+	}
+	if len(key) != 32 {
+		log.Fatalf("Failed to load mfa encryption key, expected 32 bytes but got %d\n", len(key))
+	}
+
+	return key
+}
+
+// getSigningSecret rejects HMAC secrets shorter than 32 bytes, required only checks the variable is set.
+func getSigningSecret(name, secret string) []byte {
+	if len(secret) < 32 {
+		log.Fatalf("Failed to load %s, the secret must be at least 32 bytes\n", name)
+	}
+
+	return []byte(secret)
+}
+
+func getOIDCProviders(cfg configEnv) []*OIDCProvider {
+	providers := make([]*OIDCProvider, 0, len(cfg.OIDCProviders))
+	for _, name := range cfg.OIDCProviders {
+		name = strings.ToLower(strings.TrimSpace(name))
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+		provider := &OIDCProvider{
+			Name:      name,
+			Issuers:   splitEnvList(os.Getenv(prefix + "ISSUERS")),
+			ClientIDs: splitEnvList(os.Getenv(prefix + "CLIENT_IDS")),
+			JWKSURL:   os.Getenv(prefix + "JWKS_URL"),
+		}
+		if len(provider.Issuers) == 0 {
+			provider.Issuers = oidcDefaultIssuers[name]
+		}
+		if provider.JWKSURL == "" {
+			provider.JWKSURL = oidcDefaultJWKSURLs[name]
+		}
+
+		if len(provider.Issuers) == 0 || len(provider.ClientIDs) == 0 || provider.JWKSURL == "" {
+			log.Fatalf("Failed to load oidc provider %q, %sCLIENT_IDS, %sISSUERS and %sJWKS_URL are required\n", name, prefix, prefix, prefix)
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return providers
+}
+
+func getServiceClients(cfg configEnv) map[string][]byte {
+	clients := map[string][]byte{}
+	for _, client := range cfg.ServiceClients {
+		clientID, encodedSecret, ok := strings.Cut(client, ":")
+		if !ok || clientID == "" {
+			log.Fatalf("Failed to load service client, expected client_id:base64 secret\n")
+		}
+
+		secret, err := base64.StdEncoding.DecodeString(encodedSecret)
+		if err != nil {
+			log.Fatalf("Failed to load service client %q, %+v\n", clientID, err)
+		}
+		if len(secret) < 32 {
+			log.Fatalf("Failed to load service client %q, the secret must be at least 32 bytes\n", clientID)
+		}
+
+		clients[clientID] = secret
+	}
+
+	return clients
+}
+
+func splitEnvList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 func initDB(c *configEnv) {
 	appConfig.DBMaster = &DB{
 		ConnectionString: fmt.Sprintf(
@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN `locked_until` datetime DEFAULT NULL AFTER `phone_number`;
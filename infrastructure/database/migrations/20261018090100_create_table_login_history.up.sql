@@ -0,0 +1,16 @@
+CREATE TABLE login_history (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `uid` varchar(27) NOT NULL,
+    `user_uid` varchar(27) DEFAULT NULL, -- NULL when the identifier does not belong to any user
+    `identifier` varchar(100) NOT NULL,
+    `ip_address` varchar(45) NOT NULL,
+    `user_agent` varchar(255) DEFAULT NULL,
+    `is_success` boolean NOT NULL DEFAULT false,
+    `failure_reason` varchar(50) DEFAULT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `login_history_uid_unique` (`uid`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`),
+    INDEX `login_history_user_uid_created_at_idx` (`user_uid`, `created_at`),
+    INDEX `login_history_ip_address_created_at_idx` (`ip_address`, `created_at`)
+);
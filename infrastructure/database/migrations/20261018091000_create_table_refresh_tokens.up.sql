@@ -0,0 +1,18 @@
+CREATE TABLE refresh_tokens (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `uid` varchar(27) NOT NULL,
+    `user_uid` varchar(27) NOT NULL,
+    `family_uid` varchar(27) NOT NULL, -- every rotation of the same login shares the family
+    `token_hash` char(64) NOT NULL, -- sha256 of the opaque token, the token itself is never stored
+    `expires_at` datetime NOT NULL,
+    `rotated_at` datetime DEFAULT NULL,
+    `revoked_at` datetime DEFAULT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `refresh_token_uid_unique` (`uid`),
+    UNIQUE KEY `refresh_token_hash_unique` (`token_hash`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`),
+    INDEX `refresh_token_family_uid_idx` (`family_uid`),
+    INDEX `refresh_token_user_uid_idx` (`user_uid`)
+);
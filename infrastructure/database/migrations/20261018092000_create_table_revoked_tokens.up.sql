@@ -0,0 +1,7 @@
+CREATE TABLE revoked_tokens (
+    `jti` varchar(27) NOT NULL,
+    `expires_at` datetime NOT NULL, -- the token expiry, after that the row can be garbage collected
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`jti`),
+    INDEX `revoked_tokens_expires_at_idx` (`expires_at`)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE otp_codes (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `uid` varchar(27) NOT NULL,
+    `purpose` varchar(30) NOT NULL,
+    `phone_number` varchar(40) NOT NULL,
+    `code_hash` char(64) NOT NULL, -- sha256 of the uid and the code, the code itself is never stored
+    `attempts` int NOT NULL DEFAULT 0,
+    `expires_at` datetime NOT NULL,
+    `consumed_at` datetime DEFAULT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `otp_code_uid_unique` (`uid`),
+    INDEX `otp_code_phone_number_purpose_created_at_idx` (`phone_number`, `purpose`, `created_at`)
+);
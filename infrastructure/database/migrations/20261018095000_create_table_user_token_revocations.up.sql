@@ -0,0 +1,9 @@
+CREATE TABLE user_token_revocations (
+    `user_uid` varchar(27) NOT NULL,
+    `revoked_before` datetime NOT NULL, -- access tokens issued up to this time are rejected
+    `expires_at` datetime NOT NULL, -- when the last affected access token expires, after that the row can be garbage collected
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`user_uid`),
+    INDEX `user_token_revocations_expires_at_idx` (`expires_at`)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE password_resets (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `uid` varchar(27) NOT NULL,
+    `user_uid` varchar(27) NOT NULL,
+    `token_hash` char(64) NOT NULL, -- sha256 of the token, the token itself is never stored
+    `expires_at` datetime NOT NULL,
+    `used_at` datetime DEFAULT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `password_reset_uid_unique` (`uid`),
+    UNIQUE KEY `password_reset_token_hash_unique` (`token_hash`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`),
+    INDEX `password_reset_user_uid_created_at_idx` (`user_uid`, `created_at`)
+);
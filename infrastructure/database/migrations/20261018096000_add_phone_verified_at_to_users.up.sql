@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN `phone_verified_at` datetime DEFAULT NULL AFTER `phone_number`;
@@ -0,0 +1,2 @@
+ALTER TABLE otp_codes
+    ADD COLUMN `user_uid` varchar(27) DEFAULT NULL AFTER `purpose`; -- the user that asked for the code, set when verifying a new phone number
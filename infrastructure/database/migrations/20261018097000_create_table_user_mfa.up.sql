@@ -0,0 +1,12 @@
+CREATE TABLE user_mfa (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `user_uid` varchar(27) NOT NULL,
+    `secret` varchar(255) NOT NULL, -- TOTP secret encrypted with MFA_ENCRYPTION_KEY
+    `enabled_at` datetime DEFAULT NULL, -- NULL until the enrollment is confirmed with a first code
+    `last_used_step` bigint NOT NULL DEFAULT 0, -- last accepted TOTP time step, older or equal steps are replays
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `user_mfa_user_uid_unique` (`user_uid`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`)
+);
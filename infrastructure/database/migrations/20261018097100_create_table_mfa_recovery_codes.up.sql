@@ -0,0 +1,10 @@
+CREATE TABLE mfa_recovery_codes (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `user_uid` varchar(27) NOT NULL,
+    `code_hash` char(64) NOT NULL, -- sha256 of the recovery code, the code itself is never stored
+    `used_at` datetime DEFAULT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `mfa_recovery_code_user_uid_code_hash_unique` (`user_uid`, `code_hash`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`)
+);
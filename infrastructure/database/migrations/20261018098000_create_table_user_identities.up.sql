@@ -0,0 +1,14 @@
+CREATE TABLE user_identities (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `uid` varchar(27) NOT NULL,
+    `user_uid` varchar(27) NOT NULL,
+    `provider` varchar(50) NOT NULL,
+    `subject` varchar(255) NOT NULL, -- sub claim of the ID token, stable per provider
+    `email` varchar(100) DEFAULT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `user_identity_uid_unique` (`uid`),
+    UNIQUE KEY `user_identity_provider_subject_unique` (`provider`, `subject`),
+    INDEX `user_identity_user_uid_idx` (`user_uid`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE user_roles (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `user_uid` varchar(27) NOT NULL,
+    `role` varchar(50) NOT NULL, -- every user is a regular user, only elevated roles are stored
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `user_role_user_uid_role_unique` (`user_uid`, `role`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`)
+);
@@ -0,0 +1,16 @@
+CREATE TABLE user_sessions (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `uid` varchar(27) NOT NULL, -- same as the family_uid of the refresh tokens of the session
+    `user_uid` varchar(27) NOT NULL,
+    `device_id` varchar(255) DEFAULT NULL, -- x-device-id header sent by the app
+    `user_agent` varchar(255) DEFAULT NULL,
+    `ip_address` varchar(45) NOT NULL,
+    `last_seen_at` datetime NOT NULL DEFAULT current_timestamp(),
+    `expires_at` datetime NOT NULL,
+    `revoked_at` datetime DEFAULT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `user_session_uid_unique` (`uid`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`),
+    INDEX `user_session_user_uid_idx` (`user_uid`)
+);
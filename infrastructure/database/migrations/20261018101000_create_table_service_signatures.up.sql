@@ -0,0 +1,8 @@
+CREATE TABLE service_signatures (
+    `client_id` varchar(64) NOT NULL,
+    `signature` char(64) NOT NULL, -- hex HMAC-SHA256 of an accepted service request, seen again means a replay
+    `expires_at` datetime NOT NULL, -- the end of the clock skew window, after that the timestamp is rejected anyway
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`client_id`, `signature`),
+    INDEX `service_signatures_expires_at_idx` (`expires_at`)
+);
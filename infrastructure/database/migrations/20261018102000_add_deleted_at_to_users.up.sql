@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN `deleted_at` datetime DEFAULT NULL AFTER `locked_until`, -- set when the user deletes the account, the account is purged after the grace period
+    ADD INDEX `users_deleted_at_idx` (`deleted_at`);
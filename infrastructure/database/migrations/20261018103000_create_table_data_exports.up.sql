@@ -0,0 +1,19 @@
+CREATE TABLE data_exports (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `uid` varchar(27) NOT NULL,
+    `user_uid` varchar(27) NOT NULL,
+    `status` varchar(20) NOT NULL, -- pending, processing, completed, failed or expired
+    `blob_key` varchar(255) DEFAULT NULL, -- the archive in the blob store, cleared once it expired
+    `size` bigint(20) DEFAULT NULL,
+    `failure_reason` varchar(255) DEFAULT NULL,
+    `started_at` datetime DEFAULT NULL,
+    `completed_at` datetime DEFAULT NULL,
+    `expires_at` datetime DEFAULT NULL, -- the archive is deleted after this time
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `data_export_uid_unique` (`uid`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`),
+    INDEX `data_export_user_uid_created_at_idx` (`user_uid`, `created_at`),
+    INDEX `data_export_status_expires_at_idx` (`status`, `expires_at`)
+);
@@ -0,0 +1 @@
+ALTER TABLE users DROP INDEX `users_status_idx`, DROP COLUMN `suspended_until`, DROP COLUMN `status_reason`, DROP COLUMN `status`;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN `status` varchar(20) NOT NULL DEFAULT 'active' AFTER `locked_until`, -- active, suspended or banned
+    ADD COLUMN `status_reason` varchar(255) DEFAULT NULL AFTER `status`, -- shown to the user when a login is rejected
+    ADD COLUMN `suspended_until` datetime DEFAULT NULL AFTER `status_reason`, -- a suspension ends on its own after this time
+    ADD INDEX `users_status_idx` (`status`);
@@ -0,0 +1,14 @@
+CREATE TABLE user_status_audits (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `uid` varchar(27) NOT NULL,
+    `user_uid` varchar(27) NOT NULL, -- no foreign key, the trail is kept when the account is purged
+    `actor_uid` varchar(27) NOT NULL, -- the staff member that changed the status
+    `previous_status` varchar(20) NOT NULL,
+    `status` varchar(20) NOT NULL,
+    `reason` varchar(255) DEFAULT NULL,
+    `suspended_until` datetime DEFAULT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `user_status_audit_uid_unique` (`uid`),
+    INDEX `user_status_audit_user_uid_created_at_idx` (`user_uid`, `created_at`)
+);
@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN `interests`, DROP COLUMN `height_cm`, DROP COLUMN `education`, DROP COLUMN `company`, DROP COLUMN `job_title`, DROP COLUMN `bio`, DROP COLUMN `gender`, DROP COLUMN `birthdate`;
@@ -0,0 +1,9 @@
+ALTER TABLE users
+    ADD COLUMN `birthdate` date DEFAULT NULL AFTER `phone_number`,
+    ADD COLUMN `gender` varchar(20) DEFAULT NULL AFTER `birthdate`, -- male, female or non_binary
+    ADD COLUMN `bio` varchar(500) DEFAULT NULL AFTER `gender`,
+    ADD COLUMN `job_title` varchar(100) DEFAULT NULL AFTER `bio`,
+    ADD COLUMN `company` varchar(100) DEFAULT NULL AFTER `job_title`,
+    ADD COLUMN `education` varchar(100) DEFAULT NULL AFTER `company`,
+    ADD COLUMN `height_cm` smallint(5) unsigned DEFAULT NULL AFTER `education`,
+    ADD COLUMN `interests` varchar(1000) DEFAULT NULL AFTER `height_cm`; -- JSON array of strings
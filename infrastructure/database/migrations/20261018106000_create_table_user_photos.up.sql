@@ -0,0 +1,19 @@
+CREATE TABLE user_photos (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `uid` varchar(27) NOT NULL,
+    `user_uid` varchar(27) NOT NULL,
+    `position` int(11) NOT NULL, -- display order, 0 is shown first
+    `is_primary` tinyint(1) NOT NULL DEFAULT 0, -- every user with photos has exactly one primary photo
+    `content_type` varchar(50) NOT NULL,
+    `blob_key` varchar(255) NOT NULL, -- the original without metadata in the blob store
+    `thumbnail_key` varchar(255) NOT NULL,
+    `width` int(11) NOT NULL,
+    `height` int(11) NOT NULL,
+    `size` bigint(20) NOT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `user_photo_uid_unique` (`uid`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`),
+    INDEX `user_photo_user_uid_position_idx` (`user_uid`, `position`)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE user_preferences (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `user_uid` varchar(27) NOT NULL,
+    `min_age` int(11) DEFAULT NULL,
+    `max_age` int(11) DEFAULT NULL,
+    `genders` varchar(100) DEFAULT NULL, -- JSON array of genders to show, NULL shows every gender
+    `max_distance_km` int(11) DEFAULT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `user_preference_user_uid_unique` (`user_uid`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE user_locations (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `user_uid` varchar(27) NOT NULL,
+    `latitude` decimal(9,6) NOT NULL,
+    `longitude` decimal(9,6) NOT NULL,
+    `geohash` varchar(12) NOT NULL, -- prefix searches find the users near a location
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `user_location_user_uid_unique` (`user_uid`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`),
+    INDEX `user_location_geohash_idx` (`geohash`)
+);
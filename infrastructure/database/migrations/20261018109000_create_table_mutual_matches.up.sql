@@ -0,0 +1,14 @@
+CREATE TABLE mutual_matches (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `uid` varchar(27) NOT NULL,
+    `user_uid` varchar(27) NOT NULL, -- the smaller uid of the pair so every pair has a single row
+    `match_uid` varchar(27) NOT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `mutual_match_uid_unique` (`uid`),
+    UNIQUE KEY `mutual_match_pair_unique` (`user_uid`, `match_uid`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`),
+    FOREIGN KEY (`match_uid`) REFERENCES users(`uid`),
+    INDEX `mutual_match_match_uid_idx` (`match_uid`)
+);
@@ -0,0 +1 @@
+ALTER TABLE premium_config DROP COLUMN `super_like_quota`;
@@ -0,0 +1,6 @@
+ALTER TABLE premium_config
+    ADD COLUMN `super_like_quota` int NOT NULL DEFAULT 0 AFTER `expired_day`; -- super-likes per day, if 0 the free allowance applies
+
+UPDATE premium_config SET super_like_quota = 3 WHERE uid = 'basic123';
+UPDATE premium_config SET super_like_quota = 5 WHERE uid = 'standar123';
+UPDATE premium_config SET super_like_quota = 10 WHERE uid = 'premium123';
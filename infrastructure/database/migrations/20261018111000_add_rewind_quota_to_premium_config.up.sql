@@ -0,0 +1,5 @@
+ALTER TABLE premium_config
+    ADD COLUMN `rewind_quota` int NOT NULL DEFAULT 0 AFTER `super_like_quota`; -- rewinds per day, if 0 the package does not include rewind
+
+UPDATE premium_config SET rewind_quota = 3 WHERE uid = 'standar123';
+UPDATE premium_config SET rewind_quota = 10 WHERE uid = 'premium123';
@@ -0,0 +1,11 @@
+CREATE TABLE user_rewinds (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `user_uid` varchar(27) NOT NULL,
+    `match_uid` varchar(27) NOT NULL, -- the swipe that was undone
+    `match_type` varchar(20) NOT NULL,
+    `swiped_at` datetime NOT NULL,
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`),
+    INDEX `user_rewind_user_uid_created_at_idx` (`user_uid`, `created_at`)
+);
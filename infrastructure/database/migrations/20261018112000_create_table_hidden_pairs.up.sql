@@ -0,0 +1,12 @@
+CREATE TABLE hidden_pairs (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `user_uid` varchar(27) NOT NULL, -- the smaller uid of the pair so every pair has a single row
+    `match_uid` varchar(27) NOT NULL,
+    `hidden_by` varchar(27) NOT NULL, -- the user who unmatched
+    `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `hidden_pair_unique` (`user_uid`, `match_uid`),
+    FOREIGN KEY (`user_uid`) REFERENCES users(`uid`),
+    FOREIGN KEY (`match_uid`) REFERENCES users(`uid`),
+    INDEX `hidden_pair_match_uid_idx` (`match_uid`)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN `pending_email` varchar(100) DEFAULT NULL AFTER `email_verified_at`; -- replaces email once the verification link sent to it is opened
@@ -0,0 +1,2 @@
+ALTER TABLE user_token_revocations
+    MODIFY `revoked_before` datetime NOT NULL;
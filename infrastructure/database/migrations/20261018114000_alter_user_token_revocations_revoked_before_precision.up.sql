@@ -0,0 +1,2 @@
+ALTER TABLE user_token_revocations
+    MODIFY `revoked_before` datetime(6) NOT NULL; -- microseconds like the iat claim, a login right after the cutoff is accepted
@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"date-apps-be/internal/api/http/handler/request"
+	"date-apps-be/internal/api/http/handler/response"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/container"
+	"date-apps-be/internal/model"
+	accountusecase "date-apps-be/internal/usecase/account"
+	otpusecase "date-apps-be/internal/usecase/otp"
+	"date-apps-be/pkg/api"
+	"date-apps-be/pkg/derrors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	accountHandler struct {
+		accountUsecase accountusecase.AccountUsecase
+		otpUsecase     otpusecase.OTPUsecase
+	}
+
+	AccountHandler interface {
+		VerifyEmail(c echo.Context) error
+		ResendEmailVerification(c echo.Context) error
+		ForgotPassword(c echo.Context) error
+		ResetPassword(c echo.Context) error
+		ChangePassword(c echo.Context) error
+		ChangeEmail(c echo.Context) error
+		ChangePhoneNumber(c echo.Context) error
+		VerifyPhoneNumber(c echo.Context) error
+		DeleteAccount(c echo.Context) error
+		RestoreAccount(c echo.Context) error
+	}
+)
+
+func NewAccountHandler(hc *container.HandlerComponent) AccountHandler {
+	return &accountHandler{
+		accountUsecase: hc.AccountUsecase,
+		otpUsecase:     hc.OTPUsecase,
+	}
+}
+
+// VerifyEmail verifies the email address with the token from the verification email.
+// @Summary Verify email
+// @Description Verify the email address with the token sent by email
+// @Tags account
+// @ID verify-email
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid or expired verification link"
+// @Router /verify-email [get]
+func (a *accountHandler) VerifyEmail(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return api.RenderErrorResponse(c, c.Request(), derrors.New(derrors.InvalidArgument, "Token is required"))
+	}
+
+	if err := a.accountUsecase.VerifyEmail(c.Request().Context(), token); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Email verified", http.StatusOK)
+}
+
+// ResendEmailVerification sends a new verification email to the logged in user.
+// @Summary Resend verification email
+// @Description Send a new verification email to the current email address
+// @Tags account
+// @ID resend-email-verification
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Email is already verified"
+// @Router /verify-email/resend [post]
+func (a *accountHandler) ResendEmailVerification(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	if err := a.accountUsecase.ResendEmailVerification(c.Request().Context(), userInfo.UserUID); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Verification email sent", http.StatusOK)
+}
+
+// ForgotPassword emails a password reset link.
+// The response is the same whether the email is registered or not.
+// @Summary Forgot password
+// @Description Send a password reset link to the email address
+// @Tags account
+// @ID forgot-password
+// @Accept json
+// @Produce json
+// @Param req body request.ForgotPassword true "Email"
+// @Success 200 {object} map[string]string
+// @Router /password/forgot [post]
+func (a *accountHandler) ForgotPassword(c echo.Context) error {
+	req := new(request.ForgotPassword)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := a.accountUsecase.ForgotPassword(c.Request().Context(), req.Email); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "If the email is registered, a password reset link has been sent", http.StatusOK)
+}
+
+// ResetPassword sets a new password with the token from the reset email,
+// every existing login of the user is signed out.
+// @Summary Reset password
+// @Description Set a new password with a reset token and sign out every device
+// @Tags account
+// @ID reset-password
+// @Accept json
+// @Produce json
+// @Param req body request.ResetPassword true "Reset token and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid or expired reset token"
+// @Router /password/reset [post]
+func (a *accountHandler) ResetPassword(c echo.Context) error {
+	req := new(request.ResetPassword)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := a.accountUsecase.ResetPassword(c.Request().Context(), req.Token, req.Password); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Password has been reset, please login again", http.StatusOK)
+}
+
+// ChangePassword sets a new password after checking the current one,
+// every existing login of the user is signed out.
+// @Summary Change password
+// @Description Change the password with the current password and sign out every device
+// @Tags account
+// @ID change-password
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param req body request.ChangePassword true "Current and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Current password is incorrect"
+// @Failure 423 {object} map[string]string "Account locked"
+// @Router /users/account/password [patch]
+func (a *accountHandler) ChangePassword(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.ChangePassword)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	err := a.accountUsecase.ChangePassword(c.Request().Context(), userInfo.UserUID, req.CurrentPassword, req.NewPassword, model.Device{
+		ID:        c.Request().Header.Get(constant.HeaderDeviceID),
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	})
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Password changed, please login again", http.StatusOK)
+}
+
+// ChangeEmail requests a new email address, a verification email is sent to the new address.
+// @Summary Change email
+// @Description Change the email address after confirming the password, the new address replaces the current one once the link sent to it is opened
+// @Tags account
+// @ID change-email
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param req body request.ChangeEmail true "New email"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Password is incorrect or email already registered"
+// @Failure 423 {object} map[string]string "Account locked"
+// @Router /users/account/email [patch]
+func (a *accountHandler) ChangeEmail(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.ChangeEmail)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	err := a.accountUsecase.ChangeEmail(c.Request().Context(), userInfo.UserUID, req.Password, req.Email, model.Device{
+		ID:        c.Request().Header.Get(constant.HeaderDeviceID),
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	})
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Please open the link sent to the new email to complete the change", http.StatusOK)
+}
+
+// ChangePhoneNumber sends a verification code to the new phone number,
+// the phone number is changed by VerifyPhoneNumber.
+// @Summary Change phone number
+// @Description Send a verification code to the new phone number
+// @Tags account
+// @ID change-phone-number
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param req body request.ChangePhoneNumber true "New phone number"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Phone number already registered"
+// @Failure 429 {object} map[string]string "Too many code requests"
+// @Router /users/account/phone [patch]
+func (a *accountHandler) ChangePhoneNumber(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.ChangePhoneNumber)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := a.otpUsecase.RequestPhoneVerification(c.Request().Context(), userInfo.UserUID, req.PhoneNumber); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Verification code sent", http.StatusOK)
+}
+
+// VerifyPhoneNumber changes the phone number with the code sent by ChangePhoneNumber.
+// @Summary Verify phone number
+// @Description Verify the code sent to the new phone number and change it
+// @Tags account
+// @ID verify-phone-number
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param req body request.VerifyPhoneNumber true "Phone number and code"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string "Invalid or expired code"
+// @Router /users/account/phone/verify [post]
+func (a *accountHandler) VerifyPhoneNumber(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.VerifyPhoneNumber)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := a.otpUsecase.VerifyPhoneNumber(c.Request().Context(), userInfo.UserUID, req.PhoneNumber, req.Code); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Phone number changed", http.StatusOK)
+}
+
+// DeleteAccount deletes the account of the logged in user and signs out every device,
+// the account can be restored until it is purged after the grace period.
+// @Summary Delete account
+// @Description Delete the account, it is hidden immediately and purged with all its data after the grace period
+// @Tags account
+// @ID delete-account
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param req body request.DeleteAccount true "Password confirmation"
+// @Success 200 {object} response.AccountDeletion
+// @Failure 400 {object} map[string]string "Password is incorrect"
+// @Failure 423 {object} map[string]string "Account locked"
+// @Router /users/me [delete]
+func (a *accountHandler) DeleteAccount(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.DeleteAccount)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	restoreBefore, err := a.accountUsecase.DeleteAccount(c.Request().Context(), userInfo.UserUID, req.Password, model.Device{
+		ID:        c.Request().Header.Get(constant.HeaderDeviceID),
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	})
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, response.AccountDeletion{RestoreBefore: restoreBefore}, "Account deleted", http.StatusOK)
+}
+
+// RestoreAccount restores the deleted account of the logged in user during the grace period.
+// @Summary Restore account
+// @Description Restore a deleted account before it is purged, login returns the restore token instead of the token pair while the account is deleted. Login again once it is restored.
+// @Tags account
+// @ID restore-account
+// @Produce json
+// @Param authorization header string true "bearer restore token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Account is not deleted or can no longer be restored"
+// @Router /users/me/restore [post]
+func (a *accountHandler) RestoreAccount(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	if err := a.accountUsecase.RestoreAccount(c.Request().Context(), userInfo.UserUID); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Account restored, please login again", http.StatusOK)
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"date-apps-be/internal/api/http/handler/request"
+	"date-apps-be/internal/container"
+	"date-apps-be/internal/model"
+	moderationusecase "date-apps-be/internal/usecase/moderation"
+	"date-apps-be/internal/usecase/moderation/dto"
+	roleusecase "date-apps-be/internal/usecase/role"
+	userusecase "date-apps-be/internal/usecase/user"
+	"date-apps-be/pkg/api"
+	"date-apps-be/pkg/derrors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	adminHandler struct {
+		userUsecase       userusecase.UserUsecase
+		roleUsecase       roleusecase.RoleUsecase
+		moderationUsecase moderationusecase.ModerationUsecase
+	}
+
+	AdminHandler interface {
+		GetUser(c echo.Context) error
+		GrantRole(c echo.Context) error
+		RevokeRole(c echo.Context) error
+		ChangeUserStatus(c echo.Context) error
+		GetUserStatusAudits(c echo.Context) error
+	}
+)
+
+func NewAdminHandler(hc *container.HandlerComponent) AdminHandler {
+	return &adminHandler{
+		userUsecase:       hc.UserUsecase,
+		roleUsecase:       hc.RoleUsecase,
+		moderationUsecase: hc.ModerationUsecase,
+	}
+}
+
+// GetUser returns any user together with their roles.
+// @Summary Get user
+// @Description Get a user with their roles, requires the users:read permission
+// @Tags admin
+// @ID admin-get-user
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param uid path string true "User UID"
+// @Success 200 {object} model.User
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /admin/users/{uid} [get]
+func (a *adminHandler) GetUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	user, err := a.userUsecase.GetUser(ctx, c.Param("uid"))
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if user == nil {
+		return api.RenderErrorResponse(c, c.Request(), derrors.New(derrors.NotFound, "User not found"))
+	}
+
+	user.Roles, err = a.roleUsecase.GetUserRoles(ctx, user.UID)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, user, http.StatusOK)
+}
+
+// GrantRole gives a role to a user.
+// @Summary Grant role
+// @Description Grant a role to a user, requires the roles:manage permission
+// @Tags admin
+// @ID admin-grant-role
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param uid path string true "User UID"
+// @Param role path string true "Role" Enums(moderator, admin)
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Unknown role"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Router /admin/users/{uid}/roles/{role} [put]
+func (a *adminHandler) GrantRole(c echo.Context) error {
+	if err := a.roleUsecase.GrantRole(c.Request().Context(), c.Param("uid"), c.Param("role")); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Role granted", http.StatusOK)
+}
+
+// RevokeRole removes a role from a user, the user is signed out of every device.
+// @Summary Revoke role
+// @Description Revoke a role from a user and sign them out, requires the roles:manage permission
+// @Tags admin
+// @ID admin-revoke-role
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param uid path string true "User UID"
+// @Param role path string true "Role" Enums(moderator, admin)
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "User does not have the role"
+// @Router /admin/users/{uid}/roles/{role} [delete]
+func (a *adminHandler) RevokeRole(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	if err := a.roleUsecase.RevokeRole(c.Request().Context(), userInfo.UserUID, c.Param("uid"), c.Param("role")); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Role revoked", http.StatusOK)
+}
+
+// ChangeUserStatus suspends, bans or reactivates a user.
+// @Summary Change user status
+// @Description Suspend a user until a date, ban or reactivate them, requires the users:moderate permission.
+// @Description Suspended and banned users are signed out of every device, only admins can change the status of staff.
+// @Tags admin
+// @ID admin-change-user-status
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param uid path string true "User UID"
+// @Param request body request.ChangeUserStatus true "New status"
+// @Success 200 {object} model.User
+// @Failure 400 {object} map[string]string "Invalid status"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /admin/users/{uid}/status [put]
+func (a *adminHandler) ChangeUserStatus(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.ChangeUserStatus)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	d := dto.ChangeUserStatus{
+		ActorUID:   userInfo.UserUID,
+		ActorRoles: userInfo.Roles,
+		UserUID:    c.Param("uid"),
+		Status:     req.Status,
+		Reason:     req.Reason,
+	}
+	if req.SuspendedUntil != nil {
+		d.SuspendedUntil = *req.SuspendedUntil
+	}
+
+	user, err := a.moderationUsecase.ChangeUserStatus(c.Request().Context(), d)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, user, http.StatusOK)
+}
+
+// GetUserStatusAudits returns the status changes of a user.
+// @Summary Get user status audits
+// @Description Get the status changes of a user, newest first, requires the users:moderate permission
+// @Tags admin
+// @ID admin-get-user-status-audits
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param uid path string true "User UID"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {array} model.UserStatusAudit
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Router /admin/users/{uid}/status/audits [get]
+func (a *adminHandler) GetUserStatusAudits(c echo.Context) error {
+	page, limit, err := api.ParsePagination(c.Request())
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	audits, err := a.moderationUsecase.GetUserStatusAudits(c.Request().Context(), c.Param("uid"), page, limit)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, audits, http.StatusOK)
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"date-apps-be/internal/api/http/handler/request"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/container"
+	"date-apps-be/internal/model"
+	authservice "date-apps-be/internal/service/auth"
+	otpusecase "date-apps-be/internal/usecase/otp"
+	"date-apps-be/pkg/api"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	authHandler struct {
+		authService authservice.AuthService
+		otpUsecase  otpusecase.OTPUsecase
+	}
+
+	AuthHandler interface {
+		RefreshToken(c echo.Context) error
+		Logout(c echo.Context) error
+		JWKS(c echo.Context) error
+		RequestLoginOTP(c echo.Context) error
+		VerifyLoginOTP(c echo.Context) error
+	}
+)
+
+func NewAuthHandler(hc *container.HandlerComponent) AuthHandler {
+	return &authHandler{
+		authService: hc.AuthService,
+		otpUsecase:  hc.OTPUsecase,
+	}
+}
+
+// RefreshToken exchanges a refresh token for a new token pair.
+// The presented refresh token is rotated and can not be used again,
+// reusing it revokes every token issued from the same login.
+// @Summary Refresh token
+// @Description Rotate a refresh token and return a new access token and refresh token
+// @Tags auth
+// @ID refresh-token
+// @Accept json
+// @Produce json
+// @Param req body request.RefreshToken true "Refresh token"
+// @Success 200 {object} model.AuthToken
+// @Failure 401 {object} map[string]string "Invalid or reused refresh token"
+// @Router /token/refresh [post]
+func (a *authHandler) RefreshToken(c echo.Context) error {
+	req := new(request.RefreshToken)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	token, err := a.authService.RefreshToken(c.Request().Context(), req.RefreshToken)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, token, http.StatusOK)
+}
+
+// Logout revokes the access token used for the request together with its session,
+// every token issued from the same login stops working.
+// Tokens issued before sessions existed only revoke their refresh token when it is sent too.
+// @Summary Logout
+// @Description Revoke the current access token and its session
+// @Tags auth
+// @ID logout
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param req body request.Logout false "Refresh token to revoke"
+// @Success 200 {object} map[string]string
+// @Router /logout [post]
+func (a *authHandler) Logout(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.Logout)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := a.authService.Logout(c.Request().Context(), userInfo, req.RefreshToken); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Successfully logged out", http.StatusOK)
+}
+
+// JWKS publishes the public keys that verify access tokens.
+// It follows the JSON Web Key Set format instead of the usual response format,
+// so other services can use it with any JWT library.
+// @Summary JSON Web Key Set
+// @Description Public keys to verify access tokens, selected by the kid header of the token
+// @Tags auth
+// @ID jwks
+// @Produce json
+// @Success 200 {object} jwk.Set
+// @Router /.well-known/jwks.json [get]
+func (a *authHandler) JWKS(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, a.authService.JWKS())
+}
+
+// RequestLoginOTP sends a one time login code by SMS.
+// The response is the same whether the phone number is registered or not.
+// @Summary Request login code
+// @Description Send a one time login code to the phone number
+// @Tags auth
+// @ID request-login-otp
+// @Accept json
+// @Produce json
+// @Param req body request.RequestLoginOTP true "Phone number"
+// @Success 200 {object} map[string]string
+// @Failure 429 {object} map[string]string "Too many code requests"
+// @Router /login/otp/request [post]
+func (a *authHandler) RequestLoginOTP(c echo.Context) error {
+	req := new(request.RequestLoginOTP)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := a.otpUsecase.RequestLoginOTP(c.Request().Context(), req.PhoneNumber); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "If the phone number is registered, a login code has been sent", http.StatusOK)
+}
+
+// VerifyLoginOTP logs in with the code sent by RequestLoginOTP.
+// @Summary Login with code
+// @Description Verify the one time login code and return an access token and refresh token
+// @Tags auth
+// @ID verify-login-otp
+// @Accept json
+// @Produce json
+// @Param x-device-id header string false "Device ID"
+// @Param req body request.VerifyLoginOTP true "Phone number and code"
+// @Success 200 {object} model.AuthToken
+// @Failure 401 {object} map[string]string "Invalid or expired code"
+// @Failure 423 {object} map[string]string "Account locked"
+// @Failure 429 {object} map[string]string "Too many attempts"
+// @Router /login/otp/verify [post]
+func (a *authHandler) VerifyLoginOTP(c echo.Context) error {
+	req := new(request.VerifyLoginOTP)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	token, err := a.otpUsecase.VerifyLoginOTP(c.Request().Context(), req.PhoneNumber, req.Code, model.Device{
+		ID:        c.Request().Header.Get(constant.HeaderDeviceID),
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	})
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, token, http.StatusOK)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"date-apps-be/internal/container"
+	"date-apps-be/internal/model"
+	dataexportusecase "date-apps-be/internal/usecase/data_export"
+	"date-apps-be/pkg/api"
+	"date-apps-be/pkg/derrors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	dataExportHandler struct {
+		dataExportUsecase dataexportusecase.DataExportUsecase
+	}
+
+	DataExportHandler interface {
+		RequestDataExport(c echo.Context) error
+		GetDataExport(c echo.Context) error
+		DownloadDataExport(c echo.Context) error
+	}
+)
+
+func NewDataExportHandler(hc *container.HandlerComponent) DataExportHandler {
+	return &dataExportHandler{
+		dataExportUsecase: hc.DataExportUsecase,
+	}
+}
+
+// RequestDataExport queues an archive of the personal data of the user.
+// @Summary Request data export
+// @Description Queue an archive of the profile, matches, premium and login history, poll its status until it is completed
+// @Tags users
+// @ID request-data-export
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Success 202 {object} model.DataExport
+// @Failure 429 {object} map[string]string "Too many data exports requested"
+// @Router /users/me/exports [post]
+func (d *dataExportHandler) RequestDataExport(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	export, err := d.dataExportUsecase.RequestDataExport(c.Request().Context(), userInfo.UserUID)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, export, http.StatusAccepted)
+}
+
+// GetDataExport returns the status of a data export, completed exports include a signed download link.
+// @Summary Get data export
+// @Description Get the status of a data export, download_url is set once the archive is ready and expires after a few minutes
+// @Tags users
+// @ID get-data-export
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param id path string true "Data export ID"
+// @Success 200 {object} model.DataExport
+// @Failure 404 {object} map[string]string "Data export not found"
+// @Router /users/me/exports/{id} [get]
+func (d *dataExportHandler) GetDataExport(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	export, err := d.dataExportUsecase.GetDataExport(c.Request().Context(), userInfo.UserUID, c.Param("id"))
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, export, http.StatusOK)
+}
+
+// DownloadDataExport streams the archive of a signed download link, the link is the only authorization.
+// @Summary Download data export
+// @Description Download the archive with the signed link from the data export status
+// @Tags users
+// @ID download-data-export
+// @Produce application/zip
+// @Param token query string true "Download token"
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]string "Invalid or expired download link"
+// @Failure 404 {object} map[string]string "Data export not found or expired"
+// @Router /exports/download [get]
+func (d *dataExportHandler) DownloadDataExport(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return api.RenderErrorResponse(c, c.Request(), derrors.New(derrors.InvalidArgument, "Token is required"))
+	}
+
+	export, archive, err := d.dataExportUsecase.OpenDataExport(c.Request().Context(), token)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+	defer archive.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "data-export-"+export.UID+".zip"))
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.Stream(http.StatusOK, "application/zip", archive)
+}
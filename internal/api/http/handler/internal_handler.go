@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"date-apps-be/internal/api/http/handler/response"
+	"date-apps-be/internal/container"
+	roleusecase "date-apps-be/internal/usecase/role"
+	userusecase "date-apps-be/internal/usecase/user"
+	"date-apps-be/pkg/api"
+	"date-apps-be/pkg/derrors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	internalHandler struct {
+		userUsecase userusecase.UserUsecase
+		roleUsecase roleusecase.RoleUsecase
+	}
+
+	// InternalHandler serves the other backends, its routes are authenticated with HMAC signatures.
+	InternalHandler interface {
+		GetUser(c echo.Context) error
+		GetEntitlements(c echo.Context) error
+	}
+)
+
+func NewInternalHandler(hc *container.HandlerComponent) InternalHandler {
+	return &internalHandler{
+		userUsecase: hc.UserUsecase,
+		roleUsecase: hc.RoleUsecase,
+	}
+}
+
+// GetUser looks up a user for another backend.
+// @Summary Internal get user
+// @Description Get a user with their roles, the request must be signed with a service secret
+// @Tags internal
+// @ID internal-get-user
+// @Produce json
+// @Param x-service-authorization header string true "HMAC-SHA256 Credential=<client id>, Signature=<hex signature>"
+// @Param x-service-timestamp header string true "Unix timestamp of the request"
+// @Param uid path string true "User UID"
+// @Success 200 {object} model.User
+// @Failure 401 {object} map[string]string "Invalid, expired or replayed signature"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /internal/users/{uid} [get]
+func (i *internalHandler) GetUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	user, err := i.userUsecase.GetUser(ctx, c.Param("uid"))
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if user == nil {
+		return api.RenderErrorResponse(c, c.Request(), derrors.New(derrors.NotFound, "User not found"))
+	}
+
+	user.Roles, err = i.roleUsecase.GetUserRoles(ctx, user.UID)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, user, http.StatusOK)
+}
+
+// GetEntitlements looks up the premium entitlements of a user for another backend.
+// @Summary Internal get entitlements
+// @Description Get the active premium package and quota of a user, the request must be signed with a service secret
+// @Tags internal
+// @ID internal-get-entitlements
+// @Produce json
+// @Param x-service-authorization header string true "HMAC-SHA256 Credential=<client id>, Signature=<hex signature>"
+// @Param x-service-timestamp header string true "Unix timestamp of the request"
+// @Param uid path string true "User UID"
+// @Success 200 {object} response.Entitlements
+// @Failure 401 {object} map[string]string "Invalid, expired or replayed signature"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /internal/users/{uid}/entitlements [get]
+func (i *internalHandler) GetEntitlements(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	user, err := i.userUsecase.GetUser(ctx, c.Param("uid"))
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if user == nil {
+		return api.RenderErrorResponse(c, c.Request(), derrors.New(derrors.NotFound, "User not found"))
+	}
+
+	userPackage, err := i.userUsecase.GetUserPackage(ctx, user.UID)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, response.NewEntitlements(user.UID, userPackage), http.StatusOK)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"date-apps-be/internal/api/http/handler/request"
+	"date-apps-be/internal/container"
+	"date-apps-be/internal/model"
+	locationusecase "date-apps-be/internal/usecase/location"
+	"date-apps-be/pkg/api"
+	"date-apps-be/pkg/derrors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	locationHandler struct {
+		locationUsecase locationusecase.LocationUsecase
+	}
+
+	LocationHandler interface {
+		UpdateLocation(c echo.Context) error
+	}
+)
+
+func NewLocationHandler(hc *container.HandlerComponent) LocationHandler {
+	return &locationHandler{
+		locationUsecase: hc.LocationUsecase,
+	}
+}
+
+// UpdateLocation stores the current location of the user for distance based discovery.
+// @Summary Update location
+// @Description Report the current location, it is snapped to a grid of about 1 km and can move to another cell once every 5 minutes.
+// @Description Other users only see the distance rounded up to a bucket and never the coordinates.
+// @Description The max_distance_km preference applies once a location is reported.
+// @Tags users
+// @ID update-location
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param request body request.UpdateLocation true "Coordinates"
+// @Success 200 {object} model.UserLocation
+// @Failure 400 {object} map[string]string "Invalid coordinates"
+// @Failure 429 {object} map[string]string "Location was updated recently"
+// @Router /users/location [put]
+func (l *locationHandler) UpdateLocation(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.UpdateLocation)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if req.Latitude == nil || req.Longitude == nil {
+		return api.RenderErrorResponse(c, c.Request(), derrors.New(derrors.InvalidArgument, "Latitude and longitude are required"))
+	}
+
+	location, err := l.locationUsecase.UpdateLocation(c.Request().Context(), userInfo.UserUID, *req.Latitude, *req.Longitude)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, location, http.StatusOK)
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"date-apps-be/internal/api/http/handler/request"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/container"
+	"date-apps-be/internal/model"
+	mfausecase "date-apps-be/internal/usecase/mfa"
+	"date-apps-be/pkg/api"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	mfaHandler struct {
+		mfaUsecase mfausecase.MFAUsecase
+	}
+
+	MFAHandler interface {
+		Enroll(c echo.Context) error
+		Confirm(c echo.Context) error
+		Disable(c echo.Context) error
+	}
+)
+
+func NewMFAHandler(hc *container.HandlerComponent) MFAHandler {
+	return &mfaHandler{
+		mfaUsecase: hc.MFAUsecase,
+	}
+}
+
+// Enroll starts the two-factor authentication setup, the secret and the recovery codes are only shown once.
+// @Summary Enroll two-factor authentication
+// @Description Generate a TOTP secret with its otpauth URI and recovery codes, login is unchanged until the enrollment is confirmed
+// @Tags mfa
+// @ID mfa-enroll
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Success 200 {object} dto.Enrollment
+// @Failure 400 {object} map[string]string "Two-factor authentication is already enabled"
+// @Router /users/mfa/enroll [post]
+func (m *mfaHandler) Enroll(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	enrollment, err := m.mfaUsecase.Enroll(c.Request().Context(), userInfo.UserUID)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, enrollment, http.StatusOK)
+}
+
+// Confirm enables two-factor authentication with a code from the authenticator app.
+// @Summary Confirm two-factor authentication
+// @Description Enable two-factor authentication with a TOTP code of the enrolled secret
+// @Tags mfa
+// @ID mfa-confirm
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param req body request.MFACode true "TOTP code"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string "Invalid code"
+// @Failure 423 {object} map[string]string "Account locked"
+// @Router /users/mfa/confirm [post]
+func (m *mfaHandler) Confirm(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.MFACode)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	err := m.mfaUsecase.Confirm(c.Request().Context(), userInfo.UserUID, req.Code, model.Device{
+		ID:        c.Request().Header.Get(constant.HeaderDeviceID),
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	})
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Two-factor authentication enabled", http.StatusOK)
+}
+
+// Disable turns off two-factor authentication with a TOTP or recovery code.
+// @Summary Disable two-factor authentication
+// @Description Disable two-factor authentication, the recovery codes are deleted as well
+// @Tags mfa
+// @ID mfa-disable
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param req body request.MFACode true "TOTP or recovery code"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string "Invalid code"
+// @Failure 423 {object} map[string]string "Account locked"
+// @Router /users/mfa/disable [post]
+func (m *mfaHandler) Disable(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.MFACode)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	err := m.mfaUsecase.Disable(c.Request().Context(), userInfo.UserUID, req.Code, model.Device{
+		ID:        c.Request().Header.Get(constant.HeaderDeviceID),
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	})
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Two-factor authentication disabled", http.StatusOK)
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"date-apps-be/internal/api/http/handler/request"
+	"date-apps-be/internal/container"
+	"date-apps-be/internal/model"
+	photousecase "date-apps-be/internal/usecase/photo"
+	"date-apps-be/pkg/api"
+	"date-apps-be/pkg/derrors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	photoHandler struct {
+		photoUsecase photousecase.PhotoUsecase
+	}
+
+	PhotoHandler interface {
+		UploadPhoto(c echo.Context) error
+		GetPhotos(c echo.Context) error
+		DeletePhoto(c echo.Context) error
+		ReorderPhotos(c echo.Context) error
+		SetPrimaryPhoto(c echo.Context) error
+		GetPhoto(c echo.Context) error
+		GetPhotoThumbnail(c echo.Context) error
+	}
+)
+
+func NewPhotoHandler(hc *container.HandlerComponent) PhotoHandler {
+	return &photoHandler{
+		photoUsecase: hc.PhotoUsecase,
+	}
+}
+
+// UploadPhoto adds a profile photo, the metadata of the image is removed before it is stored.
+// @Summary Upload photo
+// @Description Upload a JPEG or PNG photo of at most 10 MB, a user has at most 6 photos and the first one becomes primary.
+// @Description EXIF data including the GPS location is removed and a thumbnail is generated.
+// @Tags users
+// @ID upload-photo
+// @Accept multipart/form-data
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param photo formData file true "JPEG or PNG image"
+// @Success 201 {object} model.UserPhoto
+// @Failure 400 {object} map[string]string "Invalid image or photo limit reached"
+// @Failure 413 {object} map[string]string "Photo too large"
+// @Router /users/me/photos [post]
+func (p *photoHandler) UploadPhoto(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	fileHeader, err := c.FormFile("photo")
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), derrors.New(derrors.InvalidArgument, "Photo is required"))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), derrors.WrapStack(err, derrors.Unknown, "Open"))
+	}
+	defer file.Close()
+
+	photo, err := p.photoUsecase.UploadPhoto(c.Request().Context(), userInfo.UserUID, file)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, photo, http.StatusCreated)
+}
+
+// GetPhotos lists the photos of the user in display order.
+// @Summary List photos
+// @Description List the photos of the user in display order
+// @Tags users
+// @ID get-photos
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Success 200 {array} model.UserPhoto
+// @Router /users/me/photos [get]
+func (p *photoHandler) GetPhotos(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	photos, err := p.photoUsecase.GetUserPhotos(c.Request().Context(), userInfo.UserUID)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, photos, http.StatusOK)
+}
+
+// DeletePhoto removes a photo of the user.
+// @Summary Delete photo
+// @Description Delete a photo, the next photo becomes primary when the primary photo is deleted
+// @Tags users
+// @ID delete-photo
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param id path string true "Photo ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "Photo not found"
+// @Router /users/me/photos/{id} [delete]
+func (p *photoHandler) DeletePhoto(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	if err := p.photoUsecase.DeletePhoto(c.Request().Context(), userInfo.UserUID, c.Param("id")); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Photo deleted", http.StatusOK)
+}
+
+// ReorderPhotos changes the display order of the photos.
+// @Summary Reorder photos
+// @Description Set the display order, photo_ids must list every photo of the user exactly once
+// @Tags users
+// @ID reorder-photos
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param request body request.ReorderPhotos true "Photo IDs in display order"
+// @Success 200 {array} model.UserPhoto
+// @Failure 400 {object} map[string]string "Invalid order"
+// @Router /users/me/photos/order [put]
+func (p *photoHandler) ReorderPhotos(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.ReorderPhotos)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	photos, err := p.photoUsecase.ReorderPhotos(c.Request().Context(), userInfo.UserUID, req.PhotoIDs)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, photos, http.StatusOK)
+}
+
+// SetPrimaryPhoto chooses the photo shown first on the profile.
+// @Summary Set primary photo
+// @Description Choose the primary photo, the display order does not change
+// @Tags users
+// @ID set-primary-photo
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param id path string true "Photo ID"
+// @Success 200 {array} model.UserPhoto
+// @Failure 404 {object} map[string]string "Photo not found"
+// @Router /users/me/photos/{id}/primary [put]
+func (p *photoHandler) SetPrimaryPhoto(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	photos, err := p.photoUsecase.SetPrimaryPhoto(c.Request().Context(), userInfo.UserUID, c.Param("id"))
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, photos, http.StatusOK)
+}
+
+// GetPhoto streams a photo, the unguessable ID is the only authorization so it can be used in image tags.
+// @Summary Get photo
+// @Description Download a photo by the url from a profile
+// @Tags photos
+// @ID get-photo
+// @Produce image/jpeg,image/png
+// @Param id path string true "Photo ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string "Photo not found"
+// @Router /photos/{id} [get]
+func (p *photoHandler) GetPhoto(c echo.Context) error {
+	return p.streamPhoto(c, false)
+}
+
+// GetPhotoThumbnail streams the thumbnail of a photo.
+// @Summary Get photo thumbnail
+// @Description Download the thumbnail of a photo by the thumbnail url from a profile
+// @Tags photos
+// @ID get-photo-thumbnail
+// @Produce image/jpeg,image/png
+// @Param id path string true "Photo ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string "Photo not found"
+// @Router /photos/{id}/thumbnail [get]
+func (p *photoHandler) GetPhotoThumbnail(c echo.Context) error {
+	return p.streamPhoto(c, true)
+}
+
+func (p *photoHandler) streamPhoto(c echo.Context, thumbnail bool) error {
+	photo, body, err := p.photoUsecase.OpenPhoto(c.Request().Context(), c.Param("id"), thumbnail)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+	defer body.Close()
+
+	// only cached by the client and briefly, a deleted photo or a blocked user must stop being served soon
+	c.Response().Header().Set("Cache-Control", "private, max-age=300")
+	return c.Stream(http.StatusOK, photo.ContentType, body)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"date-apps-be/internal/api/http/handler/request"
+	"date-apps-be/internal/container"
+	"date-apps-be/internal/model"
+	preferenceusecase "date-apps-be/internal/usecase/preference"
+	"date-apps-be/internal/usecase/preference/dto"
+	"date-apps-be/pkg/api"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	preferenceHandler struct {
+		preferenceUsecase preferenceusecase.PreferenceUsecase
+	}
+
+	PreferenceHandler interface {
+		GetPreference(c echo.Context) error
+		UpdatePreference(c echo.Context) error
+	}
+)
+
+func NewPreferenceHandler(hc *container.HandlerComponent) PreferenceHandler {
+	return &preferenceHandler{
+		preferenceUsecase: hc.PreferenceUsecase,
+	}
+}
+
+// GetPreference returns the discovery preferences of the user.
+// @Summary Get discovery preferences
+// @Description Get the age range, genders and distance used to filter the discovery deck, null fields do not filter
+// @Tags users
+// @ID get-preferences
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Success 200 {object} model.UserPreference
+// @Router /users/preferences [get]
+func (p *preferenceHandler) GetPreference(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	preference, err := p.preferenceUsecase.GetPreference(c.Request().Context(), userInfo.UserUID)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, preference, http.StatusOK)
+}
+
+// UpdatePreference replaces the discovery preferences of the user.
+// @Summary Update discovery preferences
+// @Description Replace the discovery preferences, null or empty fields do not filter.
+// @Description Preferences apply both ways, the deck only shows users who match the preferences and whose preferences the user matches.
+// @Tags users
+// @ID update-preferences
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param request body request.UpdatePreference true "Discovery preferences"
+// @Success 200 {object} model.UserPreference
+// @Failure 400 {object} map[string]string "Invalid preference"
+// @Router /users/preferences [put]
+func (p *preferenceHandler) UpdatePreference(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.UpdatePreference)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	preference, err := p.preferenceUsecase.UpdatePreference(c.Request().Context(), dto.UpdatePreference{
+		UserUID:       userInfo.UserUID,
+		MinAge:        req.MinAge,
+		MaxAge:        req.MaxAge,
+		Genders:       req.Genders,
+		MaxDistanceKM: req.MaxDistanceKM,
+	})
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, preference, http.StatusOK)
+}
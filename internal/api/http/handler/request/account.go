@@ -0,0 +1,34 @@
+package request
+
+type ForgotPassword struct {
+	Email string `json:"email" valid:"email,required"`
+}
+
+type ResetPassword struct {
+	Token    string `json:"token" valid:"required"`
+	Password string `json:"password" valid:"required,stringlength(8|72)"`
+}
+
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password" valid:"required"`
+	NewPassword     string `json:"new_password" valid:"required,stringlength(8|72)"`
+}
+
+type ChangeEmail struct {
+	Password string `json:"password" valid:"required"`
+	Email    string `json:"email" valid:"email,required"`
+}
+
+type ChangePhoneNumber struct {
+	PhoneNumber string `json:"phone_number" valid:"required,numeric"`
+}
+
+type VerifyPhoneNumber struct {
+	PhoneNumber string `json:"phone_number" valid:"required,numeric"`
+	Code        string `json:"code" valid:"required,numeric,stringlength(6|6)"`
+}
+
+// DeleteAccount confirms the deletion with the password, accounts without one leave it empty.
+type DeleteAccount struct {
+	Password string `json:"password"`
+}
@@ -0,0 +1,10 @@
+package request
+
+import "time"
+
+// ChangeUserStatus suspends, bans or reactivates a user, suspended_until is only used for suspensions.
+type ChangeUserStatus struct {
+	Status         string     `json:"status" valid:"required,in(active|suspended|banned)"`
+	Reason         string     `json:"reason" valid:"stringlength(1|255)"`
+	SuspendedUntil *time.Time `json:"suspended_until"`
+}
@@ -0,0 +1,35 @@
+package request
+
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token" valid:"required"`
+}
+
+type Logout struct {
+	RefreshToken string `json:"refresh_token" valid:"optional"`
+}
+
+type RequestLoginOTP struct {
+	PhoneNumber string `json:"phone_number" valid:"required,numeric"`
+}
+
+type VerifyLoginOTP struct {
+	PhoneNumber string `json:"phone_number" valid:"required,numeric"`
+	Code        string `json:"code" valid:"required,numeric,stringlength(6|6)"`
+}
+
+type LoginMFA struct {
+	MFAToken string `json:"mfa_token" valid:"required"`
+	// Code is either a 6 digits TOTP code or a recovery code
+	Code string `json:"code" valid:"required,stringlength(6|16)"`
+}
+
+type MFACode struct {
+	Code string `json:"code" valid:"required,stringlength(6|16)"`
+}
+
+type LoginOIDC struct {
+	Provider string `json:"provider" valid:"required"`
+	IDToken  string `json:"id_token" valid:"required"`
+	// Nonce is compared with the nonce claim, it is required when the client set one in the authorization request
+	Nonce string `json:"nonce" valid:"optional"`
+}
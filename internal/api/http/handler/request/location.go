@@ -0,0 +1,6 @@
+package request
+
+type UpdateLocation struct {
+	Latitude  *float64 `json:"latitude" example:"-6.2088"`
+	Longitude *float64 `json:"longitude" example:"106.8456"`
+}
@@ -0,0 +1,6 @@
+package request
+
+// ReorderPhotos lists every photo of the user in the new display order.
+type ReorderPhotos struct {
+	PhotoIDs []string `json:"photo_ids"`
+}
@@ -0,0 +1,9 @@
+package request
+
+// UpdatePreference replaces the discovery preferences, a null or empty field does not filter.
+type UpdatePreference struct {
+	MinAge        *int     `json:"min_age" example:"25"`
+	MaxAge        *int     `json:"max_age" example:"35"`
+	Genders       []string `json:"genders" enums:"male,female,non_binary"`
+	MaxDistanceKM *int     `json:"max_distance_km" example:"50"`
+}
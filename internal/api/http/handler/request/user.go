@@ -7,8 +7,23 @@ type UserRegister struct {
 	PhoneNumber string `json:"phone_number" valid:"numeric,optional"`
 }
 
+// UserLogin logs in with the email and password, requests that also carry
+// a phone number are rejected, phone numbers login with a one time code.
 type UserLogin struct {
 	Email       string `json:"email" valid:"email,required"`
 	Password    string `json:"password" valid:"required"`
 	PhoneNumber string `json:"phone_number" valid:"numeric,optional"`
 }
+
+// UpdateProfile changes the fields that are set, an empty value clears an optional field.
+type UpdateProfile struct {
+	Name      *string  `json:"name"`
+	Birthdate *string  `json:"birthdate" example:"1995-04-23"`
+	Gender    *string  `json:"gender" enums:"male,female,non_binary"`
+	Bio       *string  `json:"bio"`
+	JobTitle  *string  `json:"job_title"`
+	Company   *string  `json:"company"`
+	Education *string  `json:"education"`
+	HeightCM  *int     `json:"height_cm"`
+	Interests []string `json:"interests"`
+}
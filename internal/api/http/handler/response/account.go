@@ -0,0 +1,8 @@
+package response
+
+import "date-apps-be/pkg/datatype"
+
+type AccountDeletion struct {
+	// RestoreBefore is when the account is purged, until then it can be restored
+	RestoreBefore datatype.Time `json:"restore_before"`
+}
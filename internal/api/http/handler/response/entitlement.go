@@ -0,0 +1,34 @@
+package response
+
+import (
+	"date-apps-be/internal/model"
+	"date-apps-be/pkg/datatype"
+)
+
+type Entitlements struct {
+	UserUID   string         `json:"user_uid"`
+	IsPremium bool           `json:"is_premium"`
+	Package   string         `json:"package,omitempty"`
+	Quota     int64          `json:"quota"`
+	EndedAt   *datatype.Date `json:"ended_at,omitempty"`
+}
+
+// NewEntitlements describes what the user is entitled to, an expired package entitles to nothing.
+func NewEntitlements(userUID string, userPackage *model.UserPackage) Entitlements {
+	entitlements := Entitlements{
+		UserUID: userUID,
+	}
+
+	if userPackage == nil || userPackage.IsExpiredPackage() {
+		return entitlements
+	}
+
+	entitlements.IsPremium = true
+	entitlements.Quota = userPackage.Quota
+	entitlements.EndedAt = userPackage.EndedAt
+	if userPackage.PremiumConfig != nil {
+		entitlements.Package = userPackage.PremiumConfig.Name
+	}
+
+	return entitlements
+}
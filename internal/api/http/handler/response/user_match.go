@@ -1,24 +1,77 @@
 package response
 
-import "date-apps-be/internal/model"
+import (
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	"date-apps-be/pkg/datatype"
+	"time"
+)
 
 type UserMatchResponse struct {
 	QuotaLeft int     `json:"quota_left"`
 	Users     []*User `json:"users"`
 }
 
+// User is the public profile shown to other users, it has the age instead of the birthdate.
 type User struct {
-	UserUID string `json:"user_uid"`
-	Name    string `json:"name"`
+	UserUID    string           `json:"user_uid"`
+	Name       string           `json:"name"`
+	Age        int              `json:"age,omitempty"`
+	Gender     *constant.Gender `json:"gender,omitempty"`
+	Bio        *string          `json:"bio,omitempty"`
+	JobTitle   *string          `json:"job_title,omitempty"`
+	Company    *string          `json:"company,omitempty"`
+	Education  *string          `json:"education,omitempty"`
+	HeightCM   *int             `json:"height_cm,omitempty"`
+	Interests  []string         `json:"interests,omitempty"`
+	Photos     []*UserPhoto     `json:"photos,omitempty"`
+	DistanceKM *int             `json:"distance_km,omitempty" example:"5"` // upper bound, the user is at most this far away
+	SuperLiked bool             `json:"super_liked,omitempty"`             // the user super-liked you
+}
+
+// UserPhoto is a photo of the public profile.
+type UserPhoto struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	IsPrimary    bool   `json:"is_primary"`
+}
+
+// NewUser returns the public profile of the user.
+func NewUser(user *model.User, now time.Time) *User {
+	var photos []*UserPhoto
+	for _, photo := range user.Photos {
+		photos = append(photos, &UserPhoto{
+			ID:           photo.UID,
+			URL:          photo.URL,
+			ThumbnailURL: photo.ThumbnailURL,
+			IsPrimary:    photo.IsPrimary,
+		})
+	}
+
+	return &User{
+		UserUID:    user.UID,
+		Name:       user.Name,
+		Age:        user.Age(now),
+		Gender:     user.Gender,
+		Bio:        user.Bio,
+		JobTitle:   user.JobTitle,
+		Company:    user.Company,
+		Education:  user.Education,
+		HeightCM:   user.HeightCM,
+		Interests:  user.Interests,
+		Photos:     photos,
+		DistanceKM: user.DistanceKM,
+		SuperLiked: user.SuperLiked,
+	}
 }
 
 func NewUserMatchResponse(users []*model.User, quotaLeft int) UserMatchResponse {
+	now := time.Now()
+
 	var userMatchResponse []*User
 	for _, user := range users {
-		userMatchResponse = append(userMatchResponse, &User{
-			UserUID: user.UID,
-			Name:    user.Name,
-		})
+		userMatchResponse = append(userMatchResponse, NewUser(user, now))
 	}
 
 	return UserMatchResponse{
@@ -26,3 +79,94 @@ func NewUserMatchResponse(users []*model.User, quotaLeft int) UserMatchResponse
 		Users:     userMatchResponse,
 	}
 }
+
+// CreateMatchResponse tells whether the swipe completed a mutual match.
+type CreateMatchResponse struct {
+	Matched       bool   `json:"matched"`
+	MutualMatchID string `json:"mutual_match_id,omitempty"`
+}
+
+func NewCreateMatchResponse(mutualMatch *model.MutualMatch) CreateMatchResponse {
+	if mutualMatch == nil {
+		return CreateMatchResponse{}
+	}
+
+	return CreateMatchResponse{
+		Matched:       true,
+		MutualMatchID: mutualMatch.UID,
+	}
+}
+
+// MutualMatch is a user the user matched with.
+type MutualMatch struct {
+	ID        string        `json:"id"`
+	MatchedAt datatype.Time `json:"matched_at"`
+	User      *User         `json:"user"`
+}
+
+func NewMutualMatchesResponse(mutualMatches []*model.MutualMatch) []*MutualMatch {
+	now := time.Now()
+
+	res := []*MutualMatch{}
+	for _, mutualMatch := range mutualMatches {
+		res = append(res, &MutualMatch{
+			ID:        mutualMatch.UID,
+			MatchedAt: mutualMatch.CreatedAt,
+			User:      NewUser(mutualMatch.Match, now),
+		})
+	}
+
+	return res
+}
+
+// RewindResponse is the swipe that was undone, the user shows up in the discovery deck again.
+type RewindResponse struct {
+	MatchUID    string                 `json:"match_uid"`
+	MatchType   constant.UserMatchType `json:"match_type"`
+	RewindsLeft int                    `json:"rewinds_left"`
+}
+
+func NewRewindResponse(rewind *model.UserRewind, rewindsLeft int) RewindResponse {
+	return RewindResponse{
+		MatchUID:    rewind.MatchUID,
+		MatchType:   rewind.MatchType,
+		RewindsLeft: rewindsLeft,
+	}
+}
+
+// LikesReceivedResponse lists the users who liked the user, users without a package
+// only get the total and a blurred placeholder for every like on the page.
+type LikesReceivedResponse struct {
+	Total        int                `json:"total"`
+	Locked       bool               `json:"locked"`
+	Users        []*User            `json:"users"`
+	Placeholders []*LikePlaceholder `json:"placeholders,omitempty"`
+}
+
+// LikePlaceholder stands in for a user the client shows blurred, it does not identify the user.
+type LikePlaceholder struct {
+	Blurred bool `json:"blurred" example:"true"`
+}
+
+func NewLikesReceivedResponse(users []*model.User, total int, locked bool, page, limit uint64) LikesReceivedResponse {
+	now := time.Now()
+
+	res := LikesReceivedResponse{
+		Total:  total,
+		Locked: locked,
+		Users:  []*User{},
+	}
+
+	for _, user := range users {
+		res.Users = append(res.Users, NewUser(user, now))
+	}
+
+	if locked {
+		offset := (page - 1) * limit
+		for i := offset; i < uint64(total) && i < offset+limit; i++ {
+			res.Placeholders = append(res.Placeholders, &LikePlaceholder{Blurred: true})
+		}
+	}
+
+	return res
+}
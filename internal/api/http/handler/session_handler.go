@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"date-apps-be/internal/container"
+	"date-apps-be/internal/model"
+	sessionusecase "date-apps-be/internal/usecase/session"
+	"date-apps-be/pkg/api"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	sessionHandler struct {
+		sessionUsecase sessionusecase.SessionUsecase
+	}
+
+	SessionHandler interface {
+		GetSessions(c echo.Context) error
+		RevokeSession(c echo.Context) error
+	}
+)
+
+func NewSessionHandler(hc *container.HandlerComponent) SessionHandler {
+	return &sessionHandler{
+		sessionUsecase: hc.SessionUsecase,
+	}
+}
+
+// GetSessions lists the devices the user is logged in on.
+// @Summary List sessions
+// @Description List the active sessions of the user, current is set for the session of the access token
+// @Tags users
+// @ID get-sessions
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Success 200 {array} model.UserSession
+// @Router /users/sessions [get]
+func (s *sessionHandler) GetSessions(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	sessions, err := s.sessionUsecase.GetSessions(c.Request().Context(), userInfo.UserUID, userInfo.SessionID)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, sessions, http.StatusOK)
+}
+
+// RevokeSession signs one device out, its access and refresh tokens stop working.
+// @Summary Revoke session
+// @Description Revoke a session of the user, the device has to login again
+// @Tags users
+// @ID revoke-session
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "Session not found"
+// @Router /users/sessions/{id} [delete]
+func (s *sessionHandler) RevokeSession(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	if err := s.sessionUsecase.RevokeSession(c.Request().Context(), userInfo.UserUID, c.Param("id")); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Session revoked", http.StatusOK)
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"date-apps-be/internal/api/http/handler/request"
+	"date-apps-be/internal/constant"
 	"date-apps-be/internal/container"
 	"date-apps-be/internal/model"
 	authservice "date-apps-be/internal/service/auth"
@@ -22,8 +23,11 @@ type (
 
 	UserHandler interface {
 		GetUserProfile(c echo.Context) error
+		UpdateUserProfile(c echo.Context) error
 		GetMyPackage(c echo.Context) error
 		Login(c echo.Context) error
+		LoginMFA(c echo.Context) error
+		LoginOIDC(c echo.Context) error
 		Register(c echo.Context) error
 	}
 )
@@ -57,6 +61,46 @@ func (u *userHandler) GetUserProfile(c echo.Context) error {
 	return api.ResponseOK(c, user, http.StatusOK)
 }
 
+// UpdateUserProfile changes the dating profile of the user.
+// @Summary Update user profile
+// @Description Update the name and dating profile, only the fields that are set change and an empty value clears an optional field.
+// @Description Users must be between 18 and 100 years old, interests are lowercased and at most 10.
+// @Tags users
+// @ID update-user-profile
+// @Accept json
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param request body request.UpdateProfile true "Profile fields"
+// @Success 200 {object} model.User
+// @Failure 400 {object} map[string]string "Invalid field"
+// @Router /users/profile [patch]
+func (u *userHandler) UpdateUserProfile(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	req := new(request.UpdateProfile)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	user, err := u.userUsecase.UpdateProfile(c.Request().Context(), dto.UpdateProfile{
+		UserUID:   userInfo.UserUID,
+		Name:      req.Name,
+		Birthdate: req.Birthdate,
+		Gender:    req.Gender,
+		Bio:       req.Bio,
+		JobTitle:  req.JobTitle,
+		Company:   req.Company,
+		Education: req.Education,
+		HeightCM:  req.HeightCM,
+		Interests: req.Interests,
+	})
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, user, http.StatusOK)
+}
+
 // RegisterUser creates a new user account.
 // It reads the request body and decodes it into a User model.
 // RegisterUser creates a new user account.
@@ -68,8 +112,9 @@ func (u *userHandler) GetUserProfile(c echo.Context) error {
 // @ID register-user
 // @Accept json
 // @Produce json
+// @Param x-device-id header string false "Device ID"
 // @Param user body request.UserRegister true "User registration details"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} model.AuthToken
 // @Router /register [post]
 func (u *userHandler) Register(c echo.Context) error {
 	req := new(request.UserRegister)
@@ -90,31 +135,38 @@ func (u *userHandler) Register(c echo.Context) error {
 		return api.RenderErrorResponse(c, c.Request(), derrors.New(derrors.InvalidArgument, "Email or Phone Number already registered, please login"))
 	}
 
-	t, err := u.userUsecase.CreateUser(c.Request().Context(), &dto.CreateUser{
+	token, err := u.userUsecase.CreateUser(c.Request().Context(), &dto.CreateUser{
 		Email:       req.Email,
 		Password:    req.Password,
 		PhoneNumber: req.PhoneNumber,
 		Name:        req.Name,
+		DeviceID:    c.Request().Header.Get(constant.HeaderDeviceID),
+		IPAddress:   c.RealIP(),
+		UserAgent:   c.Request().UserAgent(),
 	})
 	if err != nil {
 		return api.RenderErrorResponse(c, c.Request(), err)
 	}
 
-	return api.ResponseOK(c, map[string]string{
-		"token": t,
-	}, http.StatusOK)
+	return api.ResponseOK(c, token, http.StatusOK)
 }
 
 // Login
 // Login user
 // @Summary Login user
-// @Description Authenticate user and return a JWT token
+// @Description Authenticate user and return a JWT token with a refresh token, the account is locked for a while after too many failed attempts.
+// @Description When two-factor authentication is enabled only mfa_required and mfa_token are returned, see /login/mfa
 // @Tags auth
 // @ID login-user
 // @Accept json
 // @Produce json
+// @Param x-device-id header string false "Device ID"
 // @Param user body request.UserLogin true "User login details"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} model.AuthToken
+// @Failure 400 {object} map[string]string "Both email and phone number are set"
+// @Failure 401 {object} map[string]string "Invalid credentials"
+// @Failure 423 {object} map[string]string "Account locked"
+// @Failure 429 {object} map[string]string "Too many failed attempts"
 // @Router /login [post]
 func (u *userHandler) Login(c echo.Context) error {
 	req := new(request.UserLogin)
@@ -126,23 +178,97 @@ func (u *userHandler) Login(c echo.Context) error {
 		return api.RenderErrorResponse(c, c.Request(), err)
 	}
 
-	user, err := u.userUsecase.GetUserByEmailOrPhoneNumber(c.Request().Context(), req.Email, req.PhoneNumber)
+	token, err := u.userUsecase.Authenticate(c.Request().Context(), dto.Authenticate{
+		Email:       req.Email,
+		PhoneNumber: req.PhoneNumber,
+		Password:    req.Password,
+		DeviceID:    c.Request().Header.Get(constant.HeaderDeviceID),
+		IPAddress:   c.RealIP(),
+		UserAgent:   c.Request().UserAgent(),
+	})
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, token, http.StatusOK)
+}
+
+// LoginMFA completes a login of a user with two-factor authentication enabled.
+// @Summary Login second factor
+// @Description Exchange the mfa_token returned by /login and a TOTP or recovery code for a JWT token with a refresh token
+// @Tags auth
+// @ID login-mfa
+// @Accept json
+// @Produce json
+// @Param x-device-id header string false "Device ID"
+// @Param req body request.LoginMFA true "MFA token and code"
+// @Success 200 {object} model.AuthToken
+// @Failure 401 {object} map[string]string "Invalid code or token"
+// @Failure 423 {object} map[string]string "Account locked"
+// @Failure 429 {object} map[string]string "Too many failed attempts"
+// @Router /login/mfa [post]
+func (u *userHandler) LoginMFA(c echo.Context) error {
+	req := new(request.LoginMFA)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	token, err := u.userUsecase.VerifyMFALogin(c.Request().Context(), dto.VerifyMFALogin{
+		MFAToken:  req.MFAToken,
+		Code:      req.Code,
+		DeviceID:  c.Request().Header.Get(constant.HeaderDeviceID),
+		IPAddress: c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	})
 	if err != nil {
 		return api.RenderErrorResponse(c, c.Request(), err)
 	}
 
-	if user == nil {
-		return api.RenderErrorResponse(c, c.Request(), derrors.New(derrors.NotFound, "Email or Phone Number not found, please register"))
+	return api.ResponseOK(c, token, http.StatusOK)
+}
+
+// LoginOIDC logs in with an ID token from Google, Apple or another configured identity provider.
+// @Summary Social sign-in
+// @Description Validate a provider ID token and return a JWT token with a refresh token.
+// @Description The identity is linked to the user with the same verified email, otherwise a new user is created
+// @Tags auth
+// @ID login-oidc
+// @Accept json
+// @Produce json
+// @Param x-device-id header string false "Device ID"
+// @Param req body request.LoginOIDC true "Provider and ID token"
+// @Success 200 {object} model.AuthToken
+// @Failure 400 {object} map[string]string "Unsupported identity provider or email already registered"
+// @Failure 401 {object} map[string]string "Invalid ID token"
+// @Failure 423 {object} map[string]string "Account locked"
+// @Router /login/oidc [post]
+func (u *userHandler) LoginOIDC(c echo.Context) error {
+	req := new(request.LoginOIDC)
+	if err := c.Bind(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
 	}
 
-	token, err := u.authservice.GenerateToken(user.UID)
+	token, err := u.userUsecase.AuthenticateOIDC(c.Request().Context(), dto.AuthenticateOIDC{
+		Provider:  req.Provider,
+		IDToken:   req.IDToken,
+		Nonce:     req.Nonce,
+		DeviceID:  c.Request().Header.Get(constant.HeaderDeviceID),
+		IPAddress: c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	})
 	if err != nil {
 		return api.RenderErrorResponse(c, c.Request(), err)
 	}
 
-	return api.ResponseOK(c, map[string]string{
-		"token": token,
-	}, http.StatusOK)
+	return api.ResponseOK(c, token, http.StatusOK)
 }
 
 // GetMyPackage retrieves the user's package information.
@@ -5,6 +5,8 @@ import (
 	"date-apps-be/internal/container"
 	"date-apps-be/internal/model"
 	"date-apps-be/internal/test"
+	"date-apps-be/internal/usecase/user/dto"
+	"date-apps-be/pkg/derrors"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -125,7 +127,7 @@ func TestUserHandler_Register(t *testing.T) {
 				mockComponent.UserUsecase.On("CreateUser",
 					mock.Anything,
 					mock.AnythingOfType("*dto.CreateUser"),
-				).Return("jwt-token", nil)
+				).Return(&model.AuthToken{Token: "jwt-token", RefreshToken: "refresh-token"}, nil)
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -173,3 +175,67 @@ func TestUserHandler_Register(t *testing.T) {
 		})
 	}
 }
+
+func TestUserHandler_Login(t *testing.T) {
+	e := echo.New()
+	e.Validator = &requestValidator{}
+	mockComponent := test.InitMockComponent(t)
+
+	hc := &container.HandlerComponent{
+		UserUsecase: mockComponent.UserUsecase,
+		AuthService: mockComponent.AuthService,
+	}
+
+	h := handler.NewUserHandler(hc)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func()
+		expectedStatus int
+	}{
+		{
+			name: "success login",
+			requestBody: `{
+				"email": "test@example.com",
+				"password": "password123"
+			}`,
+			setupMock: func() {
+				mockComponent.UserUsecase.On("Authenticate",
+					mock.Anything,
+					mock.MatchedBy(func(d dto.Authenticate) bool { return d.Email == "test@example.com" }),
+				).Return(&model.AuthToken{Token: "jwt-token", RefreshToken: "refresh-token"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "account locked",
+			requestBody: `{
+				"email": "locked@example.com",
+				"password": "password123"
+			}`,
+			setupMock: func() {
+				mockComponent.UserUsecase.On("Authenticate",
+					mock.Anything,
+					mock.MatchedBy(func(d dto.Authenticate) bool { return d.Email == "locked@example.com" }),
+				).Return(nil, derrors.New(derrors.Locked, "Account is temporarily locked"))
+			},
+			expectedStatus: http.StatusLocked,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(tc.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := h.Login(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -20,6 +20,10 @@ type (
 	UserMatchHandler interface {
 		CreateMatch(c echo.Context) error
 		GetUserMatches(c echo.Context) error
+		GetMutualMatches(c echo.Context) error
+		RewindMatch(c echo.Context) error
+		GetLikesReceived(c echo.Context) error
+		Unmatch(c echo.Context) error
 	}
 
 	userMatchHandler struct {
@@ -72,7 +76,7 @@ func (u *userMatchHandler) GetUserMatches(c echo.Context) error {
 // @Produce json
 // @Param authorization header string true "bearer token"
 // @Param req body request.CreateMatch true "Create Match Request"
-// @Success 201 {object} map[string]string "Success Match with that Person"
+// @Success 201 {object} response.CreateMatchResponse "matched is true when the other user liked the user before"
 // @Router /matches [post]
 func (u *userMatchHandler) CreateMatch(c echo.Context) error {
 	userInfo := c.Get("userInfo").(*model.JWTClaims)
@@ -106,10 +110,107 @@ func (u *userMatchHandler) CreateMatch(c echo.Context) error {
 		MatchType: matchType,
 	}
 
-	err = u.userMatchUsecase.CreateUserMatch(c.Request().Context(), &userMatch)
+	mutualMatch, err := u.userMatchUsecase.CreateUserMatch(c.Request().Context(), &userMatch)
 	if err != nil {
 		return api.RenderErrorResponse(c, c.Request(), err)
 	}
 
-	return api.ResponseSuccess(c, nil, "Success Match with that Person", http.StatusCreated)
+	if mutualMatch != nil {
+		return api.ResponseSuccess(c, response.NewCreateMatchResponse(mutualMatch), "It's a match", http.StatusCreated)
+	}
+
+	return api.ResponseSuccess(c, response.NewCreateMatchResponse(nil), "Success Match with that Person", http.StatusCreated)
+}
+
+// GetMutualMatches lists the users who liked the user back.
+// @Summary Get mutual matches
+// @Description List the users the user matched with, newest first
+// @Tags UserMatch
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Success 200 {array} response.MutualMatch
+// @Router /matches/mutual [get]
+func (u *userMatchHandler) GetMutualMatches(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	page, limit, err := api.ParsePagination(c.Request())
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	mutualMatches, err := u.userMatchUsecase.GetMutualMatches(c.Request().Context(), userInfo.UserUID, page, limit)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, response.NewMutualMatchesResponse(mutualMatches), http.StatusOK)
+}
+
+// RewindMatch undoes the most recent swipe of the user.
+// @Summary Rewind the last swipe
+// @Description Delete the most recent swipe if it is recent and did not turn into a mutual match, its quota is given back. Only packages with rewind include it.
+// @Tags UserMatch
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Success 200 {object} response.RewindResponse
+// @Failure 403 {object} map[string]string "Rewind not included, quota reached or already matched"
+// @Failure 404 {object} map[string]string "No recent swipe"
+// @Router /matches/rewind [post]
+func (u *userMatchHandler) RewindMatch(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	rewind, rewindsLeft, err := u.userMatchUsecase.RewindUserMatch(c.Request().Context(), userInfo.UserUID)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, response.NewRewindResponse(rewind, rewindsLeft), http.StatusOK)
+}
+
+// GetLikesReceived lists the users who liked the user.
+// @Summary Get likes received
+// @Description List the users who liked the user and the user has not swiped on yet, most recent first. Users without a premium package only get the total and blurred placeholders.
+// @Tags UserMatch
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.LikesReceivedResponse
+// @Router /matches/likes-received [get]
+func (u *userMatchHandler) GetLikesReceived(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	page, limit, err := api.ParsePagination(c.Request())
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	users, total, locked, err := u.userMatchUsecase.GetLikesReceived(c.Request().Context(), userInfo.UserUID, page, limit)
+	if err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseOK(c, response.NewLikesReceivedResponse(users, total, locked, page, limit), http.StatusOK)
+}
+
+// Unmatch removes the mutual match with a user.
+// @Summary Unmatch a user
+// @Description Remove the mutual match for both users, they never show up in each other's discovery again. Unmatching again succeeds.
+// @Tags UserMatch
+// @Produce json
+// @Param authorization header string true "bearer token"
+// @Param uid path string true "UID of the matched user"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "Not matched with this user"
+// @Router /matches/{uid} [delete]
+func (u *userMatchHandler) Unmatch(c echo.Context) error {
+	userInfo := c.Get("userInfo").(*model.JWTClaims)
+
+	if err := u.userMatchUsecase.Unmatch(c.Request().Context(), userInfo.UserUID, c.Param("uid")); err != nil {
+		return api.RenderErrorResponse(c, c.Request(), err)
+	}
+
+	return api.ResponseSuccess(c, nil, "Unmatched", http.StatusOK)
 }
@@ -7,11 +7,14 @@ import (
 	"date-apps-be/internal/container"
 	"date-apps-be/internal/model"
 	"date-apps-be/internal/test"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -90,6 +93,49 @@ func TestUserMatchHandler_GetUserMatches(t *testing.T) {
 	}
 }
 
+func TestUserMatchHandler_GetUserMatchesPublicProfile(t *testing.T) {
+	e := echo.New()
+	mockComponent := test.InitMockComponent(t)
+
+	hc := &container.HandlerComponent{
+		UserMatchUsecase: mockComponent.UserMatchUsecase,
+	}
+
+	h := handler.NewUserMatchHandler(hc)
+
+	birthdate, err := datatype.ParseDate(time.Now().AddDate(-25, 0, -1).Format("2006-01-02"), "UTC")
+	assert.NoError(t, err)
+	gender := constant.GenderNonBinary
+	distanceKM := 5
+	mockComponent.UserMatchUsecase.On("GetAvailableUsers", mock.Anything, "profile-uid", uint64(1), uint64(10)).Return([]*model.User{
+		{UID: "user-1", Name: "Alex", Email: datatype.String("alex@example.com"), Birthdate: birthdate, Gender: &gender, Interests: datatype.StringList{"music"},
+			Photos:     []*model.UserPhoto{{UID: "photo-1", BlobKey: "photos/user-1/photo-1.jpg", IsPrimary: true, URL: "http://localhost/photos/photo-1"}},
+			DistanceKM: &distanceKM},
+	}, 5, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/matches?page=1&limit=10", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("userInfo", &model.JWTClaims{UserUID: "profile-uid"})
+
+	assert.NoError(t, h.GetUserMatches(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "birthdate", "other users only see the age")
+	assert.NotContains(t, rec.Body.String(), "alex@example.com")
+	assert.NotContains(t, rec.Body.String(), "photos/user-1", "blob keys are internal")
+
+	var res struct {
+		Data response.UserMatchResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, 25, res.Data.Users[0].Age)
+	assert.Equal(t, constant.GenderNonBinary, *res.Data.Users[0].Gender)
+	assert.Equal(t, []string{"music"}, res.Data.Users[0].Interests)
+	assert.Equal(t, "http://localhost/photos/photo-1", res.Data.Users[0].Photos[0].URL)
+	assert.True(t, res.Data.Users[0].Photos[0].IsPrimary)
+	assert.Equal(t, 5, *res.Data.Users[0].DistanceKM)
+}
+
 func TestUserMatchHandler_CreateMatch(t *testing.T) {
 	// Setup
 	e := echo.New()
@@ -125,7 +171,22 @@ func TestUserMatchHandler_CreateMatch(t *testing.T) {
 							match.MatchUID == "match-uid" &&
 							match.MatchType == constant.UserMatchTypeLike
 					}),
-				).Return(nil)
+				).Return(nil, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:        "success mutual match",
+			requestBody: `{"match_uid":"liker-uid","match_type":"like"}`,
+			setupMock: func() {
+				mockComponent.UserMatchUsecase.On("GetUserMatchTodayByUserUIDAndMatchUID",
+					mock.Anything,
+					"test-uid",
+					"liker-uid",
+				).Return(nil, nil)
+
+				mockComponent.UserMatchUsecase.On("CreateUserMatch", mock.Anything, mock.Anything).
+					Return(&model.MutualMatch{UID: "mutual-uid", UserUID: "liker-uid", MatchUID: "test-uid"}, nil).Once()
 			},
 			expectedStatus: http.StatusCreated,
 		},
@@ -155,3 +216,140 @@ func TestUserMatchHandler_CreateMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestUserMatchHandler_RewindMatch(t *testing.T) {
+	e := echo.New()
+	mockComponent := test.InitMockComponent(t)
+
+	hc := &container.HandlerComponent{
+		UserMatchUsecase: mockComponent.UserMatchUsecase,
+	}
+
+	h := handler.NewUserMatchHandler(hc)
+
+	tests := []struct {
+		name           string
+		userUID        string
+		setupMock      func()
+		expectedStatus int
+	}{
+		{
+			name:    "success rewind",
+			userUID: "test-uid",
+			setupMock: func() {
+				mockComponent.UserMatchUsecase.On("RewindUserMatch", mock.Anything, "test-uid").
+					Return(&model.UserRewind{UserUID: "test-uid", MatchUID: "match-uid", MatchType: constant.UserMatchTypePass}, 2, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "rewind not included",
+			userUID: "free-uid",
+			setupMock: func() {
+				mockComponent.UserMatchUsecase.On("RewindUserMatch", mock.Anything, "free-uid").
+					Return(nil, 0, derrors.New(derrors.Forbidden, "Your package does not include rewind")).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			req := httptest.NewRequest(http.MethodPost, "/matches/rewind", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userInfo", &model.JWTClaims{UserUID: tc.userUID})
+
+			err := h.RewindMatch(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestUserMatchHandler_GetLikesReceived(t *testing.T) {
+	e := echo.New()
+	mockComponent := test.InitMockComponent(t)
+
+	hc := &container.HandlerComponent{
+		UserMatchUsecase: mockComponent.UserMatchUsecase,
+	}
+
+	h := handler.NewUserMatchHandler(hc)
+
+	tests := []struct {
+		name                 string
+		userUID              string
+		url                  string
+		setupMock            func()
+		expectedUsers        int
+		expectedPlaceholders int
+	}{
+		{
+			name:    "premium sees the profiles",
+			userUID: "premium-uid",
+			url:     "/matches/likes-received?page=1&limit=10",
+			setupMock: func() {
+				mockComponent.UserMatchUsecase.On("GetLikesReceived", mock.Anything, "premium-uid", uint64(1), uint64(10)).
+					Return([]*model.User{{UID: "liker-1", Name: "Liker"}}, 1, false, nil).Once()
+			},
+			expectedUsers: 1,
+		},
+		{
+			name:    "free user sees placeholders",
+			userUID: "free-uid",
+			url:     "/matches/likes-received?page=2&limit=10",
+			setupMock: func() {
+				mockComponent.UserMatchUsecase.On("GetLikesReceived", mock.Anything, "free-uid", uint64(2), uint64(10)).
+					Return(nil, 13, true, nil).Once()
+			},
+			expectedPlaceholders: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userInfo", &model.JWTClaims{UserUID: tc.userUID})
+
+			assert.NoError(t, h.GetLikesReceived(c))
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Data response.LikesReceivedResponse `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			assert.Len(t, res.Data.Users, tc.expectedUsers)
+			assert.Len(t, res.Data.Placeholders, tc.expectedPlaceholders)
+		})
+	}
+}
+
+func TestUserMatchHandler_Unmatch(t *testing.T) {
+	e := echo.New()
+	mockComponent := test.InitMockComponent(t)
+
+	hc := &container.HandlerComponent{
+		UserMatchUsecase: mockComponent.UserMatchUsecase,
+	}
+
+	h := handler.NewUserMatchHandler(hc)
+
+	mockComponent.UserMatchUsecase.On("Unmatch", mock.Anything, "test-uid", "match-uid").Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodDelete, "/matches/match-uid", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("uid")
+	c.SetParamValues("match-uid")
+	c.Set("userInfo", &model.JWTClaims{UserUID: "test-uid"})
+
+	assert.NoError(t, h.Unmatch(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -1,49 +1,49 @@
 package middleware
 
 import (
-	"date-apps-be/infrastructure/config"
+	"context"
 	"date-apps-be/internal/model"
-	"fmt"
+	authservice "date-apps-be/internal/service/auth"
+	"date-apps-be/pkg/api"
 	"net/http"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
-func Authorized(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		authHeader := c.Request().Header.Get("Authorization")
-		if authHeader == "" {
-			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"message": "Missing Authorization header",
-			})
-		}
+// Authorized only lets requests with a valid and not revoked access token through,
+// the token claims are stored in the context as userInfo.
+func Authorized(authService authservice.AuthService) echo.MiddlewareFunc {
+	return authorized(authService.ParseToken)
+}
 
-		tokenString := strings.Replace(authHeader, "Bearer ", "", -1)
+// RestoreAuthorized only lets requests with a valid restore token through, deleted users get it
+// instead of the token pair when they login. The token claims are stored in the context as userInfo.
+func RestoreAuthorized(authService authservice.AuthService) echo.MiddlewareFunc {
+	return authorized(authService.ParseRestoreToken)
+}
 
-		token, err := jwt.ParseWithClaims(tokenString, &model.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			if method, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-				return nil, fmt.Errorf("signing method invalid")
-			} else if method != jwt.SigningMethodRS256 {
-				return nil, fmt.Errorf("signing method invalid")
+func authorized(parseToken func(ctx context.Context, tokenString string) (*model.JWTClaims, error)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authHeader := c.Request().Header.Get("Authorization")
+			if authHeader == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"message": "Missing Authorization header",
+				})
 			}
-			return config.Get().JWTRS256PubKey, nil
-		})
 
-		if err != nil {
-			return echo.NewHTTPError(http.StatusUnauthorized, err)
-		}
+			tokenString := strings.Replace(authHeader, "Bearer ", "", -1)
 
-		claims, ok := token.Claims.(*model.JWTClaims)
-		if !ok || !token.Valid {
-			return echo.NewHTTPError(http.StatusUnauthorized, err)
-		}
+			claims, err := parseToken(c.Request().Context(), tokenString)
+			if err != nil {
+				return api.RenderErrorResponse(c, c.Request(), err)
+			}
 
-		c.Set("userInfo", claims)
-		c.Set("authHeader", authHeader)
-		next(c)
+			c.Set("userInfo", claims)
+			c.Set("authHeader", authHeader)
 
-		return nil
+			return next(c)
+		}
 	}
 }
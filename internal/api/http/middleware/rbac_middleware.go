@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"date-apps-be/internal/model"
+	"date-apps-be/pkg/api"
+	"date-apps-be/pkg/derrors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireRole only lets requests through when the access token has any of the roles,
+// it must run after Authorized.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return requireClaims(func(claims *model.JWTClaims) bool {
+		for _, role := range roles {
+			if claims.Roles.Has(role) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// RequirePermission only lets requests through when the roles of the access token
+// grant every permission, see constant.RolePermissions. It must run after Authorized.
+func RequirePermission(permissions ...string) echo.MiddlewareFunc {
+	return requireClaims(func(claims *model.JWTClaims) bool {
+		for _, permission := range permissions {
+			if !claims.Roles.Can(permission) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func requireClaims(allowed func(claims *model.JWTClaims) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, ok := c.Get("userInfo").(*model.JWTClaims)
+			if !ok {
+				return api.RenderErrorResponse(c, c.Request(), derrors.New(derrors.Unauthorized, "Missing access token"))
+			}
+
+			if !allowed(claims) {
+				return api.RenderErrorResponse(c, c.Request(), derrors.New(derrors.Forbidden, "You do not have access to this resource"))
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	hmacservice "date-apps-be/internal/service/hmac"
+	"date-apps-be/pkg/api"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ServiceAuthorized only lets requests signed by a registered backend through,
+// see pkg/hmacauth for the signature scheme. The client ID is stored in the context as serviceClient.
+func ServiceAuthorized(hmacService hmacservice.HMACService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			clientID, err := hmacService.VerifyRequest(c.Request().Context(), c.Request())
+			if err != nil {
+				return api.RenderErrorResponse(c, c.Request(), err)
+			}
+
+			c.Set("serviceClient", clientID)
+
+			return next(c)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"date-apps-be/internal/api/http/handler"
 	"date-apps-be/internal/container"
 	"net/http"
 
@@ -22,6 +23,7 @@ func Init(e *echo.Echo, hc *container.HandlerComponent, sc *container.SharedComp
 	e.GET("/docs/doc.json", echoSwagger.WrapHandler)
 	e.GET("/docs/*", echoSwagger.WrapHandler)
 	e.GET("/ping", ping)
+	e.GET("/.well-known/jwks.json", handler.NewAuthHandler(hc).JWKS)
 	publicRouter(e, hc)
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
@@ -3,6 +3,7 @@ package router
 import (
 	"date-apps-be/internal/api/http/handler"
 	"date-apps-be/internal/api/http/middleware"
+	"date-apps-be/internal/constant"
 	"date-apps-be/internal/container"
 	"net/http"
 	"time"
@@ -16,18 +17,66 @@ func publicRouter(e *echo.Echo, hc *container.HandlerComponent) {
 
 	// User
 	userHandler := handler.NewUserHandler(hc)
+	authHandler := handler.NewAuthHandler(hc)
+	accountHandler := handler.NewAccountHandler(hc)
+	mfaHandler := handler.NewMFAHandler(hc)
+	adminHandler := handler.NewAdminHandler(hc)
+	sessionHandler := handler.NewSessionHandler(hc)
+	dataExportHandler := handler.NewDataExportHandler(hc)
+	photoHandler := handler.NewPhotoHandler(hc)
+	preferenceHandler := handler.NewPreferenceHandler(hc)
+	locationHandler := handler.NewLocationHandler(hc)
+	internalHandler := handler.NewInternalHandler(hc)
 	userMatchHandler := handler.NewUserMatchHandler(hc)
 	premiumConfigHandler := handler.NewPremiumConfigHandler(hc)
 
+	authorized := middleware.Authorized(hc.AuthService)
+	restoreAuthorized := middleware.RestoreAuthorized(hc.AuthService)
+
 	//route
 	e.POST("/login", userHandler.Login)
+	e.POST("/login/mfa", userHandler.LoginMFA)
+	e.POST("/login/oidc", userHandler.LoginOIDC)
+	e.POST("/login/otp/request", authHandler.RequestLoginOTP)
+	e.POST("/login/otp/verify", authHandler.VerifyLoginOTP)
 	e.POST("/register", userHandler.Register)
+	e.POST("/token/refresh", authHandler.RefreshToken)
+	e.POST("/logout", authHandler.Logout, authorized)
+	e.GET("/verify-email", accountHandler.VerifyEmail)
+	e.POST("/verify-email/resend", accountHandler.ResendEmailVerification, authorized)
+	e.POST("/password/forgot", accountHandler.ForgotPassword)
+	e.POST("/password/reset", accountHandler.ResetPassword)
+	e.GET("/exports/download", dataExportHandler.DownloadDataExport)
+	e.POST("/users/me/restore", accountHandler.RestoreAccount, restoreAuthorized)
+	e.GET("/photos/:id", photoHandler.GetPhoto)
+	e.GET("/photos/:id/thumbnail", photoHandler.GetPhotoThumbnail)
 
 	userRoute := e.Group("/users")
 	{
-		userRoute.Use(middleware.Authorized)
+		userRoute.Use(authorized)
 		userRoute.GET("/profile", userHandler.GetUserProfile)
+		userRoute.PATCH("/profile", userHandler.UpdateUserProfile)
+		userRoute.GET("/preferences", preferenceHandler.GetPreference)
+		userRoute.PUT("/preferences", preferenceHandler.UpdatePreference)
+		userRoute.PUT("/location", locationHandler.UpdateLocation)
 		userRoute.GET("/package", userHandler.GetMyPackage)
+		userRoute.PATCH("/account/password", accountHandler.ChangePassword)
+		userRoute.PATCH("/account/email", accountHandler.ChangeEmail)
+		userRoute.PATCH("/account/phone", accountHandler.ChangePhoneNumber)
+		userRoute.POST("/account/phone/verify", accountHandler.VerifyPhoneNumber)
+		userRoute.DELETE("/me", accountHandler.DeleteAccount)
+		userRoute.POST("/me/exports", dataExportHandler.RequestDataExport)
+		userRoute.GET("/me/exports/:id", dataExportHandler.GetDataExport)
+		userRoute.POST("/me/photos", photoHandler.UploadPhoto, echoMiddleware.BodyLimit(constant.MaxPhotoRequestSize))
+		userRoute.GET("/me/photos", photoHandler.GetPhotos)
+		userRoute.PUT("/me/photos/order", photoHandler.ReorderPhotos)
+		userRoute.DELETE("/me/photos/:id", photoHandler.DeletePhoto)
+		userRoute.PUT("/me/photos/:id/primary", photoHandler.SetPrimaryPhoto)
+		userRoute.POST("/mfa/enroll", mfaHandler.Enroll)
+		userRoute.POST("/mfa/confirm", mfaHandler.Confirm)
+		userRoute.POST("/mfa/disable", mfaHandler.Disable)
+		userRoute.GET("/sessions", sessionHandler.GetSessions)
+		userRoute.DELETE("/sessions/:id", sessionHandler.RevokeSession)
 	}
 
 	userMatchRoute := e.Group("/matches")
@@ -50,16 +99,39 @@ func publicRouter(e *echo.Echo, hc *container.HandlerComponent) {
 		}
 
 		userMatchRoute.Use(echoMiddleware.RateLimiterWithConfig(config))
-		userMatchRoute.Use(middleware.Authorized)
+		userMatchRoute.Use(authorized)
 		userMatchRoute.POST("", userMatchHandler.CreateMatch)
 		userMatchRoute.GET("", userMatchHandler.GetUserMatches)
+		userMatchRoute.GET("/mutual", userMatchHandler.GetMutualMatches)
+		userMatchRoute.POST("/rewind", userMatchHandler.RewindMatch)
+		userMatchRoute.GET("/likes-received", userMatchHandler.GetLikesReceived)
+		userMatchRoute.DELETE("/:uid", userMatchHandler.Unmatch)
+	}
+
+	// staff only, every route also requires its own permission
+	adminRoute := e.Group("/admin")
+	{
+		adminRoute.Use(authorized, middleware.RequireRole(constant.RoleModerator, constant.RoleAdmin))
+		adminRoute.GET("/users/:uid", adminHandler.GetUser, middleware.RequirePermission(constant.PermissionReadUsers))
+		adminRoute.PUT("/users/:uid/roles/:role", adminHandler.GrantRole, middleware.RequirePermission(constant.PermissionManageRoles))
+		adminRoute.DELETE("/users/:uid/roles/:role", adminHandler.RevokeRole, middleware.RequirePermission(constant.PermissionManageRoles))
+		adminRoute.PUT("/users/:uid/status", adminHandler.ChangeUserStatus, middleware.RequirePermission(constant.PermissionModerateUsers))
+		adminRoute.GET("/users/:uid/status/audits", adminHandler.GetUserStatusAudits, middleware.RequirePermission(constant.PermissionModerateUsers))
+	}
+
+	// other backends only, requests are signed with a per client shared secret
+	internalRoute := e.Group("/internal")
+	{
+		internalRoute.Use(middleware.ServiceAuthorized(hc.HMACService))
+		internalRoute.GET("/users/:uid", internalHandler.GetUser)
+		internalRoute.GET("/users/:uid/entitlements", internalHandler.GetEntitlements)
 	}
 
 	premiumConfigRoute := e.Group("/packages")
 	{
 		premiumConfigRoute.GET("", premiumConfigHandler.GetPackages)
 		premiumConfigRoute.GET("/:uid", premiumConfigHandler.GetPackageByUID)
-		premiumConfigRoute.POST("/purchase", premiumConfigHandler.PurchasePackage, middleware.Authorized)
+		premiumConfigRoute.POST("/purchase", premiumConfigHandler.PurchasePackage, authorized)
 	}
 
 }
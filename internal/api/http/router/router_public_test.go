@@ -0,0 +1,255 @@
+package router
+
+import (
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/container"
+	"date-apps-be/internal/model"
+	servicesignaturerepository "date-apps-be/internal/repository/service_signature"
+	hmacservice "date-apps-be/internal/service/hmac"
+	"date-apps-be/internal/test"
+	"date-apps-be/pkg/hmacauth"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// publicRoutes can be called without an access token, every other route must reject anonymous requests.
+var publicRoutes = map[string]bool{
+	"POST /login":               true,
+	"POST /login/mfa":           true,
+	"POST /login/oidc":          true,
+	"POST /login/otp/request":   true,
+	"POST /login/otp/verify":    true,
+	"POST /register":            true,
+	"POST /token/refresh":       true,
+	"GET /verify-email":         true,
+	"POST /password/forgot":     true,
+	"POST /password/reset":      true,
+	"GET /exports/download":     true,
+	"GET /photos/:id":           true,
+	"GET /photos/:id/thumbnail": true,
+	"GET /packages":             true,
+	"GET /packages/:uid":        true,
+}
+
+// testServiceSecret is the secret of the test-backend service client.
+var testServiceSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// adminRoutes lists the expected status of every admin route per role,
+// 200 means the request reached the handler.
+var adminRoutes = []struct {
+	route    string
+	body     string
+	expected map[string]int
+}{
+	{
+		route: "GET /admin/users/:uid",
+		expected: map[string]int{
+			constant.RoleUser:      http.StatusForbidden,
+			constant.RoleModerator: http.StatusOK,
+			constant.RoleAdmin:     http.StatusOK,
+		},
+	},
+	{
+		route: "PUT /admin/users/:uid/roles/:role",
+		expected: map[string]int{
+			constant.RoleUser:      http.StatusForbidden,
+			constant.RoleModerator: http.StatusForbidden,
+			constant.RoleAdmin:     http.StatusOK,
+		},
+	},
+	{
+		route: "DELETE /admin/users/:uid/roles/:role",
+		expected: map[string]int{
+			constant.RoleUser:      http.StatusForbidden,
+			constant.RoleModerator: http.StatusForbidden,
+			constant.RoleAdmin:     http.StatusOK,
+		},
+	},
+	{
+		route: "PUT /admin/users/:uid/status",
+		body:  `{"status":"banned","reason":"spam"}`,
+		expected: map[string]int{
+			constant.RoleUser:      http.StatusForbidden,
+			constant.RoleModerator: http.StatusOK,
+			constant.RoleAdmin:     http.StatusOK,
+		},
+	},
+	{
+		route: "GET /admin/users/:uid/status/audits",
+		expected: map[string]int{
+			constant.RoleUser:      http.StatusForbidden,
+			constant.RoleModerator: http.StatusOK,
+			constant.RoleAdmin:     http.StatusOK,
+		},
+	},
+}
+
+func newTestRouter(t *testing.T) (*echo.Echo, *test.MockComponent) {
+	mc := test.InitMockComponent(t)
+	hmacService := hmacservice.NewHMACService(
+		map[string][]byte{"test-backend": testServiceSecret},
+		servicesignaturerepository.NewMemoryServiceSignatureRepository(),
+	)
+	hc := &container.HandlerComponent{
+		Config:               mc.Config,
+		AuthService:          mc.AuthService,
+		HMACService:          hmacService,
+		UserUsecase:          mc.UserUsecase,
+		UserMatchUsecase:     mc.UserMatchUsecase,
+		PremiumConfigUsecase: mc.PremiumConfigUsecase,
+		OTPUsecase:           mc.OTPUsecase,
+		AccountUsecase:       mc.AccountUsecase,
+		MFAUsecase:           mc.MFAUsecase,
+		RoleUsecase:          mc.RoleUsecase,
+		SessionUsecase:       mc.SessionUsecase,
+		DataExportUsecase:    mc.DataExportUsecase,
+		ModerationUsecase:    mc.ModerationUsecase,
+		PhotoUsecase:         mc.PhotoUsecase,
+		PreferenceUsecase:    mc.PreferenceUsecase,
+		LocationUsecase:      mc.LocationUsecase,
+	}
+
+	e := echo.New()
+	e.Validator = &requestValidator{}
+	publicRouter(e, hc)
+	return e, mc
+}
+
+type requestValidator struct{}
+
+func (rv *requestValidator) Validate(i interface{}) (err error) {
+	_, err = govalidator.ValidateStruct(i)
+	return
+}
+
+// requestPath fills the path parameters of a route with dummy values.
+func requestPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			parts[i] = "param"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func isHTTPMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func TestProtectedRoutesRequireToken(t *testing.T) {
+	e, _ := newTestRouter(t)
+
+	for _, route := range e.Routes() {
+		key := route.Method + " " + route.Path
+		if !isHTTPMethod(route.Method) || publicRoutes[key] {
+			continue
+		}
+
+		t.Run(key, func(t *testing.T) {
+			req := httptest.NewRequest(route.Method, requestPath(route.Path), nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		})
+	}
+}
+
+func TestAdminRoutePermissions(t *testing.T) {
+	e, mc := newTestRouter(t)
+
+	for _, role := range []string{constant.RoleUser, constant.RoleModerator, constant.RoleAdmin} {
+		claims := &model.JWTClaims{UserUID: role + "-uid"}
+		if role != constant.RoleUser {
+			claims.Roles = model.Roles{role}
+		}
+		mc.AuthService.On("ParseToken", mock.Anything, role+"-token").Return(claims, nil).Maybe()
+	}
+	mc.UserUsecase.On("GetUser", mock.Anything, "param").Return(&model.User{UID: "param"}, nil).Maybe()
+	mc.RoleUsecase.On("GetUserRoles", mock.Anything, "param").Return([]string{}, nil).Maybe()
+	mc.RoleUsecase.On("GrantRole", mock.Anything, "param", "param").Return(nil).Maybe()
+	mc.RoleUsecase.On("RevokeRole", mock.Anything, mock.Anything, "param", "param").Return(nil).Maybe()
+	mc.ModerationUsecase.On("ChangeUserStatus", mock.Anything, mock.Anything).Return(&model.User{UID: "param"}, nil).Maybe()
+	mc.ModerationUsecase.On("GetUserStatusAudits", mock.Anything, "param", mock.Anything, mock.Anything).Return([]*model.UserStatusAudit{}, nil).Maybe()
+
+	tested := map[string]bool{}
+	for _, adminRoute := range adminRoutes {
+		tested[adminRoute.route] = true
+		method, path, _ := strings.Cut(adminRoute.route, " ")
+
+		for role, expected := range adminRoute.expected {
+			t.Run(adminRoute.route+" as "+role, func(t *testing.T) {
+				req := httptest.NewRequest(method, requestPath(path), strings.NewReader(adminRoute.body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				req.Header.Set("Authorization", "Bearer "+role+"-token")
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+
+				assert.Equal(t, expected, rec.Code)
+			})
+		}
+	}
+
+	for _, route := range e.Routes() {
+		key := route.Method + " " + route.Path
+		if isHTTPMethod(route.Method) && strings.HasPrefix(route.Path, "/admin") {
+			assert.True(t, tested[key], "admin route %s is missing from the permission table", key)
+		}
+	}
+}
+
+func TestInternalRoutesRequireSignature(t *testing.T) {
+	e, mc := newTestRouter(t)
+
+	mc.AuthService.On("ParseToken", mock.Anything, "admin-token").Return(&model.JWTClaims{UserUID: "admin-uid", Roles: model.Roles{constant.RoleAdmin}}, nil).Maybe()
+	mc.UserUsecase.On("GetUser", mock.Anything, "param").Return(&model.User{UID: "param"}, nil).Maybe()
+	mc.UserUsecase.On("GetUserPackage", mock.Anything, "param").Return(nil, nil).Maybe()
+	mc.RoleUsecase.On("GetUserRoles", mock.Anything, "param").Return([]string{}, nil).Maybe()
+
+	for _, route := range e.Routes() {
+		if !isHTTPMethod(route.Method) || !strings.HasPrefix(route.Path, "/internal") {
+			continue
+		}
+
+		t.Run(route.Method+" "+route.Path, func(t *testing.T) {
+			path := requestPath(route.Path)
+
+			req := httptest.NewRequest(route.Method, path, nil)
+			req.Header.Set("Authorization", "Bearer admin-token")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, "access tokens are not accepted")
+
+			timestamp := time.Now().Unix()
+			signature := hmacauth.Sign(testServiceSecret, route.Method, path, timestamp, nil)
+			signed := func() *http.Request {
+				req := httptest.NewRequest(route.Method, path, nil)
+				req.Header.Set(hmacauth.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+				req.Header.Set(hmacauth.HeaderAuthorization, hmacauth.Authorization("test-backend", signature))
+				return req
+			}
+
+			rec = httptest.NewRecorder()
+			e.ServeHTTP(rec, signed())
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			rec = httptest.NewRecorder()
+			e.ServeHTTP(rec, signed())
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, "replayed requests are rejected")
+		})
+	}
+}
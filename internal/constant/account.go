@@ -0,0 +1,34 @@
+package constant
+
+import "time"
+
+// List of supported mailers
+const (
+	MailerLog  = "log"
+	MailerSMTP = "smtp"
+)
+
+// EmailVerificationAudience is the audience of email verification tokens,
+// so they can not be mistaken for other tokens.
+const EmailVerificationAudience = "email-verification"
+
+// List of internal constant for password reset
+const (
+	PasswordResetExpiration = time.Hour
+	PasswordResetTokenBytes = 32
+	// MaxPasswordResetPerWindow limits how many reset emails a user receives inside PasswordResetWindow.
+	MaxPasswordResetPerWindow = 3
+	PasswordResetWindow       = time.Hour
+	// PasswordResetSendTimeout bounds the background work of a password reset request.
+	PasswordResetSendTimeout = 30 * time.Second
+)
+
+// List of internal constant for account deletion
+const (
+	// AccountPurgeInterval is how often accounts past the deletion grace period are purged.
+	AccountPurgeInterval = time.Hour
+	// AccountPurgeBatchSize is how many accounts are purged per run at most.
+	AccountPurgeBatchSize = 100
+	// RestoreTokenExpiration is how long a deleted user has to restore the account after logging in.
+	RestoreTokenExpiration = 15 * time.Minute
+)
@@ -0,0 +1,60 @@
+package constant
+
+import "time"
+
+// TokenIssuer is the iss claim of every token the service signs, tokens with another issuer are rejected.
+const TokenIssuer = "date-apps"
+
+// List of internal constant for authentication
+const (
+	// MaxFailedLoginPerAccount is the number of consecutive failed logins
+	// inside FailedLoginWindow before the account gets locked.
+	MaxFailedLoginPerAccount = 5
+	// MaxFailedLoginPerIP is the number of failed logins inside
+	// FailedLoginWindow before any further attempt from the same IP is rejected.
+	MaxFailedLoginPerIP = 20
+	FailedLoginWindow   = 15 * time.Minute
+	AccountLockDuration = 15 * time.Minute
+
+	MaxUserAgentLength = 255
+	MaxDeviceIDLength  = 255
+
+	// HeaderDeviceID identifies the device of a login, every login on a device starts a session.
+	HeaderDeviceID = "x-device-id"
+)
+
+// List of login failure reasons recorded on the login history
+const (
+	LoginFailureUserNotFound     = "user_not_found"
+	LoginFailureInvalidPassword  = "invalid_password"
+	LoginFailureAccountLocked    = "account_locked"
+	LoginFailureAccountSuspended = "account_suspended"
+	LoginFailureInvalidMFACode   = "invalid_mfa_code"
+	LoginFailureInvalidOTPCode   = "invalid_otp_code"
+)
+
+// List of token scopes, access tokens have no scope
+const (
+	TokenScopeMFAPending     = "mfa_pending"
+	TokenScopeAccountRestore = "account_restore"
+)
+
+// List of internal constant for two-factor authentication
+const (
+	// MFATokenExpiration is how long the user has to enter the TOTP code after the password.
+	MFATokenExpiration   = 5 * time.Minute
+	MFASecretBytes       = 20
+	MFAIssuer            = "Date Apps"
+	MFARecoveryCodeCount = 10
+	// MFAValidationSkew is the number of 30 seconds steps accepted before and after the current one.
+	MFAValidationSkew = 1
+)
+
+// List of supported access token revocation stores
+const (
+	TokenRevocationStoreMySQL  = "mysql"
+	TokenRevocationStoreMemory = "memory"
+
+	// TokenRevocationCleanupInterval is how often expired revocations are garbage collected.
+	TokenRevocationCleanupInterval = 10 * time.Minute
+)
@@ -0,0 +1,35 @@
+package constant
+
+import "time"
+
+// List of data export statuses
+const (
+	DataExportStatusPending    = "pending"
+	DataExportStatusProcessing = "processing"
+	DataExportStatusCompleted  = "completed"
+	DataExportStatusFailed     = "failed"
+	DataExportStatusExpired    = "expired"
+)
+
+// DataExportAudience is the audience of data export download tokens,
+// so they can not be mistaken for other tokens.
+const DataExportAudience = "data-export"
+
+// List of internal constant for data exports
+const (
+	// DataExportExpiration is how long an archive can be downloaded before it is deleted.
+	DataExportExpiration = 7 * 24 * time.Hour
+	// DataExportLinkExpiration is how long a signed download link is valid.
+	DataExportLinkExpiration = 15 * time.Minute
+	// MaxDataExportPerWindow limits how many archives a user can request inside DataExportWindow.
+	MaxDataExportPerWindow = 3
+	DataExportWindow       = 24 * time.Hour
+	// DataExportProcessingTimeout is when an export stuck in processing, e.g. after a crash, is picked up again.
+	DataExportProcessingTimeout = 15 * time.Minute
+
+	DataExportProcessInterval = time.Minute
+	DataExportCleanupInterval = 10 * time.Minute
+	DataExportBatchSize       = 10
+	// DataExportPageSize is how many rows are read at once while building an archive.
+	DataExportPageSize = 500
+)
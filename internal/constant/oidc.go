@@ -0,0 +1,11 @@
+package constant
+
+import "time"
+
+// List of internal constant for OpenID Connect
+const (
+	OIDCJWKSCacheTTL = time.Hour
+	// OIDCJWKSMinRefreshInterval limits refetching the key set when a token has an unknown kid.
+	OIDCJWKSMinRefreshInterval = time.Minute
+	OIDCHTTPTimeout            = 5 * time.Second
+)
@@ -0,0 +1,25 @@
+package constant
+
+import "time"
+
+// List of OTP purposes
+const (
+	OTPPurposeLogin       = "login"
+	OTPPurposeVerifyPhone = "verify_phone"
+)
+
+// List of internal constant for OTP
+const (
+	OTPLength      = 6
+	OTPExpiration  = 5 * time.Minute
+	OTPMaxAttempts = 5
+	// OTPMaxRequestPerWindow limits how many codes can be sent to a phone number inside OTPRequestWindow.
+	OTPMaxRequestPerWindow = 3
+	OTPRequestWindow       = 15 * time.Minute
+	OTPResendCooldown      = time.Minute
+)
+
+// List of supported SMS senders
+const (
+	SMSSenderLog = "log"
+)
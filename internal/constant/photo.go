@@ -0,0 +1,25 @@
+package constant
+
+// List of internal constant for profile photos
+const (
+	// MaxPhotoSize is the largest accepted upload in bytes.
+	MaxPhotoSize = 10 << 20
+	// MaxPhotoRequestSize limits the whole multipart request, it leaves room for the form overhead.
+	MaxPhotoRequestSize = "11M"
+	MaxPhotosPerUser    = 6
+	// MaxPhotoPixels rejects images that would use too much memory once decoded, 16 MP takes 64 MB as RGBA.
+	MaxPhotoPixels = 16_000_000
+	// PhotoMaxSize is the square the stored photo is scaled down to fit in.
+	PhotoMaxSize       = 2048
+	PhotoThumbnailSize = 320
+	PhotoJPEGQuality   = 90
+	// MaxConcurrentPhotoProcessing limits how many uploads are decoded at the same time,
+	// the others wait so the memory used stays bounded.
+	MaxConcurrentPhotoProcessing = 2
+)
+
+// List of supported photo content types, the type is sniffed from the content
+const (
+	PhotoContentTypeJPEG = "image/jpeg"
+	PhotoContentTypePNG  = "image/png"
+)
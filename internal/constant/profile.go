@@ -0,0 +1,41 @@
+package constant
+
+import "time"
+
+//go:generate go-enum --marshal --sql --values --names --file
+
+// ENUM(male, female, non_binary)
+type Gender string
+
+// List of internal constant for dating profiles
+const (
+	MinAge = 18
+	MaxAge = 100
+
+	MinHeightCM = 100
+	MaxHeightCM = 250
+
+	MaxNameLength      = 255
+	MaxBioLength       = 500
+	MaxJobLength       = 100
+	MaxEducationLength = 100
+
+	MaxInterests      = 10
+	MaxInterestLength = 30
+)
+
+// List of internal constant for discovery preferences
+const (
+	MinDistanceKM = 1
+	MaxDistanceKM = 500
+)
+
+// LocationGridKM is the size of the grid cells reported locations are snapped to before they are stored.
+const LocationGridKM = 1
+
+// LocationUpdateInterval is how long a user waits before the stored location can be moved to another cell,
+// otherwise many fake locations in a row would narrow down the location of others from the distances shown.
+const LocationUpdateInterval = 5 * time.Minute
+
+// LocationGeohashPrecision is the number of geohash characters stored for a location, about 5 m.
+const LocationGeohashPrecision = 9
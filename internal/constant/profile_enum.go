@@ -0,0 +1,128 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package constant
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// GenderMale is a Gender of type male.
+	GenderMale Gender = "male"
+	// GenderFemale is a Gender of type female.
+	GenderFemale Gender = "female"
+	// GenderNonBinary is a Gender of type non_binary.
+	GenderNonBinary Gender = "non_binary"
+)
+
+var ErrInvalidGender = fmt.Errorf("not a valid Gender, try [%s]", strings.Join(_GenderNames, ", "))
+
+var _GenderNames = []string{
+	string(GenderMale),
+	string(GenderFemale),
+	string(GenderNonBinary),
+}
+
+// GenderNames returns a list of possible string values of Gender.
+func GenderNames() []string {
+	tmp := make([]string, len(_GenderNames))
+	copy(tmp, _GenderNames)
+	return tmp
+}
+
+// GenderValues returns a list of the values for Gender
+func GenderValues() []Gender {
+	return []Gender{
+		GenderMale,
+		GenderFemale,
+		GenderNonBinary,
+	}
+}
+
+// String implements the Stringer interface.
+func (x Gender) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x Gender) IsValid() bool {
+	_, err := ParseGender(string(x))
+	return err == nil
+}
+
+var _GenderValue = map[string]Gender{
+	"male":       GenderMale,
+	"female":     GenderFemale,
+	"non_binary": GenderNonBinary,
+}
+
+// ParseGender attempts to convert a string to a Gender.
+func ParseGender(name string) (Gender, error) {
+	if x, ok := _GenderValue[name]; ok {
+		return x, nil
+	}
+	return Gender(""), fmt.Errorf("%s is %w", name, ErrInvalidGender)
+}
+
+// MarshalText implements the text marshaller method.
+func (x Gender) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *Gender) UnmarshalText(text []byte) error {
+	tmp, err := ParseGender(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+var errGenderNilPtr = errors.New("value pointer is nil") // one per type for package clashes
+
+// Scan implements the Scanner interface.
+func (x *Gender) Scan(value interface{}) (err error) {
+	if value == nil {
+		*x = Gender("")
+		return
+	}
+
+	// A wider range of scannable types.
+	// driver.Value values at the top of the list for expediency
+	switch v := value.(type) {
+	case string:
+		*x, err = ParseGender(v)
+	case []byte:
+		*x, err = ParseGender(string(v))
+	case Gender:
+		*x = v
+	case *Gender:
+		if v == nil {
+			return errGenderNilPtr
+		}
+		*x = *v
+	case *string:
+		if v == nil {
+			return errGenderNilPtr
+		}
+		*x, err = ParseGender(*v)
+	default:
+		return errors.New("invalid type for Gender")
+	}
+
+	return
+}
+
+// Value implements the driver Valuer interface.
+func (x Gender) Value() (driver.Value, error) {
+	return x.String(), nil
+}
@@ -0,0 +1,39 @@
+package constant
+
+// List of roles, every user has RoleUser and only elevated roles are stored in user_roles
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// List of permissions checked by middleware.RequirePermission
+const (
+	PermissionReadUsers     = "users:read"
+	PermissionModerateUsers = "users:moderate"
+	PermissionManageRoles   = "roles:manage"
+)
+
+// RolePermissions is the permission matrix, a user has the union of the permissions of their roles.
+var RolePermissions = map[string][]string{
+	RoleUser: {},
+	RoleModerator: {
+		PermissionReadUsers,
+		PermissionModerateUsers,
+	},
+	RoleAdmin: {
+		PermissionReadUsers,
+		PermissionModerateUsers,
+		PermissionManageRoles,
+	},
+}
+
+// List of user statuses, a suspension ends on its own once suspended_until passed
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusBanned    = "banned"
+)
+
+// MaxStatusReasonLength is the size of the status reason column.
+const MaxStatusReasonLength = 255
@@ -0,0 +1,21 @@
+package constant
+
+import "time"
+
+// List of internal constant for service to service authentication
+const (
+	// ServiceAuthClockSkew is how far the request timestamp may be from the server clock,
+	// in both directions. Signatures are remembered for as long to reject replays.
+	ServiceAuthClockSkew = 5 * time.Minute
+	// ServiceAuthMaxBodyBytes is the largest body that is hashed to verify a signature.
+	ServiceAuthMaxBodyBytes = 1 << 20
+
+	// ServiceSignatureCleanupInterval is how often expired signatures are garbage collected.
+	ServiceSignatureCleanupInterval = 10 * time.Minute
+)
+
+// List of supported replay stores of service request signatures
+const (
+	ServiceReplayStoreMySQL  = "mysql"
+	ServiceReplayStoreMemory = "memory"
+)
@@ -1,11 +1,17 @@
 package constant
 
+import "time"
+
 //go:generate go-enum --marshal --sql --values --names --file
 
-// ENUM(pass, like)
+// ENUM(pass, like, super_like)
 type UserMatchType string
 
 // List of internal constant for user match
 const (
 	MaxMatchPerDay = 10
+	// MaxSuperLikePerDay applies to users without a package and to packages without a super-like quota
+	MaxSuperLikePerDay = 1
+	// RewindWindow is how long after a swipe it can still be rewound
+	RewindWindow = 10 * time.Minute
 )
@@ -18,6 +18,8 @@ const (
 	UserMatchTypePass UserMatchType = "pass"
 	// UserMatchTypeLike is a UserMatchType of type like.
 	UserMatchTypeLike UserMatchType = "like"
+	// UserMatchTypeSuperLike is a UserMatchType of type super_like.
+	UserMatchTypeSuperLike UserMatchType = "super_like"
 )
 
 var ErrInvalidUserMatchType = fmt.Errorf("not a valid UserMatchType, try [%s]", strings.Join(_UserMatchTypeNames, ", "))
@@ -25,6 +27,7 @@ var ErrInvalidUserMatchType = fmt.Errorf("not a valid UserMatchType, try [%s]",
 var _UserMatchTypeNames = []string{
 	string(UserMatchTypePass),
 	string(UserMatchTypeLike),
+	string(UserMatchTypeSuperLike),
 }
 
 // UserMatchTypeNames returns a list of possible string values of UserMatchType.
@@ -39,6 +42,7 @@ func UserMatchTypeValues() []UserMatchType {
 	return []UserMatchType{
 		UserMatchTypePass,
 		UserMatchTypeLike,
+		UserMatchTypeSuperLike,
 	}
 }
 
@@ -55,8 +59,9 @@ func (x UserMatchType) IsValid() bool {
 }
 
 var _UserMatchTypeValue = map[string]UserMatchType{
-	"pass": UserMatchTypePass,
-	"like": UserMatchTypeLike,
+	"pass":       UserMatchTypePass,
+	"like":       UserMatchTypeLike,
+	"super_like": UserMatchTypeSuperLike,
 }
 
 // ParseUserMatchType attempts to convert a string to a UserMatchType.
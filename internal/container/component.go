@@ -1,16 +1,54 @@
 package container
 
 import (
+	"context"
 	"date-apps-be/infrastructure/config"
+	"date-apps-be/internal/constant"
 	repository "date-apps-be/internal/repository/common"
+	dataexportrepository "date-apps-be/internal/repository/data_export"
+	hiddenpairrepository "date-apps-be/internal/repository/hidden_pair"
+	loginhistoryrepository "date-apps-be/internal/repository/login_history"
+	mutualmatchrepository "date-apps-be/internal/repository/mutual_match"
+	otpcoderepository "date-apps-be/internal/repository/otp_code"
+	passwordresetrepository "date-apps-be/internal/repository/password_reset"
 	premiumconfigrepository "date-apps-be/internal/repository/premium_config"
+	refreshtokenrepository "date-apps-be/internal/repository/refresh_token"
+	revokedtokenrepository "date-apps-be/internal/repository/revoked_token"
+	servicesignaturerepository "date-apps-be/internal/repository/service_signature"
 	userrepository "date-apps-be/internal/repository/user"
+	useridentityrepository "date-apps-be/internal/repository/user_identity"
+	userlocationrepository "date-apps-be/internal/repository/user_location"
 	usermatchrepository "date-apps-be/internal/repository/user_match"
+	usermfarepository "date-apps-be/internal/repository/user_mfa"
+	userphotorepository "date-apps-be/internal/repository/user_photo"
+	userpreferencerepository "date-apps-be/internal/repository/user_preference"
 	userpackagerepository "date-apps-be/internal/repository/user_premium"
+	userrewindrepository "date-apps-be/internal/repository/user_rewind"
+	userrolerepository "date-apps-be/internal/repository/user_role"
+	usersessionrepository "date-apps-be/internal/repository/user_session"
+	userstatusauditrepository "date-apps-be/internal/repository/user_status_audit"
 	authservice "date-apps-be/internal/service/auth"
+	blobservice "date-apps-be/internal/service/blob"
+	hmacservice "date-apps-be/internal/service/hmac"
+	imageservice "date-apps-be/internal/service/image"
+	mailservice "date-apps-be/internal/service/mail"
+	oidcservice "date-apps-be/internal/service/oidc"
+	smsservice "date-apps-be/internal/service/sms"
+	accountusecase "date-apps-be/internal/usecase/account"
+	dataexportusecase "date-apps-be/internal/usecase/data_export"
+	locationusecase "date-apps-be/internal/usecase/location"
+	loginattemptusecase "date-apps-be/internal/usecase/login_attempt"
+	mfausecase "date-apps-be/internal/usecase/mfa"
+	moderationusecase "date-apps-be/internal/usecase/moderation"
+	otpusecase "date-apps-be/internal/usecase/otp"
+	photousecase "date-apps-be/internal/usecase/photo"
+	preferenceusecase "date-apps-be/internal/usecase/preference"
 	premiumconfigusecase "date-apps-be/internal/usecase/premium_config"
+	roleusecase "date-apps-be/internal/usecase/role"
+	sessionusecase "date-apps-be/internal/usecase/session"
 	userusecase "date-apps-be/internal/usecase/user"
 	usermatchusecase "date-apps-be/internal/usecase/user_match"
+	"date-apps-be/internal/worker"
 )
 
 type HandlerComponent struct {
@@ -18,38 +56,143 @@ type HandlerComponent struct {
 
 	// Service
 	AuthService authservice.AuthService
+	HMACService hmacservice.HMACService
 
 	// Usecase
 	UserUsecase          userusecase.UserUsecase
 	UserMatchUsecase     usermatchusecase.UserMatchUsecase
 	PremiumConfigUsecase premiumconfigusecase.PremiumConfigUsecase
+	OTPUsecase           otpusecase.OTPUsecase
+	AccountUsecase       accountusecase.AccountUsecase
+	MFAUsecase           mfausecase.MFAUsecase
+	RoleUsecase          roleusecase.RoleUsecase
+	SessionUsecase       sessionusecase.SessionUsecase
+	DataExportUsecase    dataexportusecase.DataExportUsecase
+	ModerationUsecase    moderationusecase.ModerationUsecase
+	PhotoUsecase         photousecase.PhotoUsecase
+	PreferenceUsecase    preferenceusecase.PreferenceUsecase
+	LocationUsecase      locationusecase.LocationUsecase
+
+	// Background jobs
+	Worker *worker.Worker
 }
 
 func NewHandlerComponent(sc *SharedComponent) *HandlerComponent {
 
 	baseStore := repository.NewRepository(sc.DB)
 
-	authservice := authservice.NewAuthService(sc.Conf)
+	refreshTokenRepo := refreshtokenrepository.NewRefreshTokenRepository(baseStore)
+	revokedTokenRepo := revokedtokenrepository.NewRevokedTokenRepository(sc.Conf.TokenRevocationStore, baseStore)
+	userRoleRepo := userrolerepository.NewUserRoleRepository(baseStore)
+	userSessionRepo := usersessionrepository.NewUserSessionRepository(baseStore)
+	userRepo := userrepository.NewUserRepository(baseStore)
+	authservice := authservice.NewAuthService(sc.Conf, refreshTokenRepo, revokedTokenRepo, userRoleRepo, userSessionRepo, userRepo)
 
 	userPackageRepo := userpackagerepository.NewUserPremiumRepository(baseStore)
-	userRepo := userrepository.NewUserRepository(baseStore)
-	userUsecase := userusecase.NewUserUsecase(userRepo, authservice, userPackageRepo)
+	loginHistoryRepo := loginhistoryrepository.NewLoginHistoryRepository(baseStore)
+	mailer := mailservice.NewMailer(sc.Conf.Mail, sc.Log)
+	passwordResetRepo := passwordresetrepository.NewPasswordResetRepository(baseStore)
+	blobStore := blobservice.NewLocalBlobStore(sc.Conf.BlobStoreDir)
+	userPhotoRepo := userphotorepository.NewUserPhotoRepository(baseStore)
+	loginAttemptUsecase := loginattemptusecase.NewLoginAttemptUsecase(loginHistoryRepo, userRepo)
+	accountUsecase := accountusecase.NewAccountUsecase(sc.Conf, userRepo, passwordResetRepo, authservice, mailer, userPhotoRepo, blobStore, loginAttemptUsecase, sc.Log)
+	userMFARepo := usermfarepository.NewUserMFARepository(baseStore)
+	mfaUsecase := mfausecase.NewMFAUsecase(sc.Conf, userMFARepo, userRepo, authservice, loginAttemptUsecase)
+	userIdentityRepo := useridentityrepository.NewUserIdentityRepository(baseStore)
+	oidcService := oidcservice.NewOIDCService(sc.Conf.OIDCProviders, oidcservice.NewHTTPKeySource(nil))
+	userUsecase := userusecase.NewUserUsecase(userRepo, authservice, userPackageRepo, loginAttemptUsecase, accountUsecase, mfaUsecase, userIdentityRepo, oidcService)
+
+	smsSender := smsservice.NewSMSSender(sc.Conf.SMSSender, sc.Log)
+	otpCodeRepo := otpcoderepository.NewOTPCodeRepository(baseStore)
+	otpUsecase := otpusecase.NewOTPUsecase(otpCodeRepo, userRepo, mfaUsecase, smsSender, loginAttemptUsecase)
+
+	imageProcessor := imageservice.NewImageProcessor(constant.PhotoMaxSize, constant.PhotoThumbnailSize)
+	photoUsecase := photousecase.NewPhotoUsecase(sc.Conf, userPhotoRepo, blobStore, imageProcessor)
+
+	userPreferenceRepo := userpreferencerepository.NewUserPreferenceRepository(baseStore)
+	preferenceUsecase := preferenceusecase.NewPreferenceUsecase(userPreferenceRepo)
+	userLocationRepo := userlocationrepository.NewUserLocationRepository(baseStore)
+	locationUsecase := locationusecase.NewLocationUsecase(userLocationRepo)
 
 	userMatchRepo := usermatchrepository.NewUserMatchRepository(baseStore)
-	userMatchUsecase := usermatchusecase.NewUserMatchUsecase(userMatchRepo, userUsecase)
+	mutualMatchRepo := mutualmatchrepository.NewMutualMatchRepository(baseStore)
+	userRewindRepo := userrewindrepository.NewUserRewindRepository(baseStore)
+	hiddenPairRepo := hiddenpairrepository.NewHiddenPairRepository(baseStore)
+	userMatchUsecase := usermatchusecase.NewUserMatchUsecase(usermatchusecase.Dependencies{
+		Conf:               sc.Conf,
+		UserMatchRepo:      userMatchRepo,
+		UserUsecase:        userUsecase,
+		PhotoUsecase:       photoUsecase,
+		UserRepo:           userRepo,
+		UserLocationRepo:   userLocationRepo,
+		UserPreferenceRepo: userPreferenceRepo,
+		MutualMatchRepo:    mutualMatchRepo,
+		UserRewindRepo:     userRewindRepo,
+		HiddenPairRepo:     hiddenPairRepo,
+		Mailer:             mailer,
+	})
+
+	roleUsecase := roleusecase.NewRoleUsecase(userRepo, userRoleRepo, authservice)
+	sessionUsecase := sessionusecase.NewSessionUsecase(userSessionRepo, authservice)
+	userStatusAuditRepo := userstatusauditrepository.NewUserStatusAuditRepository(baseStore)
+	moderationUsecase := moderationusecase.NewModerationUsecase(userRepo, userRoleRepo, userStatusAuditRepo, authservice)
+
+	dataExportRepo := dataexportrepository.NewDataExportRepository(baseStore)
+	dataExportUsecase := dataexportusecase.NewDataExportUsecase(sc.Conf, dataExportRepo, userRepo, userMatchRepo, userPackageRepo, loginHistoryRepo,
+		userPhotoRepo, userPreferenceRepo, userLocationRepo, mutualMatchRepo, userRewindRepo, blobStore)
+
+	serviceSignatureRepo := servicesignaturerepository.NewServiceSignatureRepository(sc.Conf.ServiceReplayStore, baseStore)
+	hmacService := hmacservice.NewHMACService(sc.Conf.ServiceClients, serviceSignatureRepo)
 
 	premiumConfigRepo := premiumconfigrepository.NewPremiumConfigRepository(baseStore)
 	premiumConfigUsecase := premiumconfigusecase.NewPremiumConfigUsecase(premiumConfigRepo, userPackageRepo)
 
+	w := worker.New(sc.Log)
+	w.Register("delete-expired-revoked-tokens", constant.TokenRevocationCleanupInterval, func(ctx context.Context) error {
+		_, err := revokedTokenRepo.DeleteExpiredTokens(ctx)
+		return err
+	})
+	w.Register("delete-expired-service-signatures", constant.ServiceSignatureCleanupInterval, func(ctx context.Context) error {
+		_, err := serviceSignatureRepo.DeleteExpiredSignatures(ctx)
+		return err
+	})
+
+	w.Register("purge-deleted-accounts", constant.AccountPurgeInterval, func(ctx context.Context) error {
+		_, err := accountUsecase.PurgeDeletedAccounts(ctx)
+		return err
+	})
+	w.Register("process-data-exports", constant.DataExportProcessInterval, func(ctx context.Context) error {
+		_, err := dataExportUsecase.ProcessDataExports(ctx)
+		return err
+	})
+	w.Register("delete-expired-data-exports", constant.DataExportCleanupInterval, func(ctx context.Context) error {
+		_, err := dataExportUsecase.DeleteExpiredDataExports(ctx)
+		return err
+	})
+
 	return &HandlerComponent{
 		Config: sc.Conf,
 
 		// Service
 		AuthService: authservice,
+		HMACService: hmacService,
 
 		// Usecase
 		UserUsecase:          userUsecase,
 		UserMatchUsecase:     userMatchUsecase,
 		PremiumConfigUsecase: premiumConfigUsecase,
+		OTPUsecase:           otpUsecase,
+		AccountUsecase:       accountUsecase,
+		MFAUsecase:           mfaUsecase,
+		RoleUsecase:          roleUsecase,
+		SessionUsecase:       sessionUsecase,
+		DataExportUsecase:    dataExportUsecase,
+		ModerationUsecase:    moderationUsecase,
+		PhotoUsecase:         photoUsecase,
+		PreferenceUsecase:    preferenceUsecase,
+		LocationUsecase:      locationUsecase,
+
+		// Background jobs
+		Worker: w,
 	}
 }
@@ -0,0 +1,17 @@
+package model
+
+// AuthToken is the token pair handed to the client after a successful login.
+// When the user enabled two-factor authentication only MFARequired and MFAToken are set,
+// the MFA token is exchanged for the token pair with a TOTP or recovery code.
+// When the account is deleted only RestoreRequired and RestoreToken are set,
+// the restore token is only accepted by the restore endpoint.
+type AuthToken struct {
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"` // access token lifetime in seconds
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+	// RestoreRequired is set instead of the token pair when the account is deleted
+	RestoreRequired bool   `json:"restore_required,omitempty"`
+	RestoreToken    string `json:"restore_token,omitempty"`
+}
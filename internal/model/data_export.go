@@ -0,0 +1,36 @@
+package model
+
+import (
+	"date-apps-be/internal/constant"
+	"date-apps-be/pkg/datatype"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DataExport is an archive of the personal data of a user, it is generated in the background.
+type DataExport struct {
+	UID           string        `json:"id"`
+	UserUID       string        `json:"-"`
+	Status        string        `json:"status"`
+	BlobKey       *string       `json:"-"`
+	Size          int64         `json:"size,omitempty"`
+	FailureReason *string       `json:"-"`
+	CompletedAt   datatype.Time `json:"completed_at"`
+	ExpiresAt     datatype.Time `json:"expires_at"`
+	CreatedAt     datatype.Time `json:"created_at"`
+	// DownloadURL is a short lived signed link, set when the archive can be downloaded
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// IsDownloadable reports whether the archive is complete and not expired yet.
+func (e *DataExport) IsDownloadable() bool {
+	now := datatype.NewTimeNow()
+	return e.Status == constant.DataExportStatusCompleted && e.BlobKey != nil && e.ExpiresAt.IsAfter(now)
+}
+
+// DataExportClaims are signed into the download link of an archive.
+type DataExportClaims struct {
+	UserUID  string `json:"user_uid"`
+	ExportID string `json:"export_id"`
+	jwt.RegisteredClaims
+}
@@ -0,0 +1,12 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+// HiddenPair keeps two users out of each other's discovery after one of them unmatched,
+// UserUID is the smaller uid of the pair.
+type HiddenPair struct {
+	UserUID   string
+	MatchUID  string
+	HiddenBy  string
+	CreatedAt datatype.Time
+}
@@ -6,6 +6,12 @@ import (
 
 type JWTClaims struct {
 	UserUID string `json:"user_uid"`
+	// Scope is empty for access tokens, other tokens like mfa_pending are only accepted by their own endpoint
+	Scope string `json:"scope,omitempty"`
+	// Roles are the elevated roles of the user when the token was issued
+	Roles Roles `json:"roles,omitempty"`
+	// SessionID is the session the access token belongs to, revoking the session revokes the token
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -14,3 +20,11 @@ func (c JWTClaims) GetAudience() (jwt.ClaimStrings, error) {
 	return c.Audience, nil
 
 }
+
+// EmailVerificationClaims are signed into the link sent to verify an email address,
+// the email is included so the link stops working once the email is changed.
+type EmailVerificationClaims struct {
+	UserUID string `json:"user_uid"`
+	Email   string `json:"email"`
+	jwt.RegisteredClaims
+}
@@ -0,0 +1,32 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+type LoginHistory struct {
+	UID           string        `json:"uid"`
+	UserUID       *string       `json:"user_uid,omitempty"`
+	Identifier    string        `json:"identifier"`
+	IPAddress     string        `json:"ip_address"`
+	UserAgent     *string       `json:"user_agent,omitempty"`
+	IsSuccess     bool          `json:"is_success"`
+	FailureReason *string       `json:"failure_reason,omitempty"`
+	CreatedAt     datatype.Time `json:"created_at"`
+}
+
+// LoginAttempt is the identifier and the device of an attempt to login or to confirm a credential,
+// it is written to the login history.
+type LoginAttempt struct {
+	Identifier string
+	Device     Device
+}
+
+// NewLoginAttempt returns an attempt of the user identified by the email or the phone number.
+func NewLoginAttempt(user *User, device Device) LoginAttempt {
+	attempt := LoginAttempt{Device: device}
+	if user.Email != nil && *user.Email != "" {
+		attempt.Identifier = *user.Email
+	} else if user.PhoneNumber != nil {
+		attempt.Identifier = *user.PhoneNumber
+	}
+	return attempt
+}
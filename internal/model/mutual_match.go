@@ -0,0 +1,22 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+// MutualMatch is created when two users liked each other, UserUID is the smaller uid of the pair.
+type MutualMatch struct {
+	UID       string
+	UserUID   string
+	MatchUID  string
+	CreatedAt datatype.Time
+
+	// Match is the other user of the pair when listing the matches of a user
+	Match *User
+}
+
+// OtherUID returns the uid of the pair that is not userUID.
+func (m *MutualMatch) OtherUID(userUID string) string {
+	if m.UserUID == userUID {
+		return m.MatchUID
+	}
+	return m.UserUID
+}
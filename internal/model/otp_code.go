@@ -0,0 +1,20 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+type OTPCode struct {
+	UID         string
+	Purpose     string
+	UserUID     *string
+	PhoneNumber string
+	CodeHash    string
+	Attempts    int
+	ExpiresAt   datatype.Time
+	ConsumedAt  datatype.Time
+	CreatedAt   datatype.Time
+}
+
+func (o *OTPCode) IsExpired() bool {
+	now := datatype.NewTimeNow()
+	return o.ExpiresAt.IsBefore(now)
+}
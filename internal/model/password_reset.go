@@ -0,0 +1,16 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+type PasswordReset struct {
+	UID       string
+	UserUID   string
+	TokenHash string
+	ExpiresAt datatype.Time
+	UsedAt    datatype.Time
+}
+
+func (p *PasswordReset) IsExpired() bool {
+	now := datatype.NewTimeNow()
+	return p.ExpiresAt.IsBefore(now)
+}
@@ -1,11 +1,13 @@
 package model
 
 type PremiumConfig struct {
-	UID         string `json:"uid"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Price       int64  `json:"price"`
-	Quota       int64  `json:"quota"`
-	ExpiredDay  int64  `json:"expired_day"`
-	IsActive    bool   `json:"is_active"`
+	UID            string `json:"uid"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	Price          int64  `json:"price"`
+	Quota          int64  `json:"quota"`
+	ExpiredDay     int64  `json:"expired_day"`
+	SuperLikeQuota int64  `json:"super_like_quota"`
+	RewindQuota    int64  `json:"rewind_quota"`
+	IsActive       bool   `json:"is_active"`
 }
@@ -0,0 +1,18 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+type RefreshToken struct {
+	UID       string
+	UserUID   string
+	FamilyUID string
+	TokenHash string
+	ExpiresAt datatype.Time
+	RotatedAt datatype.Time
+	RevokedAt datatype.Time
+}
+
+func (r *RefreshToken) IsExpired() bool {
+	now := datatype.NewTimeNow()
+	return r.ExpiresAt.IsBefore(now)
+}
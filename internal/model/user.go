@@ -1,11 +1,93 @@
 package model
 
+import (
+	"date-apps-be/internal/constant"
+	"date-apps-be/pkg/datatype"
+	"time"
+)
+
 type User struct {
-	UID         string  `json:"uid"`
-	Name        string  `json:"name"`
-	Email       *string `json:"email,omitempty"`
-	PhoneNumber *string `json:"phone_number,omitempty"`
-	Password    string  `json:"-"`
+	UID             string        `json:"uid"`
+	Name            string        `json:"name"`
+	Email           *string       `json:"email,omitempty"`
+	EmailVerifiedAt datatype.Time `json:"-"`
+	PendingEmail    *string       `json:"pending_email,omitempty"` // replaces Email once it is verified
+	PhoneNumber     *string       `json:"phone_number,omitempty"`
+	PhoneVerifiedAt datatype.Time `json:"-"`
+	Password        string        `json:"-"` // empty for accounts created by social sign-in
+	LockedUntil     datatype.Time `json:"-"`
+	DeletedAt       datatype.Time `json:"-"`
+	Status          string        `json:"status,omitempty"`
+	StatusReason    *string       `json:"status_reason,omitempty"`
+	SuspendedUntil  datatype.Time `json:"-"`
+
+	// dating profile, only the owner sees the birthdate, other users see the age
+	Birthdate datatype.Date       `json:"birthdate" swaggertype:"string" format:"date"`
+	Gender    *constant.Gender    `json:"gender,omitempty"`
+	Bio       *string             `json:"bio,omitempty"`
+	JobTitle  *string             `json:"job_title,omitempty"`
+	Company   *string             `json:"company,omitempty"`
+	Education *string             `json:"education,omitempty"`
+	HeightCM  *int                `json:"height_cm,omitempty"`
+	Interests datatype.StringList `json:"interests"`
+	Photos    []*UserPhoto        `json:"photos,omitempty"`
+	// distance to the user viewing the profile rounded up to a bucket, set by the discovery deck
+	DistanceKM *int `json:"distance_km,omitempty"`
+	// the user super-liked the user viewing the profile, set by the discovery deck
+	SuperLiked bool `json:"super_liked,omitempty"`
+
+	IsPremium bool  `json:"is_premium"`
+	Roles     Roles `json:"roles,omitempty"`
+}
+
+// IsLocked reports whether the account is locked because of too many failed logins.
+func (u *User) IsLocked() bool {
+	now := datatype.NewTimeNow()
+	return u.LockedUntil.IsAfter(now)
+}
+
+// IsBanned reports whether the account was banned by trust and safety.
+func (u *User) IsBanned() bool {
+	return u.Status == constant.UserStatusBanned
+}
+
+// IsSuspended reports whether the account is suspended right now, a suspension ends on its own.
+func (u *User) IsSuspended() bool {
+	now := datatype.NewTimeNow()
+	return u.Status == constant.UserStatusSuspended && u.SuspendedUntil.IsAfter(now)
+}
+
+// IsEmailVerified reports whether the user confirmed the current email address.
+func (u *User) IsEmailVerified() bool {
+	return !u.EmailVerifiedAt.IsNil()
+}
+
+// IsPhoneVerified reports whether the user confirmed the current phone number.
+func (u *User) IsPhoneVerified() bool {
+	return !u.PhoneVerifiedAt.IsNil()
+}
+
+// HasPassword reports whether the user can login with a password,
+// accounts created by social sign-in have none until they reset it.
+func (u *User) HasPassword() bool {
+	return u.Password != ""
+}
+
+// IsDeleted reports whether the user deleted the account, it can be restored until it is purged.
+func (u *User) IsDeleted() bool {
+	return !u.DeletedAt.IsNil()
+}
+
+// Age returns the age in full years at now, it is 0 when the birthdate is not set.
+func (u *User) Age(now time.Time) int {
+	birthdate := u.Birthdate.Time()
+	if birthdate == nil {
+		return 0
+	}
 
-	IsPremium bool `json:"is_premium"`
+	age := now.Year() - birthdate.Year()
+	if now.Month() < birthdate.Month() || (now.Month() == birthdate.Month() && now.Day() < birthdate.Day()) {
+		age--
+	}
+	return age
 }
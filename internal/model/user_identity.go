@@ -0,0 +1,13 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+// UserIdentity links a user to an account of an external identity provider.
+type UserIdentity struct {
+	UID       string
+	UserUID   string
+	Provider  string
+	Subject   string
+	Email     *string
+	CreatedAt datatype.Time
+}
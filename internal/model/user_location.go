@@ -0,0 +1,12 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+// UserLocation is the last location reported by a user, it is never shown to other users.
+type UserLocation struct {
+	UserUID   string        `json:"-"`
+	Latitude  float64       `json:"latitude"`
+	Longitude float64       `json:"longitude"`
+	Geohash   string        `json:"-"`
+	UpdatedAt datatype.Time `json:"updated_at"`
+}
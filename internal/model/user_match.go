@@ -6,6 +6,7 @@ import (
 )
 
 type UserMatch struct {
+	ID        int64 // only set when the swipe is about to be rewound
 	UserUID   string
 	MatchUID  string
 	MatchType constant.UserMatchType
@@ -14,3 +15,13 @@ type UserMatch struct {
 	User  User
 	Match User
 }
+
+// IsLike reports whether the swipe counts towards a mutual match.
+func (m *UserMatch) IsLike() bool {
+	return m.MatchType == constant.UserMatchTypeLike || m.IsSuperLike()
+}
+
+// IsSuperLike reports whether the swipe is a super-like, it has its own daily quota.
+func (m *UserMatch) IsSuperLike() bool {
+	return m.MatchType == constant.UserMatchTypeSuperLike
+}
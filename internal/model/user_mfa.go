@@ -0,0 +1,14 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+type UserMFA struct {
+	UserUID      string
+	Secret       string // encrypted TOTP secret
+	EnabledAt    datatype.Time
+	LastUsedStep int64
+}
+
+func (m *UserMFA) IsEnabled() bool {
+	return !m.EnabledAt.IsNil()
+}
@@ -0,0 +1,21 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+// UserPhoto is a profile photo, the original and the thumbnail are kept in the blob store.
+type UserPhoto struct {
+	UID          string        `json:"id"`
+	UserUID      string        `json:"-"`
+	Position     int           `json:"position"`
+	IsPrimary    bool          `json:"is_primary"`
+	ContentType  string        `json:"content_type"`
+	BlobKey      string        `json:"-"`
+	ThumbnailKey string        `json:"-"`
+	Width        int           `json:"width"`
+	Height       int           `json:"height"`
+	Size         int64         `json:"size"`
+	CreatedAt    datatype.Time `json:"created_at"`
+
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+// UserPreference filters the discovery deck of a user, a nil or empty field does not filter.
+type UserPreference struct {
+	UserUID       string              `json:"-"`
+	MinAge        *int                `json:"min_age"`
+	MaxAge        *int                `json:"max_age"`
+	Genders       datatype.StringList `json:"genders"`
+	MaxDistanceKM *int                `json:"max_distance_km"` // applies once both users have reported a location
+	UpdatedAt     datatype.Time       `json:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"date-apps-be/internal/constant"
+	"date-apps-be/pkg/datatype"
+)
+
+// UserRewind is a swipe the user undid, it counts towards the daily rewind quota.
+type UserRewind struct {
+	UserUID   string
+	MatchUID  string
+	MatchType constant.UserMatchType
+	SwipedAt  datatype.Time
+	CreatedAt datatype.Time
+}
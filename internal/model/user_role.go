@@ -0,0 +1,24 @@
+package model
+
+import (
+	"date-apps-be/internal/constant"
+	"date-apps-be/pkg/util"
+)
+
+// Roles are the roles of a user as embedded in the access token.
+type Roles []string
+
+// Has reports whether role is one of the roles, every user has the user role.
+func (r Roles) Has(role string) bool {
+	return role == constant.RoleUser || util.StringInSlice(r, role)
+}
+
+// Can reports whether any of the roles grants the permission.
+func (r Roles) Can(permission string) bool {
+	for _, role := range append(Roles{constant.RoleUser}, r...) {
+		if util.StringInSlice(constant.RolePermissions[role], permission) {
+			return true
+		}
+	}
+	return false
+}
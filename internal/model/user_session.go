@@ -0,0 +1,25 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+// UserSession is a login on one device, it lives as long as its refresh token family.
+type UserSession struct {
+	UID        string        `json:"id"`
+	UserUID    string        `json:"-"`
+	DeviceID   *string       `json:"device_id,omitempty"`
+	UserAgent  *string       `json:"user_agent,omitempty"`
+	IPAddress  string        `json:"ip_address"`
+	LastSeenAt datatype.Time `json:"last_seen_at"`
+	ExpiresAt  datatype.Time `json:"expires_at"`
+	RevokedAt  datatype.Time `json:"-"`
+	CreatedAt  datatype.Time `json:"created_at"`
+	// Current is set when listing sessions for the session of the access token making the request
+	Current bool `json:"current"`
+}
+
+// Device describes where a login comes from, the ID is sent by the app in the x-device-id header.
+type Device struct {
+	ID        string
+	UserAgent string
+	IPAddress string
+}
@@ -0,0 +1,15 @@
+package model
+
+import "date-apps-be/pkg/datatype"
+
+// UserStatusAudit records a status change of a user made by staff.
+type UserStatusAudit struct {
+	UID            string        `json:"id"`
+	UserUID        string        `json:"user_uid"`
+	ActorUID       string        `json:"actor_uid"`
+	PreviousStatus string        `json:"previous_status"`
+	Status         string        `json:"status"`
+	Reason         *string       `json:"reason,omitempty"`
+	SuspendedUntil datatype.Time `json:"suspended_until"`
+	CreatedAt      datatype.Time `json:"created_at"`
+}
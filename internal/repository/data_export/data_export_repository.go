@@ -0,0 +1,282 @@
+package dataexportrepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"time"
+)
+
+type (
+	dataExportRepository struct {
+		repository.Repository
+	}
+
+	// DataExportRepository stores the personal data exports requested by users.
+	DataExportRepository interface {
+		repository.Repository
+		CreateDataExport(ctx context.Context, tx *sql.Tx, export *model.DataExport) (err error)
+		GetDataExport(ctx context.Context, uid string) (export *model.DataExport, err error)
+		GetActiveDataExport(ctx context.Context, userUID string) (export *model.DataExport, err error)
+		CountDataExports(ctx context.Context, userUID string, window time.Duration) (total int, err error)
+		GetPendingDataExports(ctx context.Context, limit uint64) (exports []*model.DataExport, err error)
+		ClaimDataExport(ctx context.Context, uid string) (claimed bool, err error)
+		CompleteDataExport(ctx context.Context, uid, blobKey string, size int64, expiresAt datatype.Time) (err error)
+		FailDataExport(ctx context.Context, uid, reason string) (err error)
+		GetExpiredDataExports(ctx context.Context, limit uint64) (exports []*model.DataExport, err error)
+		ExpireDataExport(ctx context.Context, uid string) (err error)
+	}
+)
+
+const dataExportColumns = `uid, user_uid, status, blob_key, COALESCE(size, 0), failure_reason, completed_at, expires_at, created_at`
+
+func NewDataExportRepository(store repository.Repository) DataExportRepository {
+	return &dataExportRepository{
+		Repository: store,
+	}
+}
+
+func (r *dataExportRepository) getDest(export *model.DataExport) []interface{} {
+	return []interface{}{
+		&export.UID,
+		&export.UserUID,
+		&export.Status,
+		&export.BlobKey,
+		&export.Size,
+		&export.FailureReason,
+		&export.CompletedAt,
+		&export.ExpiresAt,
+		&export.CreatedAt,
+	}
+}
+
+func (r *dataExportRepository) CreateDataExport(ctx context.Context, tx *sql.Tx, export *model.DataExport) (err error) {
+	defer derrors.Wrap(&err, "CreateDataExport(%q)", export.UID)
+
+	query := `INSERT INTO data_exports (uid, user_uid, status) VALUES (?, ?, ?)`
+	args := []interface{}{
+		export.UID,
+		export.UserUID,
+		export.Status,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *dataExportRepository) GetDataExport(ctx context.Context, uid string) (export *model.DataExport, err error) {
+	defer derrors.Wrap(&err, "GetDataExport(%q)", uid)
+
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE uid = ?`
+	export = &model.DataExport{}
+	args := []interface{}{
+		uid,
+	}
+
+	err = r.Query(ctx, query, r.getDest(export), args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return export, nil
+}
+
+// GetActiveDataExport returns the latest export of the user that is not finished yet.
+func (r *dataExportRepository) GetActiveDataExport(ctx context.Context, userUID string) (export *model.DataExport, err error) {
+	defer derrors.Wrap(&err, "GetActiveDataExport(%q)", userUID)
+
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports
+			WHERE user_uid = ? AND status IN (?, ?)
+			ORDER BY created_at DESC, id DESC
+			LIMIT 1`
+	export = &model.DataExport{}
+	args := []interface{}{
+		userUID,
+		constant.DataExportStatusPending,
+		constant.DataExportStatusProcessing,
+	}
+
+	err = r.Query(ctx, query, r.getDest(export), args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return export, nil
+}
+
+// CountDataExports counts the exports the user requested inside the window.
+func (r *dataExportRepository) CountDataExports(ctx context.Context, userUID string, window time.Duration) (total int, err error) {
+	defer derrors.Wrap(&err, "CountDataExports(%q)", userUID)
+
+	query := `SELECT COUNT(*) FROM data_exports WHERE user_uid = ? AND created_at >= NOW() - INTERVAL ? SECOND`
+
+	err = r.Master().QueryRowContext(ctx, query, userUID, int64(window.Seconds())).Scan(&total)
+	if err != nil {
+		return 0, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return total, nil
+}
+
+// GetPendingDataExports returns the exports waiting to be generated, oldest first,
+// including the ones stuck in processing for longer than DataExportProcessingTimeout.
+// Exports of deleted accounts are skipped, they are removed with the account.
+func (r *dataExportRepository) GetPendingDataExports(ctx context.Context, limit uint64) (exports []*model.DataExport, err error) {
+	defer derrors.Wrap(&err, "GetPendingDataExports")
+
+	query := `SELECT de.uid, de.user_uid, de.status, de.blob_key, COALESCE(de.size, 0), de.failure_reason, de.completed_at, de.expires_at, de.created_at
+			FROM data_exports de
+			JOIN users u ON u.uid = de.user_uid AND u.deleted_at IS NULL
+			WHERE de.status = ? OR (de.status = ? AND de.started_at < NOW() - INTERVAL ? SECOND)
+			ORDER BY de.created_at, de.id
+			LIMIT ?`
+	args := []interface{}{
+		constant.DataExportStatusPending,
+		constant.DataExportStatusProcessing,
+		int64(constant.DataExportProcessingTimeout.Seconds()),
+		limit,
+	}
+
+	return r.getDataExports(ctx, query, args)
+}
+
+// ClaimDataExport marks the export as processing, claimed is false when another
+// instance claimed it first.
+func (r *dataExportRepository) ClaimDataExport(ctx context.Context, uid string) (claimed bool, err error) {
+	defer derrors.Wrap(&err, "ClaimDataExport(%q)", uid)
+
+	query := `UPDATE data_exports SET status = ?, started_at = NOW()
+			WHERE uid = ? AND (status = ? OR (status = ? AND started_at < NOW() - INTERVAL ? SECOND))`
+	args := []interface{}{
+		constant.DataExportStatusProcessing,
+		uid,
+		constant.DataExportStatusPending,
+		constant.DataExportStatusProcessing,
+		int64(constant.DataExportProcessingTimeout.Seconds()),
+	}
+
+	result, err := r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "RowsAffected")
+	}
+
+	return affected > 0, nil
+}
+
+func (r *dataExportRepository) CompleteDataExport(ctx context.Context, uid, blobKey string, size int64, expiresAt datatype.Time) (err error) {
+	defer derrors.Wrap(&err, "CompleteDataExport(%q)", uid)
+
+	query := `UPDATE data_exports SET status = ?, blob_key = ?, size = ?, completed_at = NOW(), expires_at = ? WHERE uid = ?`
+	args := []interface{}{
+		constant.DataExportStatusCompleted,
+		blobKey,
+		size,
+		&expiresAt,
+		uid,
+	}
+
+	_, err = r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *dataExportRepository) FailDataExport(ctx context.Context, uid, reason string) (err error) {
+	defer derrors.Wrap(&err, "FailDataExport(%q)", uid)
+
+	query := `UPDATE data_exports SET status = ?, failure_reason = ? WHERE uid = ?`
+	args := []interface{}{
+		constant.DataExportStatusFailed,
+		reason,
+		uid,
+	}
+
+	_, err = r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// GetExpiredDataExports returns the completed exports whose archive has to be deleted,
+// because it expired or because the account was deleted.
+func (r *dataExportRepository) GetExpiredDataExports(ctx context.Context, limit uint64) (exports []*model.DataExport, err error) {
+	defer derrors.Wrap(&err, "GetExpiredDataExports")
+
+	query := `SELECT de.uid, de.user_uid, de.status, de.blob_key, COALESCE(de.size, 0), de.failure_reason, de.completed_at, de.expires_at, de.created_at
+			FROM data_exports de
+			JOIN users u ON u.uid = de.user_uid
+			WHERE de.status = ? AND (de.expires_at <= NOW() OR u.deleted_at IS NOT NULL)
+			ORDER BY de.expires_at
+			LIMIT ?`
+	args := []interface{}{
+		constant.DataExportStatusCompleted,
+		limit,
+	}
+
+	return r.getDataExports(ctx, query, args)
+}
+
+// ExpireDataExport marks the export as expired once its archive was deleted.
+func (r *dataExportRepository) ExpireDataExport(ctx context.Context, uid string) (err error) {
+	defer derrors.Wrap(&err, "ExpireDataExport(%q)", uid)
+
+	query := `UPDATE data_exports SET status = ?, blob_key = NULL WHERE uid = ?`
+	args := []interface{}{
+		constant.DataExportStatusExpired,
+		uid,
+	}
+
+	_, err = r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *dataExportRepository) getDataExports(ctx context.Context, query string, args []interface{}) (exports []*model.DataExport, err error) {
+	exports = []*model.DataExport{}
+
+	rows, err := r.Master().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		export := &model.DataExport{}
+		if err = rows.Scan(r.getDest(export)...); err != nil {
+			return nil, derrors.HandleSQLError(err, "Scan")
+		}
+		exports = append(exports, export)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, derrors.HandleSQLError(err, "rows.Err")
+	}
+
+	return exports, nil
+}
@@ -0,0 +1,61 @@
+package hiddenpairrepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	hiddenPairRepository struct {
+		repository.Repository
+	}
+
+	// HiddenPairRepository stores the pairs of users who never see each other again.
+	HiddenPairRepository interface {
+		repository.Repository
+		CreateHiddenPair(ctx context.Context, tx *sql.Tx, hiddenPair *model.HiddenPair) (err error)
+		IsPairHidden(ctx context.Context, tx *sql.Tx, userUID, matchUID string) (hidden bool, err error)
+	}
+)
+
+func NewHiddenPairRepository(store repository.Repository) HiddenPairRepository {
+	return &hiddenPairRepository{
+		Repository: store,
+	}
+}
+
+// CreateHiddenPair stores the pair in the order of their uids, a pair that is hidden already is left as it is.
+func (r *hiddenPairRepository) CreateHiddenPair(ctx context.Context, tx *sql.Tx, hiddenPair *model.HiddenPair) (err error) {
+	defer derrors.Wrap(&err, "CreateHiddenPair(%q, %q)", hiddenPair.UserUID, hiddenPair.MatchUID)
+
+	query := `INSERT IGNORE INTO hidden_pairs (user_uid, match_uid, hidden_by) VALUES (LEAST(?, ?), GREATEST(?, ?), ?)`
+	args := []interface{}{
+		hiddenPair.UserUID, hiddenPair.MatchUID,
+		hiddenPair.UserUID, hiddenPair.MatchUID,
+		hiddenPair.HiddenBy,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.HandleSQLError(err, "r.Exec")
+	}
+
+	return nil
+}
+
+// IsPairHidden reports whether the pair is hidden, the order of the uids does not matter.
+func (r *hiddenPairRepository) IsPairHidden(ctx context.Context, tx *sql.Tx, userUID, matchUID string) (hidden bool, err error) {
+	defer derrors.Wrap(&err, "IsPairHidden(%q, %q)", userUID, matchUID)
+
+	query := `SELECT EXISTS (SELECT 1 FROM hidden_pairs WHERE user_uid = LEAST(?, ?) AND match_uid = GREATEST(?, ?))`
+
+	err = tx.QueryRowContext(ctx, query, userUID, matchUID, userUID, matchUID).Scan(&hidden)
+	if err != nil {
+		return false, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return hidden, nil
+}
@@ -0,0 +1,126 @@
+package loginhistoryrepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+	"time"
+)
+
+type (
+	loginHistoryRepository struct {
+		repository.Repository
+	}
+
+	LoginHistoryRepository interface {
+		repository.Repository
+		CreateLoginHistory(ctx context.Context, tx *sql.Tx, loginHistory *model.LoginHistory) (err error)
+		GetLoginHistories(ctx context.Context, userUID string, page, limit uint64) (loginHistories []*model.LoginHistory, err error)
+		CountFailedLoginByUserUID(ctx context.Context, userUID string, window time.Duration) (total int, err error)
+		CountFailedLoginByIPAddress(ctx context.Context, ipAddress string, window time.Duration) (total int, err error)
+	}
+)
+
+func NewLoginHistoryRepository(store repository.Repository) LoginHistoryRepository {
+	return &loginHistoryRepository{
+		Repository: store,
+	}
+}
+
+func (r *loginHistoryRepository) getDest(loginHistory *model.LoginHistory) []interface{} {
+	return []interface{}{
+		&loginHistory.UID,
+		&loginHistory.UserUID,
+		&loginHistory.Identifier,
+		&loginHistory.IPAddress,
+		&loginHistory.UserAgent,
+		&loginHistory.IsSuccess,
+		&loginHistory.FailureReason,
+		&loginHistory.CreatedAt,
+	}
+}
+
+func (r *loginHistoryRepository) CreateLoginHistory(ctx context.Context, tx *sql.Tx, loginHistory *model.LoginHistory) (err error) {
+	defer derrors.Wrap(&err, "CreateLoginHistory(%q)", loginHistory.Identifier)
+
+	query := `INSERT INTO login_history (uid, user_uid, identifier, ip_address, user_agent, is_success, failure_reason) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	args := []interface{}{
+		loginHistory.UID,
+		r.NewNullString(loginHistory.UserUID),
+		loginHistory.Identifier,
+		loginHistory.IPAddress,
+		r.NewNullString(loginHistory.UserAgent),
+		loginHistory.IsSuccess,
+		r.NewNullString(loginHistory.FailureReason),
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *loginHistoryRepository) GetLoginHistories(ctx context.Context, userUID string, page, limit uint64) (loginHistories []*model.LoginHistory, err error) {
+	defer derrors.Wrap(&err, "GetLoginHistories(%q)", userUID)
+
+	query := `SELECT uid, user_uid, identifier, ip_address, user_agent, is_success, failure_reason, created_at
+			FROM login_history
+			WHERE user_uid = ?
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?,?`
+
+	loginHistories = []*model.LoginHistory{}
+
+	rows, err := r.Slave().QueryContext(ctx, query, userUID, r.GetOffset(page, limit), limit)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		loginHistory := &model.LoginHistory{}
+		if err := rows.Scan(r.getDest(loginHistory)...); err != nil {
+			return nil, err
+		}
+		loginHistories = append(loginHistories, loginHistory)
+	}
+
+	return loginHistories, nil
+}
+
+// CountFailedLoginByUserUID counts the failed logins of a user inside the window
+// that happened after the last successful login and after the last lock ended,
+// so failures that already locked the account do not lock it again.
+func (r *loginHistoryRepository) CountFailedLoginByUserUID(ctx context.Context, userUID string, window time.Duration) (total int, err error) {
+	defer derrors.Wrap(&err, "CountFailedLoginByUserUID(%q)", userUID)
+
+	query := `SELECT COUNT(*) FROM login_history
+			WHERE user_uid = ? AND is_success = FALSE AND created_at >= NOW() - INTERVAL ? SECOND
+			AND id > COALESCE((SELECT MAX(id) FROM login_history WHERE user_uid = ? AND is_success = TRUE), 0)
+			AND created_at >= COALESCE((SELECT locked_until FROM users WHERE uid = ?), created_at)`
+
+	err = r.Master().QueryRowContext(ctx, query, userUID, int64(window.Seconds()), userUID, userUID).Scan(&total)
+	if err != nil {
+		return 0, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return total, nil
+}
+
+func (r *loginHistoryRepository) CountFailedLoginByIPAddress(ctx context.Context, ipAddress string, window time.Duration) (total int, err error) {
+	defer derrors.Wrap(&err, "CountFailedLoginByIPAddress(%q)", ipAddress)
+
+	query := `SELECT COUNT(*) FROM login_history
+			WHERE ip_address = ? AND is_success = FALSE AND created_at >= NOW() - INTERVAL ? SECOND`
+
+	err = r.Master().QueryRowContext(ctx, query, ipAddress, int64(window.Seconds())).Scan(&total)
+	if err != nil {
+		return 0, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return total, nil
+}
@@ -0,0 +1,177 @@
+package mutualmatchrepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	mutualMatchRepository struct {
+		repository.Repository
+	}
+
+	// MutualMatchRepository stores the pairs of users who liked each other.
+	MutualMatchRepository interface {
+		repository.Repository
+		CreateMutualMatch(ctx context.Context, tx *sql.Tx, mutualMatch *model.MutualMatch) (err error)
+		GetMutualMatch(ctx context.Context, tx *sql.Tx, userUID, matchUID string) (mutualMatch *model.MutualMatch, err error)
+		DeleteMutualMatch(ctx context.Context, tx *sql.Tx, userUID, matchUID string) (deleted bool, err error)
+		GetMutualMatches(ctx context.Context, userUID string, page, limit uint64) (mutualMatches []*model.MutualMatch, err error)
+		GetMutualMatchHistory(ctx context.Context, userUID string, page, limit uint64) (mutualMatches []*model.MutualMatch, err error)
+	}
+)
+
+func NewMutualMatchRepository(store repository.Repository) MutualMatchRepository {
+	return &mutualMatchRepository{
+		Repository: store,
+	}
+}
+
+// pair orders the uids of a pair the way they are stored.
+func pair(userUID, matchUID string) (string, string) {
+	if userUID > matchUID {
+		return matchUID, userUID
+	}
+	return userUID, matchUID
+}
+
+// CreateMutualMatch stores the pair in the order of their uids, whoever liked last.
+func (r *mutualMatchRepository) CreateMutualMatch(ctx context.Context, tx *sql.Tx, mutualMatch *model.MutualMatch) (err error) {
+	defer derrors.Wrap(&err, "CreateMutualMatch(%q, %q)", mutualMatch.UserUID, mutualMatch.MatchUID)
+
+	mutualMatch.UserUID, mutualMatch.MatchUID = pair(mutualMatch.UserUID, mutualMatch.MatchUID)
+
+	query := `INSERT INTO mutual_matches (uid, user_uid, match_uid) VALUES (?, ?, ?)`
+	args := []interface{}{
+		mutualMatch.UID,
+		mutualMatch.UserUID,
+		mutualMatch.MatchUID,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.HandleSQLError(err, "r.Exec")
+	}
+
+	return nil
+}
+
+// GetMutualMatch returns the mutual match of the pair in either order, nil when they did not match.
+func (r *mutualMatchRepository) GetMutualMatch(ctx context.Context, tx *sql.Tx, userUID, matchUID string) (mutualMatch *model.MutualMatch, err error) {
+	defer derrors.Wrap(&err, "GetMutualMatch(%q, %q)", userUID, matchUID)
+
+	userUID, matchUID = pair(userUID, matchUID)
+	query := `SELECT uid, user_uid, match_uid, created_at FROM mutual_matches WHERE user_uid = ? AND match_uid = ?`
+
+	mutualMatch = &model.MutualMatch{}
+	err = tx.QueryRowContext(ctx, query, userUID, matchUID).
+		Scan(&mutualMatch.UID, &mutualMatch.UserUID, &mutualMatch.MatchUID, &mutualMatch.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return mutualMatch, nil
+}
+
+// DeleteMutualMatch removes the mutual match of the pair in either order, deleted is false when they did not match.
+func (r *mutualMatchRepository) DeleteMutualMatch(ctx context.Context, tx *sql.Tx, userUID, matchUID string) (deleted bool, err error) {
+	defer derrors.Wrap(&err, "DeleteMutualMatch(%q, %q)", userUID, matchUID)
+
+	userUID, matchUID = pair(userUID, matchUID)
+	query := `DELETE FROM mutual_matches WHERE user_uid = ? AND match_uid = ?`
+
+	result, err := r.Exec(ctx, tx, query, []interface{}{userUID, matchUID})
+	if err != nil {
+		return false, derrors.HandleSQLError(err, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "RowsAffected")
+	}
+
+	return affected > 0, nil
+}
+
+// GetMutualMatches returns the matches of the user with the public profile of the other user, newest first.
+// Matches with deleted, banned or suspended users are left out.
+func (r *mutualMatchRepository) GetMutualMatches(ctx context.Context, userUID string, page, limit uint64) (mutualMatches []*model.MutualMatch, err error) {
+	defer derrors.Wrap(&err, "GetMutualMatches(%q)", userUID)
+
+	query := `SELECT m.uid, m.user_uid, m.match_uid, m.created_at,
+				u.uid, u.name, u.birthdate, u.gender, u.bio, u.job_title, u.company, u.education, u.height_cm, u.interests
+			FROM mutual_matches m
+			JOIN users u ON u.uid = IF(m.user_uid = ?, m.match_uid, m.user_uid)
+			WHERE (m.user_uid = ? OR m.match_uid = ?) AND u.deleted_at IS NULL
+			AND u.status != ? AND (u.suspended_until IS NULL OR u.suspended_until <= NOW())
+			ORDER BY m.created_at DESC, m.id DESC
+			LIMIT ?,?`
+
+	args := []interface{}{
+		userUID,
+		userUID,
+		userUID,
+		constant.UserStatusBanned,
+		r.GetOffset(page, limit), limit,
+	}
+
+	rows, err := r.Slave().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	mutualMatches = []*model.MutualMatch{}
+	for rows.Next() {
+		mutualMatch := &model.MutualMatch{Match: &model.User{}}
+		user := mutualMatch.Match
+		err = rows.Scan(&mutualMatch.UID, &mutualMatch.UserUID, &mutualMatch.MatchUID, &mutualMatch.CreatedAt,
+			&user.UID, &user.Name, &user.Birthdate, &user.Gender, &user.Bio, &user.JobTitle, &user.Company, &user.Education, &user.HeightCM, &user.Interests)
+		if err != nil {
+			return nil, derrors.HandleSQLError(err, "Scan")
+		}
+		mutualMatches = append(mutualMatches, mutualMatch)
+	}
+
+	return mutualMatches, nil
+}
+
+// GetMutualMatchHistory returns every mutual match of the user including those with inactive users, oldest first.
+func (r *mutualMatchRepository) GetMutualMatchHistory(ctx context.Context, userUID string, page, limit uint64) (mutualMatches []*model.MutualMatch, err error) {
+	defer derrors.Wrap(&err, "GetMutualMatchHistory(%q)", userUID)
+
+	query := `SELECT uid, user_uid, match_uid, created_at FROM mutual_matches
+			WHERE user_uid = ? OR match_uid = ?
+			ORDER BY created_at, id
+			LIMIT ?,?`
+
+	args := []interface{}{
+		userUID,
+		userUID,
+		r.GetOffset(page, limit), limit,
+	}
+
+	rows, err := r.Slave().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	mutualMatches = []*model.MutualMatch{}
+	for rows.Next() {
+		mutualMatch := &model.MutualMatch{}
+		if err = rows.Scan(&mutualMatch.UID, &mutualMatch.UserUID, &mutualMatch.MatchUID, &mutualMatch.CreatedAt); err != nil {
+			return nil, derrors.HandleSQLError(err, "Scan")
+		}
+		mutualMatches = append(mutualMatches, mutualMatch)
+	}
+
+	return mutualMatches, nil
+}
@@ -0,0 +1,153 @@
+package otpcoderepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+	"time"
+)
+
+type (
+	otpCodeRepository struct {
+		repository.Repository
+	}
+
+	OTPCodeRepository interface {
+		repository.Repository
+		CreateOTPCode(ctx context.Context, tx *sql.Tx, otpCode *model.OTPCode) (err error)
+		GetLatestOTPCode(ctx context.Context, phoneNumber, purpose string) (otpCode *model.OTPCode, err error)
+		CountOTPCodes(ctx context.Context, phoneNumber, purpose string, window time.Duration) (total int, err error)
+		ClaimOTPAttempt(ctx context.Context, uid string, maxAttempts int) (claimed bool, err error)
+		ConsumeOTPCode(ctx context.Context, uid string) (consumed bool, err error)
+	}
+)
+
+func NewOTPCodeRepository(store repository.Repository) OTPCodeRepository {
+	return &otpCodeRepository{
+		Repository: store,
+	}
+}
+
+func (r *otpCodeRepository) getDest(otpCode *model.OTPCode) []interface{} {
+	return []interface{}{
+		&otpCode.UID,
+		&otpCode.Purpose,
+		&otpCode.UserUID,
+		&otpCode.PhoneNumber,
+		&otpCode.CodeHash,
+		&otpCode.Attempts,
+		&otpCode.ExpiresAt,
+		&otpCode.ConsumedAt,
+		&otpCode.CreatedAt,
+	}
+}
+
+func (r *otpCodeRepository) CreateOTPCode(ctx context.Context, tx *sql.Tx, otpCode *model.OTPCode) (err error) {
+	defer derrors.Wrap(&err, "CreateOTPCode(%q)", otpCode.UID)
+
+	query := `INSERT INTO otp_codes (uid, purpose, user_uid, phone_number, code_hash, expires_at) VALUES (?, ?, ?, ?, ?, ?)`
+	args := []interface{}{
+		otpCode.UID,
+		otpCode.Purpose,
+		r.NewNullString(otpCode.UserUID),
+		otpCode.PhoneNumber,
+		otpCode.CodeHash,
+		&otpCode.ExpiresAt,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// GetLatestOTPCode returns the last code sent to the phone number that was not consumed yet.
+func (r *otpCodeRepository) GetLatestOTPCode(ctx context.Context, phoneNumber, purpose string) (otpCode *model.OTPCode, err error) {
+	defer derrors.Wrap(&err, "GetLatestOTPCode(%q, %q)", phoneNumber, purpose)
+
+	query := `SELECT uid, purpose, user_uid, phone_number, code_hash, attempts, expires_at, consumed_at, created_at
+			FROM otp_codes
+			WHERE phone_number = ? AND purpose = ? AND consumed_at IS NULL
+			ORDER BY id DESC
+			LIMIT 1`
+
+	otpCode = &model.OTPCode{}
+	args := []interface{}{
+		phoneNumber,
+		purpose,
+	}
+
+	err = r.Query(ctx, query, r.getDest(otpCode), args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return otpCode, nil
+}
+
+func (r *otpCodeRepository) CountOTPCodes(ctx context.Context, phoneNumber, purpose string, window time.Duration) (total int, err error) {
+	defer derrors.Wrap(&err, "CountOTPCodes(%q, %q)", phoneNumber, purpose)
+
+	query := `SELECT COUNT(*) FROM otp_codes
+			WHERE phone_number = ? AND purpose = ? AND created_at >= NOW() - INTERVAL ? SECOND`
+
+	err = r.Master().QueryRowContext(ctx, query, phoneNumber, purpose, int64(window.Seconds())).Scan(&total)
+	if err != nil {
+		return 0, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return total, nil
+}
+
+// ClaimOTPAttempt counts an attempt on the code before it is checked,
+// claimed is false when the code has no attempts left.
+func (r *otpCodeRepository) ClaimOTPAttempt(ctx context.Context, uid string, maxAttempts int) (claimed bool, err error) {
+	defer derrors.Wrap(&err, "ClaimOTPAttempt(%q, %d)", uid, maxAttempts)
+
+	query := `UPDATE otp_codes SET attempts = attempts + 1 WHERE uid = ? AND attempts < ?`
+	args := []interface{}{
+		uid,
+		maxAttempts,
+	}
+
+	result, err := r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+	}
+
+	return affected > 0, nil
+}
+
+// ConsumeOTPCode marks the code as used, consumed is false when it was already used.
+func (r *otpCodeRepository) ConsumeOTPCode(ctx context.Context, uid string) (consumed bool, err error) {
+	defer derrors.Wrap(&err, "ConsumeOTPCode(%q)", uid)
+
+	query := `UPDATE otp_codes SET consumed_at = NOW() WHERE uid = ? AND consumed_at IS NULL`
+	args := []interface{}{
+		uid,
+	}
+
+	result, err := r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,133 @@
+package passwordresetrepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+	"time"
+)
+
+type (
+	passwordResetRepository struct {
+		repository.Repository
+	}
+
+	PasswordResetRepository interface {
+		repository.Repository
+		CreatePasswordReset(ctx context.Context, tx *sql.Tx, passwordReset *model.PasswordReset) (err error)
+		GetPasswordResetByHash(ctx context.Context, tokenHash string) (passwordReset *model.PasswordReset, err error)
+		CountPasswordResets(ctx context.Context, userUID string, window time.Duration) (total int, err error)
+		UsePasswordReset(ctx context.Context, tx *sql.Tx, uid string) (used bool, err error)
+		DeletePasswordResets(ctx context.Context, tx *sql.Tx, userUID string) (err error)
+	}
+)
+
+func NewPasswordResetRepository(store repository.Repository) PasswordResetRepository {
+	return &passwordResetRepository{
+		Repository: store,
+	}
+}
+
+func (r *passwordResetRepository) getDest(passwordReset *model.PasswordReset) []interface{} {
+	return []interface{}{
+		&passwordReset.UID,
+		&passwordReset.UserUID,
+		&passwordReset.TokenHash,
+		&passwordReset.ExpiresAt,
+		&passwordReset.UsedAt,
+	}
+}
+
+func (r *passwordResetRepository) CreatePasswordReset(ctx context.Context, tx *sql.Tx, passwordReset *model.PasswordReset) (err error) {
+	defer derrors.Wrap(&err, "CreatePasswordReset(%q)", passwordReset.UID)
+
+	query := `INSERT INTO password_resets (uid, user_uid, token_hash, expires_at) VALUES (?, ?, ?, ?)`
+	args := []interface{}{
+		passwordReset.UID,
+		passwordReset.UserUID,
+		passwordReset.TokenHash,
+		&passwordReset.ExpiresAt,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *passwordResetRepository) GetPasswordResetByHash(ctx context.Context, tokenHash string) (passwordReset *model.PasswordReset, err error) {
+	defer derrors.Wrap(&err, "GetPasswordResetByHash")
+
+	query := `SELECT uid, user_uid, token_hash, expires_at, used_at FROM password_resets WHERE token_hash = ?`
+
+	passwordReset = &model.PasswordReset{}
+	args := []interface{}{
+		tokenHash,
+	}
+
+	err = r.Query(ctx, query, r.getDest(passwordReset), args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return passwordReset, nil
+}
+
+func (r *passwordResetRepository) CountPasswordResets(ctx context.Context, userUID string, window time.Duration) (total int, err error) {
+	defer derrors.Wrap(&err, "CountPasswordResets(%q)", userUID)
+
+	query := `SELECT COUNT(*) FROM password_resets WHERE user_uid = ? AND created_at >= NOW() - INTERVAL ? SECOND`
+
+	err = r.Master().QueryRowContext(ctx, query, userUID, int64(window.Seconds())).Scan(&total)
+	if err != nil {
+		return 0, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return total, nil
+}
+
+// UsePasswordReset marks the reset token as used, used is false when it was already used.
+func (r *passwordResetRepository) UsePasswordReset(ctx context.Context, tx *sql.Tx, uid string) (used bool, err error) {
+	defer derrors.Wrap(&err, "UsePasswordReset(%q)", uid)
+
+	query := `UPDATE password_resets SET used_at = NOW() WHERE uid = ? AND used_at IS NULL`
+	args := []interface{}{
+		uid,
+	}
+
+	result, err := r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+	}
+
+	return affected > 0, nil
+}
+
+// DeletePasswordResets deletes every reset token of the user, used or not.
+func (r *passwordResetRepository) DeletePasswordResets(ctx context.Context, tx *sql.Tx, userUID string) (err error) {
+	defer derrors.Wrap(&err, "DeletePasswordResets(%q)", userUID)
+
+	query := `DELETE FROM password_resets WHERE user_uid = ?`
+	args := []interface{}{
+		userUID,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
@@ -31,6 +31,8 @@ func (p *premiumConfigRepository) getDest(premiumConfig *model.PremiumConfig) []
 		&premiumConfig.Price,
 		&premiumConfig.Quota,
 		&premiumConfig.ExpiredDay,
+		&premiumConfig.SuperLikeQuota,
+		&premiumConfig.RewindQuota,
 		&premiumConfig.IsActive,
 	}
 }
@@ -38,7 +40,7 @@ func (p *premiumConfigRepository) getDest(premiumConfig *model.PremiumConfig) []
 func (p *premiumConfigRepository) GetPremiumConfigs(ctx context.Context, page, limit uint64) (configs []*model.PremiumConfig, err error) {
 	defer derrors.Wrap(&err, "GetPremiumConfigs")
 
-	query := `SELECT uid, name, description, price, quota, expired_day, is_active FROM premium_config LIMIT ?,?`
+	query := `SELECT uid, name, description, price, quota, expired_day, super_like_quota, rewind_quota, is_active FROM premium_config LIMIT ?,?`
 
 	configs = []*model.PremiumConfig{}
 
@@ -62,7 +64,7 @@ func (p *premiumConfigRepository) GetPremiumConfigs(ctx context.Context, page, l
 func (p *premiumConfigRepository) GetPremiumConfigByUID(ctx context.Context, uid string) (config *model.PremiumConfig, err error) {
 	defer derrors.Wrap(&err, "GetPremiumConfigByUID(%q)", uid)
 
-	query := `SELECT uid, name, description, price, quota, expired_day, is_active FROM premium_config WHERE uid = ?`
+	query := `SELECT uid, name, description, price, quota, expired_day, super_like_quota, rewind_quota, is_active FROM premium_config WHERE uid = ?`
 
 	config = &model.PremiumConfig{}
 
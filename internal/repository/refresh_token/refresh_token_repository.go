@@ -0,0 +1,137 @@
+package refreshtokenrepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	refreshTokenRepository struct {
+		repository.Repository
+	}
+
+	RefreshTokenRepository interface {
+		repository.Repository
+		CreateRefreshToken(ctx context.Context, tx *sql.Tx, refreshToken *model.RefreshToken) (err error)
+		GetRefreshTokenByHash(ctx context.Context, tokenHash string) (refreshToken *model.RefreshToken, err error)
+		RotateRefreshToken(ctx context.Context, tx *sql.Tx, uid string) (rotated bool, err error)
+		RevokeRefreshTokenFamily(ctx context.Context, tx *sql.Tx, familyUID string) (err error)
+		RevokeUserRefreshTokens(ctx context.Context, tx *sql.Tx, userUID string) (err error)
+	}
+)
+
+func NewRefreshTokenRepository(store repository.Repository) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		Repository: store,
+	}
+}
+
+func (r *refreshTokenRepository) getDest(refreshToken *model.RefreshToken) []interface{} {
+	return []interface{}{
+		&refreshToken.UID,
+		&refreshToken.UserUID,
+		&refreshToken.FamilyUID,
+		&refreshToken.TokenHash,
+		&refreshToken.ExpiresAt,
+		&refreshToken.RotatedAt,
+		&refreshToken.RevokedAt,
+	}
+}
+
+func (r *refreshTokenRepository) CreateRefreshToken(ctx context.Context, tx *sql.Tx, refreshToken *model.RefreshToken) (err error) {
+	defer derrors.Wrap(&err, "CreateRefreshToken(%q)", refreshToken.UID)
+
+	query := `INSERT INTO refresh_tokens (uid, user_uid, family_uid, token_hash, expires_at) VALUES (?, ?, ?, ?, ?)`
+	args := []interface{}{
+		refreshToken.UID,
+		refreshToken.UserUID,
+		refreshToken.FamilyUID,
+		refreshToken.TokenHash,
+		&refreshToken.ExpiresAt,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (refreshToken *model.RefreshToken, err error) {
+	defer derrors.Wrap(&err, "GetRefreshTokenByHash")
+
+	query := `SELECT uid, user_uid, family_uid, token_hash, expires_at, rotated_at, revoked_at FROM refresh_tokens WHERE token_hash = ?`
+	refreshToken = &model.RefreshToken{}
+	args := []interface{}{
+		tokenHash,
+	}
+
+	err = r.Query(ctx, query, r.getDest(refreshToken), args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return refreshToken, nil
+}
+
+// RotateRefreshToken marks the token as used, rotated is false when the token
+// was already rotated or revoked, which means it is being reused.
+func (r *refreshTokenRepository) RotateRefreshToken(ctx context.Context, tx *sql.Tx, uid string) (rotated bool, err error) {
+	defer derrors.Wrap(&err, "RotateRefreshToken(%q)", uid)
+
+	query := `UPDATE refresh_tokens SET rotated_at = NOW() WHERE uid = ? AND rotated_at IS NULL AND revoked_at IS NULL`
+	args := []interface{}{
+		uid,
+	}
+
+	result, err := r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+	}
+
+	return affected > 0, nil
+}
+
+func (r *refreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, tx *sql.Tx, familyUID string) (err error) {
+	defer derrors.Wrap(&err, "RevokeRefreshTokenFamily(%q)", familyUID)
+
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_uid = ? AND revoked_at IS NULL`
+	args := []interface{}{
+		familyUID,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, tx *sql.Tx, userUID string) (err error) {
+	defer derrors.Wrap(&err, "RevokeUserRefreshTokens(%q)", userUID)
+
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_uid = ? AND revoked_at IS NULL`
+	args := []interface{}{
+		userUID,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
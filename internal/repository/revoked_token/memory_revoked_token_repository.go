@@ -0,0 +1,100 @@
+package revokedtokenrepository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type (
+	// memoryRevokedTokenRepository keeps revoked tokens in process memory,
+	// it is only suitable for a single instance deployment and for tests.
+	memoryRevokedTokenRepository struct {
+		mu     sync.RWMutex
+		tokens map[string]time.Time
+		users  map[string]userRevocation
+	}
+
+	userRevocation struct {
+		revokedBefore time.Time
+		expiresAt     time.Time
+	}
+)
+
+func NewMemoryRevokedTokenRepository() RevokedTokenRepository {
+	return &memoryRevokedTokenRepository{
+		tokens: map[string]time.Time{},
+		users:  map[string]userRevocation{},
+	}
+}
+
+func (m *memoryRevokedTokenRepository) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[jti] = expiresAt
+	return nil
+}
+
+func (m *memoryRevokedTokenRepository) ConsumeToken(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.tokens[jti]; ok {
+		return false, nil
+	}
+
+	m.tokens[jti] = expiresAt
+	return true, nil
+}
+
+func (m *memoryRevokedTokenRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.tokens[jti]
+	return ok, nil
+}
+
+func (m *memoryRevokedTokenRepository) RevokeUserTokens(ctx context.Context, userUID string, revokedBefore, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.users[userUID] = userRevocation{
+		revokedBefore: revokedBefore.Truncate(time.Microsecond),
+		expiresAt:     expiresAt,
+	}
+	return nil
+}
+
+func (m *memoryRevokedTokenRepository) IsUserTokenRevoked(ctx context.Context, userUID string, issuedAt time.Time) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	revocation, ok := m.users[userUID]
+	if !ok {
+		return false, nil
+	}
+	return !issuedAt.Truncate(time.Microsecond).After(revocation.revokedBefore), nil
+}
+
+func (m *memoryRevokedTokenRepository) DeleteExpiredTokens(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var total int64
+	now := time.Now()
+	for jti, expiresAt := range m.tokens {
+		if expiresAt.Before(now) {
+			delete(m.tokens, jti)
+			total++
+		}
+	}
+	for userUID, revocation := range m.users {
+		if revocation.expiresAt.Before(now) {
+			delete(m.users, userUID)
+			total++
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,90 @@
+package revokedtokenrepository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	revokedtokenrepository "date-apps-be/internal/repository/revoked_token"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRevokedTokenRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := revokedtokenrepository.NewMemoryRevokedTokenRepository()
+
+	assert.NoError(t, repo.RevokeToken(ctx, "active", time.Now().Add(time.Hour)))
+	assert.NoError(t, repo.RevokeToken(ctx, "expired", time.Now().Add(-time.Minute)))
+
+	revoked, err := repo.IsTokenRevoked(ctx, "active")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	total, err := repo.DeleteExpiredTokens(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "expired")
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "active")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestMemoryRevokedTokenRepository_ConsumeToken(t *testing.T) {
+	ctx := context.Background()
+	repo := revokedtokenrepository.NewMemoryRevokedTokenRepository()
+
+	consumed, err := repo.ConsumeToken(ctx, "mfa", time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, consumed)
+
+	consumed, err = repo.ConsumeToken(ctx, "mfa", time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, consumed)
+
+	revoked, err := repo.IsTokenRevoked(ctx, "mfa")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestMemoryRevokedTokenRepository_UserTokens(t *testing.T) {
+	ctx := context.Background()
+	repo := revokedtokenrepository.NewMemoryRevokedTokenRepository()
+	now := time.Now()
+
+	assert.NoError(t, repo.RevokeUserTokens(ctx, "user-1", now, now.Add(time.Hour)))
+	assert.NoError(t, repo.RevokeUserTokens(ctx, "user-2", now, now.Add(-time.Minute)))
+
+	revoked, err := repo.IsUserTokenRevoked(ctx, "user-1", now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsUserTokenRevoked(ctx, "user-1", now)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	// a login right after the revocation is accepted
+	revoked, err = repo.IsUserTokenRevoked(ctx, "user-1", now.Add(time.Microsecond))
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = repo.IsUserTokenRevoked(ctx, "user-3", now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	total, err := repo.DeleteExpiredTokens(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	revoked, err = repo.IsUserTokenRevoked(ctx, "user-2", now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
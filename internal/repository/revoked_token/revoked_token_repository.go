@@ -0,0 +1,160 @@
+package revokedtokenrepository
+
+import (
+	"context"
+	"date-apps-be/internal/constant"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"time"
+)
+
+// cutoffLayout keeps the microseconds of the cutoffs, the iat claim has the same precision.
+const cutoffLayout = "2006-01-02 15:04:05.000000"
+
+type (
+	// RevokedTokenRepository stores the jti of access tokens that must not be accepted anymore,
+	// and per user cutoffs that reject every access token issued before them.
+	// Entries are kept until the tokens would have expired anyway.
+	RevokedTokenRepository interface {
+		RevokeToken(ctx context.Context, jti string, expiresAt time.Time) (err error)
+		ConsumeToken(ctx context.Context, jti string, expiresAt time.Time) (consumed bool, err error)
+		IsTokenRevoked(ctx context.Context, jti string) (revoked bool, err error)
+		RevokeUserTokens(ctx context.Context, userUID string, revokedBefore, expiresAt time.Time) (err error)
+		IsUserTokenRevoked(ctx context.Context, userUID string, issuedAt time.Time) (revoked bool, err error)
+		DeleteExpiredTokens(ctx context.Context) (total int64, err error)
+	}
+
+	revokedTokenRepository struct {
+		repository.Repository
+	}
+)
+
+// NewRevokedTokenRepository returns the revocation store configured by storeType.
+func NewRevokedTokenRepository(storeType string, store repository.Repository) RevokedTokenRepository {
+	if storeType == constant.TokenRevocationStoreMemory {
+		return NewMemoryRevokedTokenRepository()
+	}
+
+	return NewMySQLRevokedTokenRepository(store)
+}
+
+func NewMySQLRevokedTokenRepository(store repository.Repository) RevokedTokenRepository {
+	return &revokedTokenRepository{
+		Repository: store,
+	}
+}
+
+func (r *revokedTokenRepository) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) (err error) {
+	defer derrors.Wrap(&err, "RevokeToken(%q)", jti)
+
+	query := `INSERT IGNORE INTO revoked_tokens (jti, expires_at) VALUES (?, ?)`
+	expiredTime := datatype.NewTime(&expiresAt)
+	args := []interface{}{
+		jti,
+		&expiredTime,
+	}
+
+	_, err = r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// ConsumeToken revokes a single use token, consumed is false when it was revoked already.
+func (r *revokedTokenRepository) ConsumeToken(ctx context.Context, jti string, expiresAt time.Time) (consumed bool, err error) {
+	defer derrors.Wrap(&err, "ConsumeToken(%q)", jti)
+
+	query := `INSERT IGNORE INTO revoked_tokens (jti, expires_at) VALUES (?, ?)`
+	expiredTime := datatype.NewTime(&expiresAt)
+	args := []interface{}{
+		jti,
+		&expiredTime,
+	}
+
+	result, err := r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+	}
+
+	return affected > 0, nil
+}
+
+func (r *revokedTokenRepository) IsTokenRevoked(ctx context.Context, jti string) (revoked bool, err error) {
+	defer derrors.Wrap(&err, "IsTokenRevoked(%q)", jti)
+
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = ?)`
+
+	err = r.Query(ctx, query, []interface{}{&revoked}, []interface{}{jti})
+	if err != nil {
+		return false, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return revoked, nil
+}
+
+// RevokeUserTokens rejects every access token of the user issued up to revokedBefore,
+// tokens issued even a microsecond later are accepted.
+func (r *revokedTokenRepository) RevokeUserTokens(ctx context.Context, userUID string, revokedBefore, expiresAt time.Time) (err error) {
+	defer derrors.Wrap(&err, "RevokeUserTokens(%q)", userUID)
+
+	query := `INSERT INTO user_token_revocations (user_uid, revoked_before, expires_at) VALUES (?, ?, ?)
+			ON DUPLICATE KEY UPDATE revoked_before = VALUES(revoked_before), expires_at = VALUES(expires_at)`
+	expiredTime := datatype.NewTime(&expiresAt)
+	args := []interface{}{
+		userUID,
+		revokedBefore.Format(cutoffLayout),
+		&expiredTime,
+	}
+
+	_, err = r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// IsUserTokenRevoked compares in microseconds like the iat claim,
+// only tokens issued strictly after the cutoff are accepted.
+func (r *revokedTokenRepository) IsUserTokenRevoked(ctx context.Context, userUID string, issuedAt time.Time) (revoked bool, err error) {
+	defer derrors.Wrap(&err, "IsUserTokenRevoked(%q)", userUID)
+
+	query := `SELECT EXISTS(SELECT 1 FROM user_token_revocations WHERE user_uid = ? AND revoked_before >= ?)`
+
+	err = r.Query(ctx, query, []interface{}{&revoked}, []interface{}{userUID, issuedAt.Format(cutoffLayout)})
+	if err != nil {
+		return false, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return revoked, nil
+}
+
+func (r *revokedTokenRepository) DeleteExpiredTokens(ctx context.Context) (total int64, err error) {
+	defer derrors.Wrap(&err, "DeleteExpiredTokens")
+
+	for _, query := range []string{
+		`DELETE FROM revoked_tokens WHERE expires_at < NOW()`,
+		`DELETE FROM user_token_revocations WHERE expires_at < NOW()`,
+	} {
+		result, err := r.Exec(ctx, nil, query, nil)
+		if err != nil {
+			return 0, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+		}
+		total += affected
+	}
+
+	return total, nil
+}
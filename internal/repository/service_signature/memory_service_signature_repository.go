@@ -0,0 +1,48 @@
+package servicesignaturerepository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryServiceSignatureRepository keeps signatures in process memory,
+// it is only suitable for a single instance deployment and for tests.
+type memoryServiceSignatureRepository struct {
+	mu         sync.Mutex
+	signatures map[string]time.Time
+}
+
+func NewMemoryServiceSignatureRepository() ServiceSignatureRepository {
+	return &memoryServiceSignatureRepository{
+		signatures: map[string]time.Time{},
+	}
+}
+
+func (m *memoryServiceSignatureRepository) UseSignature(ctx context.Context, clientID, signature string, expiresAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := clientID + ":" + signature
+	if _, ok := m.signatures[key]; ok {
+		return false, nil
+	}
+
+	m.signatures[key] = expiresAt
+	return true, nil
+}
+
+func (m *memoryServiceSignatureRepository) DeleteExpiredSignatures(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var total int64
+	now := time.Now()
+	for key, expiresAt := range m.signatures {
+		if expiresAt.Before(now) {
+			delete(m.signatures, key)
+			total++
+		}
+	}
+	return total, nil
+}
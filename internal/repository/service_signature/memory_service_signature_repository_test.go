@@ -0,0 +1,40 @@
+package servicesignaturerepository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	servicesignaturerepository "date-apps-be/internal/repository/service_signature"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryServiceSignatureRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := servicesignaturerepository.NewMemoryServiceSignatureRepository()
+
+	firstUse, err := repo.UseSignature(ctx, "billing", "active", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, firstUse)
+
+	firstUse, err = repo.UseSignature(ctx, "billing", "active", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, firstUse, "the same signature is a replay")
+
+	firstUse, err = repo.UseSignature(ctx, "search", "active", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, firstUse, "signatures are scoped per client")
+
+	firstUse, err = repo.UseSignature(ctx, "billing", "expired", time.Now().Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, firstUse)
+
+	total, err := repo.DeleteExpiredSignatures(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	firstUse, err = repo.UseSignature(ctx, "billing", "active", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, firstUse, "unexpired signatures are kept")
+}
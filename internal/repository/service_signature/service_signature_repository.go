@@ -0,0 +1,82 @@
+package servicesignaturerepository
+
+import (
+	"context"
+	"date-apps-be/internal/constant"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"time"
+)
+
+type (
+	// ServiceSignatureRepository remembers the signatures of accepted service requests
+	// so a captured request can not be sent again. Entries are kept until the request
+	// timestamp leaves the clock skew window.
+	ServiceSignatureRepository interface {
+		UseSignature(ctx context.Context, clientID, signature string, expiresAt time.Time) (firstUse bool, err error)
+		DeleteExpiredSignatures(ctx context.Context) (total int64, err error)
+	}
+
+	serviceSignatureRepository struct {
+		repository.Repository
+	}
+)
+
+// NewServiceSignatureRepository returns the replay store configured by storeType.
+func NewServiceSignatureRepository(storeType string, store repository.Repository) ServiceSignatureRepository {
+	if storeType == constant.ServiceReplayStoreMemory {
+		return NewMemoryServiceSignatureRepository()
+	}
+
+	return NewMySQLServiceSignatureRepository(store)
+}
+
+func NewMySQLServiceSignatureRepository(store repository.Repository) ServiceSignatureRepository {
+	return &serviceSignatureRepository{
+		Repository: store,
+	}
+}
+
+// UseSignature records the signature, firstUse is false when it was already recorded.
+func (r *serviceSignatureRepository) UseSignature(ctx context.Context, clientID, signature string, expiresAt time.Time) (firstUse bool, err error) {
+	defer derrors.Wrap(&err, "UseSignature(%q)", clientID)
+
+	query := `INSERT IGNORE INTO service_signatures (client_id, signature, expires_at) VALUES (?, ?, ?)`
+	expiredTime := datatype.NewTime(&expiresAt)
+	args := []interface{}{
+		clientID,
+		signature,
+		&expiredTime,
+	}
+
+	result, err := r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+	}
+
+	return affected > 0, nil
+}
+
+func (r *serviceSignatureRepository) DeleteExpiredSignatures(ctx context.Context) (total int64, err error) {
+	defer derrors.Wrap(&err, "DeleteExpiredSignatures")
+
+	query := `DELETE FROM service_signatures WHERE expires_at < NOW()`
+
+	result, err := r.Exec(ctx, nil, query, nil)
+	if err != nil {
+		return 0, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	total, err = result.RowsAffected()
+	if err != nil {
+		return 0, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+	}
+
+	return total, nil
+}
@@ -5,10 +5,16 @@ import (
 	"database/sql"
 	"date-apps-be/internal/model"
 	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/datatype"
 	"date-apps-be/pkg/derrors"
 	"strings"
+	"time"
 )
 
+// userColumns selects a NULL password of accounts created by social sign-in as an empty string.
+const userColumns = `uid, name, email, email_verified_at, pending_email, phone_number, phone_verified_at, COALESCE(password, ''), locked_until, deleted_at, status, status_reason, suspended_until,
+	birthdate, gender, bio, job_title, company, education, height_cm, interests`
+
 type (
 	userRepository struct {
 		repository.Repository
@@ -17,10 +23,21 @@ type (
 	UserRepository interface {
 		repository.Repository
 		CreateUser(ctx context.Context, tx *sql.Tx, user *model.User) (id int64, err error)
-		DeleteUser(ctx context.Context, tx *sql.Tx, id string) error
 		GetUserByUID(ctx context.Context, id string) (user *model.User, err error)
+		GetUserForUpdate(ctx context.Context, tx *sql.Tx, uid string) (user *model.User, err error)
 		GetUserByEmailOrPhoneNumber(ctx context.Context, email, phoneNumber string) (user *model.User, err error)
 		UpdateUser(ctx context.Context, tx *sql.Tx, user *model.User) error
+		UpdateLockedUntil(ctx context.Context, tx *sql.Tx, uid string, lockedUntil datatype.Time) error
+		UpdateEmailVerifiedAt(ctx context.Context, tx *sql.Tx, uid string, emailVerifiedAt datatype.Time) error
+		UpdatePendingEmail(ctx context.Context, tx *sql.Tx, uid string, pendingEmail *string) error
+		UpdatePassword(ctx context.Context, tx *sql.Tx, uid, password string) error
+		UpdatePhoneVerifiedAt(ctx context.Context, tx *sql.Tx, uid string, phoneVerifiedAt datatype.Time) error
+		UpdateUserProfile(ctx context.Context, tx *sql.Tx, user *model.User) error
+		UpdateUserStatus(ctx context.Context, tx *sql.Tx, uid, status string, reason *string, suspendedUntil datatype.Time) error
+		SoftDeleteUser(ctx context.Context, tx *sql.Tx, uid string, deletedAt datatype.Time) (deleted bool, err error)
+		RestoreUser(ctx context.Context, tx *sql.Tx, uid string, deletedAfter time.Time) (restored bool, err error)
+		GetDeletedUserUIDs(ctx context.Context, deletedBefore time.Time, limit uint64) (uids []string, err error)
+		PurgeUser(ctx context.Context, tx *sql.Tx, uid string, deletedBefore time.Time) (purged bool, err error)
 	}
 )
 
@@ -30,6 +47,32 @@ func NewUserRepository(store repository.Repository) UserRepository {
 	}
 }
 
+func (r *userRepository) getDest(user *model.User) []interface{} {
+	return []interface{}{
+		&user.UID,
+		&user.Name,
+		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.PendingEmail,
+		&user.PhoneNumber,
+		&user.PhoneVerifiedAt,
+		&user.Password,
+		&user.LockedUntil,
+		&user.DeletedAt,
+		&user.Status,
+		&user.StatusReason,
+		&user.SuspendedUntil,
+		&user.Birthdate,
+		&user.Gender,
+		&user.Bio,
+		&user.JobTitle,
+		&user.Company,
+		&user.Education,
+		&user.HeightCM,
+		&user.Interests,
+	}
+}
+
 func (r *userRepository) CreateUser(ctx context.Context, tx *sql.Tx, user *model.User) (id int64, err error) {
 	defer derrors.Wrap(&err, "CreateUser(%q)", user.UID)
 
@@ -39,7 +82,7 @@ func (r *userRepository) CreateUser(ctx context.Context, tx *sql.Tx, user *model
 		user.Name,
 		r.NewNullString(user.Email),
 		r.NewNullString(user.PhoneNumber),
-		user.Password,
+		r.NewNullString(&user.Password),
 	}
 
 	result, err := r.Exec(ctx, tx, query, args)
@@ -53,15 +96,9 @@ func (r *userRepository) CreateUser(ctx context.Context, tx *sql.Tx, user *model
 func (r *userRepository) GetUserByUID(ctx context.Context, uid string) (user *model.User, err error) {
 	defer derrors.Wrap(&err, "GetUserByUID(%q)", uid)
 
-	query := `SELECT uid, name, email, phone_number, password FROM users WHERE uid = ?`
+	query := `SELECT ` + userColumns + ` FROM users WHERE uid = ?`
 	user = &model.User{}
-	dest := []interface{}{
-		&user.UID,
-		&user.Name,
-		&user.Email,
-		&user.PhoneNumber,
-		&user.Password,
-	}
+	dest := r.getDest(user)
 
 	args := []interface{}{
 		uid,
@@ -78,18 +115,30 @@ func (r *userRepository) GetUserByUID(ctx context.Context, uid string) (user *mo
 	return user, nil
 }
 
+// GetUserForUpdate reads and locks the user in tx, user is nil when it does not exist.
+func (r *userRepository) GetUserForUpdate(ctx context.Context, tx *sql.Tx, uid string) (user *model.User, err error) {
+	defer derrors.Wrap(&err, "GetUserForUpdate(%q)", uid)
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE uid = ? FOR UPDATE`
+	user = &model.User{}
+
+	err = tx.QueryRowContext(ctx, query, uid).Scan(r.getDest(user)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return user, nil
+}
+
 func (r *userRepository) GetUserByEmailOrPhoneNumber(ctx context.Context, email, phoneNumber string) (user *model.User, err error) {
 	defer derrors.Wrap(&err, "GetUserByEmailOrPhoneNumber(%q, %q)", email, phoneNumber)
 
-	query := `SELECT uid, name, email, phone_number, password FROM users WHERE`
+	query := `SELECT ` + userColumns + ` FROM users WHERE`
 	user = &model.User{}
-	dest := []interface{}{
-		&user.UID,
-		&user.Name,
-		&user.Email,
-		&user.PhoneNumber,
-		&user.Password,
-	}
+	dest := r.getDest(user)
 
 	args := []interface{}{}
 	whereFilter := []string{}
@@ -125,7 +174,34 @@ func (r *userRepository) UpdateUser(ctx context.Context, tx *sql.Tx, user *model
 		user.Name,
 		user.Email,
 		user.PhoneNumber,
-		user.Password,
+		r.NewNullString(&user.Password),
+		user.UID,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// UpdateUserProfile saves the name and the dating profile of the user.
+func (r *userRepository) UpdateUserProfile(ctx context.Context, tx *sql.Tx, user *model.User) (err error) {
+	defer derrors.Wrap(&err, "UpdateUserProfile(%q)", user.UID)
+
+	query := `UPDATE users SET name = ?, birthdate = ?, gender = ?, bio = ?, job_title = ?, company = ?, education = ?, height_cm = ?, interests = ?
+			WHERE uid = ?`
+	args := []interface{}{
+		user.Name,
+		&user.Birthdate,
+		user.Gender,
+		r.NewNullString(user.Bio),
+		r.NewNullString(user.JobTitle),
+		r.NewNullString(user.Company),
+		r.NewNullString(user.Education),
+		user.HeightCM,
+		user.Interests,
 		user.UID,
 	}
 
@@ -137,12 +213,30 @@ func (r *userRepository) UpdateUser(ctx context.Context, tx *sql.Tx, user *model
 	return nil
 }
 
-func (r *userRepository) DeleteUser(ctx context.Context, tx *sql.Tx, id string) (err error) {
-	defer derrors.Wrap(&err, "DeleteUser(%q)", id)
+func (r *userRepository) UpdateLockedUntil(ctx context.Context, tx *sql.Tx, uid string, lockedUntil datatype.Time) (err error) {
+	defer derrors.Wrap(&err, "UpdateLockedUntil(%q)", uid)
+
+	query := `UPDATE users SET locked_until = ? WHERE uid = ?`
+	args := []interface{}{
+		&lockedUntil,
+		uid,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateEmailVerifiedAt(ctx context.Context, tx *sql.Tx, uid string, emailVerifiedAt datatype.Time) (err error) {
+	defer derrors.Wrap(&err, "UpdateEmailVerifiedAt(%q)", uid)
 
-	query := `DELETE FROM users WHERE uid = ?`
+	query := `UPDATE users SET email_verified_at = ? WHERE uid = ?`
 	args := []interface{}{
-		id,
+		&emailVerifiedAt,
+		uid,
 	}
 
 	_, err = r.Exec(ctx, tx, query, args)
@@ -152,3 +246,213 @@ func (r *userRepository) DeleteUser(ctx context.Context, tx *sql.Tx, id string)
 
 	return nil
 }
+
+func (r *userRepository) UpdatePhoneVerifiedAt(ctx context.Context, tx *sql.Tx, uid string, phoneVerifiedAt datatype.Time) (err error) {
+	defer derrors.Wrap(&err, "UpdatePhoneVerifiedAt(%q)", uid)
+
+	query := `UPDATE users SET phone_verified_at = ? WHERE uid = ?`
+	args := []interface{}{
+		&phoneVerifiedAt,
+		uid,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// UpdatePendingEmail sets the email the user is changing to, nil clears it.
+func (r *userRepository) UpdatePendingEmail(ctx context.Context, tx *sql.Tx, uid string, pendingEmail *string) (err error) {
+	defer derrors.Wrap(&err, "UpdatePendingEmail(%q)", uid)
+
+	query := `UPDATE users SET pending_email = ? WHERE uid = ?`
+	args := []interface{}{
+		r.NewNullString(pendingEmail),
+		uid,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, tx *sql.Tx, uid, password string) (err error) {
+	defer derrors.Wrap(&err, "UpdatePassword(%q)", uid)
+
+	query := `UPDATE users SET password = ? WHERE uid = ?`
+	args := []interface{}{
+		password,
+		uid,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateUserStatus(ctx context.Context, tx *sql.Tx, uid, status string, reason *string, suspendedUntil datatype.Time) (err error) {
+	defer derrors.Wrap(&err, "UpdateUserStatus(%q, %q)", uid, status)
+
+	query := `UPDATE users SET status = ?, status_reason = ?, suspended_until = ? WHERE uid = ?`
+	args := []interface{}{
+		status,
+		r.NewNullString(reason),
+		&suspendedUntil,
+		uid,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// SoftDeleteUser marks the user as deleted, deleted is false when it already was.
+func (r *userRepository) SoftDeleteUser(ctx context.Context, tx *sql.Tx, uid string, deletedAt datatype.Time) (deleted bool, err error) {
+	defer derrors.Wrap(&err, "SoftDeleteUser(%q)", uid)
+
+	query := `UPDATE users SET deleted_at = ? WHERE uid = ? AND deleted_at IS NULL`
+	args := []interface{}{
+		&deletedAt,
+		uid,
+	}
+
+	result, err := r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "RowsAffected")
+	}
+
+	return affected > 0, nil
+}
+
+// RestoreUser undoes SoftDeleteUser when the user was deleted after deletedAfter,
+// restored is false when the user is not deleted or the grace period is over.
+func (r *userRepository) RestoreUser(ctx context.Context, tx *sql.Tx, uid string, deletedAfter time.Time) (restored bool, err error) {
+	defer derrors.Wrap(&err, "RestoreUser(%q)", uid)
+
+	query := `UPDATE users SET deleted_at = NULL WHERE uid = ? AND deleted_at > ?`
+	args := []interface{}{
+		uid,
+		deletedAfter,
+	}
+
+	result, err := r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "RowsAffected")
+	}
+
+	return affected > 0, nil
+}
+
+// GetDeletedUserUIDs returns up to limit users deleted before deletedBefore, oldest first.
+func (r *userRepository) GetDeletedUserUIDs(ctx context.Context, deletedBefore time.Time, limit uint64) (uids []string, err error) {
+	defer derrors.Wrap(&err, "GetDeletedUserUIDs")
+
+	query := `SELECT uid FROM users WHERE deleted_at <= ? ORDER BY deleted_at LIMIT ?`
+	args := []interface{}{
+		deletedBefore,
+		limit,
+	}
+
+	uids = []string{}
+
+	rows, err := r.Master().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var uid string
+		if err = rows.Scan(&uid); err != nil {
+			return nil, derrors.HandleSQLError(err, "Scan")
+		}
+		uids = append(uids, uid)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, derrors.HandleSQLError(err, "rows.Err")
+	}
+
+	return uids, nil
+}
+
+// purgeQueries removes or anonymizes every row that belongs to a user, in the order
+// the foreign keys require. Every table with a user_uid column must be listed here.
+// user_token_revocations is left to expire on its own so access tokens issued
+// before the deletion stay rejected until they expire, user_status_audits is kept
+// as the trust and safety trail.
+var purgeQueries = []string{
+	`DELETE FROM user_matches WHERE user_uid = ? OR match_uid = ?`,
+	`DELETE FROM mutual_matches WHERE user_uid = ? OR match_uid = ?`,
+	`DELETE FROM user_rewinds WHERE user_uid = ? OR match_uid = ?`,
+	`DELETE FROM hidden_pairs WHERE user_uid = ? OR match_uid = ?`,
+	`DELETE FROM user_premium WHERE user_uid = ?`,
+	// login attempts are kept for the failed login limits of the ip address
+	`UPDATE login_history SET user_uid = NULL, identifier = 'deleted', user_agent = NULL WHERE user_uid = ?`,
+	`DELETE FROM refresh_tokens WHERE user_uid = ?`,
+	`DELETE FROM user_sessions WHERE user_uid = ?`,
+	`DELETE FROM password_resets WHERE user_uid = ?`,
+	`DELETE FROM otp_codes WHERE user_uid = ? OR phone_number = (SELECT phone_number FROM users WHERE uid = ?)`,
+	`DELETE FROM mfa_recovery_codes WHERE user_uid = ?`,
+	`DELETE FROM user_mfa WHERE user_uid = ?`,
+	`DELETE FROM user_identities WHERE user_uid = ?`,
+	`DELETE FROM user_roles WHERE user_uid = ?`,
+	`DELETE FROM user_preferences WHERE user_uid = ?`,
+	`DELETE FROM user_locations WHERE user_uid = ?`,
+	// photo blobs are deleted by the caller once the transaction is committed
+	`DELETE FROM user_photos WHERE user_uid = ?`,
+	// archives are deleted from the blob store by the data export cleanup once the account is deleted
+	`DELETE FROM data_exports WHERE user_uid = ?`,
+	`DELETE FROM users WHERE uid = ?`,
+}
+
+// PurgeUser deletes a soft deleted user with every dependent row, it must run in a
+// transaction. purged is false when the user was restored or purged in the meantime.
+func (r *userRepository) PurgeUser(ctx context.Context, tx *sql.Tx, uid string, deletedBefore time.Time) (purged bool, err error) {
+	defer derrors.Wrap(&err, "PurgeUser(%q)", uid)
+
+	// lock the user so a concurrent restore waits for the purge or wins before it
+	var lockedUID string
+	err = tx.QueryRowContext(ctx, `SELECT uid FROM users WHERE uid = ? AND deleted_at <= ? FOR UPDATE`, uid, deletedBefore).Scan(&lockedUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	for _, query := range purgeQueries {
+		args := []interface{}{}
+		for i := 0; i < strings.Count(query, "?"); i++ {
+			args = append(args, uid)
+		}
+
+		if _, err = r.Exec(ctx, tx, query, args); err != nil {
+			return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,81 @@
+package useridentityrepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	userIdentityRepository struct {
+		repository.Repository
+	}
+
+	// UserIdentityRepository stores the identity provider accounts linked to users.
+	UserIdentityRepository interface {
+		repository.Repository
+		CreateUserIdentity(ctx context.Context, tx *sql.Tx, identity *model.UserIdentity) (err error)
+		GetUserIdentity(ctx context.Context, provider, subject string) (identity *model.UserIdentity, err error)
+	}
+)
+
+func NewUserIdentityRepository(store repository.Repository) UserIdentityRepository {
+	return &userIdentityRepository{
+		Repository: store,
+	}
+}
+
+func (r *userIdentityRepository) getDest(identity *model.UserIdentity) []interface{} {
+	return []interface{}{
+		&identity.UID,
+		&identity.UserUID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	}
+}
+
+func (r *userIdentityRepository) CreateUserIdentity(ctx context.Context, tx *sql.Tx, identity *model.UserIdentity) (err error) {
+	defer derrors.Wrap(&err, "CreateUserIdentity(%q, %q)", identity.Provider, identity.UserUID)
+
+	query := `INSERT INTO user_identities (uid, user_uid, provider, subject, email) VALUES (?, ?, ?, ?, ?)`
+	args := []interface{}{
+		identity.UID,
+		identity.UserUID,
+		identity.Provider,
+		identity.Subject,
+		r.NewNullString(identity.Email),
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *userIdentityRepository) GetUserIdentity(ctx context.Context, provider, subject string) (identity *model.UserIdentity, err error) {
+	defer derrors.Wrap(&err, "GetUserIdentity(%q)", provider)
+
+	query := `SELECT uid, user_uid, provider, subject, email, created_at FROM user_identities WHERE provider = ? AND subject = ?`
+
+	identity = &model.UserIdentity{}
+	args := []interface{}{
+		provider,
+		subject,
+	}
+
+	err = r.Query(ctx, query, r.getDest(identity), args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,81 @@
+package userlocationrepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	userLocationRepository struct {
+		repository.Repository
+	}
+
+	// UserLocationRepository stores the last reported location of users.
+	UserLocationRepository interface {
+		repository.Repository
+		GetUserLocation(ctx context.Context, userUID string) (location *model.UserLocation, err error)
+		UpsertUserLocation(ctx context.Context, location *model.UserLocation) (err error)
+	}
+)
+
+func NewUserLocationRepository(store repository.Repository) UserLocationRepository {
+	return &userLocationRepository{
+		Repository: store,
+	}
+}
+
+func (r *userLocationRepository) getDest(location *model.UserLocation) []interface{} {
+	return []interface{}{
+		&location.UserUID,
+		&location.Latitude,
+		&location.Longitude,
+		&location.Geohash,
+		&location.UpdatedAt,
+	}
+}
+
+// GetUserLocation returns nil when the user has not reported a location.
+func (r *userLocationRepository) GetUserLocation(ctx context.Context, userUID string) (location *model.UserLocation, err error) {
+	defer derrors.Wrap(&err, "GetUserLocation(%q)", userUID)
+
+	query := `SELECT user_uid, latitude, longitude, geohash, updated_at FROM user_locations WHERE user_uid = ?`
+
+	location = &model.UserLocation{}
+	args := []interface{}{
+		userUID,
+	}
+
+	err = r.Query(ctx, query, r.getDest(location), args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return location, nil
+}
+
+// UpsertUserLocation replaces the location of the user.
+func (r *userLocationRepository) UpsertUserLocation(ctx context.Context, location *model.UserLocation) (err error) {
+	defer derrors.Wrap(&err, "UpsertUserLocation(%q)", location.UserUID)
+
+	query := `INSERT INTO user_locations (user_uid, latitude, longitude, geohash) VALUES (?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE latitude = VALUES(latitude), longitude = VALUES(longitude), geohash = VALUES(geohash)`
+	args := []interface{}{
+		location.UserUID,
+		location.Latitude,
+		location.Longitude,
+		location.Geohash,
+	}
+
+	_, err = r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
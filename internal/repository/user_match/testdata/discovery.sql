@@ -0,0 +1,43 @@
+-- Discovery deck of `me`, a 30 year old woman in Jakarta looking for men and non binary
+-- people between 25 and 40 within 50 km. Only near_man and near_non_binary are expected,
+-- near_non_binary super-liked me so it comes first.
+INSERT INTO users (uid, name, email, gender, birthdate, status) VALUES
+('me', 'Me', 'me@example.com', 'female', DATE_SUB(CURDATE(), INTERVAL 30 YEAR), 'active'),
+('near_man', 'Near man', 'near_man@example.com', 'male', DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'active'),
+('near_non_binary', 'Near non binary', 'near_non_binary@example.com', 'non_binary', DATE_SUB(CURDATE(), INTERVAL 28 YEAR), 'active'),
+('too_far', 'Too far', 'too_far@example.com', 'male', DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'active'),
+('no_location', 'No location', 'no_location@example.com', 'male', DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'active'),
+('wrong_gender', 'Wrong gender', 'wrong_gender@example.com', 'female', DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'active'),
+('no_gender', 'No gender', 'no_gender@example.com', NULL, DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'active'),
+('too_old', 'Too old', 'too_old@example.com', 'male', DATE_SUB(CURDATE(), INTERVAL 45 YEAR), 'active'),
+('wants_men', 'Wants men', 'wants_men@example.com', 'male', DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'active'),
+('wants_younger', 'Wants younger', 'wants_younger@example.com', 'male', DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'active'),
+('wants_closer', 'Wants closer', 'wants_closer@example.com', 'male', DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'active'),
+('swiped', 'Swiped', 'swiped@example.com', 'male', DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'active'),
+('banned', 'Banned', 'banned@example.com', 'male', DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'banned');
+
+INSERT INTO user_locations (user_uid, latitude, longitude, geohash) VALUES
+('me', -6.208800, 106.845600, 'qqguxmdx4'), -- Jakarta
+('near_man', -6.595000, 106.816600, 'qqgfqxmmr'), -- Bogor, about 43 km
+('near_non_binary', -6.402500, 106.794200, 'qqggw4mnt'), -- Depok, about 22 km
+('too_far', -6.917500, 107.619100, 'qqu88utyd'), -- Bandung, about 116 km
+('wrong_gender', -6.402500, 106.794200, 'qqggw4mnt'),
+('no_gender', -6.402500, 106.794200, 'qqggw4mnt'),
+('too_old', -6.402500, 106.794200, 'qqggw4mnt'),
+('wants_men', -6.402500, 106.794200, 'qqggw4mnt'),
+('wants_younger', -6.402500, 106.794200, 'qqggw4mnt'),
+('wants_closer', -6.402500, 106.794200, 'qqggw4mnt'),
+('swiped', -6.402500, 106.794200, 'qqggw4mnt'),
+('banned', -6.402500, 106.794200, 'qqggw4mnt');
+
+INSERT INTO user_preferences (user_uid, min_age, max_age, genders, max_distance_km) VALUES
+('me', 25, 40, '["male","non_binary"]', 50),
+('wants_men', NULL, NULL, '["male"]', NULL),
+('wants_younger', NULL, 28, NULL, NULL),
+('wants_closer', NULL, NULL, NULL, 10);
+
+INSERT INTO user_matches (user_uid, match_uid, match_type) VALUES
+('me', 'swiped', 'like'),
+('near_non_binary', 'me', 'super_like'),
+('swiped', 'me', 'like'),
+('banned', 'me', 'like');
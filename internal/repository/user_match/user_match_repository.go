@@ -3,19 +3,32 @@ package usermatchrepository
 import (
 	"context"
 	"database/sql"
+	"date-apps-be/internal/constant"
 	"date-apps-be/internal/model"
 	repository "date-apps-be/internal/repository/common"
 	"date-apps-be/internal/usecase/user_match/dto"
 	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/geo"
+	"strings"
+	"time"
 )
 
 type UserMatchRepository interface {
 	repository.Repository
-	CreateUserMatch(ctx context.Context, userMatch *model.UserMatch) (err error)
+	LockSwipePair(ctx context.Context, tx *sql.Tx, userUID, matchUID string) (err error)
+	LockUser(ctx context.Context, tx *sql.Tx, userUID string) (err error)
+	CreateUserMatch(ctx context.Context, tx *sql.Tx, userMatch *model.UserMatch) (err error)
+	GetLatestUserMatch(ctx context.Context, tx *sql.Tx, userUID, matchUID string) (userMatch *model.UserMatch, err error)
+	GetRewindableUserMatch(ctx context.Context, tx *sql.Tx, userUID string, window time.Duration) (userMatch *model.UserMatch, err error)
+	DeleteUserMatch(ctx context.Context, tx *sql.Tx, id int64) (err error)
 	GetUserMatches(ctx context.Context, d dto.GetUserMatches) (userMatches []*model.UserMatch, err error)
-	GetTotalUserMatchToday(ctx context.Context, userUID string) (total int, err error)
-	GetAvailableUsers(ctx context.Context, userUID string, page, limit uint64) (users []*model.User, err error)
+	GetTotalUserMatchToday(ctx context.Context, tx *sql.Tx, userUID string) (total int, err error)
+	GetTotalSuperLikeToday(ctx context.Context, tx *sql.Tx, userUID string) (total int, err error)
+	GetLikesReceived(ctx context.Context, userUID string, page, limit uint64) (users []*model.User, err error)
+	GetTotalLikesReceived(ctx context.Context, userUID string) (total int, err error)
+	GetAvailableUsers(ctx context.Context, d dto.GetAvailableUsers) (users []*model.User, err error)
 	GetUserMatchTodayByUserUIDAndMatchUID(ctx context.Context, userUID, matchUID string) (userMatch *model.UserMatch, err error)
+	GetUserMatchHistory(ctx context.Context, userUID string, page, limit uint64) (userMatches []*model.UserMatch, err error)
 }
 
 type userMatchRepository struct {
@@ -39,15 +52,18 @@ func (u *userMatchRepository) getDest(userMatch *model.UserMatch) []interface{}
 func (u *userMatchRepository) GetUserMatches(ctx context.Context, d dto.GetUserMatches) (userMatches []*model.UserMatch, err error) {
 	defer derrors.Wrap(&err, "GetUserMatches(%q)", d.UserUID)
 
-	query := `SELECT user_uid , match_uid, name, match_type FROM user_matches 
+	query := `SELECT user_matches.user_uid, user_matches.match_uid, users.name, user_matches.match_type, user_matches.created_at
+			FROM user_matches
 			LEFT JOIN users ON user_matches.match_uid = users.uid
-			WHERE user_uid = ?`
+			WHERE user_uid = ? AND users.deleted_at IS NULL
+			AND users.status != ? AND (users.suspended_until IS NULL OR users.suspended_until <= NOW())`
 
 	var args = []interface{}{
 		d.UserUID,
+		constant.UserStatusBanned,
 	}
 	if d.MatchType.IsValid() {
-		query += ` AND match_type = ? `
+		query += ` AND user_matches.match_type = ? `
 		args = append(args, d.MatchType.String())
 	}
 
@@ -62,6 +78,7 @@ func (u *userMatchRepository) GetUserMatches(ctx context.Context, d dto.GetUserM
 		err = derrors.HandleSQLError(err, "QueryContext")
 		return
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		wc := &model.UserMatch{}
@@ -76,7 +93,39 @@ func (u *userMatchRepository) GetUserMatches(ctx context.Context, d dto.GetUserM
 	return userMatches, nil
 }
 
-func (u *userMatchRepository) CreateUserMatch(ctx context.Context, userMatch *model.UserMatch) (err error) {
+// LockSwipePair locks both users in the order of their uids, swipes between the same
+// pair run one after the other so a reciprocal like is never missed.
+func (u *userMatchRepository) LockSwipePair(ctx context.Context, tx *sql.Tx, userUID, matchUID string) (err error) {
+	defer derrors.Wrap(&err, "LockSwipePair(%q, %q)", userUID, matchUID)
+
+	rows, err := tx.QueryContext(ctx, `SELECT uid FROM users WHERE uid IN (?, ?) ORDER BY uid FOR UPDATE`, userUID, matchUID)
+	if err != nil {
+		return derrors.HandleSQLError(err, "QueryContext")
+	}
+
+	return rows.Close()
+}
+
+func (u *userMatchRepository) queryRow(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) *sql.Row {
+	if tx != nil {
+		return tx.QueryRowContext(ctx, query, args...)
+	}
+	return u.Slave().QueryRowContext(ctx, query, args...)
+}
+
+// LockUser locks the user, every swipe locks the user as well so a rewind never races a swipe of the user.
+func (u *userMatchRepository) LockUser(ctx context.Context, tx *sql.Tx, userUID string) (err error) {
+	defer derrors.Wrap(&err, "LockUser(%q)", userUID)
+
+	rows, err := tx.QueryContext(ctx, `SELECT uid FROM users WHERE uid = ? FOR UPDATE`, userUID)
+	if err != nil {
+		return derrors.HandleSQLError(err, "QueryContext")
+	}
+
+	return rows.Close()
+}
+
+func (u *userMatchRepository) CreateUserMatch(ctx context.Context, tx *sql.Tx, userMatch *model.UserMatch) (err error) {
 	defer derrors.Wrap(&err, "CreateUserMatch(%v)", userMatch)
 
 	query := `INSERT INTO user_matches (user_uid, match_uid, match_type) VALUES (?, ?, ?)`
@@ -86,34 +135,147 @@ func (u *userMatchRepository) CreateUserMatch(ctx context.Context, userMatch *mo
 		userMatch.MatchType,
 	}
 
-	_, err = u.Master().ExecContext(ctx, query, args...)
+	_, err = u.Exec(ctx, tx, query, args)
 	if err != nil {
-		err = derrors.HandleSQLError(err, "ExecContext")
+		err = derrors.HandleSQLError(err, "r.Exec")
 		return
 	}
 
 	return nil
 }
 
-func (u *userMatchRepository) GetAvailableUsers(ctx context.Context, userUID string, page, limit uint64) (users []*model.User, err error) {
-	defer derrors.Wrap(&err, "GetAvailableUsers(%q)", userUID)
+// GetLatestUserMatch returns the most recent swipe of the user on matchUID, nil when there is none.
+func (u *userMatchRepository) GetLatestUserMatch(ctx context.Context, tx *sql.Tx, userUID, matchUID string) (userMatch *model.UserMatch, err error) {
+	defer derrors.Wrap(&err, "GetLatestUserMatch(%q, %q)", userUID, matchUID)
+
+	query := `SELECT user_uid, match_uid, match_type, created_at FROM user_matches
+			WHERE user_uid = ? AND match_uid = ?
+			ORDER BY created_at DESC, id DESC
+			LIMIT 1`
 
-	query := `SELECT u.uid, u.name, IF(up.uid IS NOT NULL, TRUE, FALSE) AS is_premium
+	userMatch = &model.UserMatch{}
+	err = tx.QueryRowContext(ctx, query, userUID, matchUID).
+		Scan(&userMatch.UserUID, &userMatch.MatchUID, &userMatch.MatchType, &userMatch.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return userMatch, nil
+}
+
+// GetRewindableUserMatch returns the most recent swipe of the user when it was made inside window, nil otherwise.
+func (u *userMatchRepository) GetRewindableUserMatch(ctx context.Context, tx *sql.Tx, userUID string, window time.Duration) (userMatch *model.UserMatch, err error) {
+	defer derrors.Wrap(&err, "GetRewindableUserMatch(%q)", userUID)
+
+	query := `SELECT id, user_uid, match_uid, match_type, created_at FROM (
+				SELECT id, user_uid, match_uid, match_type, created_at FROM user_matches
+				WHERE user_uid = ?
+				ORDER BY created_at DESC, id DESC
+				LIMIT 1
+			) latest
+			WHERE created_at >= NOW() - INTERVAL ? SECOND`
+
+	userMatch = &model.UserMatch{}
+	err = tx.QueryRowContext(ctx, query, userUID, int64(window.Seconds())).
+		Scan(&userMatch.ID, &userMatch.UserUID, &userMatch.MatchUID, &userMatch.MatchType, &userMatch.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return userMatch, nil
+}
+
+func (u *userMatchRepository) DeleteUserMatch(ctx context.Context, tx *sql.Tx, id int64) (err error) {
+	defer derrors.Wrap(&err, "DeleteUserMatch(%d)", id)
+
+	_, err = u.Exec(ctx, tx, `DELETE FROM user_matches WHERE id = ?`, []interface{}{id})
+	if err != nil {
+		return derrors.HandleSQLError(err, "r.Exec")
+	}
+
+	return nil
+}
+
+// distanceKM is the haversine distance between the candidate and the user, NULL when either has no location.
+const distanceKM = `(6371 * 2 * ASIN(SQRT(
+				POWER(SIN(RADIANS(cl.latitude - ml.latitude) / 2), 2) +
+				COS(RADIANS(ml.latitude)) * COS(RADIANS(cl.latitude)) * POWER(SIN(RADIANS(cl.longitude - ml.longitude) / 2), 2)
+			)))`
+
+// GetAvailableUsers returns users the user has not swiped on today. The preferences apply both ways,
+// a candidate must match the preferences of the user and the user must match the preferences of the candidate.
+// A preference on age or gender excludes users who left that field empty, a distance preference excludes
+// users without a location once the user with the preference has reported one.
+// Candidates who super-liked the user come first until the user swipes on them.
+// Pairs hidden after an unmatch never see each other again.
+func (u *userMatchRepository) GetAvailableUsers(ctx context.Context, d dto.GetAvailableUsers) (users []*model.User, err error) {
+	defer derrors.Wrap(&err, "GetAvailableUsers(%q)", d.UserUID)
+
+	query := `SELECT u.uid, u.name, IF(up.uid IS NOT NULL, TRUE, FALSE) AS is_premium,
+				u.birthdate, u.gender, u.bio, u.job_title, u.company, u.education, u.height_cm, u.interests,
+				` + distanceKM + ` AS distance_km,
+				EXISTS (
+					SELECT 1 FROM user_matches sl
+					WHERE sl.user_uid = u.uid AND sl.match_uid = me.uid AND sl.match_type = ?
+					AND NOT EXISTS (
+						SELECT 1 FROM user_matches r
+						WHERE r.user_uid = me.uid AND r.match_uid = u.uid AND r.created_at >= sl.created_at
+					)
+				) AS super_liked
 			FROM users u
+			JOIN users me ON me.uid = ?
 			LEFT JOIN user_premium up ON u.uid = up.user_uid AND up.ended_at > NOW()
-			WHERE u.uid != ? AND u.uid NOT IN (
+			LEFT JOIN user_preferences mp ON mp.user_uid = me.uid
+			LEFT JOIN user_preferences cp ON cp.user_uid = u.uid
+			LEFT JOIN user_locations ml ON ml.user_uid = me.uid
+			LEFT JOIN user_locations cl ON cl.user_uid = u.uid
+			WHERE u.uid != me.uid AND u.deleted_at IS NULL
+			AND u.status != ? AND (u.suspended_until IS NULL OR u.suspended_until <= NOW())
+			AND u.uid NOT IN (
 				SELECT match_uid FROM user_matches
 				WHERE user_uid = ? AND DATE(created_at) = CURDATE()
 			)
-			ORDER BY RAND()
-			LIMIT ?,?`
+			AND NOT EXISTS (
+				SELECT 1 FROM hidden_pairs h
+				WHERE h.user_uid = LEAST(me.uid, u.uid) AND h.match_uid = GREATEST(me.uid, u.uid)
+			)
+			AND (mp.min_age IS NULL OR TIMESTAMPDIFF(YEAR, u.birthdate, CURDATE()) >= mp.min_age)
+			AND (mp.max_age IS NULL OR TIMESTAMPDIFF(YEAR, u.birthdate, CURDATE()) <= mp.max_age)
+			AND (mp.genders IS NULL OR JSON_CONTAINS(mp.genders, JSON_QUOTE(u.gender)))
+			AND (cp.min_age IS NULL OR TIMESTAMPDIFF(YEAR, me.birthdate, CURDATE()) >= cp.min_age)
+			AND (cp.max_age IS NULL OR TIMESTAMPDIFF(YEAR, me.birthdate, CURDATE()) <= cp.max_age)
+			AND (cp.genders IS NULL OR JSON_CONTAINS(cp.genders, JSON_QUOTE(me.gender)))
+			AND (mp.max_distance_km IS NULL OR ml.user_uid IS NULL OR ` + distanceKM + ` <= mp.max_distance_km)
+			AND (cp.max_distance_km IS NULL OR cl.user_uid IS NULL OR ` + distanceKM + ` <= cp.max_distance_km)`
 
 	args := []interface{}{
-		userUID,
-		userUID,
-		u.GetOffset(page, limit), limit,
+		constant.UserMatchTypeSuperLike,
+		d.UserUID,
+		constant.UserStatusBanned,
+		d.UserUID,
 	}
 
+	// the geohash prefixes only let the index skip far away users, the distance above decides
+	if d.Origin != nil && d.MaxDistanceKM != nil {
+		if cells := geo.Cover(d.Origin.Latitude, d.Origin.Longitude, float64(*d.MaxDistanceKM)); len(cells) > 0 {
+			query += ` AND (` + strings.TrimSuffix(strings.Repeat(`cl.geohash LIKE ? OR `, len(cells)), ` OR `) + `)`
+			for _, cell := range cells {
+				args = append(args, cell+"%")
+			}
+		}
+	}
+
+	query += `
+			ORDER BY super_liked DESC, RAND()
+			LIMIT ?,?`
+	args = append(args, u.GetOffset(d.Page, d.Limit), d.Limit)
+
 	users = []*model.User{}
 
 	rows, err := u.Slave().QueryContext(ctx, query, args...)
@@ -125,24 +287,130 @@ func (u *userMatchRepository) GetAvailableUsers(ctx context.Context, userUID str
 
 	for rows.Next() {
 		user := &model.User{}
-		err = rows.Scan(&user.UID, &user.Name, &user.IsPremium)
+		var distance sql.NullFloat64
+		err = rows.Scan(&user.UID, &user.Name, &user.IsPremium,
+			&user.Birthdate, &user.Gender, &user.Bio, &user.JobTitle, &user.Company, &user.Education, &user.HeightCM, &user.Interests,
+			&distance, &user.SuperLiked)
 		if err != nil {
 			return nil, err
 		}
 
+		// only the bucket leaves the server so the location of the candidate cannot be triangulated
+		if distance.Valid {
+			bucket := geo.RoundDistance(distance.Float64)
+			user.DistanceKM = &bucket
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// likesReceived are the users who liked or super-liked the user and the user has not swiped on yet,
+// with the time of their latest like. Deleted, banned and suspended users and hidden pairs are left out.
+const likesReceived = `FROM (
+				SELECT user_uid, MAX(created_at) AS liked_at, MAX(match_type = ?) AS super_liked
+				FROM user_matches
+				WHERE match_uid = ? AND match_type IN (?, ?)
+				GROUP BY user_uid
+			) l
+			JOIN users u ON u.uid = l.user_uid
+			WHERE u.deleted_at IS NULL
+			AND u.status != ? AND (u.suspended_until IS NULL OR u.suspended_until <= NOW())
+			AND NOT EXISTS (SELECT 1 FROM user_matches s WHERE s.user_uid = ? AND s.match_uid = u.uid)
+			AND NOT EXISTS (
+				SELECT 1 FROM hidden_pairs h
+				WHERE h.user_uid = LEAST(?, u.uid) AND h.match_uid = GREATEST(?, u.uid)
+			)`
+
+func likesReceivedArgs(userUID string) []interface{} {
+	return []interface{}{
+		constant.UserMatchTypeSuperLike,
+		userUID,
+		constant.UserMatchTypeLike,
+		constant.UserMatchTypeSuperLike,
+		constant.UserStatusBanned,
+		userUID,
+		userUID,
+		userUID,
+	}
+}
+
+// GetLikesReceived returns the users who liked the user, most recent like first.
+func (u *userMatchRepository) GetLikesReceived(ctx context.Context, userUID string, page, limit uint64) (users []*model.User, err error) {
+	defer derrors.Wrap(&err, "GetLikesReceived(%q)", userUID)
+
+	query := `SELECT u.uid, u.name, u.birthdate, u.gender, u.bio, u.job_title, u.company, u.education, u.height_cm, u.interests, l.super_liked
+			` + likesReceived + `
+			ORDER BY l.liked_at DESC, u.uid
+			LIMIT ?,?`
+
+	args := append(likesReceivedArgs(userUID), u.GetOffset(page, limit), limit)
+
+	rows, err := u.Slave().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	users = []*model.User{}
+	for rows.Next() {
+		user := &model.User{}
+		err = rows.Scan(&user.UID, &user.Name, &user.Birthdate, &user.Gender, &user.Bio, &user.JobTitle, &user.Company, &user.Education, &user.HeightCM, &user.Interests,
+			&user.SuperLiked)
+		if err != nil {
+			return nil, derrors.HandleSQLError(err, "Scan")
+		}
 		users = append(users, user)
 	}
 
 	return users, nil
 }
 
-func (u *userMatchRepository) GetTotalUserMatchToday(ctx context.Context, userUID string) (total int, err error) {
+func (u *userMatchRepository) GetTotalLikesReceived(ctx context.Context, userUID string) (total int, err error) {
+	defer derrors.Wrap(&err, "GetTotalLikesReceived(%q)", userUID)
+
+	query := `SELECT COUNT(*) ` + likesReceived
+
+	err = u.Slave().QueryRowContext(ctx, query, likesReceivedArgs(userUID)...).Scan(&total)
+	if err != nil {
+		return 0, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return total, nil
+}
+
+// GetTotalUserMatchToday reads in tx when it is given so the count is current once the user is locked,
+// otherwise it reads from the slave.
+func (u *userMatchRepository) GetTotalUserMatchToday(ctx context.Context, tx *sql.Tx, userUID string) (total int, err error) {
 	defer derrors.Wrap(&err, "GetTotalUserMatchToday(%q)", userUID)
 
+	// super-likes have their own quota
 	query := `SELECT COUNT(*) FROM user_matches
-			WHERE user_uid = ? AND DATE(created_at) = CURDATE()`
+			WHERE user_uid = ? AND match_type != ? AND DATE(created_at) = CURDATE()`
 
-	err = u.Slave().QueryRowContext(ctx, query, userUID).Scan(&total)
+	err = u.queryRow(ctx, tx, query, userUID, constant.UserMatchTypeSuperLike).Scan(&total)
+	if err != nil {
+		err = derrors.HandleSQLError(err, "QueryRowContext")
+		return
+	}
+
+	return total, nil
+}
+
+// GetTotalSuperLikeToday reads in tx when it is given, see GetTotalUserMatchToday.
+// Rewound super-likes still count, their notice was sent already.
+func (u *userMatchRepository) GetTotalSuperLikeToday(ctx context.Context, tx *sql.Tx, userUID string) (total int, err error) {
+	defer derrors.Wrap(&err, "GetTotalSuperLikeToday(%q)", userUID)
+
+	query := `SELECT
+				(SELECT COUNT(*) FROM user_matches
+					WHERE user_uid = ? AND match_type = ? AND DATE(created_at) = CURDATE()) +
+				(SELECT COUNT(*) FROM user_rewinds
+					WHERE user_uid = ? AND match_type = ? AND DATE(swiped_at) = CURDATE())`
+
+	err = u.queryRow(ctx, tx, query, userUID, constant.UserMatchTypeSuperLike, userUID, constant.UserMatchTypeSuperLike).Scan(&total)
 	if err != nil {
 		err = derrors.HandleSQLError(err, "QueryRowContext")
 		return
@@ -174,3 +442,37 @@ func (u *userMatchRepository) GetUserMatchTodayByUserUIDAndMatchUID(ctx context.
 
 	return userMatch, nil
 }
+
+// GetUserMatchHistory returns every swipe made by or on the user, oldest first.
+func (u *userMatchRepository) GetUserMatchHistory(ctx context.Context, userUID string, page, limit uint64) (userMatches []*model.UserMatch, err error) {
+	defer derrors.Wrap(&err, "GetUserMatchHistory(%q)", userUID)
+
+	query := `SELECT user_uid, match_uid, match_type, created_at FROM user_matches
+			WHERE user_uid = ? OR match_uid = ?
+			ORDER BY created_at, id
+			LIMIT ?,?`
+
+	args := []interface{}{
+		userUID,
+		userUID,
+		u.GetOffset(page, limit), limit,
+	}
+
+	userMatches = []*model.UserMatch{}
+
+	rows, err := u.Slave().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		userMatch := &model.UserMatch{}
+		if err = rows.Scan(&userMatch.UserUID, &userMatch.MatchUID, &userMatch.MatchType, &userMatch.CreatedAt); err != nil {
+			return nil, derrors.HandleSQLError(err, "Scan")
+		}
+		userMatches = append(userMatches, userMatch)
+	}
+
+	return userMatches, nil
+}
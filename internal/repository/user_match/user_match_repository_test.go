@@ -0,0 +1,192 @@
+package usermatchrepository_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"date-apps-be/infrastructure/database"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	usermatchrepository "date-apps-be/internal/repository/user_match"
+	"date-apps-be/internal/usecase/user_match/dto"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestDB creates a throwaway database on the MySQL server of TEST_MYSQL_DSN, migrates it
+// and loads the fixture. The database named in the DSN is never touched, the DSN needs
+// multiStatements=true, e.g. root:root@tcp(localhost:3306)/?multiStatements=true.
+// The throwaway database is dropped again when the test ends.
+func openTestDB(t *testing.T, fixture string) *sql.DB {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MYSQL_DSN is not set")
+	}
+
+	conf, err := mysql.ParseDSN(dsn)
+	require.NoError(t, err)
+
+	conf.DBName = ""
+	server, err := sql.Open("mysql", conf.FormatDSN())
+	require.NoError(t, err)
+
+	conf.DBName = "date_apps_test_" + strings.ToLower(ksuid.New().String())
+	_, err = server.Exec("CREATE DATABASE `" + conf.DBName + "`")
+	require.NoError(t, err)
+
+	db, err := sql.Open("mysql", conf.FormatDSN())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = db.Close()
+		_, err := server.Exec("DROP DATABASE `" + conf.DBName + "`")
+		assert.NoError(t, err)
+		_ = server.Close()
+	})
+
+	ups, err := filepath.Glob(filepath.Join("../../../infrastructure/database/migrations", "*.up.sql"))
+	require.NoError(t, err)
+	sort.Strings(ups)
+
+	for _, up := range append(ups, fixture) {
+		query, err := os.ReadFile(up)
+		require.NoError(t, err)
+		_, err = db.Exec(string(query))
+		require.NoError(t, err, up)
+	}
+
+	return db
+}
+
+func TestGetAvailableUsers(t *testing.T) {
+	db := openTestDB(t, "testdata/discovery.sql")
+	repo := usermatchrepository.NewUserMatchRepository(repository.NewRepository(&database.DB{Master: db, Slave: db}))
+	ctx := context.Background()
+
+	users, err := repo.GetAvailableUsers(ctx, dto.GetAvailableUsers{UserUID: "me", Page: 1, Limit: 50})
+	require.NoError(t, err)
+
+	distances := map[string]int{}
+	for _, user := range users {
+		require.NotNil(t, user.DistanceKM, user.UID)
+		distances[user.UID] = *user.DistanceKM
+	}
+	assert.Equal(t, map[string]int{"near_man": 50, "near_non_binary": 25}, distances)
+
+	// near_non_binary super-liked me and comes first
+	assert.Equal(t, "near_non_binary", users[0].UID)
+	assert.True(t, users[0].SuperLiked)
+	assert.False(t, users[1].SuperLiked)
+
+	// the geohash cells only narrow the search down, the result is the same
+	maxDistanceKM := 50
+	var origin struct{ latitude, longitude float64 }
+	require.NoError(t, db.QueryRow(`SELECT latitude, longitude FROM user_locations WHERE user_uid = 'me'`).Scan(&origin.latitude, &origin.longitude))
+
+	users, err = repo.GetAvailableUsers(ctx, dto.GetAvailableUsers{
+		UserUID: "me", Page: 1, Limit: 50,
+		Origin:        &model.UserLocation{UserUID: "me", Latitude: origin.latitude, Longitude: origin.longitude},
+		MaxDistanceKM: &maxDistanceKM,
+	})
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	// near_man sees me as well, the deck is two-way
+	users, err = repo.GetAvailableUsers(ctx, dto.GetAvailableUsers{UserUID: "near_man", Page: 1, Limit: 50})
+	require.NoError(t, err)
+
+	uids := []string{}
+	for _, user := range users {
+		uids = append(uids, user.UID)
+	}
+	assert.Contains(t, uids, "me")
+	assert.NotContains(t, uids, "wants_closer", "wants_closer is 22 km from near_man and wants at most 10 km")
+	assert.NotContains(t, uids, "banned")
+
+	// near_man unmatched me, neither of us sees the other again
+	_, err = db.Exec(`INSERT INTO hidden_pairs (user_uid, match_uid, hidden_by) VALUES (LEAST('me', 'near_man'), GREATEST('me', 'near_man'), 'near_man')`)
+	require.NoError(t, err)
+
+	users, err = repo.GetAvailableUsers(ctx, dto.GetAvailableUsers{UserUID: "me", Page: 1, Limit: 50})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "near_non_binary", users[0].UID)
+
+	users, err = repo.GetAvailableUsers(ctx, dto.GetAvailableUsers{UserUID: "near_man", Page: 1, Limit: 50})
+	require.NoError(t, err)
+	for _, user := range users {
+		assert.NotEqual(t, "me", user.UID)
+	}
+}
+
+func TestGetLikesReceived(t *testing.T) {
+	db := openTestDB(t, "testdata/discovery.sql")
+	repo := usermatchrepository.NewUserMatchRepository(repository.NewRepository(&database.DB{Master: db, Slave: db}))
+	ctx := context.Background()
+
+	// me already swiped on swiped and banned users are left out
+	users, err := repo.GetLikesReceived(ctx, "me", 1, 50)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "near_non_binary", users[0].UID)
+	assert.True(t, users[0].SuperLiked)
+
+	total, err := repo.GetTotalLikesReceived(ctx, "me")
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+}
+
+func TestGetTotalSuperLikeToday(t *testing.T) {
+	db := openTestDB(t, "testdata/discovery.sql")
+	repo := usermatchrepository.NewUserMatchRepository(repository.NewRepository(&database.DB{Master: db, Slave: db}))
+	ctx := context.Background()
+
+	total, err := repo.GetTotalSuperLikeToday(ctx, nil, "near_non_binary")
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	// a rewound super-like still counts, a rewound like does not
+	_, err = db.Exec(`INSERT INTO user_rewinds (user_uid, match_uid, match_type, swiped_at) VALUES
+		('near_non_binary', 'near_man', 'super_like', NOW()),
+		('near_non_binary', 'swiped', 'like', NOW())`)
+	require.NoError(t, err)
+
+	total, err = repo.GetTotalSuperLikeToday(ctx, nil, "near_non_binary")
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+}
+
+func TestGetUserMatches(t *testing.T) {
+	db := openTestDB(t, "testdata/discovery.sql")
+	repo := usermatchrepository.NewUserMatchRepository(repository.NewRepository(&database.DB{Master: db, Slave: db}))
+	ctx := context.Background()
+
+	_, err := db.Exec(`INSERT INTO users (uid, name, email, gender, birthdate, status, suspended_until, deleted_at) VALUES
+		('suspended', 'Suspended', 'suspended@example.com', 'male', DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'suspended', NOW() + INTERVAL 1 DAY, NULL),
+		('deleted', 'Deleted', 'deleted@example.com', 'male', DATE_SUB(CURDATE(), INTERVAL 32 YEAR), 'active', NULL, NOW())`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO user_matches (user_uid, match_uid, match_type) VALUES
+		('me', 'banned', 'like'), ('me', 'suspended', 'like'), ('me', 'deleted', 'pass')`)
+	require.NoError(t, err)
+
+	// banned, suspended and deleted users are hidden
+	userMatches, err := repo.GetUserMatches(ctx, dto.GetUserMatches{UserUID: "me", Page: 1, Limit: 50})
+	require.NoError(t, err)
+	require.Len(t, userMatches, 1)
+	assert.Equal(t, "swiped", userMatches[0].Match.UID)
+	assert.Equal(t, "Swiped", userMatches[0].Match.Name)
+	assert.False(t, userMatches[0].CreatedAt.IsNil())
+
+	userMatches, err = repo.GetUserMatches(ctx, dto.GetUserMatches{UserUID: "me", Page: 1, Limit: 50, MatchType: constant.UserMatchTypePass})
+	require.NoError(t, err)
+	assert.Empty(t, userMatches)
+}
@@ -0,0 +1,206 @@
+package usermfarepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+	"strings"
+)
+
+type (
+	userMFARepository struct {
+		repository.Repository
+	}
+
+	// UserMFARepository stores the TOTP enrollment of users and their recovery codes.
+	UserMFARepository interface {
+		repository.Repository
+		UpsertUserMFA(ctx context.Context, tx *sql.Tx, userMFA *model.UserMFA) (err error)
+		GetUserMFA(ctx context.Context, userUID string) (userMFA *model.UserMFA, err error)
+		EnableUserMFA(ctx context.Context, userUID string, step int64) (err error)
+		UpdateLastUsedStep(ctx context.Context, userUID string, step int64) (updated bool, err error)
+		DeleteUserMFA(ctx context.Context, tx *sql.Tx, userUID string) (err error)
+		CreateRecoveryCodes(ctx context.Context, tx *sql.Tx, userUID string, codeHashes []string) (err error)
+		DeleteRecoveryCodes(ctx context.Context, tx *sql.Tx, userUID string) (err error)
+		UseRecoveryCode(ctx context.Context, userUID, codeHash string) (used bool, err error)
+	}
+)
+
+func NewUserMFARepository(store repository.Repository) UserMFARepository {
+	return &userMFARepository{
+		Repository: store,
+	}
+}
+
+func (r *userMFARepository) getDest(userMFA *model.UserMFA) []interface{} {
+	return []interface{}{
+		&userMFA.UserUID,
+		&userMFA.Secret,
+		&userMFA.EnabledAt,
+		&userMFA.LastUsedStep,
+	}
+}
+
+// UpsertUserMFA starts a new enrollment, a previous unconfirmed enrollment is replaced.
+func (r *userMFARepository) UpsertUserMFA(ctx context.Context, tx *sql.Tx, userMFA *model.UserMFA) (err error) {
+	defer derrors.Wrap(&err, "UpsertUserMFA(%q)", userMFA.UserUID)
+
+	query := `INSERT INTO user_mfa (user_uid, secret) VALUES (?, ?)
+			ON DUPLICATE KEY UPDATE secret = VALUES(secret), enabled_at = NULL, last_used_step = 0`
+	args := []interface{}{
+		userMFA.UserUID,
+		userMFA.Secret,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *userMFARepository) GetUserMFA(ctx context.Context, userUID string) (userMFA *model.UserMFA, err error) {
+	defer derrors.Wrap(&err, "GetUserMFA(%q)", userUID)
+
+	query := `SELECT user_uid, secret, enabled_at, last_used_step FROM user_mfa WHERE user_uid = ?`
+
+	userMFA = &model.UserMFA{}
+	args := []interface{}{
+		userUID,
+	}
+
+	err = r.Query(ctx, query, r.getDest(userMFA), args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return userMFA, nil
+}
+
+func (r *userMFARepository) EnableUserMFA(ctx context.Context, userUID string, step int64) (err error) {
+	defer derrors.Wrap(&err, "EnableUserMFA(%q)", userUID)
+
+	query := `UPDATE user_mfa SET enabled_at = NOW(), last_used_step = ? WHERE user_uid = ?`
+	args := []interface{}{
+		step,
+		userUID,
+	}
+
+	_, err = r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// UpdateLastUsedStep records an accepted TOTP step, updated is false
+// when the step or a later one was already used.
+func (r *userMFARepository) UpdateLastUsedStep(ctx context.Context, userUID string, step int64) (updated bool, err error) {
+	defer derrors.Wrap(&err, "UpdateLastUsedStep(%q)", userUID)
+
+	query := `UPDATE user_mfa SET last_used_step = ? WHERE user_uid = ? AND last_used_step < ?`
+	args := []interface{}{
+		step,
+		userUID,
+		step,
+	}
+
+	result, err := r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+	}
+
+	return affected > 0, nil
+}
+
+func (r *userMFARepository) DeleteUserMFA(ctx context.Context, tx *sql.Tx, userUID string) (err error) {
+	defer derrors.Wrap(&err, "DeleteUserMFA(%q)", userUID)
+
+	query := `DELETE FROM user_mfa WHERE user_uid = ?`
+	args := []interface{}{
+		userUID,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *userMFARepository) CreateRecoveryCodes(ctx context.Context, tx *sql.Tx, userUID string, codeHashes []string) (err error) {
+	defer derrors.Wrap(&err, "CreateRecoveryCodes(%q)", userUID)
+
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(codeHashes))
+	args := make([]interface{}, 0, len(codeHashes)*2)
+	for _, codeHash := range codeHashes {
+		values = append(values, "(?, ?)")
+		args = append(args, userUID, codeHash)
+	}
+
+	query := `INSERT INTO mfa_recovery_codes (user_uid, code_hash) VALUES ` + strings.Join(values, ", ")
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *userMFARepository) DeleteRecoveryCodes(ctx context.Context, tx *sql.Tx, userUID string) (err error) {
+	defer derrors.Wrap(&err, "DeleteRecoveryCodes(%q)", userUID)
+
+	query := `DELETE FROM mfa_recovery_codes WHERE user_uid = ?`
+	args := []interface{}{
+		userUID,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks a recovery code as used, used is false when the code
+// does not exist or was already used.
+func (r *userMFARepository) UseRecoveryCode(ctx context.Context, userUID, codeHash string) (used bool, err error) {
+	defer derrors.Wrap(&err, "UseRecoveryCode(%q)", userUID)
+
+	query := `UPDATE mfa_recovery_codes SET used_at = NOW() WHERE user_uid = ? AND code_hash = ? AND used_at IS NULL`
+	args := []interface{}{
+		userUID,
+		codeHash,
+	}
+
+	result, err := r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+	}
+
+	return affected > 0, nil
+}
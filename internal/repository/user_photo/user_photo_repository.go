@@ -0,0 +1,211 @@
+package userphotorepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+	"strings"
+)
+
+type (
+	userPhotoRepository struct {
+		repository.Repository
+	}
+
+	// UserPhotoRepository stores the profile photos of users, the files are in the blob store.
+	UserPhotoRepository interface {
+		repository.Repository
+		CreateUserPhoto(ctx context.Context, tx *sql.Tx, photo *model.UserPhoto) (err error)
+		GetUserPhoto(ctx context.Context, uid string) (photo *model.UserPhoto, err error)
+		GetUserPhotos(ctx context.Context, userUID string) (photos []*model.UserPhoto, err error)
+		GetUserPhotosByUserUIDs(ctx context.Context, userUIDs []string) (photos []*model.UserPhoto, err error)
+		LockUserPhotos(ctx context.Context, tx *sql.Tx, userUID string) (photos []*model.UserPhoto, err error)
+		UpdateUserPhotoPosition(ctx context.Context, tx *sql.Tx, uid string, position int, isPrimary bool) (err error)
+		DeleteUserPhoto(ctx context.Context, tx *sql.Tx, uid string) (err error)
+	}
+)
+
+const userPhotoColumns = `p.uid, p.user_uid, p.position, p.is_primary, p.content_type, p.blob_key, p.thumbnail_key, p.width, p.height, p.size, p.created_at`
+
+func NewUserPhotoRepository(store repository.Repository) UserPhotoRepository {
+	return &userPhotoRepository{
+		Repository: store,
+	}
+}
+
+func (r *userPhotoRepository) getDest(photo *model.UserPhoto) []interface{} {
+	return []interface{}{
+		&photo.UID,
+		&photo.UserUID,
+		&photo.Position,
+		&photo.IsPrimary,
+		&photo.ContentType,
+		&photo.BlobKey,
+		&photo.ThumbnailKey,
+		&photo.Width,
+		&photo.Height,
+		&photo.Size,
+		&photo.CreatedAt,
+	}
+}
+
+func (r *userPhotoRepository) CreateUserPhoto(ctx context.Context, tx *sql.Tx, photo *model.UserPhoto) (err error) {
+	defer derrors.Wrap(&err, "CreateUserPhoto(%q)", photo.UID)
+
+	query := `INSERT INTO user_photos (uid, user_uid, position, is_primary, content_type, blob_key, thumbnail_key, width, height, size)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	args := []interface{}{
+		photo.UID,
+		photo.UserUID,
+		photo.Position,
+		photo.IsPrimary,
+		photo.ContentType,
+		photo.BlobKey,
+		photo.ThumbnailKey,
+		photo.Width,
+		photo.Height,
+		photo.Size,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// GetUserPhoto returns a photo that can be shown to other users,
+// photos of deleted and banned users are not returned.
+func (r *userPhotoRepository) GetUserPhoto(ctx context.Context, uid string) (photo *model.UserPhoto, err error) {
+	defer derrors.Wrap(&err, "GetUserPhoto(%q)", uid)
+
+	query := `SELECT ` + userPhotoColumns + ` FROM user_photos p
+			JOIN users u ON u.uid = p.user_uid
+			WHERE p.uid = ? AND u.deleted_at IS NULL AND u.status != ?`
+	photo = &model.UserPhoto{}
+	args := []interface{}{
+		uid,
+		constant.UserStatusBanned,
+	}
+
+	err = r.Query(ctx, query, r.getDest(photo), args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return photo, nil
+}
+
+// GetUserPhotos returns the photos of the user in display order.
+func (r *userPhotoRepository) GetUserPhotos(ctx context.Context, userUID string) (photos []*model.UserPhoto, err error) {
+	defer derrors.Wrap(&err, "GetUserPhotos(%q)", userUID)
+
+	query := `SELECT ` + userPhotoColumns + ` FROM user_photos p WHERE p.user_uid = ? ORDER BY p.position, p.id`
+
+	rows, err := r.Slave().QueryContext(ctx, query, userUID)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+
+	return r.scan(rows)
+}
+
+// GetUserPhotosByUserUIDs returns the photos of many users at once, in display order per user.
+func (r *userPhotoRepository) GetUserPhotosByUserUIDs(ctx context.Context, userUIDs []string) (photos []*model.UserPhoto, err error) {
+	defer derrors.Wrap(&err, "GetUserPhotosByUserUIDs(%d)", len(userUIDs))
+
+	if len(userUIDs) == 0 {
+		return []*model.UserPhoto{}, nil
+	}
+
+	query := `SELECT ` + userPhotoColumns + ` FROM user_photos p
+			WHERE p.user_uid IN (?` + strings.Repeat(`, ?`, len(userUIDs)-1) + `)
+			ORDER BY p.user_uid, p.position, p.id`
+
+	args := make([]interface{}, 0, len(userUIDs))
+	for _, userUID := range userUIDs {
+		args = append(args, userUID)
+	}
+
+	rows, err := r.Slave().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+
+	return r.scan(rows)
+}
+
+// LockUserPhotos returns the photos of the user in display order and locks them until the
+// transaction ends, so concurrent uploads and reorders of the same user run one after another.
+func (r *userPhotoRepository) LockUserPhotos(ctx context.Context, tx *sql.Tx, userUID string) (photos []*model.UserPhoto, err error) {
+	defer derrors.Wrap(&err, "LockUserPhotos(%q)", userUID)
+
+	query := `SELECT ` + userPhotoColumns + ` FROM user_photos p WHERE p.user_uid = ? ORDER BY p.position, p.id FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, userUID)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+
+	return r.scan(rows)
+}
+
+func (r *userPhotoRepository) scan(rows *sql.Rows) (photos []*model.UserPhoto, err error) {
+	defer rows.Close()
+
+	photos = []*model.UserPhoto{}
+	for rows.Next() {
+		photo := &model.UserPhoto{}
+		if err = rows.Scan(r.getDest(photo)...); err != nil {
+			return nil, derrors.HandleSQLError(err, "Scan")
+		}
+		photos = append(photos, photo)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, derrors.HandleSQLError(err, "rows.Err")
+	}
+
+	return photos, nil
+}
+
+func (r *userPhotoRepository) UpdateUserPhotoPosition(ctx context.Context, tx *sql.Tx, uid string, position int, isPrimary bool) (err error) {
+	defer derrors.Wrap(&err, "UpdateUserPhotoPosition(%q, %d)", uid, position)
+
+	query := `UPDATE user_photos SET position = ?, is_primary = ? WHERE uid = ?`
+	args := []interface{}{
+		position,
+		isPrimary,
+		uid,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *userPhotoRepository) DeleteUserPhoto(ctx context.Context, tx *sql.Tx, uid string) (err error) {
+	defer derrors.Wrap(&err, "DeleteUserPhoto(%q)", uid)
+
+	query := `DELETE FROM user_photos WHERE uid = ?`
+	args := []interface{}{
+		uid,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
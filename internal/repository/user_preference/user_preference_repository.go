@@ -0,0 +1,84 @@
+package userpreferencerepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	userPreferenceRepository struct {
+		repository.Repository
+	}
+
+	// UserPreferenceRepository stores the discovery preferences of users.
+	UserPreferenceRepository interface {
+		repository.Repository
+		GetUserPreference(ctx context.Context, userUID string) (preference *model.UserPreference, err error)
+		UpsertUserPreference(ctx context.Context, preference *model.UserPreference) (err error)
+	}
+)
+
+func NewUserPreferenceRepository(store repository.Repository) UserPreferenceRepository {
+	return &userPreferenceRepository{
+		Repository: store,
+	}
+}
+
+func (r *userPreferenceRepository) getDest(preference *model.UserPreference) []interface{} {
+	return []interface{}{
+		&preference.UserUID,
+		&preference.MinAge,
+		&preference.MaxAge,
+		&preference.Genders,
+		&preference.MaxDistanceKM,
+		&preference.UpdatedAt,
+	}
+}
+
+// GetUserPreference returns nil when the user has not set any preference.
+func (r *userPreferenceRepository) GetUserPreference(ctx context.Context, userUID string) (preference *model.UserPreference, err error) {
+	defer derrors.Wrap(&err, "GetUserPreference(%q)", userUID)
+
+	query := `SELECT user_uid, min_age, max_age, genders, max_distance_km, updated_at FROM user_preferences WHERE user_uid = ?`
+
+	preference = &model.UserPreference{}
+	args := []interface{}{
+		userUID,
+	}
+
+	err = r.Query(ctx, query, r.getDest(preference), args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return preference, nil
+}
+
+// UpsertUserPreference replaces every preference of the user.
+func (r *userPreferenceRepository) UpsertUserPreference(ctx context.Context, preference *model.UserPreference) (err error) {
+	defer derrors.Wrap(&err, "UpsertUserPreference(%q)", preference.UserUID)
+
+	query := `INSERT INTO user_preferences (user_uid, min_age, max_age, genders, max_distance_km) VALUES (?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE min_age = VALUES(min_age), max_age = VALUES(max_age),
+			genders = VALUES(genders), max_distance_km = VALUES(max_distance_km)`
+	args := []interface{}{
+		preference.UserUID,
+		preference.MinAge,
+		preference.MaxAge,
+		preference.Genders,
+		preference.MaxDistanceKM,
+	}
+
+	_, err = r.Exec(ctx, nil, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
@@ -12,6 +12,7 @@ type UserPremiumRepository interface {
 	repository.Repository
 	CreateUserPackage(ctx context.Context, tx *sql.Tx, userPackage *model.UserPackage) (err error)
 	GetUserPackage(ctx context.Context, userUID string) (userPackage *model.UserPackage, err error)
+	GetUserPackages(ctx context.Context, userUID string) (userPackages []*model.UserPackage, err error)
 }
 
 type userPremiumRepository struct {
@@ -34,6 +35,8 @@ func (u *userPremiumRepository) getDest(userPremium *model.UserPackage) []interf
 		&userPremium.PremiumConfig.Price,
 		&userPremium.PremiumConfig.Quota,
 		&userPremium.PremiumConfig.ExpiredDay,
+		&userPremium.PremiumConfig.SuperLikeQuota,
+		&userPremium.PremiumConfig.RewindQuota,
 		&userPremium.StartedAt,
 		&userPremium.EndedAt,
 		&userPremium.Quota,
@@ -51,6 +54,8 @@ func (u *userPremiumRepository) GetUserPackage(ctx context.Context, userUID stri
 		pc.price, 
 		pc.quota, 
 		pc.expired_day, 
+		pc.super_like_quota, 
+		pc.rewind_quota, 
 		up.started_at, 
 		up.ended_at, 
 		up.quota
@@ -79,6 +84,37 @@ func (u *userPremiumRepository) GetUserPackage(ctx context.Context, userUID stri
 	return userPackage, nil
 }
 
+// GetUserPackages returns every package the user ever bought, oldest first.
+func (u *userPremiumRepository) GetUserPackages(ctx context.Context, userUID string) (userPackages []*model.UserPackage, err error) {
+	defer derrors.Wrap(&err, "GetUserPackages(%q)", userUID)
+
+	query := `SELECT up.uid, up.user_uid, up.premium_config_uid, pc.name, pc.description, pc.price, pc.quota, pc.expired_day, pc.super_like_quota, pc.rewind_quota, up.started_at, up.ended_at, up.quota
+		FROM user_premium up
+		JOIN premium_config pc ON up.premium_config_uid = pc.uid
+		WHERE up.user_uid = ?
+		ORDER BY up.started_at, up.id`
+
+	userPackages = []*model.UserPackage{}
+
+	rows, err := u.Slave().QueryContext(ctx, query, userUID)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		userPackage := &model.UserPackage{
+			PremiumConfig: &model.PremiumConfig{},
+		}
+		if err = rows.Scan(u.getDest(userPackage)...); err != nil {
+			return nil, derrors.HandleSQLError(err, "Scan")
+		}
+		userPackages = append(userPackages, userPackage)
+	}
+
+	return userPackages, nil
+}
+
 func (u *userPremiumRepository) CreateUserPackage(ctx context.Context, tx *sql.Tx, userPackage *model.UserPackage) (err error) {
 	defer derrors.Wrap(&err, "CreateUserPackage(%v)", userPackage)
 
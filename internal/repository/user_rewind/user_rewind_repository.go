@@ -0,0 +1,95 @@
+package userrewindrepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	userRewindRepository struct {
+		repository.Repository
+	}
+
+	// UserRewindRepository stores the swipes users undid.
+	UserRewindRepository interface {
+		repository.Repository
+		CreateUserRewind(ctx context.Context, tx *sql.Tx, rewind *model.UserRewind) (err error)
+		GetTotalUserRewindToday(ctx context.Context, tx *sql.Tx, userUID string) (total int, err error)
+		GetUserRewinds(ctx context.Context, userUID string, page, limit uint64) (rewinds []*model.UserRewind, err error)
+	}
+)
+
+func NewUserRewindRepository(store repository.Repository) UserRewindRepository {
+	return &userRewindRepository{
+		Repository: store,
+	}
+}
+
+func (r *userRewindRepository) CreateUserRewind(ctx context.Context, tx *sql.Tx, rewind *model.UserRewind) (err error) {
+	defer derrors.Wrap(&err, "CreateUserRewind(%q, %q)", rewind.UserUID, rewind.MatchUID)
+
+	query := `INSERT INTO user_rewinds (user_uid, match_uid, match_type, swiped_at) VALUES (?, ?, ?, ?)`
+	args := []interface{}{
+		rewind.UserUID,
+		rewind.MatchUID,
+		rewind.MatchType,
+		&rewind.SwipedAt,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.HandleSQLError(err, "r.Exec")
+	}
+
+	return nil
+}
+
+// GetTotalUserRewindToday reads in tx so the count is current once the user is locked.
+func (r *userRewindRepository) GetTotalUserRewindToday(ctx context.Context, tx *sql.Tx, userUID string) (total int, err error) {
+	defer derrors.Wrap(&err, "GetTotalUserRewindToday(%q)", userUID)
+
+	query := `SELECT COUNT(*) FROM user_rewinds
+			WHERE user_uid = ? AND DATE(created_at) = CURDATE()`
+
+	err = tx.QueryRowContext(ctx, query, userUID).Scan(&total)
+	if err != nil {
+		return 0, derrors.HandleSQLError(err, "QueryRowContext")
+	}
+
+	return total, nil
+}
+
+// GetUserRewinds returns every swipe the user undid, oldest first.
+func (r *userRewindRepository) GetUserRewinds(ctx context.Context, userUID string, page, limit uint64) (rewinds []*model.UserRewind, err error) {
+	defer derrors.Wrap(&err, "GetUserRewinds(%q)", userUID)
+
+	query := `SELECT user_uid, match_uid, match_type, swiped_at, created_at FROM user_rewinds
+			WHERE user_uid = ?
+			ORDER BY created_at, id
+			LIMIT ?,?`
+
+	args := []interface{}{
+		userUID,
+		r.GetOffset(page, limit), limit,
+	}
+
+	rows, err := r.Slave().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	rewinds = []*model.UserRewind{}
+	for rows.Next() {
+		rewind := &model.UserRewind{}
+		if err = rows.Scan(&rewind.UserUID, &rewind.MatchUID, &rewind.MatchType, &rewind.SwipedAt, &rewind.CreatedAt); err != nil {
+			return nil, derrors.HandleSQLError(err, "Scan")
+		}
+		rewinds = append(rewinds, rewind)
+	}
+
+	return rewinds, nil
+}
@@ -0,0 +1,95 @@
+package userrolerepository
+
+import (
+	"context"
+	"database/sql"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	userRoleRepository struct {
+		repository.Repository
+	}
+
+	// UserRoleRepository stores the elevated roles of users.
+	UserRoleRepository interface {
+		repository.Repository
+		GetUserRoles(ctx context.Context, userUID string) (roles []string, err error)
+		AddUserRole(ctx context.Context, tx *sql.Tx, userUID, role string) (err error)
+		DeleteUserRole(ctx context.Context, tx *sql.Tx, userUID, role string) (deleted bool, err error)
+	}
+)
+
+func NewUserRoleRepository(store repository.Repository) UserRoleRepository {
+	return &userRoleRepository{
+		Repository: store,
+	}
+}
+
+// GetUserRoles reads from master, a granted role is used by the next issued token.
+func (r *userRoleRepository) GetUserRoles(ctx context.Context, userUID string) (roles []string, err error) {
+	defer derrors.Wrap(&err, "GetUserRoles(%q)", userUID)
+
+	query := `SELECT role FROM user_roles WHERE user_uid = ? ORDER BY role`
+
+	rows, err := r.Master().QueryContext(ctx, query, userUID)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	roles = []string{}
+	for rows.Next() {
+		var role string
+		if err = rows.Scan(&role); err != nil {
+			return nil, derrors.WrapStack(err, derrors.Unknown, "rows.Scan")
+		}
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "rows.Err")
+	}
+
+	return roles, nil
+}
+
+func (r *userRoleRepository) AddUserRole(ctx context.Context, tx *sql.Tx, userUID, role string) (err error) {
+	defer derrors.Wrap(&err, "AddUserRole(%q, %q)", userUID, role)
+
+	query := `INSERT IGNORE INTO user_roles (user_uid, role) VALUES (?, ?)`
+	args := []interface{}{
+		userUID,
+		role,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *userRoleRepository) DeleteUserRole(ctx context.Context, tx *sql.Tx, userUID, role string) (deleted bool, err error) {
+	defer derrors.Wrap(&err, "DeleteUserRole(%q, %q)", userUID, role)
+
+	query := `DELETE FROM user_roles WHERE user_uid = ? AND role = ?`
+	args := []interface{}{
+		userUID,
+		role,
+	}
+
+	result, err := r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,178 @@
+package usersessionrepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	userSessionRepository struct {
+		repository.Repository
+	}
+
+	// UserSessionRepository stores the logins of users per device.
+	UserSessionRepository interface {
+		repository.Repository
+		CreateUserSession(ctx context.Context, tx *sql.Tx, session *model.UserSession) (err error)
+		GetUserSession(ctx context.Context, uid string) (session *model.UserSession, err error)
+		GetActiveUserSessions(ctx context.Context, userUID string) (sessions []*model.UserSession, err error)
+		TouchUserSession(ctx context.Context, tx *sql.Tx, uid string, expiresAt datatype.Time) (err error)
+		RevokeUserSession(ctx context.Context, tx *sql.Tx, uid string) (revoked bool, err error)
+		RevokeUserSessions(ctx context.Context, tx *sql.Tx, userUID string) (err error)
+	}
+)
+
+const userSessionColumns = `uid, user_uid, device_id, user_agent, ip_address, last_seen_at, expires_at, revoked_at, created_at`
+
+func NewUserSessionRepository(store repository.Repository) UserSessionRepository {
+	return &userSessionRepository{
+		Repository: store,
+	}
+}
+
+func (r *userSessionRepository) getDest(session *model.UserSession) []interface{} {
+	return []interface{}{
+		&session.UID,
+		&session.UserUID,
+		&session.DeviceID,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+		&session.CreatedAt,
+	}
+}
+
+func (r *userSessionRepository) CreateUserSession(ctx context.Context, tx *sql.Tx, session *model.UserSession) (err error) {
+	defer derrors.Wrap(&err, "CreateUserSession(%q)", session.UID)
+
+	query := `INSERT INTO user_sessions (uid, user_uid, device_id, user_agent, ip_address, expires_at) VALUES (?, ?, ?, ?, ?, ?)`
+	args := []interface{}{
+		session.UID,
+		session.UserUID,
+		r.NewNullString(session.DeviceID),
+		r.NewNullString(session.UserAgent),
+		session.IPAddress,
+		&session.ExpiresAt,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+func (r *userSessionRepository) GetUserSession(ctx context.Context, uid string) (session *model.UserSession, err error) {
+	defer derrors.Wrap(&err, "GetUserSession(%q)", uid)
+
+	query := `SELECT ` + userSessionColumns + ` FROM user_sessions WHERE uid = ?`
+	session = &model.UserSession{}
+	args := []interface{}{
+		uid,
+	}
+
+	err = r.Query(ctx, query, r.getDest(session), args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, derrors.HandleSQLError(err, "r.Query")
+	}
+
+	return session, nil
+}
+
+// GetActiveUserSessions returns the sessions that are neither revoked nor expired,
+// the most recently used first.
+func (r *userSessionRepository) GetActiveUserSessions(ctx context.Context, userUID string) (sessions []*model.UserSession, err error) {
+	defer derrors.Wrap(&err, "GetActiveUserSessions(%q)", userUID)
+
+	query := `SELECT ` + userSessionColumns + ` FROM user_sessions
+		WHERE user_uid = ? AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC`
+
+	rows, err := r.Slave().QueryContext(ctx, query, userUID)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	sessions = []*model.UserSession{}
+	for rows.Next() {
+		session := &model.UserSession{}
+		if err = rows.Scan(r.getDest(session)...); err != nil {
+			return nil, derrors.WrapStack(err, derrors.Unknown, "rows.Scan")
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "rows.Err")
+	}
+
+	return sessions, nil
+}
+
+// TouchUserSession records that the session was used and extends it
+// to the expiry of its newest refresh token.
+func (r *userSessionRepository) TouchUserSession(ctx context.Context, tx *sql.Tx, uid string, expiresAt datatype.Time) (err error) {
+	defer derrors.Wrap(&err, "TouchUserSession(%q)", uid)
+
+	query := `UPDATE user_sessions SET last_seen_at = NOW(), expires_at = ? WHERE uid = ? AND revoked_at IS NULL`
+	args := []interface{}{
+		&expiresAt,
+		uid,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// RevokeUserSession marks the session as revoked, revoked is false when it already was.
+func (r *userSessionRepository) RevokeUserSession(ctx context.Context, tx *sql.Tx, uid string) (revoked bool, err error) {
+	defer derrors.Wrap(&err, "RevokeUserSession(%q)", uid)
+
+	query := `UPDATE user_sessions SET revoked_at = NOW() WHERE uid = ? AND revoked_at IS NULL`
+	args := []interface{}{
+		uid,
+	}
+
+	result, err := r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "result.RowsAffected")
+	}
+
+	return affected > 0, nil
+}
+
+func (r *userSessionRepository) RevokeUserSessions(ctx context.Context, tx *sql.Tx, userUID string) (err error) {
+	defer derrors.Wrap(&err, "RevokeUserSessions(%q)", userUID)
+
+	query := `UPDATE user_sessions SET revoked_at = NOW() WHERE user_uid = ? AND revoked_at IS NULL`
+	args := []interface{}{
+		userUID,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
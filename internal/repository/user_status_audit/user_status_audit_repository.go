@@ -0,0 +1,92 @@
+package userstatusauditrepository
+
+import (
+	"context"
+	"database/sql"
+	"date-apps-be/internal/model"
+	repository "date-apps-be/internal/repository/common"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	userStatusAuditRepository struct {
+		repository.Repository
+	}
+
+	// UserStatusAuditRepository is the trail of every status change of users.
+	UserStatusAuditRepository interface {
+		repository.Repository
+		CreateUserStatusAudit(ctx context.Context, tx *sql.Tx, audit *model.UserStatusAudit) (err error)
+		GetUserStatusAudits(ctx context.Context, userUID string, page, limit uint64) (audits []*model.UserStatusAudit, err error)
+	}
+)
+
+func NewUserStatusAuditRepository(store repository.Repository) UserStatusAuditRepository {
+	return &userStatusAuditRepository{
+		Repository: store,
+	}
+}
+
+func (r *userStatusAuditRepository) getDest(audit *model.UserStatusAudit) []interface{} {
+	return []interface{}{
+		&audit.UID,
+		&audit.UserUID,
+		&audit.ActorUID,
+		&audit.PreviousStatus,
+		&audit.Status,
+		&audit.Reason,
+		&audit.SuspendedUntil,
+		&audit.CreatedAt,
+	}
+}
+
+func (r *userStatusAuditRepository) CreateUserStatusAudit(ctx context.Context, tx *sql.Tx, audit *model.UserStatusAudit) (err error) {
+	defer derrors.Wrap(&err, "CreateUserStatusAudit(%q)", audit.UserUID)
+
+	query := `INSERT INTO user_status_audits (uid, user_uid, actor_uid, previous_status, status, reason, suspended_until) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	args := []interface{}{
+		audit.UID,
+		audit.UserUID,
+		audit.ActorUID,
+		audit.PreviousStatus,
+		audit.Status,
+		r.NewNullString(audit.Reason),
+		&audit.SuspendedUntil,
+	}
+
+	_, err = r.Exec(ctx, tx, query, args)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "r.Exec")
+	}
+
+	return nil
+}
+
+// GetUserStatusAudits returns the status changes of a user, newest first.
+func (r *userStatusAuditRepository) GetUserStatusAudits(ctx context.Context, userUID string, page, limit uint64) (audits []*model.UserStatusAudit, err error) {
+	defer derrors.Wrap(&err, "GetUserStatusAudits(%q)", userUID)
+
+	query := `SELECT uid, user_uid, actor_uid, previous_status, status, reason, suspended_until, created_at
+			FROM user_status_audits
+			WHERE user_uid = ?
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?,?`
+
+	audits = []*model.UserStatusAudit{}
+
+	rows, err := r.Slave().QueryContext(ctx, query, userUID, r.GetOffset(page, limit), limit)
+	if err != nil {
+		return nil, derrors.HandleSQLError(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		audit := &model.UserStatusAudit{}
+		if err = rows.Scan(r.getDest(audit)...); err != nil {
+			return nil, derrors.HandleSQLError(err, "Scan")
+		}
+		audits = append(audits, audit)
+	}
+
+	return audits, nil
+}
@@ -1,30 +1,71 @@
 package authservice
 
 import (
-	"crypto/rsa"
+	"context"
+	"database/sql"
 	"date-apps-be/infrastructure/config"
+	"date-apps-be/internal/constant"
 	"date-apps-be/internal/model"
+	refreshtokenrepo "date-apps-be/internal/repository/refresh_token"
+	revokedtokenrepo "date-apps-be/internal/repository/revoked_token"
+	userrepo "date-apps-be/internal/repository/user"
+	userrolerepo "date-apps-be/internal/repository/user_role"
+	usersessionrepo "date-apps-be/internal/repository/user_session"
+	"date-apps-be/pkg/datatype"
 	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/jwk"
+	"date-apps-be/pkg/util"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/segmentio/ksuid"
 )
 
+const refreshTokenBytes = 32
+
+func init() {
+	// iat keeps microseconds, a token issued right after RevokeUserTokens must not count as revoked
+	jwt.TimePrecision = time.Microsecond
+}
+
 type (
 	AuthService interface {
 		GenerateToken(uid string) (_ string, err error)
+		IssueToken(ctx context.Context, userUID string, device model.Device) (token *model.AuthToken, err error)
+		RefreshToken(ctx context.Context, refreshToken string) (token *model.AuthToken, err error)
+		ParseToken(ctx context.Context, tokenString string) (claims *model.JWTClaims, err error)
+		Logout(ctx context.Context, claims *model.JWTClaims, refreshToken string) (err error)
+		RevokeUserTokens(ctx context.Context, userUID string) (err error)
+		RevokeSession(ctx context.Context, sessionUID string) (err error)
+		IssueMFAToken(userUID string) (token string, err error)
+		ParseMFAToken(ctx context.Context, tokenString string) (claims *model.JWTClaims, err error)
+		ConsumeMFAToken(ctx context.Context, claims *model.JWTClaims) (err error)
+		ParseRestoreToken(ctx context.Context, tokenString string) (claims *model.JWTClaims, err error)
+		JWKS() jwk.Set
 	}
 
 	authService struct {
-		privateKey *rsa.PrivateKey
-		expiration int
+		keyring                *keyring
+		expiration             int
+		refreshTokenExpiration int
+		refreshTokenRepo       refreshtokenrepo.RefreshTokenRepository
+		revokedTokenRepo       revokedtokenrepo.RevokedTokenRepository
+		userRoleRepo           userrolerepo.UserRoleRepository
+		userSessionRepo        usersessionrepo.UserSessionRepository
+		userRepo               userrepo.UserRepository
 	}
 )
 
-func NewAuthService(conf *config.Config) AuthService {
+func NewAuthService(conf *config.Config, refreshTokenRepo refreshtokenrepo.RefreshTokenRepository, revokedTokenRepo revokedtokenrepo.RevokedTokenRepository, userRoleRepo userrolerepo.UserRoleRepository, userSessionRepo usersessionrepo.UserSessionRepository, userRepo userrepo.UserRepository) AuthService {
 	return &authService{
-		privateKey: conf.JWTRS256PrivateKey,
-		expiration: conf.JWTExpiration,
+		keyring:                newKeyring(conf),
+		expiration:             conf.JWTExpiration,
+		refreshTokenExpiration: conf.RefreshTokenExpiration,
+		refreshTokenRepo:       refreshTokenRepo,
+		revokedTokenRepo:       revokedTokenRepo,
+		userRoleRepo:           userRoleRepo,
+		userSessionRepo:        userSessionRepo,
+		userRepo:               userRepo,
 	}
 }
 
@@ -33,7 +74,8 @@ func (a *authService) newJWTClaims(uid string) *model.JWTClaims {
 	claims := &model.JWTClaims{
 		UserUID: uid,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    "date-apps",
+			ID:        ksuid.New().String(),
+			Issuer:    constant.TokenIssuer,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
@@ -45,11 +87,449 @@ func (a *authService) GenerateToken(uid string) (_ string, err error) {
 	defer derrors.Wrap(&err, "GenerateToken(%q)", uid)
 
 	claims := a.newJWTClaims(uid)
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	tokenString, err := token.SignedString(a.privateKey)
+	tokenString, err := a.keyring.sign(claims)
 	if err != nil {
 		return "", derrors.WrapStack(err, derrors.Unknown, "token.SignedString")
 	}
 
 	return tokenString, nil
 }
+
+// ParseToken verifies the access token signature and expiry, and rejects tokens
+// that were revoked before they expired or belong to a suspended, banned or deleted user.
+func (a *authService) ParseToken(ctx context.Context, tokenString string) (claims *model.JWTClaims, err error) {
+	defer derrors.Wrap(&err, "ParseToken")
+
+	return a.parseToken(ctx, tokenString, "")
+}
+
+// IssueMFAToken generates a short lived token that proves the password was verified,
+// it can only be exchanged for a token pair together with a second factor.
+func (a *authService) IssueMFAToken(userUID string) (_ string, err error) {
+	defer derrors.Wrap(&err, "IssueMFAToken(%q)", userUID)
+
+	claims := a.newJWTClaims(userUID)
+	claims.Scope = constant.TokenScopeMFAPending
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(constant.MFATokenExpiration))
+
+	tokenString, err := a.keyring.sign(claims)
+	if err != nil {
+		return "", derrors.WrapStack(err, derrors.Unknown, "token.SignedString")
+	}
+
+	return tokenString, nil
+}
+
+func (a *authService) ParseMFAToken(ctx context.Context, tokenString string) (claims *model.JWTClaims, err error) {
+	defer derrors.Wrap(&err, "ParseMFAToken")
+
+	return a.parseToken(ctx, tokenString, constant.TokenScopeMFAPending)
+}
+
+// ConsumeMFAToken makes sure the MFA token is exchanged only once, concurrent requests
+// with the same token are rejected except the first.
+func (a *authService) ConsumeMFAToken(ctx context.Context, claims *model.JWTClaims) (err error) {
+	defer derrors.Wrap(&err, "ConsumeMFAToken(%q)", claims.UserUID)
+
+	expiresAt := time.Now().Add(constant.MFATokenExpiration)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	consumed, err := a.revokedTokenRepo.ConsumeToken(ctx, claims.ID, expiresAt)
+	if err != nil {
+		return
+	}
+
+	if !consumed {
+		return derrors.New(derrors.Unauthorized, "Token has been revoked")
+	}
+
+	return nil
+}
+
+// issueRestoreToken generates a short lived token for a deleted user that is only accepted
+// by the restore endpoint, the user gets a token pair by logging in again once it is restored.
+func (a *authService) issueRestoreToken(userUID string) (_ *model.AuthToken, err error) {
+	claims := a.newJWTClaims(userUID)
+	claims.Scope = constant.TokenScopeAccountRestore
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(constant.RestoreTokenExpiration))
+
+	tokenString, err := a.keyring.sign(claims)
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "token.SignedString")
+	}
+
+	return &model.AuthToken{
+		RestoreRequired: true,
+		RestoreToken:    tokenString,
+	}, nil
+}
+
+func (a *authService) ParseRestoreToken(ctx context.Context, tokenString string) (claims *model.JWTClaims, err error) {
+	defer derrors.Wrap(&err, "ParseRestoreToken")
+
+	return a.parseToken(ctx, tokenString, constant.TokenScopeAccountRestore)
+}
+
+// parseToken verifies a token of the given scope, tokens of other scopes are rejected.
+func (a *authService) parseToken(ctx context.Context, tokenString, scope string) (claims *model.JWTClaims, err error) {
+	token, err := jwt.ParseWithClaims(tokenString, &model.JWTClaims{}, a.keyring.keyFunc, jwt.WithIssuer(constant.TokenIssuer))
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unauthorized, "Invalid token")
+	}
+
+	claims, ok := token.Claims.(*model.JWTClaims)
+	if !ok || !token.Valid || claims.ID == "" || claims.Scope != scope {
+		return nil, derrors.New(derrors.Unauthorized, "Invalid token")
+	}
+
+	// checked before the revocations, suspending a user also revokes the tokens
+	// and the user should learn why they were signed out.
+	// Only access tokens need an active account, deleted users still finish the login with the other scopes.
+	if _, err = a.checkUserStatus(ctx, claims.UserUID, scope != ""); err != nil {
+		return nil, err
+	}
+
+	revoked, err := a.revokedTokenRepo.IsTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return
+	}
+
+	if !revoked && claims.SessionID != "" {
+		revoked, err = a.revokedTokenRepo.IsTokenRevoked(ctx, claims.SessionID)
+		if err != nil {
+			return
+		}
+	}
+
+	if !revoked && claims.IssuedAt != nil {
+		revoked, err = a.revokedTokenRepo.IsUserTokenRevoked(ctx, claims.UserUID, claims.IssuedAt.Time)
+		if err != nil {
+			return
+		}
+	}
+
+	if revoked {
+		return nil, derrors.New(derrors.Unauthorized, "Token has been revoked")
+	}
+
+	return claims, nil
+}
+
+// checkUserStatus rejects users that are suspended, banned or no longer exist,
+// deleted users are rejected too unless allowDeleted is set.
+func (a *authService) checkUserStatus(ctx context.Context, userUID string, allowDeleted bool) (user *model.User, err error) {
+	user, err = a.userRepo.GetUserByUID(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		return nil, derrors.New(derrors.Unauthorized, "Account not found")
+	}
+
+	if err = UserStatusError(user); err != nil {
+		return nil, err
+	}
+
+	if user.IsDeleted() && !allowDeleted {
+		return nil, derrors.New(derrors.Forbidden, "Your account is deleted, login again to restore it")
+	}
+
+	return user, nil
+}
+
+// UserStatusError explains why a suspended or banned user is rejected, it is nil for everyone else.
+func UserStatusError(user *model.User) error {
+	reason := ""
+	if user.StatusReason != nil && *user.StatusReason != "" {
+		reason = ", reason: " + *user.StatusReason
+	}
+
+	switch {
+	case user.IsBanned():
+		return derrors.New(derrors.Forbidden, "Your account is banned%s", reason)
+	case user.IsSuspended():
+		return derrors.New(derrors.Forbidden, "Your account is suspended until %s%s", user.SuspendedUntil.String(), reason)
+	}
+
+	return nil
+}
+
+// JWKS returns the public keys that verify access tokens,
+// so other services can verify them without calling this service.
+func (a *authService) JWKS() jwk.Set {
+	return a.keyring.jwks()
+}
+
+// Logout revokes the access token until it expires together with its session.
+// Tokens issued before sessions existed have no session, their refresh token family
+// is revoked when the refresh token of the same user is given.
+func (a *authService) Logout(ctx context.Context, claims *model.JWTClaims, refreshToken string) (err error) {
+	defer derrors.Wrap(&err, "Logout(%q)", claims.UserUID)
+
+	expiresAt := time.Now().Add(time.Duration(a.expiration) * time.Minute)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	if err = a.revokedTokenRepo.RevokeToken(ctx, claims.ID, expiresAt); err != nil {
+		return
+	}
+
+	if claims.SessionID != "" {
+		return a.revokeSession(ctx, claims.SessionID)
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	current, err := a.refreshTokenRepo.GetRefreshTokenByHash(ctx, util.HashToken(refreshToken))
+	if err != nil {
+		return
+	}
+
+	if current == nil || current.UserUID != claims.UserUID {
+		return nil
+	}
+
+	return a.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, nil, current.FamilyUID)
+}
+
+// RevokeUserTokens signs the user out everywhere, every refresh token is revoked
+// and access tokens issued until now are rejected.
+func (a *authService) RevokeUserTokens(ctx context.Context, userUID string) (err error) {
+	defer derrors.Wrap(&err, "RevokeUserTokens(%q)", userUID)
+
+	if err = a.refreshTokenRepo.RevokeUserRefreshTokens(ctx, nil, userUID); err != nil {
+		return
+	}
+
+	if err = a.userSessionRepo.RevokeUserSessions(ctx, nil, userUID); err != nil {
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(a.expiration) * time.Minute)
+	return a.revokedTokenRepo.RevokeUserTokens(ctx, userUID, now, expiresAt)
+}
+
+// RevokeSession signs a single device out, the refresh tokens of the session
+// stop working and its access tokens are rejected until they expire.
+func (a *authService) RevokeSession(ctx context.Context, sessionUID string) (err error) {
+	defer derrors.Wrap(&err, "RevokeSession(%q)", sessionUID)
+
+	return a.revokeSession(ctx, sessionUID)
+}
+
+func (a *authService) revokeSession(ctx context.Context, sessionUID string) (err error) {
+	tx, err := a.userSessionRepo.Begin()
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = a.userSessionRepo.Rollback(tx)
+		}
+	}()
+
+	if err = a.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, tx, sessionUID); err != nil {
+		return
+	}
+
+	if _, err = a.userSessionRepo.RevokeUserSession(ctx, tx, sessionUID); err != nil {
+		return
+	}
+
+	if err = a.userSessionRepo.Commit(tx); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	// access tokens of the session live at most the access token expiration from now
+	expiresAt := time.Now().Add(time.Duration(a.expiration) * time.Minute)
+	return a.revokedTokenRepo.RevokeToken(ctx, sessionUID, expiresAt)
+}
+
+// IssueToken starts a new session on device, an access token is generated
+// together with a refresh token that starts the refresh token family of the session.
+// Deleted users get a restore token instead and no session is started.
+func (a *authService) IssueToken(ctx context.Context, userUID string, device model.Device) (token *model.AuthToken, err error) {
+	defer derrors.Wrap(&err, "IssueToken(%q)", userUID)
+
+	user, err := a.checkUserStatus(ctx, userUID, true)
+	if err != nil {
+		return
+	}
+
+	if user.IsDeleted() {
+		return a.issueRestoreToken(userUID)
+	}
+
+	sessionUID := ksuid.New().String()
+	expiresAt := a.refreshTokenExpiresAt()
+
+	tx, err := a.userSessionRepo.Begin()
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = a.userSessionRepo.Rollback(tx)
+		}
+	}()
+
+	session := &model.UserSession{
+		UID:       sessionUID,
+		UserUID:   userUID,
+		DeviceID:  truncate(device.ID, constant.MaxDeviceIDLength),
+		UserAgent: truncate(device.UserAgent, constant.MaxUserAgentLength),
+		IPAddress: device.IPAddress,
+		ExpiresAt: datatype.NewTime(&expiresAt),
+	}
+	if err = a.userSessionRepo.CreateUserSession(ctx, tx, session); err != nil {
+		return
+	}
+
+	refreshToken, err := a.newRefreshToken(ctx, tx, userUID, sessionUID, expiresAt)
+	if err != nil {
+		return
+	}
+
+	if err = a.userSessionRepo.Commit(tx); err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return a.newAuthToken(ctx, userUID, sessionUID, refreshToken)
+}
+
+// RefreshToken rotates a refresh token. Presenting a token that was already
+// rotated revokes its whole family, since either the client or an attacker
+// holds a stolen copy.
+func (a *authService) RefreshToken(ctx context.Context, refreshToken string) (token *model.AuthToken, err error) {
+	defer derrors.Wrap(&err, "RefreshToken")
+
+	current, err := a.refreshTokenRepo.GetRefreshTokenByHash(ctx, util.HashToken(refreshToken))
+	if err != nil {
+		return
+	}
+
+	if current == nil || !current.RevokedAt.IsNil() || current.IsExpired() {
+		return nil, derrors.New(derrors.Unauthorized, "Invalid refresh token")
+	}
+
+	if _, err = a.checkUserStatus(ctx, current.UserUID, false); err != nil {
+		return
+	}
+
+	newRefreshToken, err := a.rotateRefreshToken(ctx, current)
+	if err != nil {
+		return
+	}
+
+	if newRefreshToken == "" {
+		return nil, derrors.New(derrors.Unauthorized, "Refresh token reuse detected, please login again")
+	}
+
+	return a.newAuthToken(ctx, current.UserUID, current.FamilyUID, newRefreshToken)
+}
+
+// rotateRefreshToken replaces the current refresh token with a new one of the same family
+// and extends its session, an empty token is returned when the current one was already
+// rotated and the family got revoked.
+func (a *authService) rotateRefreshToken(ctx context.Context, current *model.RefreshToken) (token string, err error) {
+	tx, err := a.refreshTokenRepo.Begin()
+	if err != nil {
+		return "", derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = a.refreshTokenRepo.Rollback(tx)
+		}
+	}()
+
+	rotated, err := a.refreshTokenRepo.RotateRefreshToken(ctx, tx, current.UID)
+	if err != nil {
+		return
+	}
+
+	if rotated {
+		expiresAt := a.refreshTokenExpiresAt()
+		token, err = a.newRefreshToken(ctx, tx, current.UserUID, current.FamilyUID, expiresAt)
+		if err == nil {
+			err = a.userSessionRepo.TouchUserSession(ctx, tx, current.FamilyUID, datatype.NewTime(&expiresAt))
+		}
+	} else {
+		err = a.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, tx, current.FamilyUID)
+	}
+	if err != nil {
+		return
+	}
+
+	if err = a.refreshTokenRepo.Commit(tx); err != nil {
+		return "", derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return token, nil
+}
+
+// newAuthToken signs an access token of the session with the current roles of the user,
+// role changes are picked up on the next refresh.
+func (a *authService) newAuthToken(ctx context.Context, userUID, sessionUID, refreshToken string) (*model.AuthToken, error) {
+	roles, err := a.userRoleRepo.GetUserRoles(ctx, userUID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := a.newJWTClaims(userUID)
+	claims.Roles = roles
+	claims.SessionID = sessionUID
+
+	accessToken, err := a.keyring.sign(claims)
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "token.SignedString")
+	}
+
+	return &model.AuthToken{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(a.expiration) * 60,
+	}, nil
+}
+
+// newRefreshToken stores the hash of a new opaque refresh token and returns the token.
+func (a *authService) newRefreshToken(ctx context.Context, tx *sql.Tx, userUID, familyUID string, expiresAt time.Time) (_ string, err error) {
+	token, err := util.RandomToken(refreshTokenBytes)
+	if err != nil {
+		return "", derrors.WrapStack(err, derrors.Unknown, "util.RandomToken")
+	}
+
+	err = a.refreshTokenRepo.CreateRefreshToken(ctx, tx, &model.RefreshToken{
+		UID:       ksuid.New().String(),
+		UserUID:   userUID,
+		FamilyUID: familyUID,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: datatype.NewTime(&expiresAt),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (a *authService) refreshTokenExpiresAt() time.Time {
+	return time.Now().Add(time.Duration(a.refreshTokenExpiration) * time.Minute)
+}
+
+// truncate returns nil for an empty value so it is stored as NULL.
+func truncate(value string, length int) *string {
+	if value == "" {
+		return nil
+	}
+	if len(value) > length {
+		value = value[:length]
+	}
+	return &value
+}
@@ -1,12 +1,26 @@
 package authservice_test
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"database/sql"
 	"testing"
+	"time"
 
+	"date-apps-be/infrastructure/config"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	revokedtokenrepository "date-apps-be/internal/repository/revoked_token"
+	authservice "date-apps-be/internal/service/auth"
 	"date-apps-be/internal/test"
+	"date-apps-be/pkg/datatype"
 	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/util"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGenerateToken(t *testing.T) {
@@ -56,3 +70,547 @@ func TestGenerateToken(t *testing.T) {
 		})
 	}
 }
+
+func TestIssueToken(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testAuthService := authservice.NewAuthService(mc.Config, mc.RefreshTokenRepository, mc.RevokedTokenRepository, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	var refreshTokenHash, familyUID string
+	var session *model.UserSession
+	mc.UserRepository.On("GetUserByUID", mock.Anything, mock.Anything).Return(&model.User{Status: constant.UserStatusActive}, nil)
+	mc.UserSessionRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+	mc.UserSessionRepository.On("CreateUserSession", mock.Anything, mock.Anything, mock.MatchedBy(func(s *model.UserSession) bool {
+		session = s
+		return s.UserUID == "test_uid"
+	})).Return(nil).Once()
+	mc.RefreshTokenRepository.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.MatchedBy(func(r *model.RefreshToken) bool {
+		refreshTokenHash = r.TokenHash
+		familyUID = r.FamilyUID
+		return r.UserUID == "test_uid" && r.FamilyUID != ""
+	})).Return(nil).Once()
+	mc.UserSessionRepository.On("Commit", mock.Anything).Return(nil).Once()
+
+	mc.UserRoleRepository.On("GetUserRoles", mock.Anything, "test_uid").Return([]string{"admin"}, nil)
+
+	token, err := testAuthService.IssueToken(ctx, "test_uid", model.Device{ID: "device_1", UserAgent: "test-agent", IPAddress: "127.0.0.1"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.Token)
+	assert.NotEmpty(t, token.RefreshToken)
+	assert.Equal(t, int64(mc.Config.JWTExpiration*60), token.ExpiresIn)
+	assert.Equal(t, util.HashToken(token.RefreshToken), refreshTokenHash, "only the hash of the refresh token is stored")
+
+	claims := &model.JWTClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(token.Token, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Roles{"admin"}, claims.Roles, "the roles of the user are embedded in the access token")
+	assert.Equal(t, familyUID, claims.SessionID, "the session is the refresh token family")
+	assert.Equal(t, familyUID, session.UID)
+	assert.Equal(t, "device_1", *session.DeviceID)
+	assert.Equal(t, "test-agent", *session.UserAgent)
+	assert.Equal(t, "127.0.0.1", session.IPAddress)
+}
+
+func TestRefreshToken(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testAuthService := authservice.NewAuthService(mc.Config, mc.RefreshTokenRepository, mc.RevokedTokenRepository, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	reason := "spam"
+
+	var testCases = []struct {
+		caseName     string
+		refreshToken string
+		expectations func()
+		results      func(token *model.AuthToken, err error)
+	}{
+		{
+			caseName:     "RefreshToken_Rotated",
+			refreshToken: "valid_token",
+			expectations: func() {
+				mc.RefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, util.HashToken("valid_token")).Return(&model.RefreshToken{
+					UID: "rt_1", UserUID: "test_uid", FamilyUID: "family_1", ExpiresAt: datatype.NewTime(&future),
+				}, nil).Once()
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "test_uid").Return(&model.User{Status: constant.UserStatusActive}, nil).Once()
+				mc.RefreshTokenRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.RefreshTokenRepository.On("RotateRefreshToken", mock.Anything, mock.Anything, "rt_1").Return(true, nil).Once()
+				mc.RefreshTokenRepository.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.MatchedBy(func(r *model.RefreshToken) bool {
+					return r.FamilyUID == "family_1" && r.UserUID == "test_uid"
+				})).Return(nil).Once()
+				mc.UserSessionRepository.On("TouchUserSession", mock.Anything, mock.Anything, "family_1", mock.Anything).Return(nil).Once()
+				mc.RefreshTokenRepository.On("Commit", mock.Anything).Return(nil).Once()
+				mc.UserRoleRepository.On("GetUserRoles", mock.Anything, "test_uid").Return([]string{}, nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, token.Token)
+				assert.NotEqual(t, "valid_token", token.RefreshToken)
+			},
+		},
+		{
+			caseName:     "RefreshToken_ReuseRevokesFamily",
+			refreshToken: "rotated_token",
+			expectations: func() {
+				mc.RefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, util.HashToken("rotated_token")).Return(&model.RefreshToken{
+					UID: "rt_2", UserUID: "test_uid", FamilyUID: "family_2", ExpiresAt: datatype.NewTime(&future), RotatedAt: datatype.NewTime(&past),
+				}, nil).Once()
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "test_uid").Return(&model.User{Status: constant.UserStatusActive}, nil).Once()
+				mc.RefreshTokenRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.RefreshTokenRepository.On("RotateRefreshToken", mock.Anything, mock.Anything, "rt_2").Return(false, nil).Once()
+				mc.RefreshTokenRepository.On("RevokeRefreshTokenFamily", mock.Anything, mock.Anything, "family_2").Return(nil).Once()
+				mc.RefreshTokenRepository.On("Commit", mock.Anything).Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, token)
+			},
+		},
+		{
+			caseName:     "RefreshToken_UserSuspended",
+			refreshToken: "suspended_token",
+			expectations: func() {
+				mc.RefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, util.HashToken("suspended_token")).Return(&model.RefreshToken{
+					UID: "rt_4", UserUID: "suspended_uid", FamilyUID: "family_4", ExpiresAt: datatype.NewTime(&future),
+				}, nil).Once()
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "suspended_uid").Return(&model.User{
+					Status: constant.UserStatusSuspended, StatusReason: &reason, SuspendedUntil: datatype.NewTime(&future),
+				}, nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				assert.ErrorContains(t, err, "reason: spam")
+				assert.Nil(t, token)
+			},
+		},
+		{
+			caseName:     "RefreshToken_Expired",
+			refreshToken: "expired_token",
+			expectations: func() {
+				mc.RefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, util.HashToken("expired_token")).Return(&model.RefreshToken{
+					UID: "rt_3", UserUID: "test_uid", FamilyUID: "family_3", ExpiresAt: datatype.NewTime(&past),
+				}, nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, token)
+			},
+		},
+		{
+			caseName:     "RefreshToken_Unknown",
+			refreshToken: "unknown_token",
+			expectations: func() {
+				mc.RefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, util.HashToken("unknown_token")).Return(nil, nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, token)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			token, err := testAuthService.RefreshToken(ctx, testCase.refreshToken)
+			testCase.results(token, err)
+		})
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testAuthService := authservice.NewAuthService(mc.Config, mc.RefreshTokenRepository, mc.RevokedTokenRepository, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	validToken, err := testAuthService.GenerateToken("test_uid")
+	assert.NoError(t, err)
+	revokedToken, err := testAuthService.GenerateToken("revoked_uid")
+	assert.NoError(t, err)
+	userRevokedToken, err := testAuthService.GenerateToken("user_revoked_uid")
+	assert.NoError(t, err)
+	suspendedToken, err := testAuthService.GenerateToken("suspended_uid")
+	assert.NoError(t, err)
+	bannedToken, err := testAuthService.GenerateToken("banned_uid")
+	assert.NoError(t, err)
+	deletedToken, err := testAuthService.GenerateToken("deleted_uid")
+	assert.NoError(t, err)
+	foreignToken := jwt.NewWithClaims(jwt.SigningMethodRS256, &model.JWTClaims{
+		UserUID: "test_uid",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "foreign_jti",
+			Issuer:    "another-service",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	foreignToken.Header["kid"] = mc.Config.JWTKeyID
+	foreignTokenString, err := foreignToken.SignedString(mc.Config.JWTRS256PrivateKey)
+	assert.NoError(t, err)
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "suspended_uid").Return(&model.User{
+		Status: constant.UserStatusSuspended, SuspendedUntil: datatype.NewTime(&future),
+	}, nil)
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "banned_uid").Return(&model.User{Status: constant.UserStatusBanned}, nil)
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "deleted_uid").Return(&model.User{
+		Status: constant.UserStatusActive, DeletedAt: datatype.NewTime(&past),
+	}, nil)
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "test_uid").Return(&model.User{
+		Status: constant.UserStatusSuspended, SuspendedUntil: datatype.NewTime(&past),
+	}, nil)
+	mc.UserRepository.On("GetUserByUID", mock.Anything, mock.Anything).Return(&model.User{Status: constant.UserStatusActive}, nil)
+
+	var testCases = []struct {
+		caseName     string
+		token        string
+		expectations func()
+		results      func(claims *model.JWTClaims, err error)
+	}{
+		{
+			caseName: "ParseToken_Valid",
+			token:    validToken,
+			expectations: func() {
+				mc.RevokedTokenRepository.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Once()
+				mc.RevokedTokenRepository.On("IsUserTokenRevoked", mock.Anything, "test_uid", mock.Anything).Return(false, nil).Once()
+			},
+			results: func(claims *model.JWTClaims, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "test_uid", claims.UserUID)
+				assert.NotEmpty(t, claims.ID)
+			},
+		},
+		{
+			caseName: "ParseToken_UserTokensRevoked",
+			token:    userRevokedToken,
+			expectations: func() {
+				mc.RevokedTokenRepository.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Once()
+				mc.RevokedTokenRepository.On("IsUserTokenRevoked", mock.Anything, "user_revoked_uid", mock.Anything).Return(true, nil).Once()
+			},
+			results: func(claims *model.JWTClaims, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			caseName:     "ParseToken_UserSuspended",
+			token:        suspendedToken,
+			expectations: func() {},
+			results: func(claims *model.JWTClaims, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				assert.ErrorContains(t, err, "suspended until")
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			caseName:     "ParseToken_UserBanned",
+			token:        bannedToken,
+			expectations: func() {},
+			results: func(claims *model.JWTClaims, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				assert.ErrorContains(t, err, "banned")
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			caseName:     "ParseToken_UserDeleted",
+			token:        deletedToken,
+			expectations: func() {},
+			results: func(claims *model.JWTClaims, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				assert.ErrorContains(t, err, "deleted")
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			caseName: "ParseToken_Revoked",
+			token:    revokedToken,
+			expectations: func() {
+				mc.RevokedTokenRepository.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(true, nil).Once()
+			},
+			results: func(claims *model.JWTClaims, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			caseName:     "ParseToken_OtherIssuer",
+			token:        foreignTokenString,
+			expectations: func() {},
+			results: func(claims *model.JWTClaims, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			caseName:     "ParseToken_Malformed",
+			token:        "not-a-jwt",
+			expectations: func() {},
+			results: func(claims *model.JWTClaims, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, claims)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			claims, err := testAuthService.ParseToken(ctx, testCase.token)
+			testCase.results(claims, err)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testAuthService := authservice.NewAuthService(mc.Config, mc.RefreshTokenRepository, mc.RevokedTokenRepository, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	expiresAt := time.Now().Add(time.Minute).Truncate(time.Second)
+	claims := &model.JWTClaims{
+		UserUID: "test_uid",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti_1",
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	mc.RevokedTokenRepository.On("RevokeToken", mock.Anything, "jti_1", expiresAt).Return(nil)
+	mc.RefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, util.HashToken("refresh_token")).Return(&model.RefreshToken{
+		UID: "rt_1", UserUID: "test_uid", FamilyUID: "family_1",
+	}, nil)
+	mc.RefreshTokenRepository.On("RevokeRefreshTokenFamily", mock.Anything, mock.Anything, "family_1").Return(nil)
+
+	err := testAuthService.Logout(ctx, claims, "refresh_token")
+	assert.NoError(t, err)
+
+	sessionClaims := &model.JWTClaims{
+		UserUID:   "test_uid",
+		SessionID: "session_1",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti_2",
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	mc.RevokedTokenRepository.On("RevokeToken", mock.Anything, "jti_2", expiresAt).Return(nil).Once()
+	mc.UserSessionRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+	mc.RefreshTokenRepository.On("RevokeRefreshTokenFamily", mock.Anything, mock.Anything, "session_1").Return(nil).Once()
+	mc.UserSessionRepository.On("RevokeUserSession", mock.Anything, mock.Anything, "session_1").Return(true, nil).Once()
+	mc.UserSessionRepository.On("Commit", mock.Anything).Return(nil).Once()
+	mc.RevokedTokenRepository.On("RevokeToken", mock.Anything, "session_1", mock.Anything).Return(nil).Once()
+
+	err = testAuthService.Logout(ctx, sessionClaims, "")
+	assert.NoError(t, err, "the session of the access token is revoked without the refresh token")
+}
+
+func TestRevokeSession(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testAuthService := authservice.NewAuthService(mc.Config, mc.RefreshTokenRepository, mc.RevokedTokenRepository, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	var sessionUID string
+	mc.UserRepository.On("GetUserByUID", mock.Anything, mock.Anything).Return(&model.User{Status: constant.UserStatusActive}, nil)
+	mc.UserSessionRepository.On("Begin").Return((*sql.Tx)(nil), nil)
+	mc.UserSessionRepository.On("Commit", mock.Anything).Return(nil)
+	mc.UserSessionRepository.On("CreateUserSession", mock.Anything, mock.Anything, mock.MatchedBy(func(s *model.UserSession) bool {
+		sessionUID = s.UID
+		return s.DeviceID == nil && s.UserAgent == nil
+	})).Return(nil).Once()
+	mc.RefreshTokenRepository.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	mc.UserRoleRepository.On("GetUserRoles", mock.Anything, "test_uid").Return([]string{}, nil).Once()
+
+	token, err := testAuthService.IssueToken(ctx, "test_uid", model.Device{IPAddress: "127.0.0.1"})
+	assert.NoError(t, err)
+
+	mc.RefreshTokenRepository.On("RevokeRefreshTokenFamily", mock.Anything, mock.Anything, sessionUID).Return(nil).Once()
+	mc.UserSessionRepository.On("RevokeUserSession", mock.Anything, mock.Anything, sessionUID).Return(true, nil).Once()
+	mc.RevokedTokenRepository.On("RevokeToken", mock.Anything, sessionUID, mock.MatchedBy(func(expiresAt time.Time) bool {
+		return expiresAt.After(time.Now().Add(time.Duration(mc.Config.JWTExpiration-1) * time.Minute))
+	})).Return(nil).Once()
+
+	assert.NoError(t, testAuthService.RevokeSession(ctx, sessionUID))
+
+	mc.RevokedTokenRepository.On("IsTokenRevoked", mock.Anything, sessionUID).Return(true, nil).Once()
+	mc.RevokedTokenRepository.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Once()
+
+	claims, err := testAuthService.ParseToken(ctx, token.Token)
+	assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized), "access tokens of a revoked session are rejected")
+	assert.Nil(t, claims)
+}
+
+func TestKeyRotation(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	oldAuthService := authservice.NewAuthService(&config.Config{
+		JWTKeyID:           "old",
+		JWTRS256PrivateKey: oldKey,
+		JWTRS256PubKey:     &oldKey.PublicKey,
+		JWTExpiration:      10,
+	}, mc.RefreshTokenRepository, mc.RevokedTokenRepository, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	rotatedConfig := *mc.Config
+	rotatedConfig.JWTKeyID = "new"
+	rotatedConfig.JWTVerificationKeys = map[string]*rsa.PublicKey{"old": &oldKey.PublicKey}
+	rotatedAuthService := authservice.NewAuthService(&rotatedConfig, mc.RefreshTokenRepository, mc.RevokedTokenRepository, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	oldToken, err := oldAuthService.GenerateToken("test_uid")
+	assert.NoError(t, err)
+	newToken, err := rotatedAuthService.GenerateToken("test_uid")
+	assert.NoError(t, err)
+
+	mc.RevokedTokenRepository.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil)
+	mc.RevokedTokenRepository.On("IsUserTokenRevoked", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	mc.UserRepository.On("GetUserByUID", mock.Anything, mock.Anything).Return(&model.User{Status: constant.UserStatusActive}, nil)
+
+	claims, err := rotatedAuthService.ParseToken(ctx, oldToken)
+	assert.NoError(t, err, "tokens signed with a verification key are still accepted")
+	assert.Equal(t, "test_uid", claims.UserUID)
+
+	claims, err = rotatedAuthService.ParseToken(ctx, newToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "test_uid", claims.UserUID)
+
+	_, err = oldAuthService.ParseToken(ctx, newToken)
+	assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized), "tokens signed with an unknown kid are rejected")
+
+	jwks := rotatedAuthService.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+	newKey, ok := jwks.Key("new")
+	assert.True(t, ok)
+	pub, err := newKey.RSAPublicKey()
+	assert.NoError(t, err)
+	assert.True(t, mc.Config.JWTRS256PubKey.Equal(pub))
+	_, ok = jwks.Key("old")
+	assert.True(t, ok)
+}
+
+func TestRevokeUserTokens(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testAuthService := authservice.NewAuthService(mc.Config, mc.RefreshTokenRepository, mc.RevokedTokenRepository, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	mc.RefreshTokenRepository.On("RevokeUserRefreshTokens", mock.Anything, mock.Anything, "user_uid").Return(nil).Once()
+	mc.UserSessionRepository.On("RevokeUserSessions", mock.Anything, mock.Anything, "user_uid").Return(nil).Once()
+	mc.RevokedTokenRepository.On("RevokeUserTokens", mock.Anything, "user_uid", mock.Anything, mock.MatchedBy(func(expiresAt time.Time) bool {
+		return expiresAt.After(time.Now().Add(time.Duration(mc.Config.JWTExpiration-1) * time.Minute))
+	})).Return(nil).Once()
+
+	assert.NoError(t, testAuthService.RevokeUserTokens(ctx, "user_uid"))
+}
+
+func TestRevokeUserTokens_LoginRightAfter(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	revokedTokenRepo := revokedtokenrepository.NewMemoryRevokedTokenRepository()
+	testAuthService := authservice.NewAuthService(mc.Config, mc.RefreshTokenRepository, revokedTokenRepo, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "user_uid").Return(&model.User{Status: constant.UserStatusActive}, nil)
+	mc.RefreshTokenRepository.On("RevokeUserRefreshTokens", mock.Anything, mock.Anything, "user_uid").Return(nil).Once()
+	mc.UserSessionRepository.On("RevokeUserSessions", mock.Anything, mock.Anything, "user_uid").Return(nil).Once()
+
+	oldToken, err := testAuthService.GenerateToken("user_uid")
+	assert.NoError(t, err)
+
+	assert.NoError(t, testAuthService.RevokeUserTokens(ctx, "user_uid"))
+
+	// issued within the same second as the revocation
+	newToken, err := testAuthService.GenerateToken("user_uid")
+	assert.NoError(t, err)
+
+	_, err = testAuthService.ParseToken(ctx, oldToken)
+	assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+
+	claims, err := testAuthService.ParseToken(ctx, newToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user_uid", claims.UserUID)
+}
+
+func TestMFAToken(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testAuthService := authservice.NewAuthService(mc.Config, mc.RefreshTokenRepository, mc.RevokedTokenRepository, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	mc.RevokedTokenRepository.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil)
+	mc.RevokedTokenRepository.On("IsUserTokenRevoked", mock.Anything, "test_uid", mock.Anything).Return(false, nil)
+	mc.UserRepository.On("GetUserByUID", mock.Anything, mock.Anything).Return(&model.User{Status: constant.UserStatusActive}, nil)
+
+	mfaToken, err := testAuthService.IssueMFAToken("test_uid")
+	assert.NoError(t, err)
+
+	claims, err := testAuthService.ParseMFAToken(ctx, mfaToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "test_uid", claims.UserUID)
+
+	_, err = testAuthService.ParseToken(ctx, mfaToken)
+	assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized), "mfa token must not be accepted as access token")
+
+	accessToken, err := testAuthService.GenerateToken("test_uid")
+	assert.NoError(t, err)
+
+	_, err = testAuthService.ParseMFAToken(ctx, accessToken)
+	assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized), "access token must not be accepted as mfa token")
+}
+
+func TestConsumeMFAToken(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	revokedTokenRepo := revokedtokenrepository.NewMemoryRevokedTokenRepository()
+	testAuthService := authservice.NewAuthService(mc.Config, mc.RefreshTokenRepository, revokedTokenRepo, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "test_uid").Return(&model.User{Status: constant.UserStatusActive}, nil)
+
+	mfaToken, err := testAuthService.IssueMFAToken("test_uid")
+	assert.NoError(t, err)
+
+	claims, err := testAuthService.ParseMFAToken(ctx, mfaToken)
+	assert.NoError(t, err)
+
+	assert.NoError(t, testAuthService.ConsumeMFAToken(ctx, claims))
+
+	err = testAuthService.ConsumeMFAToken(ctx, claims)
+	assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized), "mfa token must only be consumed once")
+
+	_, err = testAuthService.ParseMFAToken(ctx, mfaToken)
+	assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+}
+
+func TestRestoreToken(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testAuthService := authservice.NewAuthService(mc.Config, mc.RefreshTokenRepository, mc.RevokedTokenRepository, mc.UserRoleRepository, mc.UserSessionRepository, mc.UserRepository)
+
+	deletedAt := time.Now().Add(-time.Hour)
+	mc.RevokedTokenRepository.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil)
+	mc.RevokedTokenRepository.On("IsUserTokenRevoked", mock.Anything, "deleted_uid", mock.Anything).Return(false, nil)
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "deleted_uid").Return(&model.User{
+		UID: "deleted_uid", Status: constant.UserStatusActive, DeletedAt: datatype.NewTime(&deletedAt),
+	}, nil)
+
+	token, err := testAuthService.IssueToken(ctx, "deleted_uid", model.Device{ID: "device_1"})
+	assert.NoError(t, err)
+	assert.True(t, token.RestoreRequired)
+	assert.Empty(t, token.Token, "deleted users get no token pair")
+	assert.Empty(t, token.RefreshToken)
+	mc.UserSessionRepository.AssertNotCalled(t, "Begin")
+
+	claims, err := testAuthService.ParseRestoreToken(ctx, token.RestoreToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted_uid", claims.UserUID)
+
+	_, err = testAuthService.ParseToken(ctx, token.RestoreToken)
+	assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized), "restore token must not be accepted as access token")
+
+	expiresAt := time.Now().Add(time.Hour)
+	mc.RefreshTokenRepository.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).
+		Return(&model.RefreshToken{UserUID: "deleted_uid", ExpiresAt: datatype.NewTime(&expiresAt)}, nil).Once()
+
+	_, err = testAuthService.RefreshToken(ctx, "refresh_token_of_deleted_user")
+	assert.True(t, derrors.IsErrCode(err, derrors.Forbidden), "refresh tokens of deleted users must not be rotated")
+}
@@ -0,0 +1,85 @@
+package authservice
+
+import (
+	"crypto/rsa"
+	"date-apps-be/infrastructure/config"
+	"date-apps-be/pkg/jwk"
+	"fmt"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keyring holds the key used to sign new tokens and every public key
+// still accepted for verification, so signing keys can be rotated
+// without invalidating tokens that were already issued.
+type keyring struct {
+	signingKeyID string
+	signingKey   *rsa.PrivateKey
+	publicKeys   map[string]*rsa.PublicKey
+}
+
+func newKeyring(conf *config.Config) *keyring {
+	k := &keyring{
+		signingKeyID: conf.JWTKeyID,
+		signingKey:   conf.JWTRS256PrivateKey,
+		publicKeys:   map[string]*rsa.PublicKey{},
+	}
+
+	for kid, pub := range conf.JWTVerificationKeys {
+		k.publicKeys[kid] = pub
+	}
+
+	if conf.JWTRS256PubKey != nil {
+		k.publicKeys[k.signingKeyID] = conf.JWTRS256PubKey
+	} else if conf.JWTRS256PrivateKey != nil {
+		k.publicKeys[k.signingKeyID] = &conf.JWTRS256PrivateKey.PublicKey
+	}
+
+	return k
+}
+
+// sign signs the claims with the signing key and sets its kid in the header.
+func (k *keyring) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = k.signingKeyID
+	return token.SignedString(k.signingKey)
+}
+
+// keyFunc picks the verification key by the kid header, tokens issued
+// before kid was introduced are verified with the signing key.
+func (k *keyring) keyFunc(token *jwt.Token) (interface{}, error) {
+	if method, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+		return nil, fmt.Errorf("signing method invalid")
+	} else if method != jwt.SigningMethodRS256 {
+		return nil, fmt.Errorf("signing method invalid")
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = k.signingKeyID
+	}
+
+	pub, ok := k.publicKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	return pub, nil
+}
+
+// jwks returns every verification key as a JSON Web Key Set.
+func (k *keyring) jwks() jwk.Set {
+	kids := make([]string, 0, len(k.publicKeys))
+	for kid := range k.publicKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := jwk.Set{Keys: make([]jwk.Key, 0, len(kids))}
+	for _, kid := range kids {
+		set.Keys = append(set.Keys, jwk.NewRSAKey(kid, k.publicKeys[kid]))
+	}
+
+	return set
+}
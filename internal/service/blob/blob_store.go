@@ -0,0 +1,115 @@
+package blobservice
+
+import (
+	"context"
+	"date-apps-be/pkg/derrors"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type (
+	// BlobStore keeps files by key, keys are slash separated relative paths like exports/<uid>.zip.
+	BlobStore interface {
+		Put(ctx context.Context, key string, body io.Reader) (err error)
+		// Get returns a NotFound error when there is no blob with the key.
+		Get(ctx context.Context, key string) (body io.ReadCloser, err error)
+		// Delete does nothing when there is no blob with the key.
+		Delete(ctx context.Context, key string) (err error)
+	}
+
+	localBlobStore struct {
+		dir string
+	}
+)
+
+// NewLocalBlobStore keeps blobs as files below dir, it only works with a single
+// instance or a directory shared by every instance.
+func NewLocalBlobStore(dir string) BlobStore {
+	return &localBlobStore{
+		dir: dir,
+	}
+}
+
+func (s *localBlobStore) path(key string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", derrors.New(derrors.InvalidArgument, "Invalid blob key %q", key)
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes to a temporary file first, so a blob is either complete or missing.
+func (s *localBlobStore) Put(ctx context.Context, key string, body io.Reader) (err error) {
+	defer derrors.Wrap(&err, "Put(%q)", key)
+
+	path, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "os.MkdirAll")
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "os.CreateTemp")
+	}
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	if _, err = io.Copy(file, body); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "io.Copy")
+	}
+
+	if err = file.Close(); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Close")
+	}
+
+	if err = os.Rename(file.Name(), path); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "os.Rename")
+	}
+
+	return nil
+}
+
+func (s *localBlobStore) Get(ctx context.Context, key string) (body io.ReadCloser, err error) {
+	defer derrors.Wrap(&err, "Get(%q)", key)
+
+	path, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, derrors.New(derrors.NotFound, "Blob not found")
+		}
+		return nil, derrors.WrapStack(err, derrors.Unknown, "os.Open")
+	}
+
+	return file, nil
+}
+
+func (s *localBlobStore) Delete(ctx context.Context, key string) (err error) {
+	defer derrors.Wrap(&err, "Delete(%q)", key)
+
+	path, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return derrors.WrapStack(err, derrors.Unknown, "os.Remove")
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package blobservice_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	blobservice "date-apps-be/internal/service/blob"
+	"date-apps-be/pkg/derrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	ctx := context.Background()
+	store := blobservice.NewLocalBlobStore(t.TempDir())
+
+	require.NoError(t, store.Put(ctx, "exports/user-1/export-1.zip", strings.NewReader("first")))
+	require.NoError(t, store.Put(ctx, "exports/user-1/export-1.zip", strings.NewReader("second")))
+
+	body, err := store.Get(ctx, "exports/user-1/export-1.zip")
+	require.NoError(t, err)
+	content, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "second", string(content))
+
+	require.NoError(t, store.Delete(ctx, "exports/user-1/export-1.zip"))
+	require.NoError(t, store.Delete(ctx, "exports/user-1/export-1.zip"), "deleting a missing blob is not an error")
+
+	_, err = store.Get(ctx, "exports/user-1/export-1.zip")
+	assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+}
+
+func TestLocalBlobStore_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	store := blobservice.NewLocalBlobStore(t.TempDir())
+
+	for _, key := range []string{"../outside", "/etc/passwd", ""} {
+		err := store.Put(ctx, key, strings.NewReader("content"))
+		assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument), key)
+	}
+}
@@ -0,0 +1,100 @@
+package hmacservice
+
+import (
+	"bytes"
+	"context"
+	"date-apps-be/internal/constant"
+	servicesignaturerepo "date-apps-be/internal/repository/service_signature"
+	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/hmacauth"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// HMACService authenticates requests of other backends signed with pkg/hmacauth.
+	HMACService interface {
+		VerifyRequest(ctx context.Context, req *http.Request) (clientID string, err error)
+	}
+
+	hmacService struct {
+		clients       map[string][]byte
+		signatureRepo servicesignaturerepo.ServiceSignatureRepository
+	}
+)
+
+func NewHMACService(clients map[string][]byte, signatureRepo servicesignaturerepo.ServiceSignatureRepository) HMACService {
+	return &hmacService{
+		clients:       clients,
+		signatureRepo: signatureRepo,
+	}
+}
+
+// VerifyRequest checks the signature of req against the secret of its client.
+// The timestamp must be within the clock skew window and every signature is only
+// accepted once, the body of req is replaced so handlers can still read it.
+func (h *hmacService) VerifyRequest(ctx context.Context, req *http.Request) (clientID string, err error) {
+	defer derrors.Wrap(&err, "VerifyRequest")
+
+	clientID, signature, err := hmacauth.ParseAuthorization(req.Header.Get(hmacauth.HeaderAuthorization))
+	if err != nil {
+		return "", derrors.New(derrors.Unauthorized, "Missing or malformed service signature")
+	}
+
+	secret, ok := h.clients[clientID]
+	if !ok {
+		return "", derrors.New(derrors.Unauthorized, "Invalid service signature")
+	}
+
+	timestamp, err := strconv.ParseInt(req.Header.Get(hmacauth.HeaderTimestamp), 10, 64)
+	if err != nil {
+		return "", derrors.New(derrors.Unauthorized, "Missing or malformed service timestamp")
+	}
+
+	requestTime := time.Unix(timestamp, 0)
+	if skew := time.Since(requestTime); skew > constant.ServiceAuthClockSkew || skew < -constant.ServiceAuthClockSkew {
+		return "", derrors.New(derrors.Unauthorized, "Service timestamp is outside the allowed window")
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return
+	}
+
+	if !hmacauth.Verify(secret, req.Method, req.RequestURI, timestamp, body, signature) {
+		return "", derrors.New(derrors.Unauthorized, "Invalid service signature")
+	}
+
+	// the timestamp is rejected once it leaves the window, the signature does not have to be kept longer
+	firstUse, err := h.signatureRepo.UseSignature(ctx, clientID, strings.ToLower(signature), requestTime.Add(constant.ServiceAuthClockSkew))
+	if err != nil {
+		return
+	}
+
+	if !firstUse {
+		return "", derrors.New(derrors.Unauthorized, "Service request has already been used")
+	}
+
+	return clientID, nil
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, constant.ServiceAuthMaxBodyBytes+1))
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.InvalidArgument, "io.ReadAll")
+	}
+
+	if len(body) > constant.ServiceAuthMaxBodyBytes {
+		return nil, derrors.New(derrors.InvalidArgument, "Request body is too large")
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
@@ -0,0 +1,159 @@
+package hmacservice_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"date-apps-be/internal/constant"
+	hmacservice "date-apps-be/internal/service/hmac"
+	"date-apps-be/internal/test"
+	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/hmacauth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newSignedRequest(method, target, body, clientID string, secret []byte, now time.Time) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	timestamp := now.Unix()
+	signature := hmacauth.Sign(secret, method, req.RequestURI, timestamp, []byte(body))
+	req.Header.Set(hmacauth.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(hmacauth.HeaderAuthorization, hmacauth.Authorization(clientID, signature))
+	return req
+}
+
+func TestVerifyRequest(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testService := hmacservice.NewHMACService(map[string][]byte{"billing": testSecret}, mc.ServiceSignatureRepository)
+
+	var testCases = []struct {
+		caseName     string
+		request      func() *http.Request
+		expectations func()
+		results      func(clientID string, err error)
+	}{
+		{
+			caseName: "VerifyRequest_Success",
+			request: func() *http.Request {
+				return newSignedRequest(http.MethodPost, "/v1/internal/users?x=1", `{"uid":"1"}`, "billing", testSecret, time.Now())
+			},
+			expectations: func() {
+				mc.ServiceSignatureRepository.On("UseSignature", mock.Anything, "billing", mock.Anything, mock.MatchedBy(func(expiresAt time.Time) bool {
+					return expiresAt.After(time.Now().Add(constant.ServiceAuthClockSkew - time.Minute))
+				})).Return(true, nil).Once()
+			},
+			results: func(clientID string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "billing", clientID)
+			},
+		},
+		{
+			caseName: "VerifyRequest_Replayed",
+			request: func() *http.Request {
+				return newSignedRequest(http.MethodGet, "/internal/users/replayed", "", "billing", testSecret, time.Now())
+			},
+			expectations: func() {
+				mc.ServiceSignatureRepository.On("UseSignature", mock.Anything, "billing", mock.Anything, mock.Anything).Return(false, nil).Once()
+			},
+			results: func(clientID string, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Empty(t, clientID)
+			},
+		},
+		{
+			caseName: "VerifyRequest_UnknownClient",
+			request: func() *http.Request {
+				return newSignedRequest(http.MethodGet, "/internal/users/1", "", "unknown", testSecret, time.Now())
+			},
+			expectations: func() {},
+			results: func(clientID string, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyRequest_WrongSecret",
+			request: func() *http.Request {
+				return newSignedRequest(http.MethodGet, "/internal/users/1", "", "billing", []byte("another secret"), time.Now())
+			},
+			expectations: func() {},
+			results: func(clientID string, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyRequest_TamperedBody",
+			request: func() *http.Request {
+				req := newSignedRequest(http.MethodPost, "/internal/users", `{"uid":"1"}`, "billing", testSecret, time.Now())
+				req.Body = io.NopCloser(strings.NewReader(`{"uid":"2"}`))
+				return req
+			},
+			expectations: func() {},
+			results: func(clientID string, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyRequest_Expired",
+			request: func() *http.Request {
+				return newSignedRequest(http.MethodGet, "/internal/users/1", "", "billing", testSecret, time.Now().Add(-constant.ServiceAuthClockSkew-time.Minute))
+			},
+			expectations: func() {},
+			results: func(clientID string, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyRequest_FromTheFuture",
+			request: func() *http.Request {
+				return newSignedRequest(http.MethodGet, "/internal/users/1", "", "billing", testSecret, time.Now().Add(constant.ServiceAuthClockSkew+time.Minute))
+			},
+			expectations: func() {},
+			results: func(clientID string, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyRequest_MissingHeaders",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/internal/users/1", nil)
+			},
+			expectations: func() {},
+			results: func(clientID string, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			clientID, err := testService.VerifyRequest(ctx, testCase.request())
+			testCase.results(clientID, err)
+		})
+	}
+}
+
+func TestVerifyRequest_BodyCanBeReadAgain(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	testService := hmacservice.NewHMACService(map[string][]byte{"billing": testSecret}, mc.ServiceSignatureRepository)
+
+	mc.ServiceSignatureRepository.On("UseSignature", mock.Anything, "billing", mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	req := newSignedRequest(http.MethodPost, "/internal/users", "payload", "billing", testSecret, time.Now())
+	_, err := testService.VerifyRequest(context.Background(), req)
+	assert.NoError(t, err)
+
+	body, err := io.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "payload", string(body))
+}
@@ -0,0 +1,170 @@
+package imageservice
+
+import (
+	"bytes"
+	"date-apps-be/internal/constant"
+	"date-apps-be/pkg/derrors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+type (
+	// Image is an uploaded picture re-encoded without any metadata, together with its thumbnail.
+	Image struct {
+		ContentType string
+		Width       int
+		Height      int
+		Data        []byte
+		Thumbnail   []byte
+	}
+
+	// ImageProcessor turns uploads into images that are safe to show to other users.
+	ImageProcessor interface {
+		// Process decodes a JPEG or PNG, applies the EXIF orientation and re-encodes it,
+		// which drops EXIF, GPS and every other metadata of the upload.
+		Process(data []byte) (img *Image, err error)
+	}
+
+	imageProcessor struct {
+		maxSize       int
+		thumbnailSize int
+		// sem holds a slot for every image being decoded
+		sem chan struct{}
+	}
+)
+
+// NewImageProcessor returns a processor whose images fit in a maxSize square
+// and whose thumbnails fit in a thumbnailSize square.
+func NewImageProcessor(maxSize, thumbnailSize int) ImageProcessor {
+	return &imageProcessor{
+		maxSize:       maxSize,
+		thumbnailSize: thumbnailSize,
+		sem:           make(chan struct{}, constant.MaxConcurrentPhotoProcessing),
+	}
+}
+
+func (p *imageProcessor) Process(data []byte) (img *Image, err error) {
+	defer derrors.Wrap(&err, "Process")
+
+	p.sem <- struct{}{}
+	defer func() { <-p.sem }()
+
+	contentType := http.DetectContentType(data)
+	if contentType != constant.PhotoContentTypeJPEG && contentType != constant.PhotoContentTypePNG {
+		return nil, derrors.New(derrors.InvalidArgument, "Only JPEG and PNG photos are supported")
+	}
+
+	// check the dimensions before decoding, a small file can decode to a huge image
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, derrors.New(derrors.InvalidArgument, "The photo can not be read")
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > constant.MaxPhotoPixels {
+		return nil, derrors.New(derrors.InvalidArgument, "The photo must have at most %d pixels", constant.MaxPhotoPixels)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, derrors.New(derrors.InvalidArgument, "The photo can not be read")
+	}
+
+	// scaled down before orienting, so only the full size copy is as large as the upload
+	rgba := toRGBA(decoded)
+	width, height := fit(rgba.Bounds().Dx(), rgba.Bounds().Dy(), p.maxSize)
+	rgba = resize(rgba, width, height)
+	if contentType == constant.PhotoContentTypeJPEG {
+		rgba = orient(rgba, exifOrientation(data))
+	}
+
+	img = &Image{
+		ContentType: contentType,
+		Width:       rgba.Bounds().Dx(),
+		Height:      rgba.Bounds().Dy(),
+	}
+
+	if img.Data, err = encode(rgba, contentType); err != nil {
+		return
+	}
+
+	width, height = fit(img.Width, img.Height, p.thumbnailSize)
+	if img.Thumbnail, err = encode(resize(rgba, width, height), contentType); err != nil {
+		return
+	}
+
+	return img, nil
+}
+
+func encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+	if contentType == constant.PhotoContentTypePNG {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: constant.PhotoJPEGQuality})
+	}
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "encode")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// fit scales width and height down to fit in a size square, smaller images keep their size.
+func fit(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+
+	if width >= height {
+		return size, max(1, height*size/width)
+	}
+	return max(1, width*size/height), size
+}
+
+// resize scales src down with a box filter, every pixel is the average of the pixels it covers.
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	if srcWidth == width && srcHeight == height {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcHeight/height, max((y+1)*srcHeight/height, y*srcHeight/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcWidth/width, max((x+1)*srcWidth/width, x*srcWidth/width+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					i := src.PixOffset(sx, sy)
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					b += int(src.Pix[i+2])
+					a += int(src.Pix[i+3])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,132 @@
+package imageservice_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"date-apps-be/internal/constant"
+	imageservice "date-apps-be/internal/service/image"
+	"date-apps-be/pkg/derrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// halves returns an image with a red left half and a blue right half.
+func halves(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// withExif inserts an EXIF segment with the orientation and a GPS block after the start of image marker.
+func withExif(t *testing.T, jpg []byte, orientation uint16) []byte {
+	tiff := new(bytes.Buffer)
+	write := func(v interface{}) { require.NoError(t, binary.Write(tiff, binary.LittleEndian, v)) }
+	tiff.WriteString("II")
+	write(uint16(42))
+	write(uint32(8))
+	write(uint16(2))
+	// orientation, a SHORT stored in the value field
+	write([]uint16{0x0112, 3})
+	write(uint32(1))
+	write([]uint16{orientation, 0})
+	// pointer to the GPS block
+	write([]uint16{0x8825, 4})
+	write(uint32(1))
+	write(uint32(38))
+	write(uint32(0))
+	tiff.WriteString("GPS-37.7749,-122.4194")
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, app1...)
+	out = append(out, segment...)
+	return append(out, jpg[2:]...)
+}
+
+func TestProcessJPEG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, halves(40, 20), &jpeg.Options{Quality: 100}))
+	upload := withExif(t, buf.Bytes(), 6)
+	require.True(t, bytes.Contains(upload, []byte("GPS-37.7749")))
+
+	img, err := imageservice.NewImageProcessor(constant.PhotoMaxSize, 10).Process(upload)
+	require.NoError(t, err)
+
+	assert.Equal(t, constant.PhotoContentTypeJPEG, img.ContentType)
+	assert.False(t, bytes.Contains(img.Data, []byte("Exif")), "the EXIF segment is dropped")
+	assert.False(t, bytes.Contains(img.Data, []byte("GPS-37.7749")), "the location is dropped")
+	assert.False(t, bytes.Contains(img.Thumbnail, []byte("GPS-37.7749")))
+
+	// rotated 90 degrees clockwise, the red left half is now on top
+	assert.Equal(t, 20, img.Width)
+	assert.Equal(t, 40, img.Height)
+	decoded, err := jpeg.Decode(bytes.NewReader(img.Data))
+	require.NoError(t, err)
+	r, _, b, _ := decoded.At(10, 5).RGBA()
+	assert.Greater(t, r, b, "top is red")
+	r, _, b, _ = decoded.At(10, 35).RGBA()
+	assert.Greater(t, b, r, "bottom is blue")
+
+	thumbnail, err := jpeg.DecodeConfig(bytes.NewReader(img.Thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, 5, thumbnail.Width)
+	assert.Equal(t, 10, thumbnail.Height)
+}
+
+func TestProcessScalesDown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, halves(40, 20), &jpeg.Options{Quality: 100}))
+
+	img, err := imageservice.NewImageProcessor(20, 10).Process(withExif(t, buf.Bytes(), 6))
+	require.NoError(t, err)
+
+	// scaled to fit the 20 square, then rotated
+	assert.Equal(t, 10, img.Width)
+	assert.Equal(t, 20, img.Height)
+	decoded, err := jpeg.DecodeConfig(bytes.NewReader(img.Data))
+	require.NoError(t, err)
+	assert.Equal(t, 10, decoded.Width)
+	assert.Equal(t, 20, decoded.Height)
+}
+
+func TestProcessPNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, halves(8, 4)))
+
+	img, err := imageservice.NewImageProcessor(constant.PhotoMaxSize, constant.PhotoThumbnailSize).Process(buf.Bytes())
+	require.NoError(t, err)
+
+	assert.Equal(t, constant.PhotoContentTypePNG, img.ContentType)
+	thumbnail, err := png.DecodeConfig(bytes.NewReader(img.Thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, 8, thumbnail.Width, "small images are not enlarged")
+	assert.Equal(t, 4, thumbnail.Height)
+}
+
+func TestProcessUnsupported(t *testing.T) {
+	processor := imageservice.NewImageProcessor(constant.PhotoMaxSize, constant.PhotoThumbnailSize)
+
+	_, err := processor.Process([]byte("GIF89a not really a gif"))
+	assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+
+	_, err = processor.Process([]byte("\xFF\xD8\xFF\xE0 truncated jpeg"))
+	assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+}
@@ -0,0 +1,90 @@
+package mailservice
+
+import (
+	"context"
+	"date-apps-be/infrastructure/config"
+	"date-apps-be/internal/constant"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type (
+	Mail struct {
+		To      string
+		Subject string
+		Body    string
+	}
+
+	// Mailer delivers plain text emails.
+	Mailer interface {
+		Send(ctx context.Context, mail Mail) (err error)
+	}
+
+	smtpMailer struct {
+		addr string
+		from string
+		auth smtp.Auth
+	}
+
+	logMailer struct {
+		log *zap.Logger
+	}
+)
+
+// NewMailer returns the mailer configured by conf.Mailer.
+func NewMailer(conf *config.Mail, log *zap.Logger) Mailer {
+	switch conf.Mailer {
+	case constant.MailerSMTP:
+		return NewSMTPMailer(conf)
+	default:
+		return NewLogMailer(log)
+	}
+}
+
+// NewSMTPMailer sends emails through an SMTP server,
+// authentication is skipped when no username is configured.
+func NewSMTPMailer(conf *config.Mail) Mailer {
+	var auth smtp.Auth
+	if conf.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", conf.SMTPUsername, conf.SMTPPassword, conf.SMTPHost)
+	}
+
+	return &smtpMailer{
+		addr: net.JoinHostPort(conf.SMTPHost, conf.SMTPPort),
+		from: conf.From,
+		auth: auth,
+	}
+}
+
+func (s *smtpMailer) Send(ctx context.Context, mail Mail) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mail.Subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(mail.Body)
+
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{mail.To}, []byte(msg.String()))
+}
+
+// NewLogMailer writes emails to the log instead of sending them, meant for development.
+func NewLogMailer(log *zap.Logger) Mailer {
+	return &logMailer{
+		log: log,
+	}
+}
+
+func (l *logMailer) Send(ctx context.Context, mail Mail) error {
+	l.log.Info("mail sent",
+		zap.String("to", mail.To),
+		zap.String("subject", mail.Subject),
+		zap.String("body", mail.Body),
+	)
+	return nil
+}
@@ -0,0 +1,34 @@
+package mailservice
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryMailer keeps every email in memory, meant for tests.
+type MemoryMailer struct {
+	mu    sync.Mutex
+	mails []Mail
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, mail Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mails = append(m.mails, mail)
+	return nil
+}
+
+// Mails returns every email sent so far.
+func (m *MemoryMailer) Mails() []Mail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mails := make([]Mail, len(m.mails))
+	copy(mails, m.mails)
+	return mails
+}
@@ -0,0 +1,96 @@
+package oidcservice
+
+import (
+	"context"
+	"crypto/rsa"
+	"date-apps-be/internal/constant"
+	"date-apps-be/pkg/jwk"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// KeySource resolves the public key an identity provider signed an ID token with.
+	KeySource interface {
+		Key(ctx context.Context, jwksURL, kid string) (key *rsa.PublicKey, err error)
+	}
+
+	httpKeySource struct {
+		client *http.Client
+		mu     sync.Mutex
+		cache  map[string]*cachedKeySet
+	}
+
+	cachedKeySet struct {
+		set       jwk.Set
+		fetchedAt time.Time
+	}
+)
+
+// NewHTTPKeySource fetches key sets over HTTP and caches them for OIDCJWKSCacheTTL.
+// An unknown kid refetches the key set, so rotated provider keys are picked up early.
+func NewHTTPKeySource(client *http.Client) KeySource {
+	if client == nil {
+		client = &http.Client{Timeout: constant.OIDCHTTPTimeout}
+	}
+
+	return &httpKeySource{
+		client: client,
+		cache:  map[string]*cachedKeySet{},
+	}
+}
+
+func (s *httpKeySource) Key(ctx context.Context, jwksURL, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cached := s.cache[jwksURL]
+	if cached == nil || time.Since(cached.fetchedAt) > constant.OIDCJWKSCacheTTL {
+		if err := s.refresh(ctx, jwksURL); err != nil {
+			return nil, err
+		}
+		cached = s.cache[jwksURL]
+	}
+
+	key, ok := cached.set.Key(kid)
+	if !ok && time.Since(cached.fetchedAt) > constant.OIDCJWKSMinRefreshInterval {
+		if err := s.refresh(ctx, jwksURL); err != nil {
+			return nil, err
+		}
+		key, ok = s.cache[jwksURL].set.Key(kid)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	return key.RSAPublicKey()
+}
+
+func (s *httpKeySource) refresh(ctx context.Context, jwksURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch %s: unexpected status %d", jwksURL, resp.StatusCode)
+	}
+
+	var set jwk.Set
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("decode %s: %w", jwksURL, err)
+	}
+
+	s.cache[jwksURL] = &cachedKeySet{set: set, fetchedAt: time.Now()}
+	return nil
+}
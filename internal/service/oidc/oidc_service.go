@@ -0,0 +1,130 @@
+package oidcservice
+
+import (
+	"context"
+	"crypto/subtle"
+	"date-apps-be/infrastructure/config"
+	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/util"
+	"encoding/json"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type (
+	// Identity is the verified user of an ID token.
+	Identity struct {
+		Provider      string
+		Subject       string
+		Email         string
+		EmailVerified bool
+		Name          string
+	}
+
+	// OIDCService verifies ID tokens of the configured identity providers.
+	OIDCService interface {
+		VerifyIDToken(ctx context.Context, provider, idToken, nonce string) (identity *Identity, err error)
+	}
+
+	oidcService struct {
+		providers map[string]*config.OIDCProvider
+		keySource KeySource
+	}
+
+	idTokenClaims struct {
+		Email         string  `json:"email"`
+		EmailVerified boolish `json:"email_verified"`
+		Name          string  `json:"name"`
+		Nonce         string  `json:"nonce"`
+		jwt.RegisteredClaims
+	}
+
+	// boolish accepts both true and "true", Apple sends boolean claims as strings.
+	boolish bool
+)
+
+func NewOIDCService(providers []*config.OIDCProvider, keySource KeySource) OIDCService {
+	byName := make(map[string]*config.OIDCProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name] = provider
+	}
+
+	return &oidcService{
+		providers: byName,
+		keySource: keySource,
+	}
+}
+
+// VerifyIDToken checks the signature against the provider key set, the issuer, the audience
+// and the expiry of an ID token. The nonce is compared when either the client sent one or the token
+// carries one, a token issued for a request with a nonce can not be replayed by leaving the nonce out.
+func (o *oidcService) VerifyIDToken(ctx context.Context, provider, idToken, nonce string) (identity *Identity, err error) {
+	defer derrors.Wrap(&err, "VerifyIDToken(%q)", provider)
+
+	conf, ok := o.providers[provider]
+	if !ok {
+		return nil, derrors.New(derrors.InvalidArgument, "Unsupported identity provider")
+	}
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return o.keySource.Key(ctx, conf.JWKSURL, kid)
+	}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithExpirationRequired(), jwt.WithIssuedAt())
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unauthorized, "Invalid ID token")
+	}
+
+	if !util.StringInSlice(conf.Issuers, claims.Issuer) {
+		return nil, derrors.New(derrors.Unauthorized, "Invalid ID token issuer")
+	}
+
+	if !hasAudience(claims.RegisteredClaims.Audience, conf.ClientIDs) {
+		return nil, derrors.New(derrors.Unauthorized, "Invalid ID token audience")
+	}
+
+	if claims.Subject == "" {
+		return nil, derrors.New(derrors.Unauthorized, "Invalid ID token subject")
+	}
+
+	if (nonce != "" || claims.Nonce != "") && subtle.ConstantTimeCompare([]byte(nonce), []byte(claims.Nonce)) != 1 {
+		return nil, derrors.New(derrors.Unauthorized, "Invalid ID token nonce")
+	}
+
+	return &Identity{
+		Provider:      provider,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+func hasAudience(audience jwt.ClaimStrings, clientIDs []string) bool {
+	for _, aud := range audience {
+		if util.StringInSlice(clientIDs, aud) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *boolish) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		*b = boolish(v)
+	case string:
+		*b = v == "true"
+	case nil:
+		*b = false
+	default:
+		return errors.New("email_verified must be a boolean")
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package oidcservice_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"date-apps-be/infrastructure/config"
+	oidcservice "date-apps-be/internal/service/oidc"
+	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/jwk"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testClientID = "date-apps-ios"
+)
+
+// fakeIssuer serves a JWKS like a real identity provider and signs ID tokens with its key.
+type fakeIssuer struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	kid      string
+	requests atomic.Int32
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &fakeIssuer{key: key, kid: "key-1"}
+	issuer.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuer.requests.Add(1)
+		_ = json.NewEncoder(w).Encode(jwk.Set{Keys: []jwk.Key{jwk.NewRSAKey(issuer.kid, &issuer.key.PublicKey)}})
+	}))
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (f *fakeIssuer) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = f.kid
+	signed, err := token.SignedString(f.key)
+	require.NoError(t, err)
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            testIssuer,
+		"aud":            testClientID,
+		"sub":            "subject-1",
+		"email":          "john@example.com",
+		"email_verified": "true",
+		"name":           "John",
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	ctx := context.Background()
+	issuer := newFakeIssuer(t)
+	otherIssuer := newFakeIssuer(t)
+
+	testService := oidcservice.NewOIDCService([]*config.OIDCProvider{{
+		Name:      "google",
+		Issuers:   []string{testIssuer},
+		ClientIDs: []string{testClientID},
+		JWKSURL:   issuer.server.URL,
+	}}, oidcservice.NewHTTPKeySource(issuer.server.Client()))
+
+	var testCases = []struct {
+		caseName string
+		provider string
+		token    func() string
+		nonce    string
+		results  func(identity *oidcservice.Identity, err error)
+	}{
+		{
+			caseName: "VerifyIDToken_Success",
+			provider: "google",
+			token:    func() string { return issuer.sign(t, validClaims()) },
+			results: func(identity *oidcservice.Identity, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "subject-1", identity.Subject)
+				assert.Equal(t, "john@example.com", identity.Email)
+				assert.True(t, identity.EmailVerified)
+			},
+		},
+		{
+			caseName: "VerifyIDToken_UnsupportedProvider",
+			provider: "facebook",
+			token:    func() string { return issuer.sign(t, validClaims()) },
+			results: func(identity *oidcservice.Identity, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, identity)
+			},
+		},
+		{
+			caseName: "VerifyIDToken_WrongAudience",
+			provider: "google",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "another-app"
+				return issuer.sign(t, claims)
+			},
+			results: func(identity *oidcservice.Identity, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyIDToken_WrongIssuer",
+			provider: "google",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://evil.example.com"
+				return issuer.sign(t, claims)
+			},
+			results: func(identity *oidcservice.Identity, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyIDToken_Expired",
+			provider: "google",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return issuer.sign(t, claims)
+			},
+			results: func(identity *oidcservice.Identity, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyIDToken_SignedByAnotherKey",
+			provider: "google",
+			token:    func() string { return otherIssuer.sign(t, validClaims()) },
+			results: func(identity *oidcservice.Identity, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyIDToken_NonceMismatch",
+			provider: "google",
+			token: func() string {
+				claims := validClaims()
+				claims["nonce"] = "nonce-1"
+				return issuer.sign(t, claims)
+			},
+			nonce: "nonce-2",
+			results: func(identity *oidcservice.Identity, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyIDToken_NonceMissing",
+			provider: "google",
+			token: func() string {
+				claims := validClaims()
+				claims["nonce"] = "nonce-1"
+				return issuer.sign(t, claims)
+			},
+			results: func(identity *oidcservice.Identity, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, identity)
+			},
+		},
+		{
+			caseName: "VerifyIDToken_NonceMatch",
+			provider: "google",
+			token: func() string {
+				claims := validClaims()
+				claims["nonce"] = "nonce-1"
+				return issuer.sign(t, claims)
+			},
+			nonce: "nonce-1",
+			results: func(identity *oidcservice.Identity, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "subject-1", identity.Subject)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			identity, err := testService.VerifyIDToken(ctx, testCase.provider, testCase.token(), testCase.nonce)
+			testCase.results(identity, err)
+		})
+	}
+}
+
+func TestHTTPKeySourceCache(t *testing.T) {
+	ctx := context.Background()
+	issuer := newFakeIssuer(t)
+	keySource := oidcservice.NewHTTPKeySource(issuer.server.Client())
+
+	for i := 0; i < 3; i++ {
+		key, err := keySource.Key(ctx, issuer.server.URL, issuer.kid)
+		require.NoError(t, err)
+		assert.Equal(t, issuer.key.PublicKey.N, key.N)
+	}
+	assert.Equal(t, int32(1), issuer.requests.Load(), "the key set is fetched once and cached")
+
+	// an unknown kid right after a fetch does not hammer the provider
+	_, err := keySource.Key(ctx, issuer.server.URL, "unknown")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), issuer.requests.Load())
+}
@@ -0,0 +1,44 @@
+package smsservice
+
+import (
+	"context"
+	"sync"
+)
+
+type (
+	Message struct {
+		PhoneNumber string
+		Body        string
+	}
+
+	// MemorySMSSender keeps every message in memory, meant for tests.
+	MemorySMSSender struct {
+		mu       sync.Mutex
+		messages []Message
+	}
+)
+
+func NewMemorySMSSender() *MemorySMSSender {
+	return &MemorySMSSender{}
+}
+
+func (m *MemorySMSSender) Send(ctx context.Context, phoneNumber, message string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, Message{
+		PhoneNumber: phoneNumber,
+		Body:        message,
+	})
+	return nil
+}
+
+// Messages returns every message sent so far.
+func (m *MemorySMSSender) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]Message, len(m.messages))
+	copy(messages, m.messages)
+	return messages
+}
@@ -0,0 +1,45 @@
+package smsservice
+
+import (
+	"context"
+	"date-apps-be/internal/constant"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// SMSSender delivers text messages to phone numbers.
+	SMSSender interface {
+		Send(ctx context.Context, phoneNumber, message string) (err error)
+	}
+
+	logSMSSender struct {
+		log *zap.Logger
+	}
+)
+
+// NewSMSSender returns the SMS sender configured by senderType.
+// Only the log sender is available until an SMS provider is integrated.
+func NewSMSSender(senderType string, log *zap.Logger) SMSSender {
+	switch senderType {
+	case constant.SMSSenderLog:
+		return NewLogSMSSender(log)
+	default:
+		return NewLogSMSSender(log)
+	}
+}
+
+// NewLogSMSSender writes messages to the log instead of sending them, meant for development.
+func NewLogSMSSender(log *zap.Logger) SMSSender {
+	return &logSMSSender{
+		log: log,
+	}
+}
+
+func (l *logSMSSender) Send(ctx context.Context, phoneNumber, message string) error {
+	l.log.Info("sms sent",
+		zap.String("phone_number", phoneNumber),
+		zap.String("message", message),
+	)
+	return nil
+}
@@ -11,31 +11,100 @@ import (
 )
 
 type MockComponent struct {
-	Config                  *config.Config
-	UserRepository          *mockrepository.UserRepository
-	UserMatchRepository     *mockrepository.UserMatchRepository
-	UserPremiumRepository   *mockrepository.UserPremiumRepository
-	PremiumConfigRepository *mockrepository.PremiumConfigRepository
-	UserUsecase             *mockusecase.UserUsecase
-	UserMatchUsecase        *mockusecase.UserMatchUsecase
-	PremiumConfigUsecase    *mockusecase.PremiumConfigUsecase
-	AuthService             *mockservice.AuthService
+	Config                     *config.Config
+	UserRepository             *mockrepository.UserRepository
+	UserMatchRepository        *mockrepository.UserMatchRepository
+	UserPremiumRepository      *mockrepository.UserPremiumRepository
+	PremiumConfigRepository    *mockrepository.PremiumConfigRepository
+	LoginHistoryRepository     *mockrepository.LoginHistoryRepository
+	RefreshTokenRepository     *mockrepository.RefreshTokenRepository
+	RevokedTokenRepository     *mockrepository.RevokedTokenRepository
+	OTPCodeRepository          *mockrepository.OTPCodeRepository
+	PasswordResetRepository    *mockrepository.PasswordResetRepository
+	UserMFARepository          *mockrepository.UserMFARepository
+	UserIdentityRepository     *mockrepository.UserIdentityRepository
+	UserRoleRepository         *mockrepository.UserRoleRepository
+	UserSessionRepository      *mockrepository.UserSessionRepository
+	ServiceSignatureRepository *mockrepository.ServiceSignatureRepository
+	DataExportRepository       *mockrepository.DataExportRepository
+	UserStatusAuditRepository  *mockrepository.UserStatusAuditRepository
+	UserPhotoRepository        *mockrepository.UserPhotoRepository
+	UserPreferenceRepository   *mockrepository.UserPreferenceRepository
+	UserLocationRepository     *mockrepository.UserLocationRepository
+	MutualMatchRepository      *mockrepository.MutualMatchRepository
+	UserRewindRepository       *mockrepository.UserRewindRepository
+	HiddenPairRepository       *mockrepository.HiddenPairRepository
+	UserUsecase                *mockusecase.UserUsecase
+	UserMatchUsecase           *mockusecase.UserMatchUsecase
+	PremiumConfigUsecase       *mockusecase.PremiumConfigUsecase
+	OTPUsecase                 *mockusecase.OTPUsecase
+	AccountUsecase             *mockusecase.AccountUsecase
+	MFAUsecase                 *mockusecase.MFAUsecase
+	RoleUsecase                *mockusecase.RoleUsecase
+	SessionUsecase             *mockusecase.SessionUsecase
+	DataExportUsecase          *mockusecase.DataExportUsecase
+	ModerationUsecase          *mockusecase.ModerationUsecase
+	PhotoUsecase               *mockusecase.PhotoUsecase
+	PreferenceUsecase          *mockusecase.PreferenceUsecase
+	LocationUsecase            *mockusecase.LocationUsecase
+	LoginAttemptUsecase        *mockusecase.LoginAttemptUsecase
+	AuthService                *mockservice.AuthService
+	OIDCService                *mockservice.OIDCService
+	HMACService                *mockservice.HMACService
 }
 
 func InitMockComponent(t *testing.T) *MockComponent {
+	privateKey := generatePrivateKey()
+
 	return &MockComponent{
 		Config: &config.Config{
-			JWTRS256PrivateKey: generatePrivateKey(),
-			JWTExpiration:      10,
+			JWTRS256PrivateKey:         privateKey,
+			JWTRS256PubKey:             &privateKey.PublicKey,
+			JWTKeyID:                   "test",
+			JWTExpiration:              10,
+			MFAEncryptionKey:           []byte("0123456789abcdef0123456789abcdef"),
+			AccountDeletionGracePeriod: 60,
+			DataExportSecret:           []byte("secret"),
 		},
-		UserRepository:          mockrepository.NewUserRepository(t),
-		UserMatchRepository:     mockrepository.NewUserMatchRepository(t),
-		UserPremiumRepository:   mockrepository.NewUserPremiumRepository(t),
-		PremiumConfigRepository: mockrepository.NewPremiumConfigRepository(t),
-		UserUsecase:             mockusecase.NewUserUsecase(t),
-		UserMatchUsecase:        mockusecase.NewUserMatchUsecase(t),
-		PremiumConfigUsecase:    mockusecase.NewPremiumConfigUsecase(t),
-		AuthService:             mockservice.NewAuthService(t),
+		UserRepository:             mockrepository.NewUserRepository(t),
+		UserMatchRepository:        mockrepository.NewUserMatchRepository(t),
+		UserPremiumRepository:      mockrepository.NewUserPremiumRepository(t),
+		PremiumConfigRepository:    mockrepository.NewPremiumConfigRepository(t),
+		LoginHistoryRepository:     mockrepository.NewLoginHistoryRepository(t),
+		RefreshTokenRepository:     mockrepository.NewRefreshTokenRepository(t),
+		RevokedTokenRepository:     mockrepository.NewRevokedTokenRepository(t),
+		OTPCodeRepository:          mockrepository.NewOTPCodeRepository(t),
+		PasswordResetRepository:    mockrepository.NewPasswordResetRepository(t),
+		UserMFARepository:          mockrepository.NewUserMFARepository(t),
+		UserIdentityRepository:     mockrepository.NewUserIdentityRepository(t),
+		UserRoleRepository:         mockrepository.NewUserRoleRepository(t),
+		UserSessionRepository:      mockrepository.NewUserSessionRepository(t),
+		ServiceSignatureRepository: mockrepository.NewServiceSignatureRepository(t),
+		DataExportRepository:       mockrepository.NewDataExportRepository(t),
+		UserStatusAuditRepository:  mockrepository.NewUserStatusAuditRepository(t),
+		UserPhotoRepository:        mockrepository.NewUserPhotoRepository(t),
+		UserPreferenceRepository:   mockrepository.NewUserPreferenceRepository(t),
+		UserLocationRepository:     mockrepository.NewUserLocationRepository(t),
+		MutualMatchRepository:      mockrepository.NewMutualMatchRepository(t),
+		UserRewindRepository:       mockrepository.NewUserRewindRepository(t),
+		HiddenPairRepository:       mockrepository.NewHiddenPairRepository(t),
+		UserUsecase:                mockusecase.NewUserUsecase(t),
+		UserMatchUsecase:           mockusecase.NewUserMatchUsecase(t),
+		PremiumConfigUsecase:       mockusecase.NewPremiumConfigUsecase(t),
+		OTPUsecase:                 mockusecase.NewOTPUsecase(t),
+		AccountUsecase:             mockusecase.NewAccountUsecase(t),
+		MFAUsecase:                 mockusecase.NewMFAUsecase(t),
+		RoleUsecase:                mockusecase.NewRoleUsecase(t),
+		SessionUsecase:             mockusecase.NewSessionUsecase(t),
+		DataExportUsecase:          mockusecase.NewDataExportUsecase(t),
+		ModerationUsecase:          mockusecase.NewModerationUsecase(t),
+		PhotoUsecase:               mockusecase.NewPhotoUsecase(t),
+		PreferenceUsecase:          mockusecase.NewPreferenceUsecase(t),
+		LocationUsecase:            mockusecase.NewLocationUsecase(t),
+		LoginAttemptUsecase:        mockusecase.NewLoginAttemptUsecase(t),
+		AuthService:                mockservice.NewAuthService(t),
+		OIDCService:                mockservice.NewOIDCService(t),
+		HMACService:                mockservice.NewHMACService(t),
 	}
 }
 
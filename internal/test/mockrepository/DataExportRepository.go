@@ -0,0 +1,530 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockrepository
+
+import (
+	context "context"
+
+	datatype "date-apps-be/pkg/datatype"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "date-apps-be/internal/model"
+
+	sql "database/sql"
+
+	time "time"
+)
+
+// DataExportRepository is an autogenerated mock type for the DataExportRepository type
+type DataExportRepository struct {
+	mock.Mock
+}
+
+// AddSortQuery provides a mock function with given fields: query, allowedFields, sortBy
+func (_m *DataExportRepository) AddSortQuery(query string, allowedFields []string, sortBy string) (string, error) {
+	ret := _m.Called(query, allowedFields, sortBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSortQuery")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []string, string) (string, error)); ok {
+		return rf(query, allowedFields, sortBy)
+	}
+	if rf, ok := ret.Get(0).(func(string, []string, string) string); ok {
+		r0 = rf(query, allowedFields, sortBy)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []string, string) error); ok {
+		r1 = rf(query, allowedFields, sortBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddSortQueryWithPrefix provides a mock function with given fields: query, allowedFields, sortBy
+func (_m *DataExportRepository) AddSortQueryWithPrefix(query string, allowedFields map[string]string, sortBy string) (string, error) {
+	ret := _m.Called(query, allowedFields, sortBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSortQueryWithPrefix")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string) (string, error)); ok {
+		return rf(query, allowedFields, sortBy)
+	}
+	if rf, ok := ret.Get(0).(func(string, map[string]string, string) string); ok {
+		r0 = rf(query, allowedFields, sortBy)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, map[string]string, string) error); ok {
+		r1 = rf(query, allowedFields, sortBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Begin provides a mock function with given fields:
+func (_m *DataExportRepository) Begin() (*sql.Tx, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 *sql.Tx
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*sql.Tx, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *sql.Tx); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.Tx)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimDataExport provides a mock function with given fields: ctx, uid
+func (_m *DataExportRepository) ClaimDataExport(ctx context.Context, uid string) (bool, error) {
+	ret := _m.Called(ctx, uid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDataExport")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, uid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, uid)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Commit provides a mock function with given fields: tx
+func (_m *DataExportRepository) Commit(tx *sql.Tx) error {
+	ret := _m.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Commit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx) error); ok {
+		r0 = rf(tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteDataExport provides a mock function with given fields: ctx, uid, blobKey, size, expiresAt
+func (_m *DataExportRepository) CompleteDataExport(ctx context.Context, uid string, blobKey string, size int64, expiresAt datatype.Time) error {
+	ret := _m.Called(ctx, uid, blobKey, size, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteDataExport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, datatype.Time) error); ok {
+		r0 = rf(ctx, uid, blobKey, size, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountDataExports provides a mock function with given fields: ctx, userUID, window
+func (_m *DataExportRepository) CountDataExports(ctx context.Context, userUID string, window time.Duration) (int, error) {
+	ret := _m.Called(ctx, userUID, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountDataExports")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (int, error)); ok {
+		return rf(ctx, userUID, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) int); ok {
+		r0 = rf(ctx, userUID, window)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, userUID, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateDataExport provides a mock function with given fields: ctx, tx, export
+func (_m *DataExportRepository) CreateDataExport(ctx context.Context, tx *sql.Tx, export *model.DataExport) error {
+	ret := _m.Called(ctx, tx, export)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDataExport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *model.DataExport) error); ok {
+		r0 = rf(ctx, tx, export)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Exec provides a mock function with given fields: ctx, tx, query, args
+func (_m *DataExportRepository) Exec(ctx context.Context, tx *sql.Tx, query string, args []interface{}) (sql.Result, error) {
+	ret := _m.Called(ctx, tx, query, args)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exec")
+	}
+
+	var r0 sql.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, []interface{}) (sql.Result, error)); ok {
+		return rf(ctx, tx, query, args)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, []interface{}) sql.Result); ok {
+		r0 = rf(ctx, tx, query, args)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(sql.Result)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string, []interface{}) error); ok {
+		r1 = rf(ctx, tx, query, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpireDataExport provides a mock function with given fields: ctx, uid
+func (_m *DataExportRepository) ExpireDataExport(ctx context.Context, uid string) error {
+	ret := _m.Called(ctx, uid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireDataExport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, uid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FailDataExport provides a mock function with given fields: ctx, uid, reason
+func (_m *DataExportRepository) FailDataExport(ctx context.Context, uid string, reason string) error {
+	ret := _m.Called(ctx, uid, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailDataExport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, uid, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetActiveDataExport provides a mock function with given fields: ctx, userUID
+func (_m *DataExportRepository) GetActiveDataExport(ctx context.Context, userUID string) (*model.DataExport, error) {
+	ret := _m.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActiveDataExport")
+	}
+
+	var r0 *model.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.DataExport, error)); ok {
+		return rf(ctx, userUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.DataExport); ok {
+		r0 = rf(ctx, userUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDataExport provides a mock function with given fields: ctx, uid
+func (_m *DataExportRepository) GetDataExport(ctx context.Context, uid string) (*model.DataExport, error) {
+	ret := _m.Called(ctx, uid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDataExport")
+	}
+
+	var r0 *model.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.DataExport, error)); ok {
+		return rf(ctx, uid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.DataExport); ok {
+		r0 = rf(ctx, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExpiredDataExports provides a mock function with given fields: ctx, limit
+func (_m *DataExportRepository) GetExpiredDataExports(ctx context.Context, limit uint64) ([]*model.DataExport, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExpiredDataExports")
+	}
+
+	var r0 []*model.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]*model.DataExport, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []*model.DataExport); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOffset provides a mock function with given fields: page, limit
+func (_m *DataExportRepository) GetOffset(page uint64, limit uint64) uint64 {
+	ret := _m.Called(page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOffset")
+	}
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(uint64, uint64) uint64); ok {
+		r0 = rf(page, limit)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
+// GetPendingDataExports provides a mock function with given fields: ctx, limit
+func (_m *DataExportRepository) GetPendingDataExports(ctx context.Context, limit uint64) ([]*model.DataExport, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingDataExports")
+	}
+
+	var r0 []*model.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]*model.DataExport, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []*model.DataExport); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Master provides a mock function with given fields:
+func (_m *DataExportRepository) Master() *sql.DB {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Master")
+	}
+
+	var r0 *sql.DB
+	if rf, ok := ret.Get(0).(func() *sql.DB); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.DB)
+		}
+	}
+
+	return r0
+}
+
+// NewNullString provides a mock function with given fields: str
+func (_m *DataExportRepository) NewNullString(str *string) sql.NullString {
+	ret := _m.Called(str)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewNullString")
+	}
+
+	var r0 sql.NullString
+	if rf, ok := ret.Get(0).(func(*string) sql.NullString); ok {
+		r0 = rf(str)
+	} else {
+		r0 = ret.Get(0).(sql.NullString)
+	}
+
+	return r0
+}
+
+// Query provides a mock function with given fields: ctx, query, dest, args
+func (_m *DataExportRepository) Query(ctx context.Context, query string, dest []interface{}, args []interface{}) error {
+	ret := _m.Called(ctx, query, dest, args)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Query")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []interface{}, []interface{}) error); ok {
+		r0 = rf(ctx, query, dest, args)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Rollback provides a mock function with given fields: tx
+func (_m *DataExportRepository) Rollback(tx *sql.Tx) error {
+	ret := _m.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rollback")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx) error); ok {
+		r0 = rf(tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Slave provides a mock function with given fields:
+func (_m *DataExportRepository) Slave() *sql.DB {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Slave")
+	}
+
+	var r0 *sql.DB
+	if rf, ok := ret.Get(0).(func() *sql.DB); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.DB)
+		}
+	}
+
+	return r0
+}
+
+// NewDataExportRepository creates a new instance of DataExportRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataExportRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataExportRepository {
+	mock := &DataExportRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
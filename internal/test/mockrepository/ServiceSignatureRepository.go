@@ -0,0 +1,86 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockrepository
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ServiceSignatureRepository is an autogenerated mock type for the ServiceSignatureRepository type
+type ServiceSignatureRepository struct {
+	mock.Mock
+}
+
+// DeleteExpiredSignatures provides a mock function with given fields: ctx
+func (_m *ServiceSignatureRepository) DeleteExpiredSignatures(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredSignatures")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseSignature provides a mock function with given fields: ctx, clientID, signature, expiresAt
+func (_m *ServiceSignatureRepository) UseSignature(ctx context.Context, clientID string, signature string, expiresAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, clientID, signature, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseSignature")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (bool, error)); ok {
+		return rf(ctx, clientID, signature, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) bool); ok {
+		r0 = rf(ctx, clientID, signature, expiresAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, clientID, signature, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewServiceSignatureRepository creates a new instance of ServiceSignatureRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewServiceSignatureRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ServiceSignatureRepository {
+	mock := &ServiceSignatureRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
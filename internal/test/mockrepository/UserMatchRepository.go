@@ -11,6 +11,8 @@ import (
 	model "date-apps-be/internal/model"
 
 	sql "database/sql"
+
+	time "time"
 )
 
 // UserMatchRepository is an autogenerated mock type for the UserMatchRepository type
@@ -122,17 +124,35 @@ func (_m *UserMatchRepository) Commit(tx *sql.Tx) error {
 	return r0
 }
 
-// CreateUserMatch provides a mock function with given fields: ctx, userMatch
-func (_m *UserMatchRepository) CreateUserMatch(ctx context.Context, userMatch *model.UserMatch) error {
-	ret := _m.Called(ctx, userMatch)
+// CreateUserMatch provides a mock function with given fields: ctx, tx, userMatch
+func (_m *UserMatchRepository) CreateUserMatch(ctx context.Context, tx *sql.Tx, userMatch *model.UserMatch) error {
+	ret := _m.Called(ctx, tx, userMatch)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUserMatch")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *model.UserMatch) error); ok {
-		r0 = rf(ctx, userMatch)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *model.UserMatch) error); ok {
+		r0 = rf(ctx, tx, userMatch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUserMatch provides a mock function with given fields: ctx, tx, id
+func (_m *UserMatchRepository) DeleteUserMatch(ctx context.Context, tx *sql.Tx, id int64) error {
+	ret := _m.Called(ctx, tx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserMatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, int64) error); ok {
+		r0 = rf(ctx, tx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -170,14 +190,74 @@ func (_m *UserMatchRepository) Exec(ctx context.Context, tx *sql.Tx, query strin
 	return r0, r1
 }
 
-// GetAvailableUsers provides a mock function with given fields: ctx, userUID, page, limit
-func (_m *UserMatchRepository) GetAvailableUsers(ctx context.Context, userUID string, page uint64, limit uint64) ([]*model.User, error) {
-	ret := _m.Called(ctx, userUID, page, limit)
+// GetAvailableUsers provides a mock function with given fields: ctx, d
+func (_m *UserMatchRepository) GetAvailableUsers(ctx context.Context, d dto.GetAvailableUsers) ([]*model.User, error) {
+	ret := _m.Called(ctx, d)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAvailableUsers")
 	}
 
+	var r0 []*model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetAvailableUsers) ([]*model.User, error)); ok {
+		return rf(ctx, d)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetAvailableUsers) []*model.User); ok {
+		r0 = rf(ctx, d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetAvailableUsers) error); ok {
+		r1 = rf(ctx, d)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestUserMatch provides a mock function with given fields: ctx, tx, userUID, matchUID
+func (_m *UserMatchRepository) GetLatestUserMatch(ctx context.Context, tx *sql.Tx, userUID string, matchUID string) (*model.UserMatch, error) {
+	ret := _m.Called(ctx, tx, userUID, matchUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestUserMatch")
+	}
+
+	var r0 *model.UserMatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string) (*model.UserMatch, error)); ok {
+		return rf(ctx, tx, userUID, matchUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string) *model.UserMatch); ok {
+		r0 = rf(ctx, tx, userUID, matchUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserMatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string, string) error); ok {
+		r1 = rf(ctx, tx, userUID, matchUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLikesReceived provides a mock function with given fields: ctx, userUID, page, limit
+func (_m *UserMatchRepository) GetLikesReceived(ctx context.Context, userUID string, page uint64, limit uint64) ([]*model.User, error) {
+	ret := _m.Called(ctx, userUID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLikesReceived")
+	}
+
 	var r0 []*model.User
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, uint64) ([]*model.User, error)); ok {
@@ -218,12 +298,42 @@ func (_m *UserMatchRepository) GetOffset(page uint64, limit uint64) uint64 {
 	return r0
 }
 
-// GetTotalUserMatchToday provides a mock function with given fields: ctx, userUID
-func (_m *UserMatchRepository) GetTotalUserMatchToday(ctx context.Context, userUID string) (int, error) {
+// GetRewindableUserMatch provides a mock function with given fields: ctx, tx, userUID, window
+func (_m *UserMatchRepository) GetRewindableUserMatch(ctx context.Context, tx *sql.Tx, userUID string, window time.Duration) (*model.UserMatch, error) {
+	ret := _m.Called(ctx, tx, userUID, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRewindableUserMatch")
+	}
+
+	var r0 *model.UserMatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, time.Duration) (*model.UserMatch, error)); ok {
+		return rf(ctx, tx, userUID, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, time.Duration) *model.UserMatch); ok {
+		r0 = rf(ctx, tx, userUID, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserMatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string, time.Duration) error); ok {
+		r1 = rf(ctx, tx, userUID, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTotalLikesReceived provides a mock function with given fields: ctx, userUID
+func (_m *UserMatchRepository) GetTotalLikesReceived(ctx context.Context, userUID string) (int, error) {
 	ret := _m.Called(ctx, userUID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTotalUserMatchToday")
+		panic("no return value specified for GetTotalLikesReceived")
 	}
 
 	var r0 int
@@ -246,6 +356,92 @@ func (_m *UserMatchRepository) GetTotalUserMatchToday(ctx context.Context, userU
 	return r0, r1
 }
 
+// GetTotalSuperLikeToday provides a mock function with given fields: ctx, tx, userUID
+func (_m *UserMatchRepository) GetTotalSuperLikeToday(ctx context.Context, tx *sql.Tx, userUID string) (int, error) {
+	ret := _m.Called(ctx, tx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTotalSuperLikeToday")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) (int, error)); ok {
+		return rf(ctx, tx, userUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) int); ok {
+		r0 = rf(ctx, tx, userUID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string) error); ok {
+		r1 = rf(ctx, tx, userUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTotalUserMatchToday provides a mock function with given fields: ctx, tx, userUID
+func (_m *UserMatchRepository) GetTotalUserMatchToday(ctx context.Context, tx *sql.Tx, userUID string) (int, error) {
+	ret := _m.Called(ctx, tx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTotalUserMatchToday")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) (int, error)); ok {
+		return rf(ctx, tx, userUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) int); ok {
+		r0 = rf(ctx, tx, userUID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string) error); ok {
+		r1 = rf(ctx, tx, userUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserMatchHistory provides a mock function with given fields: ctx, userUID, page, limit
+func (_m *UserMatchRepository) GetUserMatchHistory(ctx context.Context, userUID string, page uint64, limit uint64) ([]*model.UserMatch, error) {
+	ret := _m.Called(ctx, userUID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserMatchHistory")
+	}
+
+	var r0 []*model.UserMatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, uint64) ([]*model.UserMatch, error)); ok {
+		return rf(ctx, userUID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, uint64) []*model.UserMatch); ok {
+		r0 = rf(ctx, userUID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserMatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, uint64) error); ok {
+		r1 = rf(ctx, userUID, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserMatchTodayByUserUIDAndMatchUID provides a mock function with given fields: ctx, userUID, matchUID
 func (_m *UserMatchRepository) GetUserMatchTodayByUserUIDAndMatchUID(ctx context.Context, userUID string, matchUID string) (*model.UserMatch, error) {
 	ret := _m.Called(ctx, userUID, matchUID)
@@ -306,6 +502,42 @@ func (_m *UserMatchRepository) GetUserMatches(ctx context.Context, d dto.GetUser
 	return r0, r1
 }
 
+// LockSwipePair provides a mock function with given fields: ctx, tx, userUID, matchUID
+func (_m *UserMatchRepository) LockSwipePair(ctx context.Context, tx *sql.Tx, userUID string, matchUID string) error {
+	ret := _m.Called(ctx, tx, userUID, matchUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockSwipePair")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string) error); ok {
+		r0 = rf(ctx, tx, userUID, matchUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LockUser provides a mock function with given fields: ctx, tx, userUID
+func (_m *UserMatchRepository) LockUser(ctx context.Context, tx *sql.Tx, userUID string) error {
+	ret := _m.Called(ctx, tx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) error); ok {
+		r0 = rf(ctx, tx, userUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Master provides a mock function with given fields:
 func (_m *UserMatchRepository) Master() *sql.DB {
 	ret := _m.Called()
@@ -216,6 +216,36 @@ func (_m *UserPremiumRepository) GetUserPackage(ctx context.Context, userUID str
 	return r0, r1
 }
 
+// GetUserPackages provides a mock function with given fields: ctx, userUID
+func (_m *UserPremiumRepository) GetUserPackages(ctx context.Context, userUID string) ([]*model.UserPackage, error) {
+	ret := _m.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserPackages")
+	}
+
+	var r0 []*model.UserPackage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.UserPackage, error)); ok {
+		return rf(ctx, userUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.UserPackage); ok {
+		r0 = rf(ctx, userUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserPackage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Master provides a mock function with given fields:
 func (_m *UserPremiumRepository) Master() *sql.DB {
 	ret := _m.Called()
@@ -4,11 +4,15 @@ package mockrepository
 
 import (
 	context "context"
-	model "date-apps-be/internal/model"
+	datatype "date-apps-be/pkg/datatype"
 
 	mock "github.com/stretchr/testify/mock"
 
+	model "date-apps-be/internal/model"
+
 	sql "database/sql"
+
+	time "time"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -148,24 +152,6 @@ func (_m *UserRepository) CreateUser(ctx context.Context, tx *sql.Tx, user *mode
 	return r0, r1
 }
 
-// DeleteUser provides a mock function with given fields: ctx, tx, id
-func (_m *UserRepository) DeleteUser(ctx context.Context, tx *sql.Tx, id string) error {
-	ret := _m.Called(ctx, tx, id)
-
-	if len(ret) == 0 {
-		panic("no return value specified for DeleteUser")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) error); ok {
-		r0 = rf(ctx, tx, id)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
 // Exec provides a mock function with given fields: ctx, tx, query, args
 func (_m *UserRepository) Exec(ctx context.Context, tx *sql.Tx, query string, args []interface{}) (sql.Result, error) {
 	ret := _m.Called(ctx, tx, query, args)
@@ -196,6 +182,36 @@ func (_m *UserRepository) Exec(ctx context.Context, tx *sql.Tx, query string, ar
 	return r0, r1
 }
 
+// GetDeletedUserUIDs provides a mock function with given fields: ctx, deletedBefore, limit
+func (_m *UserRepository) GetDeletedUserUIDs(ctx context.Context, deletedBefore time.Time, limit uint64) ([]string, error) {
+	ret := _m.Called(ctx, deletedBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletedUserUIDs")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, uint64) ([]string, error)); ok {
+		return rf(ctx, deletedBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, uint64) []string); ok {
+		r0 = rf(ctx, deletedBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, uint64) error); ok {
+		r1 = rf(ctx, deletedBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetOffset provides a mock function with given fields: page, limit
 func (_m *UserRepository) GetOffset(page uint64, limit uint64) uint64 {
 	ret := _m.Called(page, limit)
@@ -274,6 +290,36 @@ func (_m *UserRepository) GetUserByUID(ctx context.Context, id string) (*model.U
 	return r0, r1
 }
 
+// GetUserForUpdate provides a mock function with given fields: ctx, tx, uid
+func (_m *UserRepository) GetUserForUpdate(ctx context.Context, tx *sql.Tx, uid string) (*model.User, error) {
+	ret := _m.Called(ctx, tx, uid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserForUpdate")
+	}
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) (*model.User, error)); ok {
+		return rf(ctx, tx, uid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) *model.User); ok {
+		r0 = rf(ctx, tx, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string) error); ok {
+		r1 = rf(ctx, tx, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Master provides a mock function with given fields:
 func (_m *UserRepository) Master() *sql.DB {
 	ret := _m.Called()
@@ -312,6 +358,34 @@ func (_m *UserRepository) NewNullString(str *string) sql.NullString {
 	return r0
 }
 
+// PurgeUser provides a mock function with given fields: ctx, tx, uid, deletedBefore
+func (_m *UserRepository) PurgeUser(ctx context.Context, tx *sql.Tx, uid string, deletedBefore time.Time) (bool, error) {
+	ret := _m.Called(ctx, tx, uid, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeUser")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, time.Time) (bool, error)); ok {
+		return rf(ctx, tx, uid, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, time.Time) bool); ok {
+		r0 = rf(ctx, tx, uid, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string, time.Time) error); ok {
+		r1 = rf(ctx, tx, uid, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Query provides a mock function with given fields: ctx, query, dest, args
 func (_m *UserRepository) Query(ctx context.Context, query string, dest []interface{}, args []interface{}) error {
 	ret := _m.Called(ctx, query, dest, args)
@@ -330,6 +404,34 @@ func (_m *UserRepository) Query(ctx context.Context, query string, dest []interf
 	return r0
 }
 
+// RestoreUser provides a mock function with given fields: ctx, tx, uid, deletedAfter
+func (_m *UserRepository) RestoreUser(ctx context.Context, tx *sql.Tx, uid string, deletedAfter time.Time) (bool, error) {
+	ret := _m.Called(ctx, tx, uid, deletedAfter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUser")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, time.Time) (bool, error)); ok {
+		return rf(ctx, tx, uid, deletedAfter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, time.Time) bool); ok {
+		r0 = rf(ctx, tx, uid, deletedAfter)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string, time.Time) error); ok {
+		r1 = rf(ctx, tx, uid, deletedAfter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Rollback provides a mock function with given fields: tx
 func (_m *UserRepository) Rollback(tx *sql.Tx) error {
 	ret := _m.Called(tx)
@@ -368,6 +470,124 @@ func (_m *UserRepository) Slave() *sql.DB {
 	return r0
 }
 
+// SoftDeleteUser provides a mock function with given fields: ctx, tx, uid, deletedAt
+func (_m *UserRepository) SoftDeleteUser(ctx context.Context, tx *sql.Tx, uid string, deletedAt datatype.Time) (bool, error) {
+	ret := _m.Called(ctx, tx, uid, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SoftDeleteUser")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, datatype.Time) (bool, error)); ok {
+		return rf(ctx, tx, uid, deletedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, datatype.Time) bool); ok {
+		r0 = rf(ctx, tx, uid, deletedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string, datatype.Time) error); ok {
+		r1 = rf(ctx, tx, uid, deletedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateEmailVerifiedAt provides a mock function with given fields: ctx, tx, uid, emailVerifiedAt
+func (_m *UserRepository) UpdateEmailVerifiedAt(ctx context.Context, tx *sql.Tx, uid string, emailVerifiedAt datatype.Time) error {
+	ret := _m.Called(ctx, tx, uid, emailVerifiedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEmailVerifiedAt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, datatype.Time) error); ok {
+		r0 = rf(ctx, tx, uid, emailVerifiedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLockedUntil provides a mock function with given fields: ctx, tx, uid, lockedUntil
+func (_m *UserRepository) UpdateLockedUntil(ctx context.Context, tx *sql.Tx, uid string, lockedUntil datatype.Time) error {
+	ret := _m.Called(ctx, tx, uid, lockedUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLockedUntil")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, datatype.Time) error); ok {
+		r0 = rf(ctx, tx, uid, lockedUntil)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, tx, uid, password
+func (_m *UserRepository) UpdatePassword(ctx context.Context, tx *sql.Tx, uid string, password string) error {
+	ret := _m.Called(ctx, tx, uid, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string) error); ok {
+		r0 = rf(ctx, tx, uid, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePendingEmail provides a mock function with given fields: ctx, tx, uid, pendingEmail
+func (_m *UserRepository) UpdatePendingEmail(ctx context.Context, tx *sql.Tx, uid string, pendingEmail *string) error {
+	ret := _m.Called(ctx, tx, uid, pendingEmail)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePendingEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, *string) error); ok {
+		r0 = rf(ctx, tx, uid, pendingEmail)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePhoneVerifiedAt provides a mock function with given fields: ctx, tx, uid, phoneVerifiedAt
+func (_m *UserRepository) UpdatePhoneVerifiedAt(ctx context.Context, tx *sql.Tx, uid string, phoneVerifiedAt datatype.Time) error {
+	ret := _m.Called(ctx, tx, uid, phoneVerifiedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePhoneVerifiedAt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, datatype.Time) error); ok {
+		r0 = rf(ctx, tx, uid, phoneVerifiedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateUser provides a mock function with given fields: ctx, tx, user
 func (_m *UserRepository) UpdateUser(ctx context.Context, tx *sql.Tx, user *model.User) error {
 	ret := _m.Called(ctx, tx, user)
@@ -386,6 +606,42 @@ func (_m *UserRepository) UpdateUser(ctx context.Context, tx *sql.Tx, user *mode
 	return r0
 }
 
+// UpdateUserProfile provides a mock function with given fields: ctx, tx, user
+func (_m *UserRepository) UpdateUserProfile(ctx context.Context, tx *sql.Tx, user *model.User) error {
+	ret := _m.Called(ctx, tx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserProfile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *model.User) error); ok {
+		r0 = rf(ctx, tx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserStatus provides a mock function with given fields: ctx, tx, uid, status, reason, suspendedUntil
+func (_m *UserRepository) UpdateUserStatus(ctx context.Context, tx *sql.Tx, uid string, status string, reason *string, suspendedUntil datatype.Time) error {
+	ret := _m.Called(ctx, tx, uid, status, reason, suspendedUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string, *string, datatype.Time) error); ok {
+		r0 = rf(ctx, tx, uid, status, reason, suspendedUntil)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepository(t interface {
@@ -2,13 +2,38 @@
 
 package mockservice
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+	jwk "date-apps-be/pkg/jwk"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "date-apps-be/internal/model"
+)
 
 // AuthService is an autogenerated mock type for the AuthService type
 type AuthService struct {
 	mock.Mock
 }
 
+// ConsumeMFAToken provides a mock function with given fields: ctx, claims
+func (_m *AuthService) ConsumeMFAToken(ctx context.Context, claims *model.JWTClaims) error {
+	ret := _m.Called(ctx, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeMFAToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.JWTClaims) error); ok {
+		r0 = rf(ctx, claims)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GenerateToken provides a mock function with given fields: uid
 func (_m *AuthService) GenerateToken(uid string) (string, error) {
 	ret := _m.Called(uid)
@@ -37,6 +62,256 @@ func (_m *AuthService) GenerateToken(uid string) (string, error) {
 	return r0, r1
 }
 
+// IssueMFAToken provides a mock function with given fields: userUID
+func (_m *AuthService) IssueMFAToken(userUID string) (string, error) {
+	ret := _m.Called(userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueMFAToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(userUID)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(userUID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueToken provides a mock function with given fields: ctx, userUID, device
+func (_m *AuthService) IssueToken(ctx context.Context, userUID string, device model.Device) (*model.AuthToken, error) {
+	ret := _m.Called(ctx, userUID, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueToken")
+	}
+
+	var r0 *model.AuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Device) (*model.AuthToken, error)); ok {
+		return rf(ctx, userUID, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Device) *model.AuthToken); ok {
+		r0 = rf(ctx, userUID, device)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Device) error); ok {
+		r1 = rf(ctx, userUID, device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JWKS provides a mock function with given fields:
+func (_m *AuthService) JWKS() jwk.Set {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for JWKS")
+	}
+
+	var r0 jwk.Set
+	if rf, ok := ret.Get(0).(func() jwk.Set); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(jwk.Set)
+	}
+
+	return r0
+}
+
+// Logout provides a mock function with given fields: ctx, claims, refreshToken
+func (_m *AuthService) Logout(ctx context.Context, claims *model.JWTClaims, refreshToken string) error {
+	ret := _m.Called(ctx, claims, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.JWTClaims, string) error); ok {
+		r0 = rf(ctx, claims, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ParseMFAToken provides a mock function with given fields: ctx, tokenString
+func (_m *AuthService) ParseMFAToken(ctx context.Context, tokenString string) (*model.JWTClaims, error) {
+	ret := _m.Called(ctx, tokenString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseMFAToken")
+	}
+
+	var r0 *model.JWTClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.JWTClaims, error)); ok {
+		return rf(ctx, tokenString)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.JWTClaims); ok {
+		r0 = rf(ctx, tokenString)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.JWTClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenString)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseRestoreToken provides a mock function with given fields: ctx, tokenString
+func (_m *AuthService) ParseRestoreToken(ctx context.Context, tokenString string) (*model.JWTClaims, error) {
+	ret := _m.Called(ctx, tokenString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseRestoreToken")
+	}
+
+	var r0 *model.JWTClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.JWTClaims, error)); ok {
+		return rf(ctx, tokenString)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.JWTClaims); ok {
+		r0 = rf(ctx, tokenString)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.JWTClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenString)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseToken provides a mock function with given fields: ctx, tokenString
+func (_m *AuthService) ParseToken(ctx context.Context, tokenString string) (*model.JWTClaims, error) {
+	ret := _m.Called(ctx, tokenString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseToken")
+	}
+
+	var r0 *model.JWTClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.JWTClaims, error)); ok {
+		return rf(ctx, tokenString)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.JWTClaims); ok {
+		r0 = rf(ctx, tokenString)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.JWTClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenString)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshToken provides a mock function with given fields: ctx, refreshToken
+func (_m *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*model.AuthToken, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshToken")
+	}
+
+	var r0 *model.AuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.AuthToken, error)); ok {
+		return rf(ctx, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.AuthToken); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSession provides a mock function with given fields: ctx, sessionUID
+func (_m *AuthService) RevokeSession(ctx context.Context, sessionUID string) error {
+	ret := _m.Called(ctx, sessionUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, sessionUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeUserTokens provides a mock function with given fields: ctx, userUID
+func (_m *AuthService) RevokeUserTokens(ctx context.Context, userUID string) error {
+	ret := _m.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewAuthService creates a new instance of AuthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAuthService(t interface {
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockservice
+
+import (
+	context "context"
+
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HMACService is an autogenerated mock type for the HMACService type
+type HMACService struct {
+	mock.Mock
+}
+
+// VerifyRequest provides a mock function with given fields: ctx, req
+func (_m *HMACService) VerifyRequest(ctx context.Context, req *http.Request) (string, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyRequest")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *http.Request) (string, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *http.Request) string); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *http.Request) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewHMACService creates a new instance of HMACService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHMACService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HMACService {
+	mock := &HMACService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
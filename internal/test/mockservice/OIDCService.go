@@ -0,0 +1,59 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockservice
+
+import (
+	context "context"
+	oidcservice "date-apps-be/internal/service/oidc"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OIDCService is an autogenerated mock type for the OIDCService type
+type OIDCService struct {
+	mock.Mock
+}
+
+// VerifyIDToken provides a mock function with given fields: ctx, provider, idToken, nonce
+func (_m *OIDCService) VerifyIDToken(ctx context.Context, provider string, idToken string, nonce string) (*oidcservice.Identity, error) {
+	ret := _m.Called(ctx, provider, idToken, nonce)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyIDToken")
+	}
+
+	var r0 *oidcservice.Identity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*oidcservice.Identity, error)); ok {
+		return rf(ctx, provider, idToken, nonce)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *oidcservice.Identity); ok {
+		r0 = rf(ctx, provider, idToken, nonce)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*oidcservice.Identity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, provider, idToken, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOIDCService creates a new instance of OIDCService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOIDCService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OIDCService {
+	mock := &OIDCService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
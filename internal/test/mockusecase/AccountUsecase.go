@@ -0,0 +1,231 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockusecase
+
+import (
+	context "context"
+	datatype "date-apps-be/pkg/datatype"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "date-apps-be/internal/model"
+)
+
+// AccountUsecase is an autogenerated mock type for the AccountUsecase type
+type AccountUsecase struct {
+	mock.Mock
+}
+
+// ChangeEmail provides a mock function with given fields: ctx, userUID, password, email, device
+func (_m *AccountUsecase) ChangeEmail(ctx context.Context, userUID string, password string, email string, device model.Device) error {
+	ret := _m.Called(ctx, userUID, password, email, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, model.Device) error); ok {
+		r0 = rf(ctx, userUID, password, email, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ChangePassword provides a mock function with given fields: ctx, userUID, currentPassword, newPassword, device
+func (_m *AccountUsecase) ChangePassword(ctx context.Context, userUID string, currentPassword string, newPassword string, device model.Device) error {
+	ret := _m.Called(ctx, userUID, currentPassword, newPassword, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, model.Device) error); ok {
+		r0 = rf(ctx, userUID, currentPassword, newPassword, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAccount provides a mock function with given fields: ctx, userUID, password, device
+func (_m *AccountUsecase) DeleteAccount(ctx context.Context, userUID string, password string, device model.Device) (datatype.Time, error) {
+	ret := _m.Called(ctx, userUID, password, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccount")
+	}
+
+	var r0 datatype.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.Device) (datatype.Time, error)); ok {
+		return rf(ctx, userUID, password, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.Device) datatype.Time); ok {
+		r0 = rf(ctx, userUID, password, device)
+	} else {
+		r0 = ret.Get(0).(datatype.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, model.Device) error); ok {
+		r1 = rf(ctx, userUID, password, device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForgotPassword provides a mock function with given fields: ctx, email
+func (_m *AccountUsecase) ForgotPassword(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForgotPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeDeletedAccounts provides a mock function with given fields: ctx
+func (_m *AccountUsecase) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedAccounts")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResendEmailVerification provides a mock function with given fields: ctx, userUID
+func (_m *AccountUsecase) ResendEmailVerification(ctx context.Context, userUID string) error {
+	ret := _m.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendEmailVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: ctx, token, password
+func (_m *AccountUsecase) ResetPassword(ctx context.Context, token string, password string) error {
+	ret := _m.Called(ctx, token, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreAccount provides a mock function with given fields: ctx, userUID
+func (_m *AccountUsecase) RestoreAccount(ctx context.Context, userUID string) error {
+	ret := _m.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendEmailVerification provides a mock function with given fields: ctx, user
+func (_m *AccountUsecase) SendEmailVerification(ctx context.Context, user *model.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendEmailVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyEmail provides a mock function with given fields: ctx, token
+func (_m *AccountUsecase) VerifyEmail(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAccountUsecase creates a new instance of AccountUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccountUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccountUsecase {
+	mock := &AccountUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,187 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockusecase
+
+import (
+	context "context"
+
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "date-apps-be/internal/model"
+)
+
+// DataExportUsecase is an autogenerated mock type for the DataExportUsecase type
+type DataExportUsecase struct {
+	mock.Mock
+}
+
+// DeleteExpiredDataExports provides a mock function with given fields: ctx
+func (_m *DataExportUsecase) DeleteExpiredDataExports(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredDataExports")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDataExport provides a mock function with given fields: ctx, userUID, exportUID
+func (_m *DataExportUsecase) GetDataExport(ctx context.Context, userUID string, exportUID string) (*model.DataExport, error) {
+	ret := _m.Called(ctx, userUID, exportUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDataExport")
+	}
+
+	var r0 *model.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.DataExport, error)); ok {
+		return rf(ctx, userUID, exportUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.DataExport); ok {
+		r0 = rf(ctx, userUID, exportUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userUID, exportUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenDataExport provides a mock function with given fields: ctx, token
+func (_m *DataExportUsecase) OpenDataExport(ctx context.Context, token string) (*model.DataExport, io.ReadCloser, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenDataExport")
+	}
+
+	var r0 *model.DataExport
+	var r1 io.ReadCloser
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.DataExport, io.ReadCloser, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.DataExport); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) io.ReadCloser); ok {
+		r1 = rf(ctx, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, token)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ProcessDataExports provides a mock function with given fields: ctx
+func (_m *DataExportUsecase) ProcessDataExports(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProcessDataExports")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestDataExport provides a mock function with given fields: ctx, userUID
+func (_m *DataExportUsecase) RequestDataExport(ctx context.Context, userUID string) (*model.DataExport, error) {
+	ret := _m.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestDataExport")
+	}
+
+	var r0 *model.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.DataExport, error)); ok {
+		return rf(ctx, userUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.DataExport); ok {
+		r0 = rf(ctx, userUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataExportUsecase creates a new instance of DataExportUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataExportUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataExportUsecase {
+	mock := &DataExportUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockusecase
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "date-apps-be/internal/model"
+)
+
+// LocationUsecase is an autogenerated mock type for the LocationUsecase type
+type LocationUsecase struct {
+	mock.Mock
+}
+
+// UpdateLocation provides a mock function with given fields: ctx, userUID, latitude, longitude
+func (_m *LocationUsecase) UpdateLocation(ctx context.Context, userUID string, latitude float64, longitude float64) (*model.UserLocation, error) {
+	ret := _m.Called(ctx, userUID, latitude, longitude)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLocation")
+	}
+
+	var r0 *model.UserLocation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, float64) (*model.UserLocation, error)); ok {
+		return rf(ctx, userUID, latitude, longitude)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, float64) *model.UserLocation); ok {
+		r0 = rf(ctx, userUID, latitude, longitude)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserLocation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, float64, float64) error); ok {
+		r1 = rf(ctx, userUID, latitude, longitude)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLocationUsecase creates a new instance of LocationUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLocationUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LocationUsecase {
+	mock := &LocationUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
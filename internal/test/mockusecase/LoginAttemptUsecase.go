@@ -0,0 +1,102 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockusecase
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "date-apps-be/internal/model"
+)
+
+// LoginAttemptUsecase is an autogenerated mock type for the LoginAttemptUsecase type
+type LoginAttemptUsecase struct {
+	mock.Mock
+}
+
+// CheckIPAddress provides a mock function with given fields: ctx, ipAddress
+func (_m *LoginAttemptUsecase) CheckIPAddress(ctx context.Context, ipAddress string) error {
+	ret := _m.Called(ctx, ipAddress)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckIPAddress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ipAddress)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckLocked provides a mock function with given fields: ctx, attempt, user
+func (_m *LoginAttemptUsecase) CheckLocked(ctx context.Context, attempt model.LoginAttempt, user *model.User) error {
+	ret := _m.Called(ctx, attempt, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckLocked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.LoginAttempt, *model.User) error); ok {
+		r0 = rf(ctx, attempt, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordFailure provides a mock function with given fields: ctx, attempt, user, failureReason
+func (_m *LoginAttemptUsecase) RecordFailure(ctx context.Context, attempt model.LoginAttempt, user *model.User, failureReason string) error {
+	ret := _m.Called(ctx, attempt, user, failureReason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.LoginAttempt, *model.User, string) error); ok {
+		r0 = rf(ctx, attempt, user, failureReason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordLogin provides a mock function with given fields: ctx, attempt, user, failureReason
+func (_m *LoginAttemptUsecase) RecordLogin(ctx context.Context, attempt model.LoginAttempt, user *model.User, failureReason string) error {
+	ret := _m.Called(ctx, attempt, user, failureReason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.LoginAttempt, *model.User, string) error); ok {
+		r0 = rf(ctx, attempt, user, failureReason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLoginAttemptUsecase creates a new instance of LoginAttemptUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginAttemptUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginAttemptUsecase {
+	mock := &LoginAttemptUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
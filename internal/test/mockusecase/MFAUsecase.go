@@ -0,0 +1,155 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockusecase
+
+import (
+	context "context"
+	dto "date-apps-be/internal/usecase/mfa/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "date-apps-be/internal/model"
+)
+
+// MFAUsecase is an autogenerated mock type for the MFAUsecase type
+type MFAUsecase struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: ctx, userUID, code, device
+func (_m *MFAUsecase) Confirm(ctx context.Context, userUID string, code string, device model.Device) error {
+	ret := _m.Called(ctx, userUID, code, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.Device) error); ok {
+		r0 = rf(ctx, userUID, code, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Disable provides a mock function with given fields: ctx, userUID, code, device
+func (_m *MFAUsecase) Disable(ctx context.Context, userUID string, code string, device model.Device) error {
+	ret := _m.Called(ctx, userUID, code, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Disable")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.Device) error); ok {
+		r0 = rf(ctx, userUID, code, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enroll provides a mock function with given fields: ctx, userUID
+func (_m *MFAUsecase) Enroll(ctx context.Context, userUID string) (*dto.Enrollment, error) {
+	ret := _m.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enroll")
+	}
+
+	var r0 *dto.Enrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*dto.Enrollment, error)); ok {
+		return rf(ctx, userUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *dto.Enrollment); ok {
+		r0 = rf(ctx, userUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.Enrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueLoginToken provides a mock function with given fields: ctx, userUID, device
+func (_m *MFAUsecase) IssueLoginToken(ctx context.Context, userUID string, device model.Device) (*model.AuthToken, error) {
+	ret := _m.Called(ctx, userUID, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueLoginToken")
+	}
+
+	var r0 *model.AuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Device) (*model.AuthToken, error)); ok {
+		return rf(ctx, userUID, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Device) *model.AuthToken); ok {
+		r0 = rf(ctx, userUID, device)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Device) error); ok {
+		r1 = rf(ctx, userUID, device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateCode provides a mock function with given fields: ctx, userUID, code
+func (_m *MFAUsecase) ValidateCode(ctx context.Context, userUID string, code string) (bool, error) {
+	ret := _m.Called(ctx, userUID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(ctx, userUID, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, userUID, code)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userUID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMFAUsecase creates a new instance of MFAUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMFAUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MFAUsecase {
+	mock := &MFAUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,91 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockusecase
+
+import (
+	context "context"
+	dto "date-apps-be/internal/usecase/moderation/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "date-apps-be/internal/model"
+)
+
+// ModerationUsecase is an autogenerated mock type for the ModerationUsecase type
+type ModerationUsecase struct {
+	mock.Mock
+}
+
+// ChangeUserStatus provides a mock function with given fields: ctx, d
+func (_m *ModerationUsecase) ChangeUserStatus(ctx context.Context, d dto.ChangeUserStatus) (*model.User, error) {
+	ret := _m.Called(ctx, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeUserStatus")
+	}
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.ChangeUserStatus) (*model.User, error)); ok {
+		return rf(ctx, d)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.ChangeUserStatus) *model.User); ok {
+		r0 = rf(ctx, d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.ChangeUserStatus) error); ok {
+		r1 = rf(ctx, d)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserStatusAudits provides a mock function with given fields: ctx, userUID, page, limit
+func (_m *ModerationUsecase) GetUserStatusAudits(ctx context.Context, userUID string, page uint64, limit uint64) ([]*model.UserStatusAudit, error) {
+	ret := _m.Called(ctx, userUID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserStatusAudits")
+	}
+
+	var r0 []*model.UserStatusAudit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, uint64) ([]*model.UserStatusAudit, error)); ok {
+		return rf(ctx, userUID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, uint64) []*model.UserStatusAudit); ok {
+		r0 = rf(ctx, userUID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserStatusAudit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, uint64) error); ok {
+		r1 = rf(ctx, userUID, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewModerationUsecase creates a new instance of ModerationUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewModerationUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ModerationUsecase {
+	mock := &ModerationUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
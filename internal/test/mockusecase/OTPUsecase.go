@@ -0,0 +1,113 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockusecase
+
+import (
+	context "context"
+	model "date-apps-be/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OTPUsecase is an autogenerated mock type for the OTPUsecase type
+type OTPUsecase struct {
+	mock.Mock
+}
+
+// RequestLoginOTP provides a mock function with given fields: ctx, phoneNumber
+func (_m *OTPUsecase) RequestLoginOTP(ctx context.Context, phoneNumber string) error {
+	ret := _m.Called(ctx, phoneNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestLoginOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, phoneNumber)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestPhoneVerification provides a mock function with given fields: ctx, userUID, phoneNumber
+func (_m *OTPUsecase) RequestPhoneVerification(ctx context.Context, userUID string, phoneNumber string) error {
+	ret := _m.Called(ctx, userUID, phoneNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestPhoneVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userUID, phoneNumber)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyLoginOTP provides a mock function with given fields: ctx, phoneNumber, code, device
+func (_m *OTPUsecase) VerifyLoginOTP(ctx context.Context, phoneNumber string, code string, device model.Device) (*model.AuthToken, error) {
+	ret := _m.Called(ctx, phoneNumber, code, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyLoginOTP")
+	}
+
+	var r0 *model.AuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.Device) (*model.AuthToken, error)); ok {
+		return rf(ctx, phoneNumber, code, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.Device) *model.AuthToken); ok {
+		r0 = rf(ctx, phoneNumber, code, device)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, model.Device) error); ok {
+		r1 = rf(ctx, phoneNumber, code, device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyPhoneNumber provides a mock function with given fields: ctx, userUID, phoneNumber, code
+func (_m *OTPUsecase) VerifyPhoneNumber(ctx context.Context, userUID string, phoneNumber string, code string) error {
+	ret := _m.Called(ctx, userUID, phoneNumber, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyPhoneNumber")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, userUID, phoneNumber, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOTPUsecase creates a new instance of OTPUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOTPUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OTPUsecase {
+	mock := &OTPUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
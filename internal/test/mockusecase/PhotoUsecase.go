@@ -0,0 +1,226 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockusecase
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "date-apps-be/internal/model"
+)
+
+// PhotoUsecase is an autogenerated mock type for the PhotoUsecase type
+type PhotoUsecase struct {
+	mock.Mock
+}
+
+// AttachPhotos provides a mock function with given fields: ctx, users
+func (_m *PhotoUsecase) AttachPhotos(ctx context.Context, users []*model.User) error {
+	ret := _m.Called(ctx, users)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachPhotos")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.User) error); ok {
+		r0 = rf(ctx, users)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePhoto provides a mock function with given fields: ctx, userUID, photoUID
+func (_m *PhotoUsecase) DeletePhoto(ctx context.Context, userUID string, photoUID string) error {
+	ret := _m.Called(ctx, userUID, photoUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePhoto")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userUID, photoUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUserPhotos provides a mock function with given fields: ctx, userUID
+func (_m *PhotoUsecase) GetUserPhotos(ctx context.Context, userUID string) ([]*model.UserPhoto, error) {
+	ret := _m.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserPhotos")
+	}
+
+	var r0 []*model.UserPhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.UserPhoto, error)); ok {
+		return rf(ctx, userUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.UserPhoto); ok {
+		r0 = rf(ctx, userUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserPhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenPhoto provides a mock function with given fields: ctx, photoUID, thumbnail
+func (_m *PhotoUsecase) OpenPhoto(ctx context.Context, photoUID string, thumbnail bool) (*model.UserPhoto, io.ReadCloser, error) {
+	ret := _m.Called(ctx, photoUID, thumbnail)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenPhoto")
+	}
+
+	var r0 *model.UserPhoto
+	var r1 io.ReadCloser
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) (*model.UserPhoto, io.ReadCloser, error)); ok {
+		return rf(ctx, photoUID, thumbnail)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) *model.UserPhoto); ok {
+		r0 = rf(ctx, photoUID, thumbnail)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserPhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) io.ReadCloser); ok {
+		r1 = rf(ctx, photoUID, thumbnail)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, bool) error); ok {
+		r2 = rf(ctx, photoUID, thumbnail)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ReorderPhotos provides a mock function with given fields: ctx, userUID, photoUIDs
+func (_m *PhotoUsecase) ReorderPhotos(ctx context.Context, userUID string, photoUIDs []string) ([]*model.UserPhoto, error) {
+	ret := _m.Called(ctx, userUID, photoUIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReorderPhotos")
+	}
+
+	var r0 []*model.UserPhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) ([]*model.UserPhoto, error)); ok {
+		return rf(ctx, userUID, photoUIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) []*model.UserPhoto); ok {
+		r0 = rf(ctx, userUID, photoUIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserPhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, userUID, photoUIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPrimaryPhoto provides a mock function with given fields: ctx, userUID, photoUID
+func (_m *PhotoUsecase) SetPrimaryPhoto(ctx context.Context, userUID string, photoUID string) ([]*model.UserPhoto, error) {
+	ret := _m.Called(ctx, userUID, photoUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPrimaryPhoto")
+	}
+
+	var r0 []*model.UserPhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*model.UserPhoto, error)); ok {
+		return rf(ctx, userUID, photoUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*model.UserPhoto); ok {
+		r0 = rf(ctx, userUID, photoUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserPhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userUID, photoUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UploadPhoto provides a mock function with given fields: ctx, userUID, body
+func (_m *PhotoUsecase) UploadPhoto(ctx context.Context, userUID string, body io.Reader) (*model.UserPhoto, error) {
+	ret := _m.Called(ctx, userUID, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadPhoto")
+	}
+
+	var r0 *model.UserPhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) (*model.UserPhoto, error)); ok {
+		return rf(ctx, userUID, body)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) *model.UserPhoto); ok {
+		r0 = rf(ctx, userUID, body)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserPhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader) error); ok {
+		r1 = rf(ctx, userUID, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPhotoUsecase creates a new instance of PhotoUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPhotoUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PhotoUsecase {
+	mock := &PhotoUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,91 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockusecase
+
+import (
+	context "context"
+	dto "date-apps-be/internal/usecase/preference/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "date-apps-be/internal/model"
+)
+
+// PreferenceUsecase is an autogenerated mock type for the PreferenceUsecase type
+type PreferenceUsecase struct {
+	mock.Mock
+}
+
+// GetPreference provides a mock function with given fields: ctx, userUID
+func (_m *PreferenceUsecase) GetPreference(ctx context.Context, userUID string) (*model.UserPreference, error) {
+	ret := _m.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPreference")
+	}
+
+	var r0 *model.UserPreference
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.UserPreference, error)); ok {
+		return rf(ctx, userUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.UserPreference); ok {
+		r0 = rf(ctx, userUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserPreference)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePreference provides a mock function with given fields: ctx, d
+func (_m *PreferenceUsecase) UpdatePreference(ctx context.Context, d dto.UpdatePreference) (*model.UserPreference, error) {
+	ret := _m.Called(ctx, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePreference")
+	}
+
+	var r0 *model.UserPreference
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.UpdatePreference) (*model.UserPreference, error)); ok {
+		return rf(ctx, d)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.UpdatePreference) *model.UserPreference); ok {
+		r0 = rf(ctx, d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserPreference)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.UpdatePreference) error); ok {
+		r1 = rf(ctx, d)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPreferenceUsecase creates a new instance of PreferenceUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPreferenceUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PreferenceUsecase {
+	mock := &PreferenceUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,77 @@
+// Code generated by mockery v2.46.0. DO NOT EDIT.
+
+package mockusecase
+
+import (
+	context "context"
+	model "date-apps-be/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SessionUsecase is an autogenerated mock type for the SessionUsecase type
+type SessionUsecase struct {
+	mock.Mock
+}
+
+// GetSessions provides a mock function with given fields: ctx, userUID, currentSessionUID
+func (_m *SessionUsecase) GetSessions(ctx context.Context, userUID string, currentSessionUID string) ([]*model.UserSession, error) {
+	ret := _m.Called(ctx, userUID, currentSessionUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSessions")
+	}
+
+	var r0 []*model.UserSession
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*model.UserSession, error)); ok {
+		return rf(ctx, userUID, currentSessionUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*model.UserSession); ok {
+		r0 = rf(ctx, userUID, currentSessionUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserSession)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userUID, currentSessionUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSession provides a mock function with given fields: ctx, userUID, sessionUID
+func (_m *SessionUsecase) RevokeSession(ctx context.Context, userUID string, sessionUID string) error {
+	ret := _m.Called(ctx, userUID, sessionUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userUID, sessionUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSessionUsecase creates a new instance of SessionUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionUsecase {
+	mock := &SessionUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
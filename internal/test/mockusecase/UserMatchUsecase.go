@@ -17,21 +17,33 @@ type UserMatchUsecase struct {
 }
 
 // CreateUserMatch provides a mock function with given fields: ctx, userMatch
-func (_m *UserMatchUsecase) CreateUserMatch(ctx context.Context, userMatch *model.UserMatch) error {
+func (_m *UserMatchUsecase) CreateUserMatch(ctx context.Context, userMatch *model.UserMatch) (*model.MutualMatch, error) {
 	ret := _m.Called(ctx, userMatch)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUserMatch")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *model.UserMatch) error); ok {
+	var r0 *model.MutualMatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.UserMatch) (*model.MutualMatch, error)); ok {
+		return rf(ctx, userMatch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.UserMatch) *model.MutualMatch); ok {
 		r0 = rf(ctx, userMatch)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MutualMatch)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *model.UserMatch) error); ok {
+		r1 = rf(ctx, userMatch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetAvailableUsers provides a mock function with given fields: ctx, userUID, page, limit
@@ -71,6 +83,80 @@ func (_m *UserMatchUsecase) GetAvailableUsers(ctx context.Context, userUID strin
 	return r0, r1, r2
 }
 
+// GetLikesReceived provides a mock function with given fields: ctx, userUID, page, limit
+func (_m *UserMatchUsecase) GetLikesReceived(ctx context.Context, userUID string, page uint64, limit uint64) ([]*model.User, int, bool, error) {
+	ret := _m.Called(ctx, userUID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLikesReceived")
+	}
+
+	var r0 []*model.User
+	var r1 int
+	var r2 bool
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, uint64) ([]*model.User, int, bool, error)); ok {
+		return rf(ctx, userUID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, uint64) []*model.User); ok {
+		r0 = rf(ctx, userUID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, uint64) int); ok {
+		r1 = rf(ctx, userUID, page, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, uint64, uint64) bool); ok {
+		r2 = rf(ctx, userUID, page, limit)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, string, uint64, uint64) error); ok {
+		r3 = rf(ctx, userUID, page, limit)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// GetMutualMatches provides a mock function with given fields: ctx, userUID, page, limit
+func (_m *UserMatchUsecase) GetMutualMatches(ctx context.Context, userUID string, page uint64, limit uint64) ([]*model.MutualMatch, error) {
+	ret := _m.Called(ctx, userUID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMutualMatches")
+	}
+
+	var r0 []*model.MutualMatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, uint64) ([]*model.MutualMatch, error)); ok {
+		return rf(ctx, userUID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, uint64) []*model.MutualMatch); ok {
+		r0 = rf(ctx, userUID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.MutualMatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, uint64) error); ok {
+		r1 = rf(ctx, userUID, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserMatchTodayByUserUIDAndMatchUID provides a mock function with given fields: ctx, userUID, matchUID
 func (_m *UserMatchUsecase) GetUserMatchTodayByUserUIDAndMatchUID(ctx context.Context, userUID string, matchUID string) (*model.UserMatch, error) {
 	ret := _m.Called(ctx, userUID, matchUID)
@@ -131,6 +217,61 @@ func (_m *UserMatchUsecase) GetUserMatches(ctx context.Context, d dto.GetUserMat
 	return r0, r1
 }
 
+// RewindUserMatch provides a mock function with given fields: ctx, userUID
+func (_m *UserMatchUsecase) RewindUserMatch(ctx context.Context, userUID string) (*model.UserRewind, int, error) {
+	ret := _m.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RewindUserMatch")
+	}
+
+	var r0 *model.UserRewind
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.UserRewind, int, error)); ok {
+		return rf(ctx, userUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.UserRewind); ok {
+		r0 = rf(ctx, userUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserRewind)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) int); ok {
+		r1 = rf(ctx, userUID)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, userUID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Unmatch provides a mock function with given fields: ctx, userUID, matchUID
+func (_m *UserMatchUsecase) Unmatch(ctx context.Context, userUID string, matchUID string) error {
+	ret := _m.Called(ctx, userUID, matchUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unmatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userUID, matchUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserMatchUsecase creates a new instance of UserMatchUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserMatchUsecase(t interface {
@@ -16,23 +16,85 @@ type UserUsecase struct {
 	mock.Mock
 }
 
+// Authenticate provides a mock function with given fields: ctx, d
+func (_m *UserUsecase) Authenticate(ctx context.Context, d dto.Authenticate) (*model.AuthToken, error) {
+	ret := _m.Called(ctx, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 *model.AuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.Authenticate) (*model.AuthToken, error)); ok {
+		return rf(ctx, d)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.Authenticate) *model.AuthToken); ok {
+		r0 = rf(ctx, d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.Authenticate) error); ok {
+		r1 = rf(ctx, d)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthenticateOIDC provides a mock function with given fields: ctx, d
+func (_m *UserUsecase) AuthenticateOIDC(ctx context.Context, d dto.AuthenticateOIDC) (*model.AuthToken, error) {
+	ret := _m.Called(ctx, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthenticateOIDC")
+	}
+
+	var r0 *model.AuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.AuthenticateOIDC) (*model.AuthToken, error)); ok {
+		return rf(ctx, d)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.AuthenticateOIDC) *model.AuthToken); ok {
+		r0 = rf(ctx, d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.AuthenticateOIDC) error); ok {
+		r1 = rf(ctx, d)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateUser provides a mock function with given fields: ctx, user
-func (_m *UserUsecase) CreateUser(ctx context.Context, user *dto.CreateUser) (string, error) {
+func (_m *UserUsecase) CreateUser(ctx context.Context, user *dto.CreateUser) (*model.AuthToken, error) {
 	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUser")
 	}
 
-	var r0 string
+	var r0 *model.AuthToken
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateUser) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateUser) (*model.AuthToken, error)); ok {
 		return rf(ctx, user)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateUser) string); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateUser) *model.AuthToken); ok {
 		r0 = rf(ctx, user)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuthToken)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, *dto.CreateUser) error); ok {
@@ -134,6 +196,66 @@ func (_m *UserUsecase) GetUserPackage(ctx context.Context, userUID string) (*mod
 	return r0, r1
 }
 
+// UpdateProfile provides a mock function with given fields: ctx, d
+func (_m *UserUsecase) UpdateProfile(ctx context.Context, d dto.UpdateProfile) (*model.User, error) {
+	ret := _m.Called(ctx, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.UpdateProfile) (*model.User, error)); ok {
+		return rf(ctx, d)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.UpdateProfile) *model.User); ok {
+		r0 = rf(ctx, d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.UpdateProfile) error); ok {
+		r1 = rf(ctx, d)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyMFALogin provides a mock function with given fields: ctx, d
+func (_m *UserUsecase) VerifyMFALogin(ctx context.Context, d dto.VerifyMFALogin) (*model.AuthToken, error) {
+	ret := _m.Called(ctx, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyMFALogin")
+	}
+
+	var r0 *model.AuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.VerifyMFALogin) (*model.AuthToken, error)); ok {
+		return rf(ctx, d)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.VerifyMFALogin) *model.AuthToken); ok {
+		r0 = rf(ctx, d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.VerifyMFALogin) error); ok {
+		r1 = rf(ctx, d)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserUsecase creates a new instance of UserUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserUsecase(t interface {
@@ -0,0 +1,617 @@
+package accountusecase
+
+import (
+	"context"
+	"date-apps-be/infrastructure/config"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	passwordresetrepo "date-apps-be/internal/repository/password_reset"
+	userrepo "date-apps-be/internal/repository/user"
+	userphotorepo "date-apps-be/internal/repository/user_photo"
+	authservice "date-apps-be/internal/service/auth"
+	blobservice "date-apps-be/internal/service/blob"
+	mailservice "date-apps-be/internal/service/mail"
+	loginattemptusecase "date-apps-be/internal/usecase/login_attempt"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/util"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/segmentio/ksuid"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type (
+	AccountUsecase interface {
+		SendEmailVerification(ctx context.Context, user *model.User) (err error)
+		ResendEmailVerification(ctx context.Context, userUID string) (err error)
+		VerifyEmail(ctx context.Context, token string) (err error)
+		ForgotPassword(ctx context.Context, email string) (err error)
+		ResetPassword(ctx context.Context, token, password string) (err error)
+		ChangePassword(ctx context.Context, userUID, currentPassword, newPassword string, device model.Device) (err error)
+		ChangeEmail(ctx context.Context, userUID, password, email string, device model.Device) (err error)
+		DeleteAccount(ctx context.Context, userUID, password string, device model.Device) (restoreBefore datatype.Time, err error)
+		RestoreAccount(ctx context.Context, userUID string) (err error)
+		PurgeDeletedAccounts(ctx context.Context) (purged int, err error)
+	}
+
+	accountUsecase struct {
+		conf              *config.Config
+		userRepo          userrepo.UserRepository
+		passwordResetRepo passwordresetrepo.PasswordResetRepository
+		authService       authservice.AuthService
+		mailer            mailservice.Mailer
+		userPhotoRepo     userphotorepo.UserPhotoRepository
+		blobStore         blobservice.BlobStore
+		loginAttempt      loginattemptusecase.LoginAttemptUsecase
+		log               *zap.Logger
+	}
+)
+
+func NewAccountUsecase(conf *config.Config, userRepo userrepo.UserRepository, passwordResetRepo passwordresetrepo.PasswordResetRepository, authService authservice.AuthService, mailer mailservice.Mailer, userPhotoRepo userphotorepo.UserPhotoRepository, blobStore blobservice.BlobStore, loginAttempt loginattemptusecase.LoginAttemptUsecase, log *zap.Logger) AccountUsecase {
+	return &accountUsecase{
+		conf:              conf,
+		userRepo:          userRepo,
+		passwordResetRepo: passwordResetRepo,
+		authService:       authService,
+		mailer:            mailer,
+		userPhotoRepo:     userPhotoRepo,
+		blobStore:         blobStore,
+		loginAttempt:      loginAttempt,
+		log:               log,
+	}
+}
+
+// SendEmailVerification mails a signed link that verifies the current email of the user.
+func (a *accountUsecase) SendEmailVerification(ctx context.Context, user *model.User) (err error) {
+	defer derrors.Wrap(&err, "SendEmailVerification(%q)", user.UID)
+
+	if user.Email == nil || *user.Email == "" {
+		return derrors.New(derrors.InvalidArgument, "User has no email address")
+	}
+
+	return a.sendEmailVerification(ctx, user, *user.Email)
+}
+
+// sendEmailVerification mails a signed link that verifies email, the current or the pending email of the user.
+func (a *accountUsecase) sendEmailVerification(ctx context.Context, user *model.User, email string) (err error) {
+	token, err := a.signEmailVerification(user.UID, email)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "signEmailVerification")
+	}
+
+	link := a.conf.AppBaseURL + "/verify-email?token=" + url.QueryEscape(token)
+	err = a.mailer.Send(ctx, mailservice.Mail{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease verify your email address by opening the link below:\n\n%s\n\nThe link expires in %d hours.",
+			user.Name, link, a.conf.EmailVerificationExpiration/60),
+	})
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "mailer.Send")
+	}
+
+	return nil
+}
+
+func (a *accountUsecase) ResendEmailVerification(ctx context.Context, userUID string) (err error) {
+	defer derrors.Wrap(&err, "ResendEmailVerification(%q)", userUID)
+
+	user, err := a.getUser(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if user.IsEmailVerified() {
+		return derrors.New(derrors.InvalidArgument, "Email is already verified")
+	}
+
+	return a.SendEmailVerification(ctx, user)
+}
+
+// VerifyEmail marks the email as verified when the token is valid and
+// the email in it is still the email of the user. Verifying twice is not an error.
+// A token for the pending email of the user replaces the email with it.
+func (a *accountUsecase) VerifyEmail(ctx context.Context, token string) (err error) {
+	defer derrors.Wrap(&err, "VerifyEmail")
+
+	claims := &model.EmailVerificationClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return a.conf.EmailVerificationSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(constant.EmailVerificationAudience), jwt.WithIssuer(constant.TokenIssuer))
+	if err != nil {
+		return derrors.WrapStack(err, derrors.InvalidArgument, "Invalid or expired verification link")
+	}
+
+	user, err := a.userRepo.GetUserByUID(ctx, claims.UserUID)
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		return derrors.New(derrors.InvalidArgument, "Invalid or expired verification link")
+	}
+
+	if user.PendingEmail != nil && *user.PendingEmail == claims.Email {
+		return a.applyPendingEmail(ctx, user)
+	}
+
+	if user.Email == nil || *user.Email != claims.Email {
+		return derrors.New(derrors.InvalidArgument, "Invalid or expired verification link")
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	return a.userRepo.UpdateEmailVerifiedAt(ctx, nil, user.UID, datatype.NewTimeNow())
+}
+
+// ForgotPassword mails a one time password reset token. The token is created and mailed
+// in the background, so unknown emails, users that asked too often and mail failures
+// are answered the same way and in the same time, the endpoint can not be used to find registered emails.
+func (a *accountUsecase) ForgotPassword(ctx context.Context, email string) (err error) {
+	// detached from the request, which ends before the mail is sent
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), constant.PasswordResetSendTimeout)
+	go func() {
+		defer cancel()
+		// the caller must not learn the outcome, failures only delay the reset until the user asks again
+		if err := a.sendPasswordReset(ctx, email); err != nil {
+			a.log.Error("failed to send password reset", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+func (a *accountUsecase) sendPasswordReset(ctx context.Context, email string) (err error) {
+	defer derrors.Wrap(&err, "sendPasswordReset(%q)", email)
+
+	user, err := a.userRepo.GetUserByEmailOrPhoneNumber(ctx, email, "")
+	if err != nil {
+		return
+	}
+
+	if user == nil || user.Email == nil {
+		return nil
+	}
+
+	total, err := a.passwordResetRepo.CountPasswordResets(ctx, user.UID, constant.PasswordResetWindow)
+	if err != nil {
+		return
+	}
+
+	if total >= constant.MaxPasswordResetPerWindow {
+		return nil
+	}
+
+	token, err := util.RandomToken(constant.PasswordResetTokenBytes)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "util.RandomToken")
+	}
+
+	expiresAt := time.Now().Add(constant.PasswordResetExpiration)
+	err = a.passwordResetRepo.CreatePasswordReset(ctx, nil, &model.PasswordReset{
+		UID:       ksuid.New().String(),
+		UserUID:   user.UID,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: datatype.NewTime(&expiresAt),
+	})
+	if err != nil {
+		return
+	}
+
+	link := a.conf.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	err = a.mailer.Send(ctx, mailservice.Mail{
+		To:      *user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nWe received a request to reset your password, open the link below to choose a new one:\n\n%s\n\nThe link expires in %d minutes. If you did not ask for it, you can ignore this email.",
+			user.Name, link, int(constant.PasswordResetExpiration.Minutes())),
+	})
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "mailer.Send")
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password with a token from ForgotPassword, every other
+// reset token of the user stops working. Then the user is signed out of every device.
+func (a *accountUsecase) ResetPassword(ctx context.Context, token, password string) (err error) {
+	defer derrors.Wrap(&err, "ResetPassword")
+
+	passwordReset, err := a.passwordResetRepo.GetPasswordResetByHash(ctx, util.HashToken(token))
+	if err != nil {
+		return
+	}
+
+	if passwordReset == nil || !passwordReset.UsedAt.IsNil() || passwordReset.IsExpired() {
+		return derrors.New(derrors.InvalidArgument, "Invalid or expired reset token")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "bcrypt.GenerateFromPassword")
+	}
+
+	used, err := a.updatePassword(ctx, passwordReset, string(hashedPassword))
+	if err != nil {
+		return
+	}
+
+	if !used {
+		return derrors.New(derrors.InvalidArgument, "Invalid or expired reset token")
+	}
+
+	return a.authService.RevokeUserTokens(ctx, passwordReset.UserUID)
+}
+
+// updatePassword uses the reset token and updates the password in one transaction,
+// used is false when the token was already used by a concurrent request.
+func (a *accountUsecase) updatePassword(ctx context.Context, passwordReset *model.PasswordReset, hashedPassword string) (used bool, err error) {
+	tx, err := a.passwordResetRepo.Begin()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil || !used {
+			_ = a.passwordResetRepo.Rollback(tx)
+		}
+	}()
+
+	used, err = a.passwordResetRepo.UsePasswordReset(ctx, tx, passwordReset.UID)
+	if err != nil || !used {
+		return
+	}
+
+	if err = a.userRepo.UpdatePassword(ctx, tx, passwordReset.UserUID, hashedPassword); err != nil {
+		return
+	}
+
+	if err = a.passwordResetRepo.DeletePasswordResets(ctx, tx, passwordReset.UserUID); err != nil {
+		return
+	}
+
+	if err = a.passwordResetRepo.Commit(tx); err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return true, nil
+}
+
+// ChangePassword sets a new password after checking the current one, reset tokens
+// stop working and the user is signed out of every device like ResetPassword.
+// Wrong passwords count as failed logins of the account.
+func (a *accountUsecase) ChangePassword(ctx context.Context, userUID, currentPassword, newPassword string, device model.Device) (err error) {
+	defer derrors.Wrap(&err, "ChangePassword(%q)", userUID)
+
+	user, err := a.getUnlockedUser(ctx, userUID, device)
+	if err != nil {
+		return
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)) != nil {
+		return a.rejectPassword(ctx, user, device, "Current password is incorrect")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "bcrypt.GenerateFromPassword")
+	}
+
+	if err = a.changePassword(ctx, userUID, string(hashedPassword)); err != nil {
+		return
+	}
+
+	return a.authService.RevokeUserTokens(ctx, userUID)
+}
+
+// changePassword updates only the password, so concurrent changes to the profile are kept,
+// and deletes the reset tokens of the user in one transaction.
+func (a *accountUsecase) changePassword(ctx context.Context, userUID, hashedPassword string) (err error) {
+	tx, err := a.userRepo.Begin()
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = a.userRepo.Rollback(tx)
+		}
+	}()
+
+	if err = a.userRepo.UpdatePassword(ctx, tx, userUID, hashedPassword); err != nil {
+		return
+	}
+
+	if err = a.passwordResetRepo.DeletePasswordResets(ctx, tx, userUID); err != nil {
+		return
+	}
+
+	if err = a.userRepo.Commit(tx); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return nil
+}
+
+// ChangeEmail starts replacing the email of the user after checking the password. The new email
+// is kept as pending and replaces the email only once the verification link sent to it is opened,
+// so a stolen access token alone can not move the account to another inbox. The current email is notified.
+// Wrong passwords count as failed logins of the account.
+func (a *accountUsecase) ChangeEmail(ctx context.Context, userUID, password, email string, device model.Device) (err error) {
+	defer derrors.Wrap(&err, "ChangeEmail(%q, %q)", userUID, email)
+
+	user, err := a.getUnlockedUser(ctx, userUID, device)
+	if err != nil {
+		return
+	}
+
+	// accounts created by social sign-in set a password with ForgotPassword first
+	if !user.HasPassword() {
+		return derrors.New(derrors.InvalidArgument, "Please set a password before changing the email")
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return a.rejectPassword(ctx, user, device, "Password is incorrect")
+	}
+
+	if user.Email != nil && *user.Email == email {
+		return derrors.New(derrors.InvalidArgument, "Email is unchanged")
+	}
+
+	existing, err := a.userRepo.GetUserByEmailOrPhoneNumber(ctx, email, "")
+	if err != nil {
+		return
+	}
+
+	if existing != nil && existing.UID != userUID {
+		return derrors.New(derrors.Duplicate, "Email already registered")
+	}
+
+	// a newer request replaces the pending email, links sent for the previous one stop working
+	if err = a.userRepo.UpdatePendingEmail(ctx, nil, userUID, &email); err != nil {
+		return
+	}
+
+	if err = a.sendEmailVerification(ctx, user, email); err != nil {
+		return
+	}
+
+	if user.Email != nil {
+		// the request is saved already, the notice is best effort
+		_ = a.mailer.Send(ctx, mailservice.Mail{
+			To:      *user.Email,
+			Subject: "Your email address is being changed",
+			Body: fmt.Sprintf("Hi %s,\n\nA change of the email address of your account to %s was requested. It takes effect once the new address is verified. If you did not do this, please reset your password and contact support.",
+				user.Name, email),
+		})
+	}
+
+	return nil
+}
+
+// applyPendingEmail replaces the email of the user with the verified pending email.
+func (a *accountUsecase) applyPendingEmail(ctx context.Context, user *model.User) (err error) {
+	email := *user.PendingEmail
+
+	// the address could have been registered by someone else since the change was requested
+	existing, err := a.userRepo.GetUserByEmailOrPhoneNumber(ctx, email, "")
+	if err != nil {
+		return
+	}
+
+	if existing != nil && existing.UID != user.UID {
+		return derrors.New(derrors.Duplicate, "Email already registered")
+	}
+
+	tx, err := a.userRepo.Begin()
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = a.userRepo.Rollback(tx)
+		}
+	}()
+
+	user.Email = &email
+	if err = a.userRepo.UpdateUser(ctx, tx, user); err != nil {
+		return
+	}
+
+	if err = a.userRepo.UpdatePendingEmail(ctx, tx, user.UID, nil); err != nil {
+		return
+	}
+
+	if err = a.userRepo.UpdateEmailVerifiedAt(ctx, tx, user.UID, datatype.NewTimeNow()); err != nil {
+		return
+	}
+
+	if err = a.userRepo.Commit(tx); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return nil
+}
+
+// DeleteAccount hides the user from everyone else and signs the user out of every device.
+// The account can be restored until the grace period is over, then PurgeDeletedAccounts removes it.
+// Users with a password have to confirm it, accounts created by social sign-in have none.
+// Wrong passwords count as failed logins of the account.
+func (a *accountUsecase) DeleteAccount(ctx context.Context, userUID, password string, device model.Device) (restoreBefore datatype.Time, err error) {
+	defer derrors.Wrap(&err, "DeleteAccount(%q)", userUID)
+
+	user, err := a.getUnlockedUser(ctx, userUID, device)
+	if err != nil {
+		return
+	}
+
+	if user.HasPassword() && bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return restoreBefore, a.rejectPassword(ctx, user, device, "Password is incorrect")
+	}
+
+	now := time.Now()
+	deleted, err := a.userRepo.SoftDeleteUser(ctx, nil, userUID, datatype.NewTime(&now))
+	if err != nil {
+		return
+	}
+
+	if !deleted {
+		return restoreBefore, derrors.New(derrors.InvalidArgument, "Account is already deleted")
+	}
+
+	if err = a.authService.RevokeUserTokens(ctx, userUID); err != nil {
+		return
+	}
+
+	purgeAt := now.Add(a.gracePeriod())
+	if user.Email != nil {
+		// the account is deleted already, the notice is best effort
+		_ = a.mailer.Send(ctx, mailservice.Mail{
+			To:      *user.Email,
+			Subject: "Your account was deleted",
+			Body: fmt.Sprintf("Hi %s,\n\nYour account was deleted and will be removed permanently on %s. Until then you can login and restore it.",
+				user.Name, purgeAt.Format(time.RFC1123)),
+		})
+	}
+
+	return datatype.NewTime(&purgeAt), nil
+}
+
+// RestoreAccount undoes DeleteAccount while the grace period is not over.
+func (a *accountUsecase) RestoreAccount(ctx context.Context, userUID string) (err error) {
+	defer derrors.Wrap(&err, "RestoreAccount(%q)", userUID)
+
+	restored, err := a.userRepo.RestoreUser(ctx, nil, userUID, time.Now().Add(-a.gracePeriod()))
+	if err != nil {
+		return
+	}
+
+	if !restored {
+		return derrors.New(derrors.InvalidArgument, "Account is not deleted or can no longer be restored")
+	}
+
+	return nil
+}
+
+// PurgeDeletedAccounts removes the accounts deleted before the grace period with every
+// row that belongs to them, each account in its own transaction.
+func (a *accountUsecase) PurgeDeletedAccounts(ctx context.Context) (purged int, err error) {
+	defer derrors.Wrap(&err, "PurgeDeletedAccounts")
+
+	deletedBefore := time.Now().Add(-a.gracePeriod())
+	uids, err := a.userRepo.GetDeletedUserUIDs(ctx, deletedBefore, constant.AccountPurgeBatchSize)
+	if err != nil {
+		return
+	}
+
+	for _, uid := range uids {
+		ok, err := a.purgeUser(ctx, uid, deletedBefore)
+		if err != nil {
+			return purged, err
+		}
+
+		if ok {
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// purgeUser runs PurgeUser in a transaction and deletes the photos of the user from the blob store
+// afterwards, ok is false when the user was restored in the meantime.
+func (a *accountUsecase) purgeUser(ctx context.Context, uid string, deletedBefore time.Time) (ok bool, err error) {
+	tx, err := a.userRepo.Begin()
+	if err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil || !ok {
+			_ = a.userRepo.Rollback(tx)
+		}
+	}()
+
+	photos, err := a.userPhotoRepo.LockUserPhotos(ctx, tx, uid)
+	if err != nil {
+		return
+	}
+
+	ok, err = a.userRepo.PurgeUser(ctx, tx, uid, deletedBefore)
+	if err != nil || !ok {
+		return
+	}
+
+	if err = a.userRepo.Commit(tx); err != nil {
+		return false, derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	// the rows are gone already, a blob that failed to delete is orphaned but never served
+	for _, photo := range photos {
+		_ = a.blobStore.Delete(ctx, photo.BlobKey)
+		_ = a.blobStore.Delete(ctx, photo.ThumbnailKey)
+	}
+
+	return true, nil
+}
+
+func (a *accountUsecase) gracePeriod() time.Duration {
+	return time.Duration(a.conf.AccountDeletionGracePeriod) * time.Minute
+}
+
+func (a *accountUsecase) getUser(ctx context.Context, userUID string) (user *model.User, err error) {
+	user, err = a.userRepo.GetUserByUID(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		return nil, derrors.New(derrors.NotFound, "User not found")
+	}
+
+	return user, nil
+}
+
+// getUnlockedUser returns the user that confirms a password, locked accounts are rejected.
+func (a *accountUsecase) getUnlockedUser(ctx context.Context, userUID string, device model.Device) (user *model.User, err error) {
+	user, err = a.getUser(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if err = a.loginAttempt.CheckLocked(ctx, model.NewLoginAttempt(user, device), user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// rejectPassword records a wrong password as a failed login and returns the error for it,
+// Locked when the failure locked the account.
+func (a *accountUsecase) rejectPassword(ctx context.Context, user *model.User, device model.Device, message string) (err error) {
+	if err = a.loginAttempt.RecordFailure(ctx, model.NewLoginAttempt(user, device), user, constant.LoginFailureInvalidPassword); err != nil {
+		return
+	}
+
+	return derrors.New(derrors.InvalidArgument, message)
+}
+
+func (a *accountUsecase) signEmailVerification(userUID, email string) (string, error) {
+	expirationTime := time.Now().Add(time.Duration(a.conf.EmailVerificationExpiration) * time.Minute)
+	claims := &model.EmailVerificationClaims{
+		UserUID: userUID,
+		Email:   email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    constant.TokenIssuer,
+			Audience:  jwt.ClaimStrings{constant.EmailVerificationAudience},
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(a.conf.EmailVerificationSecret)
+}
@@ -0,0 +1,711 @@
+package accountusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	blobservice "date-apps-be/internal/service/blob"
+	mailservice "date-apps-be/internal/service/mail"
+	"date-apps-be/internal/test"
+	accountusecase "date-apps-be/internal/usecase/account"
+	loginattemptusecase "date-apps-be/internal/usecase/login_attempt"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var tokenPattern = regexp.MustCompile(`/verify-email\?token=(\S+)`)
+
+func ptr(s string) *string {
+	return &s
+}
+
+// sendVerification sends a verification email and returns the token from its link.
+func sendVerification(t *testing.T, testUsecase accountusecase.AccountUsecase, mailer *mailservice.MemoryMailer, user *model.User) string {
+	require.NoError(t, testUsecase.SendEmailVerification(context.Background(), user))
+
+	mails := mailer.Mails()
+	require.NotEmpty(t, mails)
+	mail := mails[len(mails)-1]
+	assert.Equal(t, *user.Email, mail.To)
+
+	match := tokenPattern.FindStringSubmatch(mail.Body)
+	require.Len(t, match, 2)
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+	return token
+}
+
+func TestVerifyEmail(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	mc.Config.EmailVerificationSecret = []byte("secret")
+	mc.Config.EmailVerificationExpiration = 60
+	ctx := context.Background()
+	mailer := mailservice.NewMemoryMailer()
+	testUsecase := accountusecase.NewAccountUsecase(mc.Config, mc.UserRepository, mc.PasswordResetRepository, mc.AuthService, mailer, mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), zap.NewNop())
+	tx := &sql.Tx{}
+
+	var testCases = []struct {
+		caseName     string
+		user         *model.User
+		expectations func(user *model.User, token string) string
+		results      func(err error)
+	}{
+		{
+			caseName: "VerifyEmail_Success",
+			user:     &model.User{UID: "user-1", Name: "John", Email: ptr("john@example.com")},
+			expectations: func(user *model.User, token string) string {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, user.UID).Return(user, nil).Once()
+				mc.UserRepository.On("UpdateEmailVerifiedAt", mock.Anything, mock.Anything, user.UID, mock.Anything).Return(nil).Once()
+				return token
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName: "VerifyEmail_AlreadyVerified",
+			user:     &model.User{UID: "user-2", Name: "Jane", Email: ptr("jane@example.com"), EmailVerifiedAt: datatype.NewTimeNow()},
+			expectations: func(user *model.User, token string) string {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, user.UID).Return(user, nil).Once()
+				return token
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName: "VerifyEmail_EmailChanged",
+			user:     &model.User{UID: "user-3", Name: "Jim", Email: ptr("jim@example.com")},
+			expectations: func(user *model.User, token string) string {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, user.UID).
+					Return(&model.User{UID: user.UID, Email: ptr("jim.new@example.com")}, nil).Once()
+				return token
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+		{
+			caseName: "VerifyEmail_PendingEmail",
+			user:     &model.User{UID: "user-5", Name: "Jack", Email: ptr("jack.new@example.com")},
+			expectations: func(user *model.User, token string) string {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, user.UID).
+					Return(&model.User{UID: user.UID, Email: ptr("jack@example.com"), PendingEmail: ptr("jack.new@example.com")}, nil).Once()
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "jack.new@example.com", "").Return(nil, nil).Once()
+				mc.UserRepository.On("Begin").Return(tx, nil).Once()
+				mc.UserRepository.On("UpdateUser", mock.Anything, tx, mock.MatchedBy(func(u *model.User) bool {
+					return *u.Email == "jack.new@example.com"
+				})).Return(nil).Once()
+				mc.UserRepository.On("UpdatePendingEmail", mock.Anything, tx, user.UID, (*string)(nil)).Return(nil).Once()
+				mc.UserRepository.On("UpdateEmailVerifiedAt", mock.Anything, tx, user.UID, mock.Anything).Return(nil).Once()
+				mc.UserRepository.On("Commit", tx).Return(nil).Once()
+				return token
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName: "VerifyEmail_TamperedToken",
+			user:     &model.User{UID: "user-4", Name: "Joe", Email: ptr("joe@example.com")},
+			expectations: func(user *model.User, token string) string {
+				return token + "x"
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.caseName, func(t *testing.T) {
+			token := sendVerification(t, testUsecase, mailer, tc.user)
+			token = tc.expectations(tc.user, token)
+			err := testUsecase.VerifyEmail(ctx, token)
+			tc.results(err)
+		})
+	}
+}
+
+func TestVerifyEmail_Expired(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	mc.Config.EmailVerificationSecret = []byte("secret")
+	mc.Config.EmailVerificationExpiration = -1
+	mailer := mailservice.NewMemoryMailer()
+	testUsecase := accountusecase.NewAccountUsecase(mc.Config, mc.UserRepository, mc.PasswordResetRepository, mc.AuthService, mailer, mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), zap.NewNop())
+
+	token := sendVerification(t, testUsecase, mailer, &model.User{UID: "user-1", Email: ptr("john@example.com")})
+	err := testUsecase.VerifyEmail(context.Background(), token)
+	assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+}
+
+func TestResendEmailVerification(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	mc.Config.EmailVerificationSecret = []byte("secret")
+	mc.Config.EmailVerificationExpiration = 60
+	ctx := context.Background()
+	mailer := mailservice.NewMemoryMailer()
+	testUsecase := accountusecase.NewAccountUsecase(mc.Config, mc.UserRepository, mc.PasswordResetRepository, mc.AuthService, mailer, mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), zap.NewNop())
+
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "user-1").
+		Return(&model.User{UID: "user-1", Email: ptr("john@example.com")}, nil).Once()
+	assert.NoError(t, testUsecase.ResendEmailVerification(ctx, "user-1"))
+	assert.Len(t, mailer.Mails(), 1)
+
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "user-2").
+		Return(&model.User{UID: "user-2", Email: ptr("jane@example.com"), EmailVerifiedAt: datatype.NewTimeNow()}, nil).Once()
+	err := testUsecase.ResendEmailVerification(ctx, "user-2")
+	assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+	assert.Len(t, mailer.Mails(), 1)
+}
+
+func TestForgotPassword(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	mc.Config.PasswordResetURL = "http://localhost:3000/reset-password"
+	ctx := context.Background()
+	mailer := mailservice.NewMemoryMailer()
+	logCore, logs := observer.New(zap.ErrorLevel)
+	testUsecase := accountusecase.NewAccountUsecase(mc.Config, mc.UserRepository, mc.PasswordResetRepository, mc.AuthService, mailer, mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), zap.New(logCore))
+
+	var created *model.PasswordReset
+
+	// waitFor fails the test unless the reset sent in the background made its last call
+	waitFor := func(t *testing.T, called <-chan struct{}) {
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			t.Fatal("the reset was not handled")
+		}
+	}
+
+	var testCases = []struct {
+		caseName     string
+		email        string
+		expectations func(email string, done func(mock.Arguments))
+		results      func(t *testing.T, called <-chan struct{})
+	}{
+		{
+			caseName: "ForgotPassword_Success",
+			email:    "john@example.com",
+			expectations: func(email string, done func(mock.Arguments)) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, email, "").
+					Return(&model.User{UID: "user-1", Email: ptr(email)}, nil).Once()
+				mc.PasswordResetRepository.On("CountPasswordResets", mock.Anything, "user-1", constant.PasswordResetWindow).
+					Return(0, nil).Once()
+				mc.PasswordResetRepository.On("CreatePasswordReset", mock.Anything, mock.Anything, mock.MatchedBy(func(p *model.PasswordReset) bool {
+					created = p
+					return p.UserUID == "user-1"
+				})).Return(nil).Once()
+			},
+			results: func(t *testing.T, called <-chan struct{}) {
+				require.Eventually(t, func() bool { return len(mailer.Mails()) == 1 }, time.Second, 10*time.Millisecond)
+				match := regexp.MustCompile(`reset-password\?token=(\S+)`).FindStringSubmatch(mailer.Mails()[0].Body)
+				require.Len(t, match, 2)
+				token, err := url.QueryUnescape(match[1])
+				require.NoError(t, err)
+				assert.Equal(t, util.HashToken(token), created.TokenHash)
+			},
+		},
+		{
+			caseName: "ForgotPassword_UnknownEmail",
+			email:    "unknown@example.com",
+			expectations: func(email string, done func(mock.Arguments)) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, email, "").
+					Return(nil, nil).Run(done).Once()
+			},
+			results: waitFor,
+		},
+		{
+			caseName: "ForgotPassword_TooManyRequests",
+			email:    "jane@example.com",
+			expectations: func(email string, done func(mock.Arguments)) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, email, "").
+					Return(&model.User{UID: "user-2", Email: ptr(email)}, nil).Once()
+				mc.PasswordResetRepository.On("CountPasswordResets", mock.Anything, "user-2", constant.PasswordResetWindow).
+					Return(constant.MaxPasswordResetPerWindow, nil).Run(done).Once()
+			},
+			results: waitFor,
+		},
+		{
+			caseName: "ForgotPassword_Failure",
+			email:    "jim@example.com",
+			expectations: func(email string, done func(mock.Arguments)) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, email, "").
+					Return(nil, derrors.New(derrors.Unknown, "connection refused")).Once()
+			},
+			results: func(t *testing.T, called <-chan struct{}) {
+				require.Eventually(t, func() bool { return logs.Len() == 1 }, time.Second, 10*time.Millisecond)
+				assert.Contains(t, logs.All()[0].ContextMap()["error"], "connection refused")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.caseName, func(t *testing.T) {
+			called := make(chan struct{})
+			tc.expectations(tc.email, func(mock.Arguments) { close(called) })
+
+			// the reset goes on after the request ends
+			ctx, cancel := context.WithCancel(ctx)
+			start := time.Now()
+			err := testUsecase.ForgotPassword(ctx, tc.email)
+			cancel()
+			assert.NoError(t, err, "every email gets the same answer")
+			assert.Less(t, time.Since(start), 100*time.Millisecond, "the answer does not wait for the reset")
+			tc.results(t, called)
+		})
+	}
+
+	assert.Len(t, mailer.Mails(), 1)
+}
+
+func TestResetPassword(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := accountusecase.NewAccountUsecase(mc.Config, mc.UserRepository, mc.PasswordResetRepository, mc.AuthService, mailservice.NewMemoryMailer(), mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), zap.NewNop())
+
+	expiresAt := time.Now().Add(time.Hour)
+	expiredAt := time.Now().Add(-time.Minute)
+	tx := &sql.Tx{}
+
+	var testCases = []struct {
+		caseName     string
+		token        string
+		expectations func(token string)
+		results      func(err error)
+	}{
+		{
+			caseName: "ResetPassword_Success",
+			token:    "valid-token",
+			expectations: func(token string) {
+				mc.PasswordResetRepository.On("GetPasswordResetByHash", mock.Anything, util.HashToken(token)).
+					Return(&model.PasswordReset{UID: "reset-1", UserUID: "user-1", ExpiresAt: datatype.NewTime(&expiresAt)}, nil).Once()
+				mc.PasswordResetRepository.On("Begin").Return(tx, nil).Once()
+				mc.PasswordResetRepository.On("UsePasswordReset", mock.Anything, tx, "reset-1").Return(true, nil).Once()
+				mc.UserRepository.On("UpdatePassword", mock.Anything, tx, "user-1", mock.MatchedBy(func(hash string) bool {
+					return bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-password")) == nil
+				})).Return(nil).Once()
+				mc.PasswordResetRepository.On("DeletePasswordResets", mock.Anything, tx, "user-1").Return(nil).Once()
+				mc.PasswordResetRepository.On("Commit", tx).Return(nil).Once()
+				mc.AuthService.On("RevokeUserTokens", mock.Anything, "user-1").Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName: "ResetPassword_AlreadyUsed",
+			token:    "used-token",
+			expectations: func(token string) {
+				mc.PasswordResetRepository.On("GetPasswordResetByHash", mock.Anything, util.HashToken(token)).
+					Return(&model.PasswordReset{UID: "reset-2", UserUID: "user-2", ExpiresAt: datatype.NewTime(&expiresAt)}, nil).Once()
+				mc.PasswordResetRepository.On("Begin").Return(tx, nil).Once()
+				mc.PasswordResetRepository.On("UsePasswordReset", mock.Anything, tx, "reset-2").Return(false, nil).Once()
+				mc.PasswordResetRepository.On("Rollback", tx).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+		{
+			caseName: "ResetPassword_Expired",
+			token:    "expired-token",
+			expectations: func(token string) {
+				mc.PasswordResetRepository.On("GetPasswordResetByHash", mock.Anything, util.HashToken(token)).
+					Return(&model.PasswordReset{UID: "reset-3", UserUID: "user-3", ExpiresAt: datatype.NewTime(&expiredAt)}, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+		{
+			caseName: "ResetPassword_UnknownToken",
+			token:    "unknown-token",
+			expectations: func(token string) {
+				mc.PasswordResetRepository.On("GetPasswordResetByHash", mock.Anything, util.HashToken(token)).
+					Return(nil, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.caseName, func(t *testing.T) {
+			tc.expectations(tc.token)
+			err := testUsecase.ResetPassword(ctx, tc.token, "new-password")
+			tc.results(err)
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := accountusecase.NewAccountUsecase(mc.Config, mc.UserRepository, mc.PasswordResetRepository, mc.AuthService, mailservice.NewMemoryMailer(), mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), zap.NewNop())
+	device := model.Device{ID: "device_1", IPAddress: "127.0.0.1"}
+	tx := &sql.Tx{}
+	lockedUntil := time.Now().Add(constant.AccountLockDuration)
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("current-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	var testCases = []struct {
+		caseName        string
+		userUID         string
+		currentPassword string
+		expectations    func(userUID string)
+		results         func(err error)
+	}{
+		{
+			caseName:        "ChangePassword_Success",
+			userUID:         "user-1",
+			currentPassword: "current-password",
+			expectations: func(userUID string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).
+					Return(&model.User{UID: userUID, Password: string(hashedPassword)}, nil).Once()
+				mc.UserRepository.On("Begin").Return(tx, nil).Once()
+				mc.UserRepository.On("UpdatePassword", mock.Anything, tx, userUID, mock.MatchedBy(func(password string) bool {
+					return bcrypt.CompareHashAndPassword([]byte(password), []byte("new-password")) == nil
+				})).Return(nil).Once()
+				mc.PasswordResetRepository.On("DeletePasswordResets", mock.Anything, tx, userUID).Return(nil).Once()
+				mc.UserRepository.On("Commit", tx).Return(nil).Once()
+				mc.AuthService.On("RevokeUserTokens", mock.Anything, userUID).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+				mc.UserRepository.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			caseName:        "ChangePassword_WrongCurrentPassword",
+			userUID:         "user-2",
+			currentPassword: "wrong-password",
+			expectations: func(userUID string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).
+					Return(&model.User{UID: userUID, Password: string(hashedPassword)}, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && *l.UserUID == userUID && *l.FailureReason == constant.LoginFailureInvalidPassword
+				})).Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, userUID, constant.FailedLoginWindow).Return(1, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				mc.AuthService.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, "user-2")
+			},
+		},
+		{
+			caseName:        "ChangePassword_WrongCurrentPasswordLocks",
+			userUID:         "user-3",
+			currentPassword: "wrong-password",
+			expectations: func(userUID string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).
+					Return(&model.User{UID: userUID, Password: string(hashedPassword)}, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && *l.UserUID == userUID && *l.FailureReason == constant.LoginFailureInvalidPassword
+				})).Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, userUID, constant.FailedLoginWindow).Return(constant.MaxFailedLoginPerAccount, nil).Once()
+				mc.UserRepository.On("UpdateLockedUntil", mock.Anything, mock.Anything, userUID, mock.Anything).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+			},
+		},
+		{
+			caseName:        "ChangePassword_Locked",
+			userUID:         "user-4",
+			currentPassword: "current-password",
+			expectations: func(userUID string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).
+					Return(&model.User{UID: userUID, Password: string(hashedPassword), LockedUntil: datatype.NewTime(&lockedUntil)}, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && *l.UserUID == userUID && *l.FailureReason == constant.LoginFailureAccountLocked
+				})).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+				mc.UserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, "user-4", mock.Anything)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.caseName, func(t *testing.T) {
+			tc.expectations(tc.userUID)
+			err := testUsecase.ChangePassword(ctx, tc.userUID, tc.currentPassword, "new-password", device)
+			tc.results(err)
+		})
+	}
+}
+
+func TestChangeEmail(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	mc.Config.EmailVerificationSecret = []byte("secret")
+	mc.Config.EmailVerificationExpiration = 60
+	ctx := context.Background()
+	mailer := mailservice.NewMemoryMailer()
+	testUsecase := accountusecase.NewAccountUsecase(mc.Config, mc.UserRepository, mc.PasswordResetRepository, mc.AuthService, mailer, mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), zap.NewNop())
+	device := model.Device{ID: "device_1", IPAddress: "127.0.0.1"}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("current-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	var testCases = []struct {
+		caseName     string
+		userUID      string
+		password     string
+		email        string
+		expectations func(userUID, email string)
+		results      func(err error)
+	}{
+		{
+			caseName: "ChangeEmail_Success",
+			userUID:  "user-1",
+			password: "current-password",
+			email:    "john.new@example.com",
+			expectations: func(userUID, email string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).
+					Return(&model.User{UID: userUID, Email: ptr("john@example.com"), Password: string(hashedPassword), EmailVerifiedAt: datatype.NewTimeNow()}, nil).Once()
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, email, "").Return(nil, nil).Once()
+				mc.UserRepository.On("UpdatePendingEmail", mock.Anything, (*sql.Tx)(nil), userUID, mock.MatchedBy(func(e *string) bool {
+					return e != nil && *e == email
+				})).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+				mc.UserRepository.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+
+				mails := mailer.Mails()
+				require.Len(t, mails, 2)
+				assert.Equal(t, "john.new@example.com", mails[0].To)
+				assert.Equal(t, "john@example.com", mails[1].To)
+			},
+		},
+		{
+			caseName: "ChangeEmail_WrongPassword",
+			userUID:  "user-2",
+			password: "wrong-password",
+			email:    "jane.new@example.com",
+			expectations: func(userUID, email string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).
+					Return(&model.User{UID: userUID, Email: ptr("jane@example.com"), Password: string(hashedPassword)}, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && *l.UserUID == userUID && *l.FailureReason == constant.LoginFailureInvalidPassword
+				})).Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, userUID, constant.FailedLoginWindow).Return(1, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Len(t, mailer.Mails(), 2)
+			},
+		},
+		{
+			caseName: "ChangeEmail_NoPassword",
+			userUID:  "user-3",
+			password: "",
+			email:    "jim.new@example.com",
+			expectations: func(userUID, email string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).
+					Return(&model.User{UID: userUID, Email: ptr("jim@example.com")}, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Len(t, mailer.Mails(), 2)
+			},
+		},
+		{
+			caseName: "ChangeEmail_Duplicate",
+			userUID:  "user-4",
+			password: "current-password",
+			email:    "taken@example.com",
+			expectations: func(userUID, email string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).
+					Return(&model.User{UID: userUID, Email: ptr("joe@example.com"), Password: string(hashedPassword)}, nil).Once()
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, email, "").
+					Return(&model.User{UID: "user-5", Email: ptr(email)}, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Duplicate))
+				assert.Len(t, mailer.Mails(), 2)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.caseName, func(t *testing.T) {
+			tc.expectations(tc.userUID, tc.email)
+			err := testUsecase.ChangeEmail(ctx, tc.userUID, tc.password, tc.email, device)
+			tc.results(err)
+		})
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	mailer := mailservice.NewMemoryMailer()
+	testUsecase := accountusecase.NewAccountUsecase(mc.Config, mc.UserRepository, mc.PasswordResetRepository, mc.AuthService, mailer, mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), zap.NewNop())
+	device := model.Device{ID: "device_1", IPAddress: "127.0.0.1"}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	var testCases = []struct {
+		caseName     string
+		userUID      string
+		password     string
+		expectations func(userUID string)
+		results      func(restoreBefore datatype.Time, err error)
+	}{
+		{
+			caseName: "DeleteAccount_Success",
+			userUID:  "user-1",
+			password: "password",
+			expectations: func(userUID string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).
+					Return(&model.User{UID: userUID, Name: "John", Email: ptr("john@example.com"), Password: string(hashedPassword)}, nil).Once()
+				mc.UserRepository.On("SoftDeleteUser", mock.Anything, mock.Anything, userUID, mock.Anything).Return(true, nil).Once()
+				mc.AuthService.On("RevokeUserTokens", mock.Anything, userUID).Return(nil).Once()
+			},
+			results: func(restoreBefore datatype.Time, err error) {
+				require.NoError(t, err)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), *restoreBefore.Time(), time.Minute)
+				mails := mailer.Mails()
+				require.Len(t, mails, 1)
+				assert.Equal(t, "john@example.com", mails[0].To)
+			},
+		},
+		{
+			caseName: "DeleteAccount_WithoutPassword",
+			userUID:  "user-2",
+			expectations: func(userUID string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).Return(&model.User{UID: userUID}, nil).Once()
+				mc.UserRepository.On("SoftDeleteUser", mock.Anything, mock.Anything, userUID, mock.Anything).Return(true, nil).Once()
+				mc.AuthService.On("RevokeUserTokens", mock.Anything, userUID).Return(nil).Once()
+			},
+			results: func(restoreBefore datatype.Time, err error) {
+				assert.NoError(t, err)
+				assert.False(t, restoreBefore.IsNil())
+			},
+		},
+		{
+			caseName: "DeleteAccount_WrongPassword",
+			userUID:  "user-3",
+			password: "wrong-password",
+			expectations: func(userUID string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).
+					Return(&model.User{UID: userUID, Password: string(hashedPassword)}, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && *l.UserUID == userUID && *l.FailureReason == constant.LoginFailureInvalidPassword
+				})).Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, userUID, constant.FailedLoginWindow).Return(1, nil).Once()
+			},
+			results: func(restoreBefore datatype.Time, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				mc.UserRepository.AssertNotCalled(t, "SoftDeleteUser", mock.Anything, mock.Anything, "user-3", mock.Anything)
+			},
+		},
+		{
+			caseName: "DeleteAccount_WrongPasswordLocks",
+			userUID:  "user-5",
+			password: "wrong-password",
+			expectations: func(userUID string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).
+					Return(&model.User{UID: userUID, Password: string(hashedPassword)}, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && *l.UserUID == userUID && *l.FailureReason == constant.LoginFailureInvalidPassword
+				})).Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, userUID, constant.FailedLoginWindow).Return(constant.MaxFailedLoginPerAccount, nil).Once()
+				mc.UserRepository.On("UpdateLockedUntil", mock.Anything, mock.Anything, userUID, mock.Anything).Return(nil).Once()
+			},
+			results: func(restoreBefore datatype.Time, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+				mc.UserRepository.AssertNotCalled(t, "SoftDeleteUser", mock.Anything, mock.Anything, "user-5", mock.Anything)
+			},
+		},
+		{
+			caseName: "DeleteAccount_AlreadyDeleted",
+			userUID:  "user-4",
+			expectations: func(userUID string) {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).Return(&model.User{UID: userUID, DeletedAt: datatype.NewTimeNow()}, nil).Once()
+				mc.UserRepository.On("SoftDeleteUser", mock.Anything, mock.Anything, userUID, mock.Anything).Return(false, nil).Once()
+			},
+			results: func(restoreBefore datatype.Time, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				mc.AuthService.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, "user-4")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.caseName, func(t *testing.T) {
+			tc.expectations(tc.userUID)
+			restoreBefore, err := testUsecase.DeleteAccount(ctx, tc.userUID, tc.password, device)
+			tc.results(restoreBefore, err)
+		})
+	}
+}
+
+func TestRestoreAccount(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := accountusecase.NewAccountUsecase(mc.Config, mc.UserRepository, mc.PasswordResetRepository, mc.AuthService, mailservice.NewMemoryMailer(), mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), zap.NewNop())
+
+	deletedAfter := mock.MatchedBy(func(deletedAfter time.Time) bool {
+		return deletedAfter.Sub(time.Now().Add(-time.Hour)).Abs() < time.Minute
+	})
+	mc.UserRepository.On("RestoreUser", mock.Anything, mock.Anything, "user-1", deletedAfter).Return(true, nil).Once()
+	mc.UserRepository.On("RestoreUser", mock.Anything, mock.Anything, "user-2", deletedAfter).Return(false, nil).Once()
+
+	assert.NoError(t, testUsecase.RestoreAccount(ctx, "user-1"))
+
+	err := testUsecase.RestoreAccount(ctx, "user-2")
+	assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+}
+
+func TestPurgeDeletedAccounts(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	blobStore := blobservice.NewLocalBlobStore(t.TempDir())
+	testUsecase := accountusecase.NewAccountUsecase(mc.Config, mc.UserRepository, mc.PasswordResetRepository, mc.AuthService, mailservice.NewMemoryMailer(), mc.UserPhotoRepository, blobStore, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), zap.NewNop())
+
+	photo := &model.UserPhoto{UID: "photo-1", UserUID: "user-1", BlobKey: "photos/user-1/photo-1.jpg", ThumbnailKey: "photos/user-1/photo-1_thumbnail.jpg"}
+	require.NoError(t, blobStore.Put(ctx, photo.BlobKey, strings.NewReader("original")))
+	require.NoError(t, blobStore.Put(ctx, photo.ThumbnailKey, strings.NewReader("thumbnail")))
+
+	mc.UserRepository.On("GetDeletedUserUIDs", mock.Anything, mock.Anything, uint64(constant.AccountPurgeBatchSize)).
+		Return([]string{"user-1", "user-2"}, nil).Once()
+	mc.UserRepository.On("Begin").Return((*sql.Tx)(nil), nil).Twice()
+	mc.UserPhotoRepository.On("LockUserPhotos", mock.Anything, mock.Anything, "user-1").Return([]*model.UserPhoto{photo}, nil).Once()
+	mc.UserPhotoRepository.On("LockUserPhotos", mock.Anything, mock.Anything, "user-2").Return([]*model.UserPhoto{}, nil).Once()
+	mc.UserRepository.On("PurgeUser", mock.Anything, mock.Anything, "user-1", mock.Anything).Return(true, nil).Once()
+	mc.UserRepository.On("Commit", mock.Anything).Return(nil).Once()
+	// restored while the purge was running
+	mc.UserRepository.On("PurgeUser", mock.Anything, mock.Anything, "user-2", mock.Anything).Return(false, nil).Once()
+	mc.UserRepository.On("Rollback", mock.Anything).Return(nil).Once()
+
+	purged, err := testUsecase.PurgeDeletedAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	_, err = blobStore.Get(ctx, photo.BlobKey)
+	assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+	_, err = blobStore.Get(ctx, photo.ThumbnailKey)
+	assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+}
@@ -0,0 +1,564 @@
+package dataexportusecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"date-apps-be/infrastructure/config"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	dataexportrepo "date-apps-be/internal/repository/data_export"
+	loginhistoryrepo "date-apps-be/internal/repository/login_history"
+	mutualmatchrepo "date-apps-be/internal/repository/mutual_match"
+	userrepo "date-apps-be/internal/repository/user"
+	userlocationrepo "date-apps-be/internal/repository/user_location"
+	usermatchrepo "date-apps-be/internal/repository/user_match"
+	userphotorepo "date-apps-be/internal/repository/user_photo"
+	userpreferencerepo "date-apps-be/internal/repository/user_preference"
+	userpremiumrepo "date-apps-be/internal/repository/user_premium"
+	userrewindrepo "date-apps-be/internal/repository/user_rewind"
+	blobservice "date-apps-be/internal/service/blob"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"encoding/json"
+	"io"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/segmentio/ksuid"
+)
+
+type (
+	DataExportUsecase interface {
+		RequestDataExport(ctx context.Context, userUID string) (export *model.DataExport, err error)
+		GetDataExport(ctx context.Context, userUID, exportUID string) (export *model.DataExport, err error)
+		OpenDataExport(ctx context.Context, token string) (export *model.DataExport, archive io.ReadCloser, err error)
+		ProcessDataExports(ctx context.Context) (processed int, err error)
+		DeleteExpiredDataExports(ctx context.Context) (deleted int, err error)
+	}
+
+	dataExportUsecase struct {
+		conf               *config.Config
+		dataExportRepo     dataexportrepo.DataExportRepository
+		userRepo           userrepo.UserRepository
+		userMatchRepo      usermatchrepo.UserMatchRepository
+		userPremiumRepo    userpremiumrepo.UserPremiumRepository
+		loginHistoryRepo   loginhistoryrepo.LoginHistoryRepository
+		userPhotoRepo      userphotorepo.UserPhotoRepository
+		userPreferenceRepo userpreferencerepo.UserPreferenceRepository
+		userLocationRepo   userlocationrepo.UserLocationRepository
+		mutualMatchRepo    mutualmatchrepo.MutualMatchRepository
+		userRewindRepo     userrewindrepo.UserRewindRepository
+		blobStore          blobservice.BlobStore
+	}
+
+	// profileExport is profile.json of the archive.
+	profileExport struct {
+		UID             string              `json:"uid"`
+		Name            string              `json:"name"`
+		Email           *string             `json:"email,omitempty"`
+		EmailVerifiedAt datatype.Time       `json:"email_verified_at"`
+		PhoneNumber     *string             `json:"phone_number,omitempty"`
+		PhoneVerifiedAt datatype.Time       `json:"phone_verified_at"`
+		Birthdate       datatype.Date       `json:"birthdate"`
+		Gender          *constant.Gender    `json:"gender,omitempty"`
+		Bio             *string             `json:"bio,omitempty"`
+		JobTitle        *string             `json:"job_title,omitempty"`
+		Company         *string             `json:"company,omitempty"`
+		Education       *string             `json:"education,omitempty"`
+		HeightCM        *int                `json:"height_cm,omitempty"`
+		Interests       datatype.StringList `json:"interests"`
+	}
+
+	// photoExport is one row of photos.json, file is the path of the original in the archive.
+	photoExport struct {
+		UID         string        `json:"id"`
+		File        string        `json:"file"`
+		Position    int           `json:"position"`
+		IsPrimary   bool          `json:"is_primary"`
+		ContentType string        `json:"content_type"`
+		Width       int           `json:"width"`
+		Height      int           `json:"height"`
+		CreatedAt   datatype.Time `json:"created_at"`
+	}
+
+	// mutualMatchExport is one row of mutual_matches.json.
+	mutualMatchExport struct {
+		MatchUID  string        `json:"match_uid"`
+		CreatedAt datatype.Time `json:"created_at"`
+	}
+
+	// rewindExport is one row of rewinds.json.
+	rewindExport struct {
+		MatchUID  string        `json:"match_uid"`
+		MatchType string        `json:"match_type"`
+		SwipedAt  datatype.Time `json:"swiped_at"`
+		CreatedAt datatype.Time `json:"created_at"`
+	}
+
+	// matchExport is one row of matches.json, direction is outgoing for swipes made by
+	// the user and incoming for swipes made on the user.
+	matchExport struct {
+		Direction string        `json:"direction"`
+		UserUID   string        `json:"user_uid"`
+		MatchUID  string        `json:"match_uid"`
+		MatchType string        `json:"match_type"`
+		CreatedAt datatype.Time `json:"created_at"`
+	}
+)
+
+func NewDataExportUsecase(
+	conf *config.Config,
+	dataExportRepo dataexportrepo.DataExportRepository,
+	userRepo userrepo.UserRepository,
+	userMatchRepo usermatchrepo.UserMatchRepository,
+	userPremiumRepo userpremiumrepo.UserPremiumRepository,
+	loginHistoryRepo loginhistoryrepo.LoginHistoryRepository,
+	userPhotoRepo userphotorepo.UserPhotoRepository,
+	userPreferenceRepo userpreferencerepo.UserPreferenceRepository,
+	userLocationRepo userlocationrepo.UserLocationRepository,
+	mutualMatchRepo mutualmatchrepo.MutualMatchRepository,
+	userRewindRepo userrewindrepo.UserRewindRepository,
+	blobStore blobservice.BlobStore,
+) DataExportUsecase {
+	return &dataExportUsecase{
+		conf:               conf,
+		dataExportRepo:     dataExportRepo,
+		userRepo:           userRepo,
+		userMatchRepo:      userMatchRepo,
+		userPremiumRepo:    userPremiumRepo,
+		loginHistoryRepo:   loginHistoryRepo,
+		userPhotoRepo:      userPhotoRepo,
+		userPreferenceRepo: userPreferenceRepo,
+		userLocationRepo:   userLocationRepo,
+		mutualMatchRepo:    mutualMatchRepo,
+		userRewindRepo:     userRewindRepo,
+		blobStore:          blobStore,
+	}
+}
+
+// RequestDataExport queues an archive of the personal data of the user, the archive is
+// generated by ProcessDataExports. An export that is still queued is returned instead of a new one.
+func (d *dataExportUsecase) RequestDataExport(ctx context.Context, userUID string) (export *model.DataExport, err error) {
+	defer derrors.Wrap(&err, "RequestDataExport(%q)", userUID)
+
+	export, err = d.dataExportRepo.GetActiveDataExport(ctx, userUID)
+	if err != nil || export != nil {
+		return
+	}
+
+	total, err := d.dataExportRepo.CountDataExports(ctx, userUID, constant.DataExportWindow)
+	if err != nil {
+		return
+	}
+
+	if total >= constant.MaxDataExportPerWindow {
+		return nil, derrors.New(derrors.TooManyRequests, "Too many data exports requested, please try again later")
+	}
+
+	export = &model.DataExport{
+		UID:       ksuid.New().String(),
+		UserUID:   userUID,
+		Status:    constant.DataExportStatusPending,
+		CreatedAt: datatype.NewTimeNow(),
+	}
+
+	if err = d.dataExportRepo.CreateDataExport(ctx, nil, export); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// GetDataExport returns an export of the user with a fresh download link once it is completed.
+func (d *dataExportUsecase) GetDataExport(ctx context.Context, userUID, exportUID string) (export *model.DataExport, err error) {
+	defer derrors.Wrap(&err, "GetDataExport(%q, %q)", userUID, exportUID)
+
+	export, err = d.dataExportRepo.GetDataExport(ctx, exportUID)
+	if err != nil {
+		return
+	}
+
+	if export == nil || export.UserUID != userUID {
+		return nil, derrors.New(derrors.NotFound, "Data export not found")
+	}
+
+	if export.IsDownloadable() {
+		token, err := d.signDownload(export)
+		if err != nil {
+			return nil, derrors.WrapStack(err, derrors.Unknown, "signDownload")
+		}
+		export.DownloadURL = d.conf.AppBaseURL + "/exports/download?token=" + url.QueryEscape(token)
+	}
+
+	return export, nil
+}
+
+// OpenDataExport checks a signed download link and opens the archive, the caller closes it.
+func (d *dataExportUsecase) OpenDataExport(ctx context.Context, token string) (export *model.DataExport, archive io.ReadCloser, err error) {
+	defer derrors.Wrap(&err, "OpenDataExport")
+
+	claims := &model.DataExportClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return d.conf.DataExportSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(constant.DataExportAudience), jwt.WithIssuer(constant.TokenIssuer))
+	if err != nil {
+		return nil, nil, derrors.WrapStack(err, derrors.Unauthorized, "Invalid or expired download link")
+	}
+
+	export, err = d.dataExportRepo.GetDataExport(ctx, claims.ExportID)
+	if err != nil {
+		return
+	}
+
+	if export == nil || export.UserUID != claims.UserUID || !export.IsDownloadable() {
+		return nil, nil, derrors.New(derrors.NotFound, "Data export not found or expired")
+	}
+
+	archive, err = d.blobStore.Get(ctx, *export.BlobKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return export, archive, nil
+}
+
+// ProcessDataExports generates the archives of queued exports. A failed archive
+// marks only its own export as failed, the user can request a new one.
+func (d *dataExportUsecase) ProcessDataExports(ctx context.Context) (processed int, err error) {
+	defer derrors.Wrap(&err, "ProcessDataExports")
+
+	exports, err := d.dataExportRepo.GetPendingDataExports(ctx, constant.DataExportBatchSize)
+	if err != nil {
+		return
+	}
+
+	for _, export := range exports {
+		claimed, err := d.dataExportRepo.ClaimDataExport(ctx, export.UID)
+		if err != nil {
+			return processed, err
+		}
+
+		if !claimed {
+			continue
+		}
+
+		if err = d.processDataExport(ctx, export); err != nil {
+			if err = d.dataExportRepo.FailDataExport(ctx, export.UID, truncate(err.Error(), 255)); err != nil {
+				return processed, err
+			}
+		}
+
+		processed++
+	}
+
+	return processed, nil
+}
+
+func (d *dataExportUsecase) processDataExport(ctx context.Context, export *model.DataExport) (err error) {
+	archive, err := d.buildArchive(ctx, export.UserUID)
+	if err != nil {
+		return
+	}
+
+	blobKey := "exports/" + export.UserUID + "/" + export.UID + ".zip"
+	if err = d.blobStore.Put(ctx, blobKey, bytes.NewReader(archive)); err != nil {
+		return
+	}
+
+	expiresAt := time.Now().Add(constant.DataExportExpiration)
+	return d.dataExportRepo.CompleteDataExport(ctx, export.UID, blobKey, int64(len(archive)), datatype.NewTime(&expiresAt))
+}
+
+// buildArchive zips the profile, the photos, the discovery preferences, the last location, the swipes
+// in both directions including super-likes, the mutual matches, the rewinds, the premium history
+// and the login history of the user as JSON files, the original photos are added under photos/.
+func (d *dataExportUsecase) buildArchive(ctx context.Context, userUID string) (archive []byte, err error) {
+	user, err := d.userRepo.GetUserByUID(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		return nil, derrors.New(derrors.NotFound, "User not found")
+	}
+
+	userPackages, err := d.userPremiumRepo.GetUserPackages(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	matches, err := d.getMatches(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	loginHistories, err := d.getLoginHistories(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	photos, err := d.userPhotoRepo.GetUserPhotos(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	preference, err := d.userPreferenceRepo.GetUserPreference(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	location, err := d.userLocationRepo.GetUserLocation(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	mutualMatches, err := d.getMutualMatches(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	rewinds, err := d.getRewinds(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+
+	photoExports := make([]photoExport, 0, len(photos))
+	for _, photo := range photos {
+		file := "photos/" + photo.UID + path.Ext(photo.BlobKey)
+		if err = d.writeBlob(ctx, zw, file, photo.BlobKey); err != nil {
+			return nil, err
+		}
+
+		photoExports = append(photoExports, photoExport{
+			UID:         photo.UID,
+			File:        file,
+			Position:    photo.Position,
+			IsPrimary:   photo.IsPrimary,
+			ContentType: photo.ContentType,
+			Width:       photo.Width,
+			Height:      photo.Height,
+			CreatedAt:   photo.CreatedAt,
+		})
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{name: "profile.json", data: profileExport{
+			UID:             user.UID,
+			Name:            user.Name,
+			Email:           user.Email,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			PhoneNumber:     user.PhoneNumber,
+			PhoneVerifiedAt: user.PhoneVerifiedAt,
+			Birthdate:       user.Birthdate,
+			Gender:          user.Gender,
+			Bio:             user.Bio,
+			JobTitle:        user.JobTitle,
+			Company:         user.Company,
+			Education:       user.Education,
+			HeightCM:        user.HeightCM,
+			Interests:       user.Interests,
+		}},
+		{name: "photos.json", data: photoExports},
+		{name: "preferences.json", data: preference},
+		{name: "location.json", data: location},
+		{name: "matches.json", data: matches},
+		{name: "mutual_matches.json", data: mutualMatches},
+		{name: "rewinds.json", data: rewinds},
+		{name: "premium.json", data: userPackages},
+		{name: "login_history.json", data: loginHistories},
+	}
+
+	for _, file := range files {
+		if err = writeJSON(zw, file.name, file.data); err != nil {
+			return nil, derrors.WrapStack(err, derrors.Unknown, "writeJSON(%q)", file.name)
+		}
+	}
+
+	if err = zw.Close(); err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "zip.Close")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (d *dataExportUsecase) getMatches(ctx context.Context, userUID string) (matches []matchExport, err error) {
+	matches = []matchExport{}
+	for page := uint64(1); ; page++ {
+		userMatches, err := d.userMatchRepo.GetUserMatchHistory(ctx, userUID, page, constant.DataExportPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, userMatch := range userMatches {
+			direction := "outgoing"
+			if userMatch.UserUID != userUID {
+				direction = "incoming"
+			}
+
+			matches = append(matches, matchExport{
+				Direction: direction,
+				UserUID:   userMatch.UserUID,
+				MatchUID:  userMatch.MatchUID,
+				MatchType: userMatch.MatchType.String(),
+				CreatedAt: userMatch.CreatedAt,
+			})
+		}
+
+		if len(userMatches) < constant.DataExportPageSize {
+			return matches, nil
+		}
+	}
+}
+
+func (d *dataExportUsecase) getMutualMatches(ctx context.Context, userUID string) (mutualMatches []mutualMatchExport, err error) {
+	mutualMatches = []mutualMatchExport{}
+	for page := uint64(1); ; page++ {
+		history, err := d.mutualMatchRepo.GetMutualMatchHistory(ctx, userUID, page, constant.DataExportPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mutualMatch := range history {
+			mutualMatches = append(mutualMatches, mutualMatchExport{
+				MatchUID:  mutualMatch.OtherUID(userUID),
+				CreatedAt: mutualMatch.CreatedAt,
+			})
+		}
+
+		if len(history) < constant.DataExportPageSize {
+			return mutualMatches, nil
+		}
+	}
+}
+
+func (d *dataExportUsecase) getRewinds(ctx context.Context, userUID string) (rewinds []rewindExport, err error) {
+	rewinds = []rewindExport{}
+	for page := uint64(1); ; page++ {
+		userRewinds, err := d.userRewindRepo.GetUserRewinds(ctx, userUID, page, constant.DataExportPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rewind := range userRewinds {
+			rewinds = append(rewinds, rewindExport{
+				MatchUID:  rewind.MatchUID,
+				MatchType: rewind.MatchType.String(),
+				SwipedAt:  rewind.SwipedAt,
+				CreatedAt: rewind.CreatedAt,
+			})
+		}
+
+		if len(userRewinds) < constant.DataExportPageSize {
+			return rewinds, nil
+		}
+	}
+}
+
+func (d *dataExportUsecase) getLoginHistories(ctx context.Context, userUID string) (loginHistories []*model.LoginHistory, err error) {
+	loginHistories = []*model.LoginHistory{}
+	for page := uint64(1); ; page++ {
+		histories, err := d.loginHistoryRepo.GetLoginHistories(ctx, userUID, page, constant.DataExportPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		loginHistories = append(loginHistories, histories...)
+		if len(histories) < constant.DataExportPageSize {
+			return loginHistories, nil
+		}
+	}
+}
+
+// DeleteExpiredDataExports deletes the archives that expired or belong to a deleted account.
+func (d *dataExportUsecase) DeleteExpiredDataExports(ctx context.Context) (deleted int, err error) {
+	defer derrors.Wrap(&err, "DeleteExpiredDataExports")
+
+	exports, err := d.dataExportRepo.GetExpiredDataExports(ctx, constant.DataExportBatchSize)
+	if err != nil {
+		return
+	}
+
+	for _, export := range exports {
+		if export.BlobKey != nil {
+			if err = d.blobStore.Delete(ctx, *export.BlobKey); err != nil {
+				return deleted, err
+			}
+		}
+
+		if err = d.dataExportRepo.ExpireDataExport(ctx, export.UID); err != nil {
+			return deleted, err
+		}
+
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// signDownload signs a download link, it expires with the archive at the latest.
+func (d *dataExportUsecase) signDownload(export *model.DataExport) (string, error) {
+	expirationTime := time.Now().Add(constant.DataExportLinkExpiration)
+	if export.ExpiresAt.Time().Before(expirationTime) {
+		expirationTime = *export.ExpiresAt.Time()
+	}
+
+	claims := &model.DataExportClaims{
+		UserUID:  export.UserUID,
+		ExportID: export.UID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    constant.TokenIssuer,
+			Audience:  jwt.ClaimStrings{constant.DataExportAudience},
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(d.conf.DataExportSecret)
+}
+
+// writeBlob copies a file of the blob store into the archive.
+func (d *dataExportUsecase) writeBlob(ctx context.Context, zw *zip.Writer, name, blobKey string) (err error) {
+	r, err := d.blobStore.Get(ctx, blobKey)
+	if err != nil {
+		return
+	}
+	defer r.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "zip.Create(%q)", name)
+	}
+
+	if _, err = io.Copy(w, r); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "io.Copy(%q)", name)
+	}
+
+	return nil
+}
+
+func writeJSON(zw *zip.Writer, name string, data interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func truncate(value string, length int) string {
+	if len(value) > length {
+		return value[:length]
+	}
+	return value
+}
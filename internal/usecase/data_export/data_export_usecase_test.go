@@ -0,0 +1,247 @@
+package dataexportusecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	blobservice "date-apps-be/internal/service/blob"
+	"date-apps-be/internal/test"
+	dataexportusecase "date-apps-be/internal/usecase/data_export"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUsecase(t *testing.T) (dataexportusecase.DataExportUsecase, *test.MockComponent, blobservice.BlobStore) {
+	mc := test.InitMockComponent(t)
+	blobStore := blobservice.NewLocalBlobStore(t.TempDir())
+	testUsecase := dataexportusecase.NewDataExportUsecase(mc.Config, mc.DataExportRepository, mc.UserRepository,
+		mc.UserMatchRepository, mc.UserPremiumRepository, mc.LoginHistoryRepository, mc.UserPhotoRepository, mc.UserPreferenceRepository,
+		mc.UserLocationRepository, mc.MutualMatchRepository, mc.UserRewindRepository, blobStore)
+	return testUsecase, mc, blobStore
+}
+
+func TestRequestDataExport(t *testing.T) {
+	testUsecase, mc, _ := newTestUsecase(t)
+	ctx := context.Background()
+
+	pending := &model.DataExport{UID: "export-1", UserUID: "user-1", Status: constant.DataExportStatusPending}
+
+	var testCases = []struct {
+		caseName     string
+		userUID      string
+		expectations func(userUID string)
+		results      func(export *model.DataExport, err error)
+	}{
+		{
+			caseName: "RequestDataExport_Success",
+			userUID:  "user-2",
+			expectations: func(userUID string) {
+				mc.DataExportRepository.On("GetActiveDataExport", mock.Anything, userUID).Return(nil, nil).Once()
+				mc.DataExportRepository.On("CountDataExports", mock.Anything, userUID, constant.DataExportWindow).Return(0, nil).Once()
+				mc.DataExportRepository.On("CreateDataExport", mock.Anything, mock.Anything, mock.MatchedBy(func(e *model.DataExport) bool {
+					return e.UserUID == userUID && e.Status == constant.DataExportStatusPending
+				})).Return(nil).Once()
+			},
+			results: func(export *model.DataExport, err error) {
+				require.NoError(t, err)
+				assert.NotEmpty(t, export.UID)
+				assert.Equal(t, constant.DataExportStatusPending, export.Status)
+			},
+		},
+		{
+			caseName: "RequestDataExport_AlreadyPending",
+			userUID:  "user-1",
+			expectations: func(userUID string) {
+				mc.DataExportRepository.On("GetActiveDataExport", mock.Anything, userUID).Return(pending, nil).Once()
+			},
+			results: func(export *model.DataExport, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, pending, export)
+			},
+		},
+		{
+			caseName: "RequestDataExport_TooMany",
+			userUID:  "user-3",
+			expectations: func(userUID string) {
+				mc.DataExportRepository.On("GetActiveDataExport", mock.Anything, userUID).Return(nil, nil).Once()
+				mc.DataExportRepository.On("CountDataExports", mock.Anything, userUID, constant.DataExportWindow).Return(constant.MaxDataExportPerWindow, nil).Once()
+			},
+			results: func(export *model.DataExport, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.TooManyRequests))
+				assert.Nil(t, export)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.caseName, func(t *testing.T) {
+			tc.expectations(tc.userUID)
+			export, err := testUsecase.RequestDataExport(ctx, tc.userUID)
+			tc.results(export, err)
+		})
+	}
+}
+
+func TestProcessAndDownloadDataExport(t *testing.T) {
+	testUsecase, mc, blobStore := newTestUsecase(t)
+	ctx := context.Background()
+
+	email := "john@example.com"
+	export := &model.DataExport{UID: "export-1", UserUID: "user-1", Status: constant.DataExportStatusPending}
+	userMatches := []*model.UserMatch{
+		{UserUID: "user-1", MatchUID: "user-2", MatchType: constant.UserMatchTypeLike},
+		{UserUID: "user-3", MatchUID: "user-1", MatchType: constant.UserMatchTypePass},
+		{UserUID: "user-1", MatchUID: "user-4", MatchType: constant.UserMatchTypeSuperLike},
+	}
+	bio := "Coffee and hiking"
+	maxDistanceKM := 25
+	photo := &model.UserPhoto{UID: "photo-1", UserUID: "user-1", IsPrimary: true, ContentType: "image/jpeg",
+		BlobKey: "photos/user-1/photo-1.jpg", ThumbnailKey: "photos/user-1/photo-1_thumbnail.jpg"}
+	require.NoError(t, blobStore.Put(ctx, photo.BlobKey, strings.NewReader("original")))
+
+	var blobKey string
+	var size int64
+	var expiresAt datatype.Time
+	mc.DataExportRepository.On("GetPendingDataExports", mock.Anything, uint64(constant.DataExportBatchSize)).Return([]*model.DataExport{export}, nil).Once()
+	mc.DataExportRepository.On("ClaimDataExport", mock.Anything, "export-1").Return(true, nil).Once()
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "user-1").Return(&model.User{UID: "user-1", Name: "John", Email: &email, Bio: &bio}, nil).Once()
+	mc.UserPremiumRepository.On("GetUserPackages", mock.Anything, "user-1").Return([]*model.UserPackage{{UID: "package-1", UserUID: "user-1", Quota: 10}}, nil).Once()
+	mc.UserMatchRepository.On("GetUserMatchHistory", mock.Anything, "user-1", uint64(1), uint64(constant.DataExportPageSize)).Return(userMatches, nil).Once()
+	mc.LoginHistoryRepository.On("GetLoginHistories", mock.Anything, "user-1", uint64(1), uint64(constant.DataExportPageSize)).
+		Return([]*model.LoginHistory{{UID: "login-1", Identifier: email, IPAddress: "127.0.0.1", IsSuccess: true}}, nil).Once()
+	mc.UserPhotoRepository.On("GetUserPhotos", mock.Anything, "user-1").Return([]*model.UserPhoto{photo}, nil).Once()
+	mc.UserPreferenceRepository.On("GetUserPreference", mock.Anything, "user-1").
+		Return(&model.UserPreference{UserUID: "user-1", MaxDistanceKM: &maxDistanceKM}, nil).Once()
+	mc.UserLocationRepository.On("GetUserLocation", mock.Anything, "user-1").
+		Return(&model.UserLocation{UserUID: "user-1", Latitude: -6.2, Longitude: 106.8}, nil).Once()
+	mc.MutualMatchRepository.On("GetMutualMatchHistory", mock.Anything, "user-1", uint64(1), uint64(constant.DataExportPageSize)).
+		Return([]*model.MutualMatch{{UID: "mutual-1", UserUID: "user-0", MatchUID: "user-1"}}, nil).Once()
+	mc.UserRewindRepository.On("GetUserRewinds", mock.Anything, "user-1", uint64(1), uint64(constant.DataExportPageSize)).
+		Return([]*model.UserRewind{{UserUID: "user-1", MatchUID: "user-5", MatchType: constant.UserMatchTypePass}}, nil).Once()
+	mc.DataExportRepository.On("CompleteDataExport", mock.Anything, "export-1", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			blobKey = args.String(2)
+			size = args.Get(3).(int64)
+			expiresAt = args.Get(4).(datatype.Time)
+		}).Return(nil).Once()
+
+	processed, err := testUsecase.ProcessDataExports(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.WithinDuration(t, time.Now().Add(constant.DataExportExpiration), *expiresAt.Time(), time.Minute)
+
+	body, err := blobStore.Get(ctx, blobKey)
+	require.NoError(t, err)
+	archive, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, int64(len(archive)), size)
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	for _, file := range zr.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		files[file.Name], err = io.ReadAll(r)
+		require.NoError(t, err)
+	}
+	assert.Len(t, files, 10)
+	assert.Contains(t, string(files["profile.json"]), email)
+	assert.Contains(t, string(files["profile.json"]), bio)
+	assert.Contains(t, string(files["photos.json"]), "photos/photo-1.jpg")
+	assert.Equal(t, "original", string(files["photos/photo-1.jpg"]))
+	assert.Contains(t, string(files["preferences.json"]), `"max_distance_km": 25`)
+	assert.Contains(t, string(files["location.json"]), "106.8")
+	assert.Contains(t, string(files["mutual_matches.json"]), "user-0")
+	assert.Contains(t, string(files["rewinds.json"]), "user-5")
+	assert.Contains(t, string(files["premium.json"]), "package-1")
+	assert.Contains(t, string(files["login_history.json"]), "login-1")
+
+	var matches []map[string]string
+	require.NoError(t, json.Unmarshal(files["matches.json"], &matches))
+	require.Len(t, matches, 3)
+	assert.Equal(t, "outgoing", matches[0]["direction"])
+	assert.Equal(t, "incoming", matches[1]["direction"])
+	assert.Equal(t, constant.UserMatchTypeSuperLike.String(), matches[2]["match_type"])
+
+	completed := &model.DataExport{
+		UID:       "export-1",
+		UserUID:   "user-1",
+		Status:    constant.DataExportStatusCompleted,
+		BlobKey:   &blobKey,
+		Size:      size,
+		ExpiresAt: expiresAt,
+	}
+	mc.DataExportRepository.On("GetDataExport", mock.Anything, "export-1").Return(completed, nil)
+
+	_, err = testUsecase.GetDataExport(ctx, "user-2", "export-1")
+	assert.True(t, derrors.IsErrCode(err, derrors.NotFound), "exports of other users are not found")
+
+	status, err := testUsecase.GetDataExport(ctx, "user-1", "export-1")
+	require.NoError(t, err)
+	require.NotEmpty(t, status.DownloadURL)
+
+	link, err := url.Parse(status.DownloadURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/exports/download", link.Path)
+
+	_, _, err = testUsecase.OpenDataExport(ctx, link.Query().Get("token")+"x")
+	assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+
+	_, download, err := testUsecase.OpenDataExport(ctx, link.Query().Get("token"))
+	require.NoError(t, err)
+	downloaded, err := io.ReadAll(download)
+	require.NoError(t, err)
+	require.NoError(t, download.Close())
+	assert.Equal(t, archive, downloaded)
+}
+
+func TestProcessDataExports_Failed(t *testing.T) {
+	testUsecase, mc, _ := newTestUsecase(t)
+	ctx := context.Background()
+
+	export := &model.DataExport{UID: "export-2", UserUID: "user-9", Status: constant.DataExportStatusPending}
+	mc.DataExportRepository.On("GetPendingDataExports", mock.Anything, mock.Anything).Return([]*model.DataExport{export}, nil).Once()
+	mc.DataExportRepository.On("ClaimDataExport", mock.Anything, "export-2").Return(true, nil).Once()
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "user-9").Return(nil, nil).Once()
+	mc.DataExportRepository.On("FailDataExport", mock.Anything, "export-2", mock.MatchedBy(func(reason string) bool {
+		return strings.Contains(reason, "User not found")
+	})).Return(nil).Once()
+
+	processed, err := testUsecase.ProcessDataExports(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+}
+
+func TestDeleteExpiredDataExports(t *testing.T) {
+	testUsecase, mc, blobStore := newTestUsecase(t)
+	ctx := context.Background()
+
+	blobKey := "exports/user-1/export-1.zip"
+	require.NoError(t, blobStore.Put(ctx, blobKey, strings.NewReader("archive")))
+
+	mc.DataExportRepository.On("GetExpiredDataExports", mock.Anything, uint64(constant.DataExportBatchSize)).
+		Return([]*model.DataExport{{UID: "export-1", UserUID: "user-1", BlobKey: &blobKey}}, nil).Once()
+	mc.DataExportRepository.On("ExpireDataExport", mock.Anything, "export-1").Return(nil).Once()
+
+	deleted, err := testUsecase.DeleteExpiredDataExports(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	_, err = blobStore.Get(ctx, blobKey)
+	assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+}
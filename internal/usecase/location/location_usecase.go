@@ -0,0 +1,69 @@
+package locationusecase
+
+import (
+	"context"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	userlocationrepo "date-apps-be/internal/repository/user_location"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/geo"
+	"math"
+	"time"
+)
+
+type (
+	LocationUsecase interface {
+		UpdateLocation(ctx context.Context, userUID string, latitude, longitude float64) (location *model.UserLocation, err error)
+	}
+
+	locationUsecase struct {
+		userLocationRepo userlocationrepo.UserLocationRepository
+	}
+)
+
+func NewLocationUsecase(userLocationRepo userlocationrepo.UserLocationRepository) LocationUsecase {
+	return &locationUsecase{
+		userLocationRepo: userLocationRepo,
+	}
+}
+
+// UpdateLocation replaces the location used to find users nearby. The location is snapped to a grid
+// and can only move to another cell once per LocationUpdateInterval.
+func (l *locationUsecase) UpdateLocation(ctx context.Context, userUID string, latitude, longitude float64) (location *model.UserLocation, err error) {
+	defer derrors.Wrap(&err, "UpdateLocation(%q)", userUID)
+
+	if math.IsNaN(latitude) || math.IsNaN(longitude) || !geo.IsValid(latitude, longitude) {
+		return nil, derrors.New(derrors.InvalidArgument, "Latitude must be between -90 and 90 and longitude between -180 and 180")
+	}
+
+	latitude, longitude = geo.Snap(latitude, longitude, constant.LocationGridKM)
+	location = &model.UserLocation{
+		UserUID:   userUID,
+		Latitude:  latitude,
+		Longitude: longitude,
+		Geohash:   geo.Encode(latitude, longitude, constant.LocationGeohashPrecision),
+		UpdatedAt: datatype.NewTimeNow(),
+	}
+
+	current, err := l.userLocationRepo.GetUserLocation(ctx, userUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if current != nil {
+		if current.Geohash == location.Geohash {
+			return current, nil
+		}
+
+		if !current.UpdatedAt.IsNil() && current.UpdatedAt.Time().Add(constant.LocationUpdateInterval).After(time.Now()) {
+			return nil, derrors.New(derrors.TooManyRequests, "Location was updated recently, please try again later")
+		}
+	}
+
+	if err = l.userLocationRepo.UpsertUserLocation(ctx, location); err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
@@ -0,0 +1,71 @@
+package locationusecase_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"date-apps-be/internal/constant"
+
+	"date-apps-be/internal/model"
+	"date-apps-be/internal/test"
+	locationusecase "date-apps-be/internal/usecase/location"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/geo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateLocation(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := locationusecase.NewLocationUsecase(mc.UserLocationRepository)
+
+	latitude, longitude := geo.Snap(-6.2088, 106.8456, constant.LocationGridKM)
+	geohash := geo.Encode(latitude, longitude, constant.LocationGeohashPrecision)
+
+	mc.UserLocationRepository.On("GetUserLocation", mock.Anything, "user_1").Return(nil, nil).Once()
+	mc.UserLocationRepository.On("UpsertUserLocation", mock.Anything, mock.MatchedBy(func(l *model.UserLocation) bool {
+		return l.UserUID == "user_1" && l.Latitude == latitude && l.Longitude == longitude && l.Geohash == geohash
+	})).Return(nil).Once()
+
+	location, err := testUsecase.UpdateLocation(ctx, "user_1", -6.2088, 106.8456)
+	assert.NoError(t, err)
+	assert.NotEqual(t, -6.2088, location.Latitude)
+	assert.False(t, location.UpdatedAt.IsNil())
+
+	// moving within the same cell keeps the stored location
+	recent := datatype.NewTimeNow()
+	stored := &model.UserLocation{UserUID: "user_3", Latitude: latitude, Longitude: longitude, Geohash: geohash, UpdatedAt: recent}
+	mc.UserLocationRepository.On("GetUserLocation", mock.Anything, "user_3").Return(stored, nil).Once()
+
+	location, err = testUsecase.UpdateLocation(ctx, "user_3", -6.2089, 106.8457)
+	assert.NoError(t, err)
+	assert.Equal(t, stored, location)
+
+	// moving to another cell right after an update is rejected
+	mc.UserLocationRepository.On("GetUserLocation", mock.Anything, "user_3").Return(stored, nil).Once()
+
+	_, err = testUsecase.UpdateLocation(ctx, "user_3", -6.3, 106.8456)
+	assert.True(t, derrors.IsErrCode(err, derrors.TooManyRequests))
+
+	// moving to another cell after the interval is stored
+	old := time.Now().Add(-constant.LocationUpdateInterval - time.Minute)
+	mc.UserLocationRepository.On("GetUserLocation", mock.Anything, "user_4").Return(&model.UserLocation{
+		UserUID: "user_4", Latitude: latitude, Longitude: longitude, Geohash: geohash, UpdatedAt: datatype.NewTime(&old),
+	}, nil).Once()
+	mc.UserLocationRepository.On("UpsertUserLocation", mock.Anything, mock.MatchedBy(func(l *model.UserLocation) bool {
+		return l.UserUID == "user_4" && l.Geohash != geohash
+	})).Return(nil).Once()
+
+	_, err = testUsecase.UpdateLocation(ctx, "user_4", -6.3, 106.8456)
+	assert.NoError(t, err)
+
+	for _, coordinates := range [][2]float64{{91, 0}, {0, -180.5}, {math.NaN(), 0}} {
+		_, err = testUsecase.UpdateLocation(ctx, "user_2", coordinates[0], coordinates[1])
+		assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument), coordinates)
+	}
+}
@@ -0,0 +1,117 @@
+package loginattemptusecase
+
+import (
+	"context"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	loginhistoryrepo "date-apps-be/internal/repository/login_history"
+	userrepo "date-apps-be/internal/repository/user"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+type (
+	// LoginAttemptUsecase writes login attempts to the login history and locks accounts after
+	// too many failures. Every check of a credential counts, not only the login itself,
+	// so a stolen access token can not be used to guess the password or the second factor.
+	LoginAttemptUsecase interface {
+		CheckIPAddress(ctx context.Context, ipAddress string) (err error)
+		CheckLocked(ctx context.Context, attempt model.LoginAttempt, user *model.User) (err error)
+		RecordLogin(ctx context.Context, attempt model.LoginAttempt, user *model.User, failureReason string) (err error)
+		RecordFailure(ctx context.Context, attempt model.LoginAttempt, user *model.User, failureReason string) (err error)
+	}
+
+	loginAttemptUsecase struct {
+		loginHistoryRepo loginhistoryrepo.LoginHistoryRepository
+		userRepo         userrepo.UserRepository
+	}
+)
+
+func NewLoginAttemptUsecase(loginHistoryRepo loginhistoryrepo.LoginHistoryRepository, userRepo userrepo.UserRepository) LoginAttemptUsecase {
+	return &loginAttemptUsecase{
+		loginHistoryRepo: loginHistoryRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// CheckIPAddress rejects the attempt when the IP address failed too often recently.
+func (l *loginAttemptUsecase) CheckIPAddress(ctx context.Context, ipAddress string) (err error) {
+	failedByIP, err := l.loginHistoryRepo.CountFailedLoginByIPAddress(ctx, ipAddress, constant.FailedLoginWindow)
+	if err != nil {
+		return
+	}
+
+	if failedByIP >= constant.MaxFailedLoginPerIP {
+		return derrors.New(derrors.TooManyRequests, "Too many failed login attempts, please try again later")
+	}
+
+	return nil
+}
+
+// CheckLocked records a failed attempt and rejects it when the account is locked.
+func (l *loginAttemptUsecase) CheckLocked(ctx context.Context, attempt model.LoginAttempt, user *model.User) (err error) {
+	if !user.IsLocked() {
+		return nil
+	}
+
+	if err = l.RecordLogin(ctx, attempt, user, constant.LoginFailureAccountLocked); err != nil {
+		return
+	}
+
+	return derrors.New(derrors.Locked, "Account is temporarily locked because of too many failed login attempts")
+}
+
+// RecordLogin writes a login attempt to the login history,
+// an empty failureReason means the attempt succeeded.
+func (l *loginAttemptUsecase) RecordLogin(ctx context.Context, attempt model.LoginAttempt, user *model.User, failureReason string) error {
+	userAgent := attempt.Device.UserAgent
+	if len(userAgent) > constant.MaxUserAgentLength {
+		userAgent = userAgent[:constant.MaxUserAgentLength]
+	}
+
+	loginHistory := &model.LoginHistory{
+		UID:        ksuid.New().String(),
+		Identifier: attempt.Identifier,
+		IPAddress:  attempt.Device.IPAddress,
+		UserAgent:  &userAgent,
+		IsSuccess:  failureReason == "",
+	}
+
+	if user != nil {
+		loginHistory.UserUID = &user.UID
+	}
+
+	if failureReason != "" {
+		loginHistory.FailureReason = &failureReason
+	}
+
+	return l.loginHistoryRepo.CreateLoginHistory(ctx, nil, loginHistory)
+}
+
+// RecordFailure records a failed attempt on the account of user and locks the account after
+// too many consecutive failures. The Locked error is returned when this failure locked the account,
+// otherwise nil so the caller can reject the attempt with its own error.
+func (l *loginAttemptUsecase) RecordFailure(ctx context.Context, attempt model.LoginAttempt, user *model.User, failureReason string) (err error) {
+	if err = l.RecordLogin(ctx, attempt, user, failureReason); err != nil {
+		return
+	}
+
+	failed, err := l.loginHistoryRepo.CountFailedLoginByUserUID(ctx, user.UID, constant.FailedLoginWindow)
+	if err != nil {
+		return
+	}
+
+	if failed < constant.MaxFailedLoginPerAccount {
+		return nil
+	}
+
+	lockedUntil := time.Now().Add(constant.AccountLockDuration)
+	if err = l.userRepo.UpdateLockedUntil(ctx, nil, user.UID, datatype.NewTime(&lockedUntil)); err != nil {
+		return
+	}
+
+	return derrors.New(derrors.Locked, "Account is temporarily locked because of too many failed login attempts")
+}
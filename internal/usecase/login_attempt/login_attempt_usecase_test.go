@@ -0,0 +1,65 @@
+package loginattemptusecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	"date-apps-be/internal/test"
+	loginattemptusecase "date-apps-be/internal/usecase/login_attempt"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckIPAddress(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository)
+
+	mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.0.1", constant.FailedLoginWindow).Return(constant.MaxFailedLoginPerIP-1, nil).Once()
+	mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.0.2", constant.FailedLoginWindow).Return(constant.MaxFailedLoginPerIP, nil).Once()
+
+	assert.NoError(t, testUsecase.CheckIPAddress(ctx, "10.0.0.1"))
+	assert.True(t, derrors.IsErrCode(testUsecase.CheckIPAddress(ctx, "10.0.0.2"), derrors.TooManyRequests))
+}
+
+func TestCheckLocked(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository)
+	attempt := model.LoginAttempt{Identifier: "john@example.com", Device: model.Device{IPAddress: "127.0.0.1"}}
+
+	assert.NoError(t, testUsecase.CheckLocked(ctx, attempt, &model.User{UID: "user_1"}))
+
+	lockedUntil := time.Now().Add(time.Minute)
+	mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(h *model.LoginHistory) bool {
+		return *h.UserUID == "user_2" && *h.FailureReason == constant.LoginFailureAccountLocked && !h.IsSuccess
+	})).Return(nil).Once()
+
+	err := testUsecase.CheckLocked(ctx, attempt, &model.User{UID: "user_2", LockedUntil: datatype.NewTime(&lockedUntil)})
+	assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+}
+
+func TestRecordFailure(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository)
+	attempt := model.LoginAttempt{Identifier: "john@example.com", Device: model.Device{IPAddress: "127.0.0.1"}}
+
+	mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(h *model.LoginHistory) bool {
+		return *h.FailureReason == constant.LoginFailureInvalidPassword && h.IPAddress == "127.0.0.1"
+	})).Return(nil).Twice()
+	mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user_1", constant.FailedLoginWindow).Return(constant.MaxFailedLoginPerAccount-1, nil).Once()
+	mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user_2", constant.FailedLoginWindow).Return(constant.MaxFailedLoginPerAccount, nil).Once()
+	mc.UserRepository.On("UpdateLockedUntil", mock.Anything, mock.Anything, "user_2", mock.Anything).Return(nil).Once()
+
+	assert.NoError(t, testUsecase.RecordFailure(ctx, attempt, &model.User{UID: "user_1"}, constant.LoginFailureInvalidPassword))
+
+	err := testUsecase.RecordFailure(ctx, attempt, &model.User{UID: "user_2"}, constant.LoginFailureInvalidPassword)
+	assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+}
@@ -0,0 +1,8 @@
+package dto
+
+// Enrollment is shown once to the user when two-factor authentication is set up.
+type Enrollment struct {
+	Secret        string   `json:"secret"`
+	OTPAuthURI    string   `json:"otpauth_uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
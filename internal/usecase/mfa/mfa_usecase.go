@@ -0,0 +1,355 @@
+package mfausecase
+
+import (
+	"context"
+	"crypto/rand"
+	"date-apps-be/infrastructure/config"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	userrepo "date-apps-be/internal/repository/user"
+	usermfarepo "date-apps-be/internal/repository/user_mfa"
+	authservice "date-apps-be/internal/service/auth"
+	loginattemptusecase "date-apps-be/internal/usecase/login_attempt"
+	"date-apps-be/internal/usecase/mfa/dto"
+	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/totp"
+	"date-apps-be/pkg/util"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// recoveryCodeAlphabet leaves out characters that are easily confused when typed, like 0/o and 1/l.
+const recoveryCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+type (
+	MFAUsecase interface {
+		Enroll(ctx context.Context, userUID string) (enrollment *dto.Enrollment, err error)
+		Confirm(ctx context.Context, userUID, code string, device model.Device) (err error)
+		Disable(ctx context.Context, userUID, code string, device model.Device) (err error)
+		ValidateCode(ctx context.Context, userUID, code string) (valid bool, err error)
+		IssueLoginToken(ctx context.Context, userUID string, device model.Device) (token *model.AuthToken, err error)
+	}
+
+	mfaUsecase struct {
+		conf         *config.Config
+		userMFARepo  usermfarepo.UserMFARepository
+		userRepo     userrepo.UserRepository
+		authService  authservice.AuthService
+		loginAttempt loginattemptusecase.LoginAttemptUsecase
+	}
+)
+
+func NewMFAUsecase(conf *config.Config, userMFARepo usermfarepo.UserMFARepository, userRepo userrepo.UserRepository, authService authservice.AuthService, loginAttempt loginattemptusecase.LoginAttemptUsecase) MFAUsecase {
+	return &mfaUsecase{
+		conf:         conf,
+		userMFARepo:  userMFARepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		loginAttempt: loginAttempt,
+	}
+}
+
+// HashRecoveryCode normalizes a recovery code as typed by the user and hashes it.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return util.HashToken(code)
+}
+
+// Enroll generates a new TOTP secret and recovery codes for the user. The secret is
+// only used for login after it is confirmed with a code from the authenticator app.
+func (m *mfaUsecase) Enroll(ctx context.Context, userUID string) (enrollment *dto.Enrollment, err error) {
+	defer derrors.Wrap(&err, "Enroll(%q)", userUID)
+
+	user, err := m.userRepo.GetUserByUID(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		return nil, derrors.New(derrors.NotFound, "User not found")
+	}
+
+	userMFA, err := m.userMFARepo.GetUserMFA(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if userMFA != nil && userMFA.IsEnabled() {
+		return nil, derrors.New(derrors.InvalidArgument, "Two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret(constant.MFASecretBytes)
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "totp.GenerateSecret")
+	}
+
+	encryptedSecret, err := util.Encrypt(m.conf.MFAEncryptionKey, []byte(secret))
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "util.Encrypt")
+	}
+
+	recoveryCodes, codeHashes, err := generateRecoveryCodes(constant.MFARecoveryCodeCount)
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "generateRecoveryCodes")
+	}
+
+	err = m.saveEnrollment(ctx, &model.UserMFA{UserUID: userUID, Secret: encryptedSecret}, codeHashes)
+	if err != nil {
+		return
+	}
+
+	return &dto.Enrollment{
+		Secret:        secret,
+		OTPAuthURI:    totp.URI(constant.MFAIssuer, accountName(user), secret, totp.Options{}),
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (m *mfaUsecase) saveEnrollment(ctx context.Context, userMFA *model.UserMFA, codeHashes []string) (err error) {
+	tx, err := m.userMFARepo.Begin()
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = m.userMFARepo.Rollback(tx)
+		}
+	}()
+
+	if err = m.userMFARepo.UpsertUserMFA(ctx, tx, userMFA); err != nil {
+		return
+	}
+
+	if err = m.userMFARepo.DeleteRecoveryCodes(ctx, tx, userMFA.UserUID); err != nil {
+		return
+	}
+
+	if err = m.userMFARepo.CreateRecoveryCodes(ctx, tx, userMFA.UserUID, codeHashes); err != nil {
+		return
+	}
+
+	if err = m.userMFARepo.Commit(tx); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return nil
+}
+
+// Confirm enables two-factor authentication once the user proves the authenticator app works.
+// Wrong codes count as failed logins of the account.
+func (m *mfaUsecase) Confirm(ctx context.Context, userUID, code string, device model.Device) (err error) {
+	defer derrors.Wrap(&err, "Confirm(%q)", userUID)
+
+	user, err := m.getUnlockedUser(ctx, userUID, device)
+	if err != nil {
+		return
+	}
+
+	userMFA, err := m.userMFARepo.GetUserMFA(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if userMFA == nil {
+		return derrors.New(derrors.NotFound, "Two-factor authentication is not enrolled")
+	}
+
+	if userMFA.IsEnabled() {
+		return derrors.New(derrors.InvalidArgument, "Two-factor authentication is already enabled")
+	}
+
+	step, ok, err := m.validateTOTP(userMFA, code)
+	if err != nil {
+		return
+	}
+
+	if !ok {
+		return m.rejectCode(ctx, user, device)
+	}
+
+	return m.userMFARepo.EnableUserMFA(ctx, userUID, step)
+}
+
+// Disable removes the secret and the recovery codes, a valid code is required
+// so a stolen access token alone can not turn off the second factor.
+// Wrong codes count as failed logins of the account.
+func (m *mfaUsecase) Disable(ctx context.Context, userUID, code string, device model.Device) (err error) {
+	defer derrors.Wrap(&err, "Disable(%q)", userUID)
+
+	user, err := m.getUnlockedUser(ctx, userUID, device)
+	if err != nil {
+		return
+	}
+
+	valid, err := m.ValidateCode(ctx, userUID, code)
+	if err != nil {
+		return
+	}
+
+	if !valid {
+		return m.rejectCode(ctx, user, device)
+	}
+
+	tx, err := m.userMFARepo.Begin()
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = m.userMFARepo.Rollback(tx)
+		}
+	}()
+
+	if err = m.userMFARepo.DeleteRecoveryCodes(ctx, tx, userUID); err != nil {
+		return
+	}
+
+	if err = m.userMFARepo.DeleteUserMFA(ctx, tx, userUID); err != nil {
+		return
+	}
+
+	if err = m.userMFARepo.Commit(tx); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return nil
+}
+
+// getUnlockedUser rejects users whose account is locked by too many failed attempts.
+func (m *mfaUsecase) getUnlockedUser(ctx context.Context, userUID string, device model.Device) (user *model.User, err error) {
+	user, err = m.userRepo.GetUserByUID(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		return nil, derrors.New(derrors.NotFound, "User not found")
+	}
+
+	if err = m.loginAttempt.CheckLocked(ctx, model.NewLoginAttempt(user, device), user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// rejectCode records a wrong code, the account is locked after too many of them.
+func (m *mfaUsecase) rejectCode(ctx context.Context, user *model.User, device model.Device) (err error) {
+	if err = m.loginAttempt.RecordFailure(ctx, model.NewLoginAttempt(user, device), user, constant.LoginFailureInvalidMFACode); err != nil {
+		return
+	}
+
+	return derrors.New(derrors.Unauthorized, "Invalid code")
+}
+
+// ValidateCode checks a TOTP code or an unused recovery code of a user with two-factor
+// authentication enabled. Each TOTP step and each recovery code is accepted only once.
+func (m *mfaUsecase) ValidateCode(ctx context.Context, userUID, code string) (valid bool, err error) {
+	defer derrors.Wrap(&err, "ValidateCode(%q)", userUID)
+
+	userMFA, err := m.userMFARepo.GetUserMFA(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if userMFA == nil || !userMFA.IsEnabled() {
+		return false, nil
+	}
+
+	if !isTOTPCode(code) {
+		return m.userMFARepo.UseRecoveryCode(ctx, userUID, HashRecoveryCode(code))
+	}
+
+	step, ok, err := m.validateTOTP(userMFA, code)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	// replayed codes lose the race on last_used_step
+	return m.userMFARepo.UpdateLastUsedStep(ctx, userUID, step)
+}
+
+// IssueLoginToken finishes a login with the first factor. Users with two-factor authentication
+// enabled get a short lived MFA token instead of the token pair, the session on device
+// is only started once the second factor is verified.
+func (m *mfaUsecase) IssueLoginToken(ctx context.Context, userUID string, device model.Device) (token *model.AuthToken, err error) {
+	defer derrors.Wrap(&err, "IssueLoginToken(%q)", userUID)
+
+	userMFA, err := m.userMFARepo.GetUserMFA(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if userMFA == nil || !userMFA.IsEnabled() {
+		return m.authService.IssueToken(ctx, userUID, device)
+	}
+
+	mfaToken, err := m.authService.IssueMFAToken(userUID)
+	if err != nil {
+		return
+	}
+
+	return &model.AuthToken{
+		MFARequired: true,
+		MFAToken:    mfaToken,
+	}, nil
+}
+
+func (m *mfaUsecase) validateTOTP(userMFA *model.UserMFA, code string) (step int64, ok bool, err error) {
+	secret, err := util.Decrypt(m.conf.MFAEncryptionKey, userMFA.Secret)
+	if err != nil {
+		return 0, false, derrors.WrapStack(err, derrors.Unknown, "util.Decrypt")
+	}
+
+	key, err := totp.DecodeSecret(string(secret))
+	if err != nil {
+		return 0, false, derrors.WrapStack(err, derrors.Unknown, "totp.DecodeSecret")
+	}
+
+	step, ok = totp.Validate(key, code, time.Now(), constant.MFAValidationSkew, totp.Options{})
+	return step, ok, nil
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func accountName(user *model.User) string {
+	if user.Email != nil && *user.Email != "" {
+		return *user.Email
+	}
+	if user.PhoneNumber != nil {
+		return *user.PhoneNumber
+	}
+	return user.UID
+}
+
+// generateRecoveryCodes returns n codes formatted as xxxx-xxxx and their hashes.
+func generateRecoveryCodes(n int) (codes []string, hashes []string, err error) {
+	for i := 0; i < n; i++ {
+		b := make([]byte, 8)
+		for j := range b {
+			c, err := rand.Int(rand.Reader, big.NewInt(int64(len(recoveryCodeAlphabet))))
+			if err != nil {
+				return nil, nil, err
+			}
+			b[j] = recoveryCodeAlphabet[c.Int64()]
+		}
+
+		code := string(b[:4]) + "-" + string(b[4:])
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
@@ -0,0 +1,278 @@
+package mfausecase_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	"date-apps-be/internal/test"
+	loginattemptusecase "date-apps-be/internal/usecase/login_attempt"
+	mfausecase "date-apps-be/internal/usecase/mfa"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/totp"
+	"date-apps-be/pkg/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrollAndConfirm(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := mfausecase.NewMFAUsecase(mc.Config, mc.UserMFARepository, mc.UserRepository, mc.AuthService, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository))
+
+	email := "john@example.com"
+	var stored *model.UserMFA
+	var codeHashes []string
+
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "user_1").Return(&model.User{UID: "user_1", Email: &email}, nil)
+	mc.UserMFARepository.On("GetUserMFA", mock.Anything, "user_1").Return(nil, nil).Once()
+	mc.UserMFARepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+	mc.UserMFARepository.On("UpsertUserMFA", mock.Anything, mock.Anything, mock.MatchedBy(func(m *model.UserMFA) bool {
+		stored = m
+		return m.UserUID == "user_1"
+	})).Return(nil).Once()
+	mc.UserMFARepository.On("DeleteRecoveryCodes", mock.Anything, mock.Anything, "user_1").Return(nil).Once()
+	mc.UserMFARepository.On("CreateRecoveryCodes", mock.Anything, mock.Anything, "user_1", mock.MatchedBy(func(hashes []string) bool {
+		codeHashes = hashes
+		return true
+	})).Return(nil).Once()
+	mc.UserMFARepository.On("Commit", mock.Anything).Return(nil).Once()
+
+	enrollment, err := testUsecase.Enroll(ctx, "user_1")
+	require.NoError(t, err)
+	assert.NotEqual(t, enrollment.Secret, stored.Secret, "the secret is stored encrypted")
+	assert.True(t, strings.HasPrefix(enrollment.OTPAuthURI, "otpauth://totp/"))
+	assert.Len(t, enrollment.RecoveryCodes, 10)
+	assert.Equal(t, mfausecase.HashRecoveryCode(enrollment.RecoveryCodes[0]), codeHashes[0], "only the hash of a recovery code is stored")
+
+	key, err := totp.DecodeSecret(enrollment.Secret)
+	require.NoError(t, err)
+	now := time.Now()
+	code := totp.GenerateCode(key, now, totp.Options{})
+
+	device := model.Device{IPAddress: "127.0.0.1"}
+
+	mc.UserMFARepository.On("GetUserMFA", mock.Anything, "user_1").Return(stored, nil).Once()
+	mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(h *model.LoginHistory) bool {
+		return *h.UserUID == "user_1" && *h.FailureReason == constant.LoginFailureInvalidMFACode && h.Identifier == email
+	})).Return(nil).Once()
+	mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user_1", constant.FailedLoginWindow).Return(1, nil).Once()
+	err = testUsecase.Confirm(ctx, "user_1", "abcdef", device)
+	assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+
+	mc.UserMFARepository.On("GetUserMFA", mock.Anything, "user_1").Return(stored, nil).Once()
+	mc.UserMFARepository.On("EnableUserMFA", mock.Anything, "user_1", mock.AnythingOfType("int64")).Return(nil).Once()
+	err = testUsecase.Confirm(ctx, "user_1", code, device)
+	assert.NoError(t, err)
+}
+
+func TestDisable(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := mfausecase.NewMFAUsecase(mc.Config, mc.UserMFARepository, mc.UserRepository, mc.AuthService, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository))
+	device := model.Device{IPAddress: "127.0.0.1"}
+
+	secret, err := totp.GenerateSecret(20)
+	require.NoError(t, err)
+	encryptedSecret, err := util.Encrypt(mc.Config.MFAEncryptionKey, []byte(secret))
+	require.NoError(t, err)
+	key, err := totp.DecodeSecret(secret)
+	require.NoError(t, err)
+
+	email := "john@example.com"
+	lockedUntil := time.Now().Add(time.Minute)
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "user_1").Return(&model.User{UID: "user_1", Email: &email}, nil)
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "locked").Return(&model.User{UID: "locked", Email: &email, LockedUntil: datatype.NewTime(&lockedUntil)}, nil)
+	mc.UserMFARepository.On("GetUserMFA", mock.Anything, "user_1").Return(&model.UserMFA{UserUID: "user_1", Secret: encryptedSecret, EnabledAt: datatype.NewTimeNow()}, nil)
+
+	var testCases = []struct {
+		caseName     string
+		userUID      string
+		code         string
+		expectations func()
+		results      func(err error)
+	}{
+		{
+			caseName: "Disable_WrongCode",
+			userUID:  "user_1",
+			code:     "000000",
+			expectations: func() {
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user_1", constant.FailedLoginWindow).Return(constant.MaxFailedLoginPerAccount-1, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "Disable_WrongCodeLocks",
+			userUID:  "user_1",
+			code:     "wrong-code",
+			expectations: func() {
+				mc.UserMFARepository.On("UseRecoveryCode", mock.Anything, "user_1", mock.Anything).Return(false, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user_1", constant.FailedLoginWindow).Return(constant.MaxFailedLoginPerAccount, nil).Once()
+				mc.UserRepository.On("UpdateLockedUntil", mock.Anything, mock.Anything, "user_1", mock.Anything).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+			},
+		},
+		{
+			caseName: "Disable_Locked",
+			userUID:  "locked",
+			code:     totp.GenerateCode(key, time.Now(), totp.Options{}),
+			expectations: func() {
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(h *model.LoginHistory) bool {
+					return *h.FailureReason == constant.LoginFailureAccountLocked
+				})).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+				mc.UserMFARepository.AssertNotCalled(t, "GetUserMFA", mock.Anything, "locked")
+			},
+		},
+		{
+			caseName: "Disable_Success",
+			userUID:  "user_1",
+			code:     totp.GenerateCode(key, time.Now(), totp.Options{}),
+			expectations: func() {
+				mc.UserMFARepository.On("UpdateLastUsedStep", mock.Anything, "user_1", mock.Anything).Return(true, nil).Once()
+				mc.UserMFARepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMFARepository.On("DeleteRecoveryCodes", mock.Anything, mock.Anything, "user_1").Return(nil).Once()
+				mc.UserMFARepository.On("DeleteUserMFA", mock.Anything, mock.Anything, "user_1").Return(nil).Once()
+				mc.UserMFARepository.On("Commit", mock.Anything).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			err := testUsecase.Disable(ctx, testCase.userUID, testCase.code, device)
+			testCase.results(err)
+		})
+	}
+}
+
+func TestEnroll_AlreadyEnabled(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := mfausecase.NewMFAUsecase(mc.Config, mc.UserMFARepository, mc.UserRepository, mc.AuthService, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository))
+
+	mc.UserRepository.On("GetUserByUID", mock.Anything, "user_1").Return(&model.User{UID: "user_1"}, nil)
+	mc.UserMFARepository.On("GetUserMFA", mock.Anything, "user_1").Return(&model.UserMFA{UserUID: "user_1", EnabledAt: datatype.NewTimeNow()}, nil)
+
+	enrollment, err := testUsecase.Enroll(ctx, "user_1")
+	assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+	assert.Nil(t, enrollment)
+}
+
+func TestValidateCode(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := mfausecase.NewMFAUsecase(mc.Config, mc.UserMFARepository, mc.UserRepository, mc.AuthService, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository))
+
+	secret, err := totp.GenerateSecret(20)
+	require.NoError(t, err)
+	encryptedSecret, err := util.Encrypt(mc.Config.MFAEncryptionKey, []byte(secret))
+	require.NoError(t, err)
+	key, err := totp.DecodeSecret(secret)
+	require.NoError(t, err)
+
+	now := time.Now()
+	code := totp.GenerateCode(key, now, totp.Options{})
+	step := totp.Step(now, totp.Options{})
+
+	enabled := &model.UserMFA{UserUID: "user_1", Secret: encryptedSecret, EnabledAt: datatype.NewTimeNow()}
+	pending := &model.UserMFA{UserUID: "user_2", Secret: encryptedSecret}
+	mc.UserMFARepository.On("GetUserMFA", mock.Anything, "user_1").Return(enabled, nil)
+	mc.UserMFARepository.On("GetUserMFA", mock.Anything, "user_2").Return(pending, nil)
+
+	var testCases = []struct {
+		caseName     string
+		userUID      string
+		code         string
+		expectations func()
+		valid        bool
+	}{
+		{
+			caseName: "ValidateCode_TOTP",
+			userUID:  "user_1",
+			code:     code,
+			expectations: func() {
+				mc.UserMFARepository.On("UpdateLastUsedStep", mock.Anything, "user_1", mock.MatchedBy(func(s int64) bool {
+					return s >= step-1 && s <= step+1
+				})).Return(true, nil).Once()
+			},
+			valid: true,
+		},
+		{
+			caseName: "ValidateCode_TOTPReplayed",
+			userUID:  "user_1",
+			code:     code,
+			expectations: func() {
+				mc.UserMFARepository.On("UpdateLastUsedStep", mock.Anything, "user_1", mock.Anything).Return(false, nil).Once()
+			},
+			valid: false,
+		},
+		{
+			caseName: "ValidateCode_RecoveryCode",
+			userUID:  "user_1",
+			code:     "ABCD-efgh",
+			expectations: func() {
+				mc.UserMFARepository.On("UseRecoveryCode", mock.Anything, "user_1", mfausecase.HashRecoveryCode("abcdefgh")).Return(true, nil).Once()
+			},
+			valid: true,
+		},
+		{
+			caseName:     "ValidateCode_NotEnabled",
+			userUID:      "user_2",
+			code:         code,
+			expectations: func() {},
+			valid:        false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			valid, err := testUsecase.ValidateCode(ctx, testCase.userUID, testCase.code)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.valid, valid)
+		})
+	}
+}
+
+func TestIssueLoginToken(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := mfausecase.NewMFAUsecase(mc.Config, mc.UserMFARepository, mc.UserRepository, mc.AuthService, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository))
+	device := model.Device{ID: "device_1", IPAddress: "127.0.0.1"}
+
+	mc.UserMFARepository.On("GetUserMFA", mock.Anything, "user_1").Return(nil, nil)
+	mc.UserMFARepository.On("GetUserMFA", mock.Anything, "user_2").Return(&model.UserMFA{UserUID: "user_2", EnabledAt: datatype.NewTimeNow()}, nil)
+	mc.AuthService.On("IssueToken", mock.Anything, "user_1", device).Return(&model.AuthToken{Token: "access_token"}, nil)
+	mc.AuthService.On("IssueMFAToken", "user_2").Return("mfa_token", nil)
+
+	token, err := testUsecase.IssueLoginToken(ctx, "user_1", device)
+	assert.NoError(t, err)
+	assert.Equal(t, "access_token", token.Token)
+	assert.False(t, token.MFARequired)
+
+	token, err = testUsecase.IssueLoginToken(ctx, "user_2", device)
+	assert.NoError(t, err)
+	assert.True(t, token.MFARequired)
+	assert.Equal(t, "mfa_token", token.MFAToken)
+	assert.Empty(t, token.Token)
+}
@@ -0,0 +1,16 @@
+package dto
+
+import (
+	"date-apps-be/internal/model"
+	"time"
+)
+
+// ChangeUserStatus is a status change of UserUID made by a staff member.
+type ChangeUserStatus struct {
+	ActorUID       string
+	ActorRoles     model.Roles
+	UserUID        string
+	Status         string
+	Reason         string
+	SuspendedUntil time.Time
+}
@@ -0,0 +1,153 @@
+package moderationusecase
+
+import (
+	"context"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	userrepo "date-apps-be/internal/repository/user"
+	userrolerepo "date-apps-be/internal/repository/user_role"
+	userstatusauditrepo "date-apps-be/internal/repository/user_status_audit"
+	authservice "date-apps-be/internal/service/auth"
+	"date-apps-be/internal/usecase/moderation/dto"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"strings"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+type (
+	ModerationUsecase interface {
+		ChangeUserStatus(ctx context.Context, d dto.ChangeUserStatus) (user *model.User, err error)
+		GetUserStatusAudits(ctx context.Context, userUID string, page, limit uint64) (audits []*model.UserStatusAudit, err error)
+	}
+
+	moderationUsecase struct {
+		userRepo            userrepo.UserRepository
+		userRoleRepo        userrolerepo.UserRoleRepository
+		userStatusAuditRepo userstatusauditrepo.UserStatusAuditRepository
+		authService         authservice.AuthService
+	}
+)
+
+func NewModerationUsecase(userRepo userrepo.UserRepository, userRoleRepo userrolerepo.UserRoleRepository, userStatusAuditRepo userstatusauditrepo.UserStatusAuditRepository, authService authservice.AuthService) ModerationUsecase {
+	return &moderationUsecase{
+		userRepo:            userRepo,
+		userRoleRepo:        userRoleRepo,
+		userStatusAuditRepo: userStatusAuditRepo,
+		authService:         authService,
+	}
+}
+
+// ChangeUserStatus suspends, bans or reactivates a user and writes the change to the audit trail.
+// Suspended and banned users are signed out of every device. Only admins can moderate staff.
+func (m *moderationUsecase) ChangeUserStatus(ctx context.Context, d dto.ChangeUserStatus) (user *model.User, err error) {
+	defer derrors.Wrap(&err, "ChangeUserStatus(%q, %q)", d.UserUID, d.Status)
+
+	var reason *string
+	if r := strings.TrimSpace(d.Reason); r != "" {
+		reason = &r
+	}
+
+	suspendedUntil := datatype.Time{}
+	switch d.Status {
+	case constant.UserStatusActive:
+	case constant.UserStatusSuspended:
+		if !d.SuspendedUntil.After(time.Now()) {
+			return nil, derrors.New(derrors.InvalidArgument, "A suspension must end in the future")
+		}
+		suspendedUntil = datatype.NewTime(&d.SuspendedUntil)
+	case constant.UserStatusBanned:
+	default:
+		return nil, derrors.New(derrors.InvalidArgument, "Unknown status")
+	}
+
+	if d.Status != constant.UserStatusActive && reason == nil {
+		return nil, derrors.New(derrors.InvalidArgument, "A reason is required")
+	}
+
+	if reason != nil && len(*reason) > constant.MaxStatusReasonLength {
+		return nil, derrors.New(derrors.InvalidArgument, "The reason must be at most %d characters", constant.MaxStatusReasonLength)
+	}
+
+	if d.ActorUID == d.UserUID {
+		return nil, derrors.New(derrors.InvalidArgument, "You can not change your own status")
+	}
+
+	user, err = m.userRepo.GetUserByUID(ctx, d.UserUID)
+	if err != nil {
+		return
+	}
+
+	if user == nil || user.IsDeleted() {
+		return nil, derrors.New(derrors.NotFound, "User not found")
+	}
+
+	roles, err := m.userRoleRepo.GetUserRoles(ctx, d.UserUID)
+	if err != nil {
+		return
+	}
+
+	if len(roles) > 0 && !d.ActorRoles.Has(constant.RoleAdmin) {
+		return nil, derrors.New(derrors.Forbidden, "Only admins can change the status of staff")
+	}
+
+	if err = m.updateUserStatus(ctx, d.ActorUID, user, d.Status, reason, suspendedUntil); err != nil {
+		return
+	}
+
+	if d.Status != constant.UserStatusActive {
+		if err = m.authService.RevokeUserTokens(ctx, user.UID); err != nil {
+			return
+		}
+	}
+
+	user.Status = d.Status
+	user.StatusReason = reason
+	user.SuspendedUntil = suspendedUntil
+
+	return user, nil
+}
+
+func (m *moderationUsecase) updateUserStatus(ctx context.Context, actorUID string, user *model.User, status string, reason *string, suspendedUntil datatype.Time) (err error) {
+	tx, err := m.userRepo.Begin()
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = m.userRepo.Rollback(tx)
+		}
+	}()
+
+	if err = m.userRepo.UpdateUserStatus(ctx, tx, user.UID, status, reason, suspendedUntil); err != nil {
+		return
+	}
+
+	err = m.userStatusAuditRepo.CreateUserStatusAudit(ctx, tx, &model.UserStatusAudit{
+		UID:            ksuid.New().String(),
+		UserUID:        user.UID,
+		ActorUID:       actorUID,
+		PreviousStatus: user.Status,
+		Status:         status,
+		Reason:         reason,
+		SuspendedUntil: suspendedUntil,
+	})
+	if err != nil {
+		return
+	}
+
+	if err = m.userRepo.Commit(tx); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return nil
+}
+
+// GetUserStatusAudits returns the status changes of a user, newest first.
+func (m *moderationUsecase) GetUserStatusAudits(ctx context.Context, userUID string, page, limit uint64) (audits []*model.UserStatusAudit, err error) {
+	defer derrors.Wrap(&err, "GetUserStatusAudits(%q)", userUID)
+
+	return m.userStatusAuditRepo.GetUserStatusAudits(ctx, userUID, page, limit)
+}
@@ -0,0 +1,162 @@
+package moderationusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	"date-apps-be/internal/test"
+	moderationusecase "date-apps-be/internal/usecase/moderation"
+	"date-apps-be/internal/usecase/moderation/dto"
+	"date-apps-be/pkg/derrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestChangeUserStatus(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := moderationusecase.NewModerationUsecase(mc.UserRepository, mc.UserRoleRepository, mc.UserStatusAuditRepository, mc.AuthService)
+
+	suspendedUntil := time.Now().Add(24 * time.Hour)
+	moderator := model.Roles{constant.RoleModerator}
+
+	var testCases = []struct {
+		caseName     string
+		params       dto.ChangeUserStatus
+		expectations func()
+		results      func(user *model.User, err error)
+	}{
+		{
+			caseName: "ChangeUserStatus_Suspended",
+			params: dto.ChangeUserStatus{
+				ActorUID: "moderator_1", ActorRoles: moderator, UserUID: "user_1",
+				Status: constant.UserStatusSuspended, Reason: " spam ", SuspendedUntil: suspendedUntil,
+			},
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "user_1").Return(&model.User{UID: "user_1", Status: constant.UserStatusActive}, nil).Once()
+				mc.UserRoleRepository.On("GetUserRoles", mock.Anything, "user_1").Return([]string{}, nil).Once()
+				mc.UserRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserRepository.On("UpdateUserStatus", mock.Anything, mock.Anything, "user_1", constant.UserStatusSuspended, mock.MatchedBy(func(reason *string) bool {
+					return *reason == "spam"
+				}), mock.Anything).Return(nil).Once()
+				mc.UserStatusAuditRepository.On("CreateUserStatusAudit", mock.Anything, mock.Anything, mock.MatchedBy(func(a *model.UserStatusAudit) bool {
+					return a.UserUID == "user_1" && a.ActorUID == "moderator_1" && a.PreviousStatus == constant.UserStatusActive &&
+						a.Status == constant.UserStatusSuspended && a.SuspendedUntil.Time().Equal(suspendedUntil)
+				})).Return(nil).Once()
+				mc.UserRepository.On("Commit", mock.Anything).Return(nil).Once()
+				mc.AuthService.On("RevokeUserTokens", mock.Anything, "user_1").Return(nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, constant.UserStatusSuspended, user.Status)
+				assert.True(t, user.IsSuspended())
+			},
+		},
+		{
+			caseName: "ChangeUserStatus_Reactivated",
+			params: dto.ChangeUserStatus{
+				ActorUID: "moderator_1", ActorRoles: moderator, UserUID: "user_2", Status: constant.UserStatusActive,
+			},
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "user_2").Return(&model.User{UID: "user_2", Status: constant.UserStatusBanned}, nil).Once()
+				mc.UserRoleRepository.On("GetUserRoles", mock.Anything, "user_2").Return([]string{}, nil).Once()
+				mc.UserRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserRepository.On("UpdateUserStatus", mock.Anything, mock.Anything, "user_2", constant.UserStatusActive, (*string)(nil), mock.Anything).Return(nil).Once()
+				mc.UserStatusAuditRepository.On("CreateUserStatusAudit", mock.Anything, mock.Anything, mock.MatchedBy(func(a *model.UserStatusAudit) bool {
+					return a.UserUID == "user_2" && a.PreviousStatus == constant.UserStatusBanned && a.Status == constant.UserStatusActive
+				})).Return(nil).Once()
+				mc.UserRepository.On("Commit", mock.Anything).Return(nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.NoError(t, err)
+				assert.False(t, user.IsBanned())
+				mc.AuthService.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, "user_2")
+			},
+		},
+		{
+			caseName: "ChangeUserStatus_SuspensionInThePast",
+			params: dto.ChangeUserStatus{
+				ActorUID: "moderator_1", ActorRoles: moderator, UserUID: "user_1",
+				Status: constant.UserStatusSuspended, Reason: "spam", SuspendedUntil: time.Now().Add(-time.Hour),
+			},
+			expectations: func() {},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, user)
+			},
+		},
+		{
+			caseName: "ChangeUserStatus_ReasonRequired",
+			params: dto.ChangeUserStatus{
+				ActorUID: "moderator_1", ActorRoles: moderator, UserUID: "user_1", Status: constant.UserStatusBanned, Reason: " ",
+			},
+			expectations: func() {},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, user)
+			},
+		},
+		{
+			caseName: "ChangeUserStatus_Self",
+			params: dto.ChangeUserStatus{
+				ActorUID: "moderator_1", ActorRoles: moderator, UserUID: "moderator_1", Status: constant.UserStatusBanned, Reason: "spam",
+			},
+			expectations: func() {},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, user)
+			},
+		},
+		{
+			caseName: "ChangeUserStatus_ModeratorCanNotModerateStaff",
+			params: dto.ChangeUserStatus{
+				ActorUID: "moderator_1", ActorRoles: moderator, UserUID: "moderator_2", Status: constant.UserStatusBanned, Reason: "spam",
+			},
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "moderator_2").Return(&model.User{UID: "moderator_2", Status: constant.UserStatusActive}, nil).Once()
+				mc.UserRoleRepository.On("GetUserRoles", mock.Anything, "moderator_2").Return([]string{constant.RoleModerator}, nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				assert.Nil(t, user)
+			},
+		},
+		{
+			caseName: "ChangeUserStatus_UserNotFound",
+			params: dto.ChangeUserStatus{
+				ActorUID: "moderator_1", ActorRoles: moderator, UserUID: "user_3", Status: constant.UserStatusBanned, Reason: "spam",
+			},
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "user_3").Return(nil, nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+				assert.Nil(t, user)
+			},
+		},
+		{
+			caseName: "ChangeUserStatus_UnknownStatus",
+			params: dto.ChangeUserStatus{
+				ActorUID: "moderator_1", ActorRoles: moderator, UserUID: "user_1", Status: "frozen", Reason: "spam",
+			},
+			expectations: func() {},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, user)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			user, err := testUsecase.ChangeUserStatus(ctx, testCase.params)
+			testCase.results(user, err)
+		})
+	}
+}
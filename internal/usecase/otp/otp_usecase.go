@@ -0,0 +1,348 @@
+package otpusecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	otpcoderepo "date-apps-be/internal/repository/otp_code"
+	userrepo "date-apps-be/internal/repository/user"
+	authservice "date-apps-be/internal/service/auth"
+	smsservice "date-apps-be/internal/service/sms"
+	loginattemptusecase "date-apps-be/internal/usecase/login_attempt"
+	mfausecase "date-apps-be/internal/usecase/mfa"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"date-apps-be/pkg/util"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+type (
+	OTPUsecase interface {
+		RequestLoginOTP(ctx context.Context, phoneNumber string) (err error)
+		VerifyLoginOTP(ctx context.Context, phoneNumber, code string, device model.Device) (token *model.AuthToken, err error)
+		RequestPhoneVerification(ctx context.Context, userUID, phoneNumber string) (err error)
+		VerifyPhoneNumber(ctx context.Context, userUID, phoneNumber, code string) (err error)
+	}
+
+	otpUsecase struct {
+		otpCodeRepo  otpcoderepo.OTPCodeRepository
+		userRepo     userrepo.UserRepository
+		mfaUsecase   mfausecase.MFAUsecase
+		smsSender    smsservice.SMSSender
+		loginAttempt loginattemptusecase.LoginAttemptUsecase
+	}
+)
+
+func NewOTPUsecase(otpCodeRepo otpcoderepo.OTPCodeRepository, userRepo userrepo.UserRepository, mfaUsecase mfausecase.MFAUsecase, smsSender smsservice.SMSSender, loginAttempt loginattemptusecase.LoginAttemptUsecase) OTPUsecase {
+	return &otpUsecase{
+		otpCodeRepo:  otpCodeRepo,
+		userRepo:     userRepo,
+		mfaUsecase:   mfaUsecase,
+		smsSender:    smsSender,
+		loginAttempt: loginAttempt,
+	}
+}
+
+// HashOTPCode hashes the code together with the uid of its record,
+// so equal codes never produce the same hash.
+func HashOTPCode(uid, code string) string {
+	return util.HashToken(uid + ":" + code)
+}
+
+// RequestLoginOTP sends a login code to the phone number. Unknown phone numbers
+// are answered the same way, so the endpoint can not be used to find registered users.
+// A code is stored for them too without being sent, so they hit the same request limits.
+func (o *otpUsecase) RequestLoginOTP(ctx context.Context, phoneNumber string) (err error) {
+	defer derrors.Wrap(&err, "RequestLoginOTP(%q)", phoneNumber)
+
+	if err = o.checkRequestLimit(ctx, phoneNumber, constant.OTPPurposeLogin); err != nil {
+		return
+	}
+
+	user, err := o.userRepo.GetUserByEmailOrPhoneNumber(ctx, "", phoneNumber)
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		_, err = o.createCode(ctx, constant.OTPPurposeLogin, nil, phoneNumber)
+		return
+	}
+
+	return o.sendCode(ctx, constant.OTPPurposeLogin, nil, phoneNumber, "Your Date Apps login code is %s. It expires in %d minutes, do not share it with anyone.")
+}
+
+// VerifyLoginOTP checks the last code sent to the phone number and issues tokens when it matches,
+// users with two-factor authentication enabled still have to enter their TOTP code.
+// A code can only be used once and is invalidated after too many wrong attempts,
+// wrong codes also count as failed logins of the account.
+func (o *otpUsecase) VerifyLoginOTP(ctx context.Context, phoneNumber, code string, device model.Device) (token *model.AuthToken, err error) {
+	defer derrors.Wrap(&err, "VerifyLoginOTP(%q)", phoneNumber)
+
+	if err = o.loginAttempt.CheckIPAddress(ctx, device.IPAddress); err != nil {
+		return
+	}
+
+	user, err := o.userRepo.GetUserByEmailOrPhoneNumber(ctx, "", phoneNumber)
+	if err != nil {
+		return
+	}
+
+	attempt := model.LoginAttempt{Identifier: phoneNumber, Device: device}
+
+	if user != nil {
+		if err = o.loginAttempt.CheckLocked(ctx, attempt, user); err != nil {
+			return
+		}
+	}
+
+	if err = o.verifyCode(ctx, constant.OTPPurposeLogin, nil, phoneNumber, code); err != nil {
+		if !derrors.IsErrCode(err, derrors.Unauthorized) {
+			return
+		}
+
+		if recordErr := o.recordFailure(ctx, attempt, user); recordErr != nil {
+			return nil, recordErr
+		}
+
+		return
+	}
+
+	if user == nil {
+		if err = o.recordFailure(ctx, attempt, nil); err != nil {
+			return
+		}
+
+		return nil, derrors.New(derrors.Unauthorized, "Invalid or expired code")
+	}
+
+	// only revealed once the code is verified
+	if statusErr := authservice.UserStatusError(user); statusErr != nil {
+		if err = o.loginAttempt.RecordLogin(ctx, attempt, user, constant.LoginFailureAccountSuspended); err != nil {
+			return
+		}
+
+		return nil, statusErr
+	}
+
+	token, err = o.mfaUsecase.IssueLoginToken(ctx, user.UID, device)
+	if err != nil || token.MFARequired {
+		return
+	}
+
+	if err = o.loginAttempt.RecordLogin(ctx, attempt, user, ""); err != nil {
+		return
+	}
+
+	return token, nil
+}
+
+// RequestPhoneVerification sends a code to a new phone number of the user,
+// the phone number is only changed once the code is verified.
+func (o *otpUsecase) RequestPhoneVerification(ctx context.Context, userUID, phoneNumber string) (err error) {
+	defer derrors.Wrap(&err, "RequestPhoneVerification(%q, %q)", userUID, phoneNumber)
+
+	if err = o.checkPhoneNumberAvailable(ctx, userUID, phoneNumber); err != nil {
+		return
+	}
+
+	if err = o.checkRequestLimit(ctx, phoneNumber, constant.OTPPurposeVerifyPhone); err != nil {
+		return
+	}
+
+	return o.sendCode(ctx, constant.OTPPurposeVerifyPhone, &userUID, phoneNumber, "Your Date Apps verification code is %s. It expires in %d minutes, do not share it with anyone.")
+}
+
+// VerifyPhoneNumber changes the phone number of the user to a verified one.
+func (o *otpUsecase) VerifyPhoneNumber(ctx context.Context, userUID, phoneNumber, code string) (err error) {
+	defer derrors.Wrap(&err, "VerifyPhoneNumber(%q, %q)", userUID, phoneNumber)
+
+	if err = o.verifyCode(ctx, constant.OTPPurposeVerifyPhone, &userUID, phoneNumber, code); err != nil {
+		return
+	}
+
+	// the number may have been taken while the code was pending
+	if err = o.checkPhoneNumberAvailable(ctx, userUID, phoneNumber); err != nil {
+		return
+	}
+
+	user, err := o.userRepo.GetUserByUID(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		return derrors.New(derrors.NotFound, "User not found")
+	}
+
+	user.PhoneNumber = &phoneNumber
+	return o.updatePhoneNumber(ctx, user)
+}
+
+func (o *otpUsecase) updatePhoneNumber(ctx context.Context, user *model.User) (err error) {
+	tx, err := o.userRepo.Begin()
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = o.userRepo.Rollback(tx)
+		}
+	}()
+
+	if err = o.userRepo.UpdateUser(ctx, tx, user); err != nil {
+		return
+	}
+
+	if err = o.userRepo.UpdatePhoneVerifiedAt(ctx, tx, user.UID, datatype.NewTimeNow()); err != nil {
+		return
+	}
+
+	if err = o.userRepo.Commit(tx); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return nil
+}
+
+// checkPhoneNumberAvailable fails when the phone number is already verified for the user
+// or belongs to another user.
+func (o *otpUsecase) checkPhoneNumberAvailable(ctx context.Context, userUID, phoneNumber string) (err error) {
+	existing, err := o.userRepo.GetUserByEmailOrPhoneNumber(ctx, "", phoneNumber)
+	if err != nil {
+		return
+	}
+
+	if existing == nil {
+		return nil
+	}
+
+	if existing.UID != userUID {
+		return derrors.New(derrors.Duplicate, "Phone number already registered")
+	}
+
+	if existing.IsPhoneVerified() {
+		return derrors.New(derrors.InvalidArgument, "Phone number is already verified")
+	}
+
+	return nil
+}
+
+// sendCode stores the hash of a new code and sends the code by SMS,
+// message is formatted with the code and its expiration in minutes.
+func (o *otpUsecase) sendCode(ctx context.Context, purpose string, userUID *string, phoneNumber, message string) (err error) {
+	code, err := o.createCode(ctx, purpose, userUID, phoneNumber)
+	if err != nil {
+		return
+	}
+
+	if err = o.smsSender.Send(ctx, phoneNumber, fmt.Sprintf(message, code, int(constant.OTPExpiration.Minutes()))); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "smsSender.Send")
+	}
+
+	return nil
+}
+
+// createCode stores the hash of a new code and returns the code.
+func (o *otpUsecase) createCode(ctx context.Context, purpose string, userUID *string, phoneNumber string) (code string, err error) {
+	code, err = util.RandomDigits(constant.OTPLength)
+	if err != nil {
+		return "", derrors.WrapStack(err, derrors.Unknown, "util.RandomDigits")
+	}
+
+	uid := ksuid.New().String()
+	expiresAt := time.Now().Add(constant.OTPExpiration)
+	err = o.otpCodeRepo.CreateOTPCode(ctx, nil, &model.OTPCode{
+		UID:         uid,
+		Purpose:     purpose,
+		UserUID:     userUID,
+		PhoneNumber: phoneNumber,
+		CodeHash:    HashOTPCode(uid, code),
+		ExpiresAt:   datatype.NewTime(&expiresAt),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// verifyCode checks and consumes the last code sent to the phone number for the purpose,
+// when userUID is given the code must have been requested by that user.
+func (o *otpUsecase) verifyCode(ctx context.Context, purpose string, userUID *string, phoneNumber, code string) (err error) {
+	otpCode, err := o.otpCodeRepo.GetLatestOTPCode(ctx, phoneNumber, purpose)
+	if err != nil {
+		return
+	}
+
+	if otpCode == nil || otpCode.IsExpired() {
+		return derrors.New(derrors.Unauthorized, "Invalid or expired code")
+	}
+
+	if userUID != nil && (otpCode.UserUID == nil || *otpCode.UserUID != *userUID) {
+		return derrors.New(derrors.Unauthorized, "Invalid or expired code")
+	}
+
+	// the attempt is claimed before comparing, so concurrent guesses can not go past the limit
+	claimed, err := o.otpCodeRepo.ClaimOTPAttempt(ctx, otpCode.UID, constant.OTPMaxAttempts)
+	if err != nil {
+		return
+	}
+
+	if !claimed {
+		return derrors.New(derrors.TooManyRequests, "Too many attempts, please request a new code")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(HashOTPCode(otpCode.UID, code)), []byte(otpCode.CodeHash)) != 1 {
+		return derrors.New(derrors.Unauthorized, "Invalid or expired code")
+	}
+
+	consumed, err := o.otpCodeRepo.ConsumeOTPCode(ctx, otpCode.UID)
+	if err != nil {
+		return
+	}
+
+	if !consumed {
+		return derrors.New(derrors.Unauthorized, "Invalid or expired code")
+	}
+
+	return nil
+}
+
+// recordFailure records a wrong login code, codes of unknown phone numbers are recorded without a user.
+// The Locked error is returned when the failure locked the account.
+func (o *otpUsecase) recordFailure(ctx context.Context, attempt model.LoginAttempt, user *model.User) (err error) {
+	if user == nil {
+		return o.loginAttempt.RecordLogin(ctx, attempt, nil, constant.LoginFailureUserNotFound)
+	}
+
+	return o.loginAttempt.RecordFailure(ctx, attempt, user, constant.LoginFailureInvalidOTPCode)
+}
+
+// checkRequestLimit limits how often codes are sent to a phone number,
+// both as a short cooldown between codes and as a total per window.
+func (o *otpUsecase) checkRequestLimit(ctx context.Context, phoneNumber, purpose string) (err error) {
+	total, err := o.otpCodeRepo.CountOTPCodes(ctx, phoneNumber, purpose, constant.OTPRequestWindow)
+	if err != nil {
+		return
+	}
+
+	if total >= constant.OTPMaxRequestPerWindow {
+		return derrors.New(derrors.TooManyRequests, "Too many code requests, please try again later")
+	}
+
+	latest, err := o.otpCodeRepo.GetLatestOTPCode(ctx, phoneNumber, purpose)
+	if err != nil {
+		return
+	}
+
+	if latest != nil && !latest.CreatedAt.IsNil() && latest.CreatedAt.Time().Add(constant.OTPResendCooldown).After(time.Now()) {
+		return derrors.New(derrors.TooManyRequests, "Please wait before requesting a new code")
+	}
+
+	return nil
+}
@@ -0,0 +1,448 @@
+package otpusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	smsservice "date-apps-be/internal/service/sms"
+	"date-apps-be/internal/test"
+	loginattemptusecase "date-apps-be/internal/usecase/login_attempt"
+	otpusecase "date-apps-be/internal/usecase/otp"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type params struct {
+	PhoneNumber string
+	Code        string
+	OTPCode     *model.OTPCode
+}
+
+func newOTPCode(uid, phoneNumber, code string, attempts int, expiresIn time.Duration) *model.OTPCode {
+	expiresAt := time.Now().Add(expiresIn)
+	createdAt := time.Now().Add(-2 * constant.OTPResendCooldown)
+	return &model.OTPCode{
+		UID:         uid,
+		Purpose:     constant.OTPPurposeLogin,
+		PhoneNumber: phoneNumber,
+		CodeHash:    otpusecase.HashOTPCode(uid, code),
+		Attempts:    attempts,
+		ExpiresAt:   datatype.NewTime(&expiresAt),
+		CreatedAt:   datatype.NewTime(&createdAt),
+	}
+}
+
+func TestRequestLoginOTP(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	smsSender := smsservice.NewMemorySMSSender()
+	testUsecase := otpusecase.NewOTPUsecase(mc.OTPCodeRepository, mc.UserRepository, mc.MFAUsecase, smsSender, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository))
+
+	var created *model.OTPCode
+
+	var testCases = []struct {
+		caseName     string
+		params       params
+		expectations func(params)
+		results      func(params, error)
+	}{
+		{
+			caseName: "RequestLoginOTP_Success",
+			params:   params{PhoneNumber: "6281100000001"},
+			expectations: func(params params) {
+				mc.OTPCodeRepository.On("CountOTPCodes", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin, constant.OTPRequestWindow).
+					Return(0, nil).Once()
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin).
+					Return(nil, nil).Once()
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).
+					Return(&model.User{UID: "user-1"}, nil).Once()
+				mc.OTPCodeRepository.On("CreateOTPCode", mock.Anything, mock.Anything, mock.MatchedBy(func(o *model.OTPCode) bool {
+					created = o
+					return o.PhoneNumber == params.PhoneNumber && o.Purpose == constant.OTPPurposeLogin
+				})).Return(nil).Once()
+			},
+			results: func(params params, err error) {
+				assert.NoError(t, err)
+
+				messages := smsSender.Messages()
+				if assert.Len(t, messages, 1) {
+					assert.Equal(t, params.PhoneNumber, messages[0].PhoneNumber)
+					code := regexp.MustCompile(`\d{6}`).FindString(messages[0].Body)
+					assert.Equal(t, created.CodeHash, otpusecase.HashOTPCode(created.UID, code))
+					assert.NotContains(t, created.CodeHash, code)
+				}
+			},
+		},
+		{
+			caseName: "RequestLoginOTP_UnknownPhoneNumber",
+			params:   params{PhoneNumber: "6281100000002"},
+			expectations: func(params params) {
+				mc.OTPCodeRepository.On("CountOTPCodes", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin, constant.OTPRequestWindow).
+					Return(0, nil).Once()
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin).
+					Return(nil, nil).Once()
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).
+					Return(nil, nil).Once()
+				// stored without being sent, so unknown numbers count towards the same limits
+				mc.OTPCodeRepository.On("CreateOTPCode", mock.Anything, mock.Anything, mock.MatchedBy(func(o *model.OTPCode) bool {
+					return o.PhoneNumber == params.PhoneNumber && o.Purpose == constant.OTPPurposeLogin
+				})).Return(nil).Once()
+			},
+			results: func(params params, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, smsSender.Messages(), 1)
+			},
+		},
+		{
+			caseName: "RequestLoginOTP_TooManyRequests",
+			params:   params{PhoneNumber: "6281100000003"},
+			expectations: func(params params) {
+				mc.OTPCodeRepository.On("CountOTPCodes", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin, constant.OTPRequestWindow).
+					Return(constant.OTPMaxRequestPerWindow, nil).Once()
+			},
+			results: func(params params, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.TooManyRequests))
+			},
+		},
+		{
+			caseName: "RequestLoginOTP_Cooldown",
+			params:   params{PhoneNumber: "6281100000004"},
+			expectations: func(params params) {
+				mc.OTPCodeRepository.On("CountOTPCodes", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin, constant.OTPRequestWindow).
+					Return(1, nil).Once()
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin).
+					Return(&model.OTPCode{UID: "otp-4", CreatedAt: datatype.NewTimeNow()}, nil).Once()
+			},
+			results: func(params params, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.TooManyRequests))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.caseName, func(t *testing.T) {
+			tc.expectations(tc.params)
+			err := testUsecase.RequestLoginOTP(ctx, tc.params.PhoneNumber)
+			tc.results(tc.params, err)
+		})
+	}
+}
+
+func TestVerifyLoginOTP(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := otpusecase.NewOTPUsecase(mc.OTPCodeRepository, mc.UserRepository, mc.MFAUsecase, smsservice.NewMemorySMSSender(), loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository))
+	device := model.Device{ID: "device_1", IPAddress: "127.0.0.1"}
+	lockedUntil := time.Now().Add(constant.AccountLockDuration)
+
+	failedWith := func(reason string) interface{} {
+		return mock.MatchedBy(func(l *model.LoginHistory) bool {
+			return !l.IsSuccess && *l.FailureReason == reason
+		})
+	}
+
+	mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, device.IPAddress, constant.FailedLoginWindow).Return(0, nil)
+
+	var testCases = []struct {
+		caseName     string
+		params       params
+		expectations func(params)
+		results      func(token *model.AuthToken, err error)
+	}{
+		{
+			caseName: "VerifyLoginOTP_Success",
+			params: params{
+				PhoneNumber: "6281100000011",
+				Code:        "123456",
+				OTPCode:     newOTPCode("otp-11", "6281100000011", "123456", 0, constant.OTPExpiration),
+			},
+			expectations: func(params params) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).
+					Return(&model.User{UID: "user-11"}, nil).Once()
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin).
+					Return(params.OTPCode, nil).Once()
+				mc.OTPCodeRepository.On("ClaimOTPAttempt", mock.Anything, params.OTPCode.UID, constant.OTPMaxAttempts).
+					Return(true, nil).Once()
+				mc.OTPCodeRepository.On("ConsumeOTPCode", mock.Anything, params.OTPCode.UID).
+					Return(true, nil).Once()
+				mc.MFAUsecase.On("IssueLoginToken", mock.Anything, "user-11", device).
+					Return(&model.AuthToken{Token: "test_token", RefreshToken: "test_refresh_token"}, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return l.IsSuccess && *l.UserUID == "user-11" && l.Identifier == params.PhoneNumber
+				})).Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "test_token", token.Token)
+			},
+		},
+		{
+			caseName: "VerifyLoginOTP_WrongCode",
+			params: params{
+				PhoneNumber: "6281100000012",
+				Code:        "654321",
+				OTPCode:     newOTPCode("otp-12", "6281100000012", "123456", 0, constant.OTPExpiration),
+			},
+			expectations: func(params params) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).
+					Return(&model.User{UID: "user-12"}, nil).Once()
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin).
+					Return(params.OTPCode, nil).Once()
+				mc.OTPCodeRepository.On("ClaimOTPAttempt", mock.Anything, params.OTPCode.UID, constant.OTPMaxAttempts).
+					Return(true, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, failedWith(constant.LoginFailureInvalidOTPCode)).
+					Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user-12", constant.FailedLoginWindow).
+					Return(1, nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.Nil(t, token)
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyLoginOTP_WrongCodeLocks",
+			params: params{
+				PhoneNumber: "6281100000016",
+				Code:        "654321",
+				OTPCode:     newOTPCode("otp-16", "6281100000016", "123456", 0, constant.OTPExpiration),
+			},
+			expectations: func(params params) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).
+					Return(&model.User{UID: "user-16"}, nil).Once()
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin).
+					Return(params.OTPCode, nil).Once()
+				mc.OTPCodeRepository.On("ClaimOTPAttempt", mock.Anything, params.OTPCode.UID, constant.OTPMaxAttempts).
+					Return(true, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, failedWith(constant.LoginFailureInvalidOTPCode)).
+					Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user-16", constant.FailedLoginWindow).
+					Return(constant.MaxFailedLoginPerAccount, nil).Once()
+				mc.UserRepository.On("UpdateLockedUntil", mock.Anything, mock.Anything, "user-16", mock.Anything).
+					Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.Nil(t, token)
+				assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+			},
+		},
+		{
+			caseName: "VerifyLoginOTP_Locked",
+			params: params{
+				PhoneNumber: "6281100000017",
+				Code:        "123456",
+			},
+			expectations: func(params params) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).
+					Return(&model.User{UID: "user-17", LockedUntil: datatype.NewTime(&lockedUntil)}, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, failedWith(constant.LoginFailureAccountLocked)).
+					Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.Nil(t, token)
+				assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+			},
+		},
+		{
+			caseName: "VerifyLoginOTP_UnknownPhoneNumber",
+			params: params{
+				PhoneNumber: "6281100000018",
+				Code:        "654321",
+				OTPCode:     newOTPCode("otp-18", "6281100000018", "123456", 0, constant.OTPExpiration),
+			},
+			expectations: func(params params) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).
+					Return(nil, nil).Once()
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin).
+					Return(params.OTPCode, nil).Once()
+				mc.OTPCodeRepository.On("ClaimOTPAttempt", mock.Anything, params.OTPCode.UID, constant.OTPMaxAttempts).
+					Return(true, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && l.UserUID == nil && *l.FailureReason == constant.LoginFailureUserNotFound
+				})).Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.Nil(t, token)
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyLoginOTP_TooManyAttempts",
+			params: params{
+				PhoneNumber: "6281100000013",
+				Code:        "123456",
+				OTPCode:     newOTPCode("otp-13", "6281100000013", "123456", constant.OTPMaxAttempts, constant.OTPExpiration),
+			},
+			expectations: func(params params) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).
+					Return(&model.User{UID: "user-13"}, nil).Once()
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin).
+					Return(params.OTPCode, nil).Once()
+				// another request may have used the last attempt after the code was read
+				mc.OTPCodeRepository.On("ClaimOTPAttempt", mock.Anything, params.OTPCode.UID, constant.OTPMaxAttempts).
+					Return(false, nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.Nil(t, token)
+				assert.True(t, derrors.IsErrCode(err, derrors.TooManyRequests))
+			},
+		},
+		{
+			caseName: "VerifyLoginOTP_Expired",
+			params: params{
+				PhoneNumber: "6281100000014",
+				Code:        "123456",
+				OTPCode:     newOTPCode("otp-14", "6281100000014", "123456", 0, -time.Minute),
+			},
+			expectations: func(params params) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).
+					Return(&model.User{UID: "user-14"}, nil).Once()
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin).
+					Return(params.OTPCode, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, failedWith(constant.LoginFailureInvalidOTPCode)).
+					Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user-14", constant.FailedLoginWindow).
+					Return(1, nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.Nil(t, token)
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyLoginOTP_AlreadyConsumed",
+			params: params{
+				PhoneNumber: "6281100000015",
+				Code:        "123456",
+				OTPCode:     newOTPCode("otp-15", "6281100000015", "123456", 0, constant.OTPExpiration),
+			},
+			expectations: func(params params) {
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).
+					Return(&model.User{UID: "user-15"}, nil).Once()
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeLogin).
+					Return(params.OTPCode, nil).Once()
+				mc.OTPCodeRepository.On("ClaimOTPAttempt", mock.Anything, params.OTPCode.UID, constant.OTPMaxAttempts).
+					Return(true, nil).Once()
+				mc.OTPCodeRepository.On("ConsumeOTPCode", mock.Anything, params.OTPCode.UID).
+					Return(false, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, failedWith(constant.LoginFailureInvalidOTPCode)).
+					Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user-15", constant.FailedLoginWindow).
+					Return(1, nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.Nil(t, token)
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.caseName, func(t *testing.T) {
+			tc.expectations(tc.params)
+			token, err := testUsecase.VerifyLoginOTP(ctx, tc.params.PhoneNumber, tc.params.Code, device)
+			tc.results(token, err)
+		})
+	}
+}
+
+func TestVerifyPhoneNumber(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := otpusecase.NewOTPUsecase(mc.OTPCodeRepository, mc.UserRepository, mc.MFAUsecase, smsservice.NewMemorySMSSender(), loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository))
+	tx := &sql.Tx{}
+
+	newPhoneCode := func(uid, userUID, phoneNumber string) *model.OTPCode {
+		otpCode := newOTPCode(uid, phoneNumber, "123456", 0, constant.OTPExpiration)
+		otpCode.Purpose = constant.OTPPurposeVerifyPhone
+		otpCode.UserUID = &userUID
+		return otpCode
+	}
+
+	var testCases = []struct {
+		caseName     string
+		userUID      string
+		params       params
+		expectations func(userUID string, params params)
+		results      func(err error)
+	}{
+		{
+			caseName: "VerifyPhoneNumber_Success",
+			userUID:  "user-1",
+			params: params{
+				PhoneNumber: "6281100000021",
+				Code:        "123456",
+				OTPCode:     newPhoneCode("otp-21", "user-1", "6281100000021"),
+			},
+			expectations: func(userUID string, params params) {
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeVerifyPhone).
+					Return(params.OTPCode, nil).Once()
+				mc.OTPCodeRepository.On("ClaimOTPAttempt", mock.Anything, params.OTPCode.UID, constant.OTPMaxAttempts).Return(true, nil).Once()
+				mc.OTPCodeRepository.On("ConsumeOTPCode", mock.Anything, params.OTPCode.UID).Return(true, nil).Once()
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).Return(nil, nil).Once()
+				mc.UserRepository.On("GetUserByUID", mock.Anything, userUID).Return(&model.User{UID: userUID}, nil).Once()
+				mc.UserRepository.On("Begin").Return(tx, nil).Once()
+				mc.UserRepository.On("UpdateUser", mock.Anything, tx, mock.MatchedBy(func(u *model.User) bool {
+					return *u.PhoneNumber == params.PhoneNumber
+				})).Return(nil).Once()
+				mc.UserRepository.On("UpdatePhoneVerifiedAt", mock.Anything, tx, userUID, mock.Anything).Return(nil).Once()
+				mc.UserRepository.On("Commit", tx).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName: "VerifyPhoneNumber_CodeOfAnotherUser",
+			userUID:  "user-2",
+			params: params{
+				PhoneNumber: "6281100000022",
+				Code:        "123456",
+				OTPCode:     newPhoneCode("otp-22", "user-3", "6281100000022"),
+			},
+			expectations: func(userUID string, params params) {
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeVerifyPhone).
+					Return(params.OTPCode, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+			},
+		},
+		{
+			caseName: "VerifyPhoneNumber_TakenMeanwhile",
+			userUID:  "user-4",
+			params: params{
+				PhoneNumber: "6281100000024",
+				Code:        "123456",
+				OTPCode:     newPhoneCode("otp-24", "user-4", "6281100000024"),
+			},
+			expectations: func(userUID string, params params) {
+				mc.OTPCodeRepository.On("GetLatestOTPCode", mock.Anything, params.PhoneNumber, constant.OTPPurposeVerifyPhone).
+					Return(params.OTPCode, nil).Once()
+				mc.OTPCodeRepository.On("ClaimOTPAttempt", mock.Anything, params.OTPCode.UID, constant.OTPMaxAttempts).Return(true, nil).Once()
+				mc.OTPCodeRepository.On("ConsumeOTPCode", mock.Anything, params.OTPCode.UID).Return(true, nil).Once()
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "", params.PhoneNumber).
+					Return(&model.User{UID: "user-5"}, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Duplicate))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.caseName, func(t *testing.T) {
+			tc.expectations(tc.userUID, tc.params)
+			err := testUsecase.VerifyPhoneNumber(ctx, tc.userUID, tc.params.PhoneNumber, tc.params.Code)
+			tc.results(err)
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package photousecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"date-apps-be/infrastructure/config"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	userphotorepo "date-apps-be/internal/repository/user_photo"
+	blobservice "date-apps-be/internal/service/blob"
+	imageservice "date-apps-be/internal/service/image"
+	"date-apps-be/pkg/derrors"
+	"io"
+
+	"github.com/segmentio/ksuid"
+)
+
+type (
+	PhotoUsecase interface {
+		UploadPhoto(ctx context.Context, userUID string, body io.Reader) (photo *model.UserPhoto, err error)
+		GetUserPhotos(ctx context.Context, userUID string) (photos []*model.UserPhoto, err error)
+		DeletePhoto(ctx context.Context, userUID, photoUID string) (err error)
+		ReorderPhotos(ctx context.Context, userUID string, photoUIDs []string) (photos []*model.UserPhoto, err error)
+		SetPrimaryPhoto(ctx context.Context, userUID, photoUID string) (photos []*model.UserPhoto, err error)
+		OpenPhoto(ctx context.Context, photoUID string, thumbnail bool) (photo *model.UserPhoto, body io.ReadCloser, err error)
+		AttachPhotos(ctx context.Context, users []*model.User) (err error)
+	}
+
+	photoUsecase struct {
+		conf           *config.Config
+		userPhotoRepo  userphotorepo.UserPhotoRepository
+		blobStore      blobservice.BlobStore
+		imageProcessor imageservice.ImageProcessor
+	}
+)
+
+func NewPhotoUsecase(conf *config.Config, userPhotoRepo userphotorepo.UserPhotoRepository, blobStore blobservice.BlobStore, imageProcessor imageservice.ImageProcessor) PhotoUsecase {
+	return &photoUsecase{
+		conf:           conf,
+		userPhotoRepo:  userPhotoRepo,
+		blobStore:      blobStore,
+		imageProcessor: imageProcessor,
+	}
+}
+
+// UploadPhoto stores the photo without its metadata together with a thumbnail,
+// the first photo of a user becomes the primary one.
+func (p *photoUsecase) UploadPhoto(ctx context.Context, userUID string, body io.Reader) (photo *model.UserPhoto, err error) {
+	defer derrors.Wrap(&err, "UploadPhoto(%q)", userUID)
+
+	data, err := io.ReadAll(io.LimitReader(body, constant.MaxPhotoSize+1))
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "io.ReadAll")
+	}
+
+	if len(data) > constant.MaxPhotoSize {
+		return nil, derrors.New(derrors.InvalidArgument, "The photo must be at most %d MB", constant.MaxPhotoSize>>20)
+	}
+
+	// fail fast, the limit is checked again while the photos are locked
+	photos, err := p.userPhotoRepo.GetUserPhotos(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if len(photos) >= constant.MaxPhotosPerUser {
+		return nil, derrors.New(derrors.InvalidArgument, "You can upload at most %d photos", constant.MaxPhotosPerUser)
+	}
+
+	img, err := p.imageProcessor.Process(data)
+	if err != nil {
+		return
+	}
+
+	photoUID := ksuid.New().String()
+	extension := ".jpg"
+	if img.ContentType == constant.PhotoContentTypePNG {
+		extension = ".png"
+	}
+
+	photo = &model.UserPhoto{
+		UID:          photoUID,
+		UserUID:      userUID,
+		ContentType:  img.ContentType,
+		BlobKey:      "photos/" + userUID + "/" + photoUID + extension,
+		ThumbnailKey: "photos/" + userUID + "/" + photoUID + "_thumbnail" + extension,
+		Width:        img.Width,
+		Height:       img.Height,
+		Size:         int64(len(img.Data)),
+	}
+
+	if err = p.blobStore.Put(ctx, photo.BlobKey, bytes.NewReader(img.Data)); err != nil {
+		return nil, err
+	}
+	if err = p.blobStore.Put(ctx, photo.ThumbnailKey, bytes.NewReader(img.Thumbnail)); err != nil {
+		p.deleteBlobs(ctx, photo)
+		return nil, err
+	}
+
+	err = p.withLockedPhotos(ctx, userUID, func(tx *sql.Tx, photos []*model.UserPhoto) error {
+		if len(photos) >= constant.MaxPhotosPerUser {
+			return derrors.New(derrors.InvalidArgument, "You can upload at most %d photos", constant.MaxPhotosPerUser)
+		}
+
+		photo.IsPrimary = len(photos) == 0
+		if len(photos) > 0 {
+			photo.Position = photos[len(photos)-1].Position + 1
+		}
+
+		return p.userPhotoRepo.CreateUserPhoto(ctx, tx, photo)
+	})
+	if err != nil {
+		p.deleteBlobs(ctx, photo)
+		return nil, err
+	}
+
+	p.setURLs(photo)
+	return photo, nil
+}
+
+// GetUserPhotos returns the photos of the user in display order.
+func (p *photoUsecase) GetUserPhotos(ctx context.Context, userUID string) (photos []*model.UserPhoto, err error) {
+	defer derrors.Wrap(&err, "GetUserPhotos(%q)", userUID)
+
+	photos, err = p.userPhotoRepo.GetUserPhotos(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	for _, photo := range photos {
+		p.setURLs(photo)
+	}
+	return photos, nil
+}
+
+// DeletePhoto removes a photo of the user, the next photo becomes primary when the primary one is deleted.
+func (p *photoUsecase) DeletePhoto(ctx context.Context, userUID, photoUID string) (err error) {
+	defer derrors.Wrap(&err, "DeletePhoto(%q, %q)", userUID, photoUID)
+
+	var deleted *model.UserPhoto
+	err = p.withLockedPhotos(ctx, userUID, func(tx *sql.Tx, photos []*model.UserPhoto) error {
+		remaining := make([]*model.UserPhoto, 0, len(photos))
+		for _, photo := range photos {
+			if photo.UID == photoUID {
+				deleted = photo
+				continue
+			}
+			remaining = append(remaining, photo)
+		}
+
+		if deleted == nil {
+			return derrors.New(derrors.NotFound, "Photo not found")
+		}
+
+		if err := p.userPhotoRepo.DeleteUserPhoto(ctx, tx, photoUID); err != nil {
+			return err
+		}
+
+		primaryUID := ""
+		if !deleted.IsPrimary {
+			primaryUID = primaryPhotoUID(remaining)
+		}
+		return p.savePositions(ctx, tx, remaining, primaryUID)
+	})
+	if err != nil {
+		return
+	}
+
+	// the row is gone already, a blob that failed to delete is orphaned but never served
+	p.deleteBlobs(ctx, deleted)
+	return nil
+}
+
+// ReorderPhotos sets the display order, photoUIDs must list every photo of the user exactly once.
+func (p *photoUsecase) ReorderPhotos(ctx context.Context, userUID string, photoUIDs []string) (photos []*model.UserPhoto, err error) {
+	defer derrors.Wrap(&err, "ReorderPhotos(%q)", userUID)
+
+	err = p.withLockedPhotos(ctx, userUID, func(tx *sql.Tx, current []*model.UserPhoto) error {
+		byUID := make(map[string]*model.UserPhoto, len(current))
+		for _, photo := range current {
+			byUID[photo.UID] = photo
+		}
+
+		if len(photoUIDs) != len(current) {
+			return derrors.New(derrors.InvalidArgument, "The order must list each of your %d photos once", len(current))
+		}
+
+		photos = make([]*model.UserPhoto, 0, len(photoUIDs))
+		for _, photoUID := range photoUIDs {
+			photo, ok := byUID[photoUID]
+			if !ok {
+				return derrors.New(derrors.InvalidArgument, "The order must list each of your %d photos once", len(current))
+			}
+			delete(byUID, photoUID)
+			photos = append(photos, photo)
+		}
+
+		return p.savePositions(ctx, tx, photos, primaryPhotoUID(photos))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, photo := range photos {
+		p.setURLs(photo)
+	}
+	return photos, nil
+}
+
+// SetPrimaryPhoto chooses the photo that represents the user, the order is unchanged.
+func (p *photoUsecase) SetPrimaryPhoto(ctx context.Context, userUID, photoUID string) (photos []*model.UserPhoto, err error) {
+	defer derrors.Wrap(&err, "SetPrimaryPhoto(%q, %q)", userUID, photoUID)
+
+	err = p.withLockedPhotos(ctx, userUID, func(tx *sql.Tx, current []*model.UserPhoto) error {
+		photos = current
+		if primaryPhotoUID(photos) == photoUID {
+			return nil
+		}
+
+		for _, photo := range photos {
+			if photo.UID == photoUID {
+				return p.savePositions(ctx, tx, photos, photoUID)
+			}
+		}
+
+		return derrors.New(derrors.NotFound, "Photo not found")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, photo := range photos {
+		p.setURLs(photo)
+	}
+	return photos, nil
+}
+
+// OpenPhoto returns the original or the thumbnail of a photo, photos of deleted and banned users are not found.
+func (p *photoUsecase) OpenPhoto(ctx context.Context, photoUID string, thumbnail bool) (photo *model.UserPhoto, body io.ReadCloser, err error) {
+	defer derrors.Wrap(&err, "OpenPhoto(%q)", photoUID)
+
+	photo, err = p.userPhotoRepo.GetUserPhoto(ctx, photoUID)
+	if err != nil {
+		return
+	}
+
+	if photo == nil {
+		return nil, nil, derrors.New(derrors.NotFound, "Photo not found")
+	}
+
+	key := photo.BlobKey
+	if thumbnail {
+		key = photo.ThumbnailKey
+	}
+
+	body, err = p.blobStore.Get(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return photo, body, nil
+}
+
+// AttachPhotos loads the photos of every user with a single query.
+func (p *photoUsecase) AttachPhotos(ctx context.Context, users []*model.User) (err error) {
+	defer derrors.Wrap(&err, "AttachPhotos")
+
+	userUIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userUIDs = append(userUIDs, user.UID)
+	}
+
+	photos, err := p.userPhotoRepo.GetUserPhotosByUserUIDs(ctx, userUIDs)
+	if err != nil {
+		return
+	}
+
+	byUserUID := map[string][]*model.UserPhoto{}
+	for _, photo := range photos {
+		p.setURLs(photo)
+		byUserUID[photo.UserUID] = append(byUserUID[photo.UserUID], photo)
+	}
+
+	for _, user := range users {
+		user.Photos = byUserUID[user.UID]
+	}
+	return nil
+}
+
+// withLockedPhotos runs fn in a transaction while the photos of the user are locked.
+func (p *photoUsecase) withLockedPhotos(ctx context.Context, userUID string, fn func(tx *sql.Tx, photos []*model.UserPhoto) error) (err error) {
+	tx, err := p.userPhotoRepo.Begin()
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = p.userPhotoRepo.Rollback(tx)
+		}
+	}()
+
+	photos, err := p.userPhotoRepo.LockUserPhotos(ctx, tx, userUID)
+	if err != nil {
+		return
+	}
+
+	if err = fn(tx, photos); err != nil {
+		return
+	}
+
+	if err = p.userPhotoRepo.Commit(tx); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return nil
+}
+
+// savePositions numbers the photos in the given order and marks primaryUID as primary,
+// the first photo becomes primary when primaryUID is empty.
+func (p *photoUsecase) savePositions(ctx context.Context, tx *sql.Tx, photos []*model.UserPhoto, primaryUID string) error {
+	if primaryUID == "" && len(photos) > 0 {
+		primaryUID = photos[0].UID
+	}
+
+	for position, photo := range photos {
+		isPrimary := photo.UID == primaryUID
+		if photo.Position == position && photo.IsPrimary == isPrimary {
+			continue
+		}
+
+		if err := p.userPhotoRepo.UpdateUserPhotoPosition(ctx, tx, photo.UID, position, isPrimary); err != nil {
+			return err
+		}
+		photo.Position = position
+		photo.IsPrimary = isPrimary
+	}
+
+	return nil
+}
+
+func (p *photoUsecase) deleteBlobs(ctx context.Context, photo *model.UserPhoto) {
+	_ = p.blobStore.Delete(ctx, photo.BlobKey)
+	_ = p.blobStore.Delete(ctx, photo.ThumbnailKey)
+}
+
+func (p *photoUsecase) setURLs(photo *model.UserPhoto) {
+	photo.URL = p.conf.AppBaseURL + "/photos/" + photo.UID
+	photo.ThumbnailURL = photo.URL + "/thumbnail"
+}
+
+func primaryPhotoUID(photos []*model.UserPhoto) string {
+	for _, photo := range photos {
+		if photo.IsPrimary {
+			return photo.UID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,283 @@
+package photousecase_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	blobservice "date-apps-be/internal/service/blob"
+	imageservice "date-apps-be/internal/service/image"
+	"date-apps-be/internal/test"
+	photousecase "date-apps-be/internal/usecase/photo"
+	"date-apps-be/pkg/derrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func pngImage(t *testing.T) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 640, 480))))
+	return buf.Bytes()
+}
+
+func photos(userUID string, uids ...string) []*model.UserPhoto {
+	photos := []*model.UserPhoto{}
+	for position, uid := range uids {
+		photos = append(photos, &model.UserPhoto{
+			UID: uid, UserUID: userUID, Position: position, IsPrimary: position == 0,
+			BlobKey: "photos/" + userUID + "/" + uid + ".png", ThumbnailKey: "photos/" + userUID + "/" + uid + "_thumbnail.png",
+		})
+	}
+	return photos
+}
+
+func TestUploadPhoto(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	blobStore := blobservice.NewLocalBlobStore(dir)
+	testUsecase := photousecase.NewPhotoUsecase(mc.Config, mc.UserPhotoRepository, blobStore, imageservice.NewImageProcessor(constant.PhotoMaxSize, constant.PhotoThumbnailSize))
+
+	full := photos("user_3", "p1", "p2", "p3", "p4", "p5", "p6")
+
+	var testCases = []struct {
+		caseName     string
+		userUID      string
+		body         []byte
+		expectations func()
+		results      func(photo *model.UserPhoto, err error)
+	}{
+		{
+			caseName: "UploadPhoto_First",
+			userUID:  "user_1",
+			body:     pngImage(t),
+			expectations: func() {
+				mc.UserPhotoRepository.On("GetUserPhotos", mock.Anything, "user_1").Return([]*model.UserPhoto{}, nil).Once()
+				mc.UserPhotoRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserPhotoRepository.On("LockUserPhotos", mock.Anything, mock.Anything, "user_1").Return([]*model.UserPhoto{}, nil).Once()
+				mc.UserPhotoRepository.On("CreateUserPhoto", mock.Anything, mock.Anything, mock.MatchedBy(func(p *model.UserPhoto) bool {
+					return p.UserUID == "user_1" && p.IsPrimary && p.Position == 0 && p.Width == 640 && p.Height == 480
+				})).Return(nil).Once()
+				mc.UserPhotoRepository.On("Commit", mock.Anything).Return(nil).Once()
+			},
+			results: func(photo *model.UserPhoto, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, constant.PhotoContentTypePNG, photo.ContentType)
+				assert.Equal(t, mc.Config.AppBaseURL+"/photos/"+photo.UID+"/thumbnail", photo.ThumbnailURL)
+
+				thumbnail, err := blobStore.Get(ctx, photo.ThumbnailKey)
+				require.NoError(t, err)
+				defer thumbnail.Close()
+				config, _, err := image.DecodeConfig(thumbnail)
+				require.NoError(t, err)
+				assert.Equal(t, constant.PhotoThumbnailSize, config.Width)
+			},
+		},
+		{
+			caseName: "UploadPhoto_Next",
+			userUID:  "user_2",
+			body:     pngImage(t),
+			expectations: func() {
+				mc.UserPhotoRepository.On("GetUserPhotos", mock.Anything, "user_2").Return(photos("user_2", "p1"), nil).Once()
+				mc.UserPhotoRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserPhotoRepository.On("LockUserPhotos", mock.Anything, mock.Anything, "user_2").Return(photos("user_2", "p1"), nil).Once()
+				mc.UserPhotoRepository.On("CreateUserPhoto", mock.Anything, mock.Anything, mock.MatchedBy(func(p *model.UserPhoto) bool {
+					return p.UserUID == "user_2" && !p.IsPrimary && p.Position == 1
+				})).Return(nil).Once()
+				mc.UserPhotoRepository.On("Commit", mock.Anything).Return(nil).Once()
+			},
+			results: func(photo *model.UserPhoto, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName: "UploadPhoto_LimitReached",
+			userUID:  "user_3",
+			body:     pngImage(t),
+			expectations: func() {
+				mc.UserPhotoRepository.On("GetUserPhotos", mock.Anything, "user_3").Return(full, nil).Once()
+			},
+			results: func(photo *model.UserPhoto, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+		{
+			caseName: "UploadPhoto_LimitReachedConcurrently",
+			userUID:  "user_4",
+			body:     pngImage(t),
+			expectations: func() {
+				mc.UserPhotoRepository.On("GetUserPhotos", mock.Anything, "user_4").Return(full[:5], nil).Once()
+				mc.UserPhotoRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserPhotoRepository.On("LockUserPhotos", mock.Anything, mock.Anything, "user_4").Return(full, nil).Once()
+				mc.UserPhotoRepository.On("Rollback", mock.Anything).Return(nil).Once()
+			},
+			results: func(photo *model.UserPhoto, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				// the stored blobs are removed again
+				blobs, err := os.ReadDir(filepath.Join(dir, "photos", "user_4"))
+				require.NoError(t, err)
+				assert.Empty(t, blobs)
+			},
+		},
+		{
+			caseName: "UploadPhoto_TooLarge",
+			userUID:  "user_5",
+			body:     make([]byte, constant.MaxPhotoSize+1),
+			expectations: func() {
+			},
+			results: func(photo *model.UserPhoto, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+		{
+			caseName: "UploadPhoto_NotAnImage",
+			userUID:  "user_6",
+			body:     []byte("GIF89a not a photo"),
+			expectations: func() {
+				mc.UserPhotoRepository.On("GetUserPhotos", mock.Anything, "user_6").Return([]*model.UserPhoto{}, nil).Once()
+			},
+			results: func(photo *model.UserPhoto, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			photo, err := testUsecase.UploadPhoto(ctx, testCase.userUID, bytes.NewReader(testCase.body))
+			testCase.results(photo, err)
+		})
+	}
+}
+
+func TestDeletePhoto(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	blobStore := blobservice.NewLocalBlobStore(t.TempDir())
+	testUsecase := photousecase.NewPhotoUsecase(mc.Config, mc.UserPhotoRepository, blobStore, imageservice.NewImageProcessor(constant.PhotoMaxSize, constant.PhotoThumbnailSize))
+
+	current := photos("user_1", "p1", "p2", "p3")
+	require.NoError(t, blobStore.Put(ctx, current[0].BlobKey, strings.NewReader("original")))
+	require.NoError(t, blobStore.Put(ctx, current[0].ThumbnailKey, strings.NewReader("thumbnail")))
+
+	mc.UserPhotoRepository.On("Begin").Return((*sql.Tx)(nil), nil).Twice()
+	mc.UserPhotoRepository.On("LockUserPhotos", mock.Anything, mock.Anything, "user_1").Return(current, nil).Once()
+	mc.UserPhotoRepository.On("DeleteUserPhoto", mock.Anything, mock.Anything, "p1").Return(nil).Once()
+	// the next photo becomes primary and the positions are closed up
+	mc.UserPhotoRepository.On("UpdateUserPhotoPosition", mock.Anything, mock.Anything, "p2", 0, true).Return(nil).Once()
+	mc.UserPhotoRepository.On("UpdateUserPhotoPosition", mock.Anything, mock.Anything, "p3", 1, false).Return(nil).Once()
+	mc.UserPhotoRepository.On("Commit", mock.Anything).Return(nil).Once()
+
+	require.NoError(t, testUsecase.DeletePhoto(ctx, "user_1", "p1"))
+	_, err := blobStore.Get(ctx, current[0].BlobKey)
+	assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+	_, err = blobStore.Get(ctx, current[0].ThumbnailKey)
+	assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+
+	// photos of other users are not found
+	mc.UserPhotoRepository.On("LockUserPhotos", mock.Anything, mock.Anything, "user_2").Return(photos("user_2", "p4"), nil).Once()
+	mc.UserPhotoRepository.On("Rollback", mock.Anything).Return(nil).Once()
+
+	err = testUsecase.DeletePhoto(ctx, "user_2", "p2")
+	assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+}
+
+func TestReorderPhotos(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := photousecase.NewPhotoUsecase(mc.Config, mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), imageservice.NewImageProcessor(constant.PhotoMaxSize, constant.PhotoThumbnailSize))
+
+	mc.UserPhotoRepository.On("Begin").Return((*sql.Tx)(nil), nil)
+	mc.UserPhotoRepository.On("LockUserPhotos", mock.Anything, mock.Anything, "user_1").Return(photos("user_1", "p1", "p2", "p3"), nil).Once()
+	// the primary photo stays primary when it moves
+	mc.UserPhotoRepository.On("UpdateUserPhotoPosition", mock.Anything, mock.Anything, "p3", 0, false).Return(nil).Once()
+	mc.UserPhotoRepository.On("UpdateUserPhotoPosition", mock.Anything, mock.Anything, "p1", 1, true).Return(nil).Once()
+	mc.UserPhotoRepository.On("UpdateUserPhotoPosition", mock.Anything, mock.Anything, "p2", 2, false).Return(nil).Once()
+	mc.UserPhotoRepository.On("Commit", mock.Anything).Return(nil).Once()
+
+	reordered, err := testUsecase.ReorderPhotos(ctx, "user_1", []string{"p3", "p1", "p2"})
+	require.NoError(t, err)
+	assert.Equal(t, "p3", reordered[0].UID)
+
+	for _, photoUIDs := range [][]string{{"p1", "p2"}, {"p1", "p1", "p2"}, {"p1", "p2", "p4"}} {
+		mc.UserPhotoRepository.On("LockUserPhotos", mock.Anything, mock.Anything, "user_2").Return(photos("user_2", "p1", "p2", "p3"), nil).Once()
+		mc.UserPhotoRepository.On("Rollback", mock.Anything).Return(nil).Once()
+
+		_, err = testUsecase.ReorderPhotos(ctx, "user_2", photoUIDs)
+		assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument), photoUIDs)
+	}
+}
+
+func TestSetPrimaryPhoto(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := photousecase.NewPhotoUsecase(mc.Config, mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), imageservice.NewImageProcessor(constant.PhotoMaxSize, constant.PhotoThumbnailSize))
+
+	mc.UserPhotoRepository.On("Begin").Return((*sql.Tx)(nil), nil)
+	mc.UserPhotoRepository.On("LockUserPhotos", mock.Anything, mock.Anything, "user_1").Return(photos("user_1", "p1", "p2"), nil).Once()
+	mc.UserPhotoRepository.On("UpdateUserPhotoPosition", mock.Anything, mock.Anything, "p1", 0, false).Return(nil).Once()
+	mc.UserPhotoRepository.On("UpdateUserPhotoPosition", mock.Anything, mock.Anything, "p2", 1, true).Return(nil).Once()
+	mc.UserPhotoRepository.On("Commit", mock.Anything).Return(nil).Once()
+
+	updated, err := testUsecase.SetPrimaryPhoto(ctx, "user_1", "p2")
+	require.NoError(t, err)
+	assert.True(t, updated[1].IsPrimary)
+	assert.Equal(t, "p1", updated[0].UID)
+
+	mc.UserPhotoRepository.On("LockUserPhotos", mock.Anything, mock.Anything, "user_2").Return(photos("user_2", "p1"), nil).Once()
+	mc.UserPhotoRepository.On("Rollback", mock.Anything).Return(nil).Once()
+
+	_, err = testUsecase.SetPrimaryPhoto(ctx, "user_2", "p9")
+	assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+}
+
+func TestOpenPhoto(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	blobStore := blobservice.NewLocalBlobStore(t.TempDir())
+	testUsecase := photousecase.NewPhotoUsecase(mc.Config, mc.UserPhotoRepository, blobStore, imageservice.NewImageProcessor(constant.PhotoMaxSize, constant.PhotoThumbnailSize))
+
+	photo := photos("user_1", "p1")[0]
+	require.NoError(t, blobStore.Put(ctx, photo.ThumbnailKey, strings.NewReader("thumbnail")))
+
+	mc.UserPhotoRepository.On("GetUserPhoto", mock.Anything, "p1").Return(photo, nil).Once()
+	// deleted or banned users
+	mc.UserPhotoRepository.On("GetUserPhoto", mock.Anything, "p2").Return(nil, nil).Once()
+
+	_, body, err := testUsecase.OpenPhoto(ctx, "p1", true)
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "thumbnail", string(data))
+
+	_, _, err = testUsecase.OpenPhoto(ctx, "p2", false)
+	assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+}
+
+func TestAttachPhotos(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := photousecase.NewPhotoUsecase(mc.Config, mc.UserPhotoRepository, blobservice.NewLocalBlobStore(t.TempDir()), imageservice.NewImageProcessor(constant.PhotoMaxSize, constant.PhotoThumbnailSize))
+
+	users := []*model.User{{UID: "user_1"}, {UID: "user_2"}}
+	mc.UserPhotoRepository.On("GetUserPhotosByUserUIDs", mock.Anything, []string{"user_1", "user_2"}).
+		Return(photos("user_2", "p1", "p2"), nil).Once()
+
+	require.NoError(t, testUsecase.AttachPhotos(ctx, users))
+	assert.Empty(t, users[0].Photos)
+	assert.Len(t, users[1].Photos, 2)
+	assert.Equal(t, mc.Config.AppBaseURL+"/photos/p1", users[1].Photos[0].URL)
+}
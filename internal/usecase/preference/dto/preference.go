@@ -0,0 +1,10 @@
+package dto
+
+// UpdatePreference replaces the discovery preferences, a nil or empty field does not filter.
+type UpdatePreference struct {
+	UserUID       string
+	MinAge        *int
+	MaxAge        *int
+	Genders       []string
+	MaxDistanceKM *int
+}
@@ -0,0 +1,95 @@
+package preferenceusecase
+
+import (
+	"context"
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	userpreferencerepo "date-apps-be/internal/repository/user_preference"
+	"date-apps-be/internal/usecase/preference/dto"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+	"strings"
+)
+
+type (
+	PreferenceUsecase interface {
+		GetPreference(ctx context.Context, userUID string) (preference *model.UserPreference, err error)
+		UpdatePreference(ctx context.Context, d dto.UpdatePreference) (preference *model.UserPreference, err error)
+	}
+
+	preferenceUsecase struct {
+		userPreferenceRepo userpreferencerepo.UserPreferenceRepository
+	}
+)
+
+func NewPreferenceUsecase(userPreferenceRepo userpreferencerepo.UserPreferenceRepository) PreferenceUsecase {
+	return &preferenceUsecase{
+		userPreferenceRepo: userPreferenceRepo,
+	}
+}
+
+// GetPreference returns the discovery preferences of the user, without filters when none are set.
+func (p *preferenceUsecase) GetPreference(ctx context.Context, userUID string) (preference *model.UserPreference, err error) {
+	defer derrors.Wrap(&err, "GetPreference(%q)", userUID)
+
+	preference, err = p.userPreferenceRepo.GetUserPreference(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if preference == nil {
+		preference = &model.UserPreference{UserUID: userUID}
+	}
+
+	return preference, nil
+}
+
+// UpdatePreference replaces the discovery preferences of the user.
+func (p *preferenceUsecase) UpdatePreference(ctx context.Context, d dto.UpdatePreference) (preference *model.UserPreference, err error) {
+	defer derrors.Wrap(&err, "UpdatePreference(%q)", d.UserUID)
+
+	if d.MinAge != nil && (*d.MinAge < constant.MinAge || *d.MinAge > constant.MaxAge) {
+		return nil, derrors.New(derrors.InvalidArgument, "Minimum age must be between %d and %d", constant.MinAge, constant.MaxAge)
+	}
+
+	if d.MaxAge != nil && (*d.MaxAge < constant.MinAge || *d.MaxAge > constant.MaxAge) {
+		return nil, derrors.New(derrors.InvalidArgument, "Maximum age must be between %d and %d", constant.MinAge, constant.MaxAge)
+	}
+
+	if d.MinAge != nil && d.MaxAge != nil && *d.MinAge > *d.MaxAge {
+		return nil, derrors.New(derrors.InvalidArgument, "Minimum age must not be greater than maximum age")
+	}
+
+	if d.MaxDistanceKM != nil && (*d.MaxDistanceKM < constant.MinDistanceKM || *d.MaxDistanceKM > constant.MaxDistanceKM) {
+		return nil, derrors.New(derrors.InvalidArgument, "Maximum distance must be between %d and %d km", constant.MinDistanceKM, constant.MaxDistanceKM)
+	}
+
+	genders := datatype.StringList{}
+	seen := map[constant.Gender]bool{}
+	for _, value := range d.Genders {
+		gender, err := constant.ParseGender(value)
+		if err != nil {
+			return nil, derrors.New(derrors.InvalidArgument, "Gender must be one of %s", strings.Join(constant.GenderNames(), ", "))
+		}
+
+		if !seen[gender] {
+			seen[gender] = true
+			genders = append(genders, gender.String())
+		}
+	}
+
+	preference = &model.UserPreference{
+		UserUID:       d.UserUID,
+		MinAge:        d.MinAge,
+		MaxAge:        d.MaxAge,
+		Genders:       genders,
+		MaxDistanceKM: d.MaxDistanceKM,
+		UpdatedAt:     datatype.NewTimeNow(),
+	}
+
+	if err = p.userPreferenceRepo.UpsertUserPreference(ctx, preference); err != nil {
+		return nil, err
+	}
+
+	return preference, nil
+}
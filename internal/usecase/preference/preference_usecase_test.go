@@ -0,0 +1,118 @@
+package preferenceusecase_test
+
+import (
+	"context"
+	"testing"
+
+	"date-apps-be/internal/model"
+	"date-apps-be/internal/test"
+	preferenceusecase "date-apps-be/internal/usecase/preference"
+	"date-apps-be/internal/usecase/preference/dto"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestGetPreference(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := preferenceusecase.NewPreferenceUsecase(mc.UserPreferenceRepository)
+
+	mc.UserPreferenceRepository.On("GetUserPreference", mock.Anything, "user_1").Return(nil, nil).Once()
+
+	preference, err := testUsecase.GetPreference(ctx, "user_1")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.UserPreference{UserUID: "user_1"}, preference, "no filter when nothing is set")
+}
+
+func TestUpdatePreference(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := preferenceusecase.NewPreferenceUsecase(mc.UserPreferenceRepository)
+
+	var testCases = []struct {
+		caseName     string
+		params       dto.UpdatePreference
+		expectations func()
+		results      func(preference *model.UserPreference, err error)
+	}{
+		{
+			caseName: "UpdatePreference_Success",
+			params: dto.UpdatePreference{
+				UserUID: "user_1", MinAge: intPtr(25), MaxAge: intPtr(35),
+				Genders: []string{"female", "non_binary", "female"}, MaxDistanceKM: intPtr(50),
+			},
+			expectations: func() {
+				mc.UserPreferenceRepository.On("UpsertUserPreference", mock.Anything, mock.MatchedBy(func(p *model.UserPreference) bool {
+					return p.UserUID == "user_1" && *p.MinAge == 25 && *p.MaxAge == 35 && *p.MaxDistanceKM == 50
+				})).Return(nil).Once()
+			},
+			results: func(preference *model.UserPreference, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, datatype.StringList{"female", "non_binary"}, preference.Genders)
+			},
+		},
+		{
+			caseName: "UpdatePreference_NoFilter",
+			params:   dto.UpdatePreference{UserUID: "user_2"},
+			expectations: func() {
+				mc.UserPreferenceRepository.On("UpsertUserPreference", mock.Anything, mock.MatchedBy(func(p *model.UserPreference) bool {
+					return p.UserUID == "user_2" && p.MinAge == nil && p.MaxAge == nil && len(p.Genders) == 0 && p.MaxDistanceKM == nil
+				})).Return(nil).Once()
+			},
+			results: func(preference *model.UserPreference, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName: "UpdatePreference_MinorAge",
+			params:   dto.UpdatePreference{UserUID: "user_3", MinAge: intPtr(16)},
+			expectations: func() {
+			},
+			results: func(preference *model.UserPreference, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+		{
+			caseName: "UpdatePreference_InvertedAgeRange",
+			params:   dto.UpdatePreference{UserUID: "user_3", MinAge: intPtr(40), MaxAge: intPtr(30)},
+			expectations: func() {
+			},
+			results: func(preference *model.UserPreference, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+		{
+			caseName: "UpdatePreference_InvalidGender",
+			params:   dto.UpdatePreference{UserUID: "user_3", Genders: []string{"robot"}},
+			expectations: func() {
+			},
+			results: func(preference *model.UserPreference, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+		{
+			caseName: "UpdatePreference_InvalidDistance",
+			params:   dto.UpdatePreference{UserUID: "user_3", MaxDistanceKM: intPtr(0)},
+			expectations: func() {
+			},
+			results: func(preference *model.UserPreference, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			preference, err := testUsecase.UpdatePreference(ctx, testCase.params)
+			testCase.results(preference, err)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package roleusecase
+
+import (
+	"context"
+	"date-apps-be/internal/constant"
+	userrepo "date-apps-be/internal/repository/user"
+	userrolerepo "date-apps-be/internal/repository/user_role"
+	authservice "date-apps-be/internal/service/auth"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	RoleUsecase interface {
+		GetUserRoles(ctx context.Context, userUID string) (roles []string, err error)
+		GrantRole(ctx context.Context, userUID, role string) (err error)
+		RevokeRole(ctx context.Context, actorUID, userUID, role string) (err error)
+	}
+
+	roleUsecase struct {
+		userRepo     userrepo.UserRepository
+		userRoleRepo userrolerepo.UserRoleRepository
+		authService  authservice.AuthService
+	}
+)
+
+func NewRoleUsecase(userRepo userrepo.UserRepository, userRoleRepo userrolerepo.UserRoleRepository, authService authservice.AuthService) RoleUsecase {
+	return &roleUsecase{
+		userRepo:     userRepo,
+		userRoleRepo: userRoleRepo,
+		authService:  authService,
+	}
+}
+
+func (r *roleUsecase) GetUserRoles(ctx context.Context, userUID string) (roles []string, err error) {
+	defer derrors.Wrap(&err, "GetUserRoles(%q)", userUID)
+
+	return r.userRoleRepo.GetUserRoles(ctx, userUID)
+}
+
+// GrantRole gives an elevated role to the user, it is part of the access token after the next login or refresh.
+func (r *roleUsecase) GrantRole(ctx context.Context, userUID, role string) (err error) {
+	defer derrors.Wrap(&err, "GrantRole(%q, %q)", userUID, role)
+
+	if err = validateRole(role); err != nil {
+		return
+	}
+
+	user, err := r.userRepo.GetUserByUID(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		return derrors.New(derrors.NotFound, "User not found")
+	}
+
+	return r.userRoleRepo.AddUserRole(ctx, nil, userUID, role)
+}
+
+// RevokeRole removes a role and signs the user out of every device,
+// so the role does not stay in access tokens that were already issued.
+func (r *roleUsecase) RevokeRole(ctx context.Context, actorUID, userUID, role string) (err error) {
+	defer derrors.Wrap(&err, "RevokeRole(%q, %q)", userUID, role)
+
+	if err = validateRole(role); err != nil {
+		return
+	}
+
+	if actorUID == userUID && role == constant.RoleAdmin {
+		return derrors.New(derrors.InvalidArgument, "You can not revoke your own admin role")
+	}
+
+	deleted, err := r.userRoleRepo.DeleteUserRole(ctx, nil, userUID, role)
+	if err != nil {
+		return
+	}
+
+	if !deleted {
+		return derrors.New(derrors.NotFound, "User does not have the role")
+	}
+
+	return r.authService.RevokeUserTokens(ctx, userUID)
+}
+
+func validateRole(role string) error {
+	if _, ok := constant.RolePermissions[role]; !ok || role == constant.RoleUser {
+		return derrors.New(derrors.InvalidArgument, "Unknown role")
+	}
+	return nil
+}
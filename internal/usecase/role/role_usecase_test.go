@@ -0,0 +1,130 @@
+package roleusecase_test
+
+import (
+	"context"
+	"testing"
+
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+	"date-apps-be/internal/test"
+	roleusecase "date-apps-be/internal/usecase/role"
+	"date-apps-be/pkg/derrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGrantRole(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := roleusecase.NewRoleUsecase(mc.UserRepository, mc.UserRoleRepository, mc.AuthService)
+
+	var testCases = []struct {
+		caseName     string
+		userUID      string
+		role         string
+		expectations func()
+		results      func(err error)
+	}{
+		{
+			caseName: "GrantRole_Success",
+			userUID:  "user_1",
+			role:     constant.RoleModerator,
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "user_1").Return(&model.User{UID: "user_1"}, nil)
+				mc.UserRoleRepository.On("AddUserRole", mock.Anything, mock.Anything, "user_1", constant.RoleModerator).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName:     "GrantRole_UnknownRole",
+			userUID:      "user_1",
+			role:         "superuser",
+			expectations: func() {},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+		{
+			caseName: "GrantRole_UserNotFound",
+			userUID:  "user_2",
+			role:     constant.RoleAdmin,
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "user_2").Return(nil, nil)
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			err := testUsecase.GrantRole(ctx, testCase.userUID, testCase.role)
+			testCase.results(err)
+		})
+	}
+}
+
+func TestRevokeRole(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := roleusecase.NewRoleUsecase(mc.UserRepository, mc.UserRoleRepository, mc.AuthService)
+
+	var testCases = []struct {
+		caseName     string
+		actorUID     string
+		userUID      string
+		role         string
+		expectations func()
+		results      func(err error)
+	}{
+		{
+			caseName: "RevokeRole_SignsUserOut",
+			actorUID: "admin_1",
+			userUID:  "user_1",
+			role:     constant.RoleAdmin,
+			expectations: func() {
+				mc.UserRoleRepository.On("DeleteUserRole", mock.Anything, mock.Anything, "user_1", constant.RoleAdmin).Return(true, nil).Once()
+				mc.AuthService.On("RevokeUserTokens", mock.Anything, "user_1").Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName: "RevokeRole_NotGranted",
+			actorUID: "admin_1",
+			userUID:  "user_2",
+			role:     constant.RoleModerator,
+			expectations: func() {
+				mc.UserRoleRepository.On("DeleteUserRole", mock.Anything, mock.Anything, "user_2", constant.RoleModerator).Return(false, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+				mc.AuthService.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, "user_2")
+			},
+		},
+		{
+			caseName:     "RevokeRole_OwnAdminRole",
+			actorUID:     "admin_1",
+			userUID:      "admin_1",
+			role:         constant.RoleAdmin,
+			expectations: func() {},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			err := testUsecase.RevokeRole(ctx, testCase.actorUID, testCase.userUID, testCase.role)
+			testCase.results(err)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package sessionusecase
+
+import (
+	"context"
+	"date-apps-be/internal/model"
+	usersessionrepo "date-apps-be/internal/repository/user_session"
+	authservice "date-apps-be/internal/service/auth"
+	"date-apps-be/pkg/derrors"
+)
+
+type (
+	SessionUsecase interface {
+		GetSessions(ctx context.Context, userUID, currentSessionUID string) (sessions []*model.UserSession, err error)
+		RevokeSession(ctx context.Context, userUID, sessionUID string) (err error)
+	}
+
+	sessionUsecase struct {
+		userSessionRepo usersessionrepo.UserSessionRepository
+		authService     authservice.AuthService
+	}
+)
+
+func NewSessionUsecase(userSessionRepo usersessionrepo.UserSessionRepository, authService authservice.AuthService) SessionUsecase {
+	return &sessionUsecase{
+		userSessionRepo: userSessionRepo,
+		authService:     authService,
+	}
+}
+
+// GetSessions lists the devices the user is logged in on,
+// the session of the current access token is flagged.
+func (s *sessionUsecase) GetSessions(ctx context.Context, userUID, currentSessionUID string) (sessions []*model.UserSession, err error) {
+	defer derrors.Wrap(&err, "GetSessions(%q)", userUID)
+
+	sessions, err = s.userSessionRepo.GetActiveUserSessions(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	for _, session := range sessions {
+		session.Current = session.UID == currentSessionUID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs one device of the user out. Sessions of other users
+// are reported as not found so their IDs can not be probed.
+func (s *sessionUsecase) RevokeSession(ctx context.Context, userUID, sessionUID string) (err error) {
+	defer derrors.Wrap(&err, "RevokeSession(%q, %q)", userUID, sessionUID)
+
+	session, err := s.userSessionRepo.GetUserSession(ctx, sessionUID)
+	if err != nil {
+		return
+	}
+
+	if session == nil || session.UserUID != userUID || !session.RevokedAt.IsNil() {
+		return derrors.New(derrors.NotFound, "Session not found")
+	}
+
+	return s.authService.RevokeSession(ctx, sessionUID)
+}
@@ -0,0 +1,101 @@
+package sessionusecase_test
+
+import (
+	"context"
+	"testing"
+
+	"date-apps-be/internal/model"
+	"date-apps-be/internal/test"
+	sessionusecase "date-apps-be/internal/usecase/session"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetSessions(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := sessionusecase.NewSessionUsecase(mc.UserSessionRepository, mc.AuthService)
+
+	mc.UserSessionRepository.On("GetActiveUserSessions", mock.Anything, "user_1").Return([]*model.UserSession{
+		{UID: "session_1", UserUID: "user_1"},
+		{UID: "session_2", UserUID: "user_1"},
+	}, nil).Once()
+
+	sessions, err := testUsecase.GetSessions(ctx, "user_1", "session_2")
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current, "the session of the access token is flagged")
+}
+
+func TestRevokeSession(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := sessionusecase.NewSessionUsecase(mc.UserSessionRepository, mc.AuthService)
+
+	var testCases = []struct {
+		caseName     string
+		userUID      string
+		sessionUID   string
+		expectations func()
+		results      func(err error)
+	}{
+		{
+			caseName:   "RevokeSession_Success",
+			userUID:    "user_1",
+			sessionUID: "session_1",
+			expectations: func() {
+				mc.UserSessionRepository.On("GetUserSession", mock.Anything, "session_1").Return(&model.UserSession{UID: "session_1", UserUID: "user_1"}, nil).Once()
+				mc.AuthService.On("RevokeSession", mock.Anything, "session_1").Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName:   "RevokeSession_OtherUser",
+			userUID:    "user_1",
+			sessionUID: "session_2",
+			expectations: func() {
+				mc.UserSessionRepository.On("GetUserSession", mock.Anything, "session_2").Return(&model.UserSession{UID: "session_2", UserUID: "user_2"}, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+				mc.AuthService.AssertNotCalled(t, "RevokeSession", mock.Anything, "session_2")
+			},
+		},
+		{
+			caseName:   "RevokeSession_AlreadyRevoked",
+			userUID:    "user_1",
+			sessionUID: "session_3",
+			expectations: func() {
+				mc.UserSessionRepository.On("GetUserSession", mock.Anything, "session_3").Return(&model.UserSession{UID: "session_3", UserUID: "user_1", RevokedAt: datatype.NewTimeNow()}, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+			},
+		},
+		{
+			caseName:   "RevokeSession_NotFound",
+			userUID:    "user_1",
+			sessionUID: "session_4",
+			expectations: func() {
+				mc.UserSessionRepository.On("GetUserSession", mock.Anything, "session_4").Return(nil, nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			err := testUsecase.RevokeSession(ctx, testCase.userUID, testCase.sessionUID)
+			testCase.results(err)
+		})
+	}
+}
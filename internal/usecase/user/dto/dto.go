@@ -1,8 +1,73 @@
 package dto
 
+import "date-apps-be/internal/model"
+
 type CreateUser struct {
 	Email       string `json:"email"`
 	Name        string `json:"name"`
 	PhoneNumber string `json:"phone_number"`
 	Password    string `json:"password"`
+	DeviceID    string `json:"device_id"`
+	IPAddress   string `json:"ip_address"`
+	UserAgent   string `json:"user_agent"`
+}
+
+// Device returns the device the user registered from.
+func (c *CreateUser) Device() model.Device {
+	return model.Device{ID: c.DeviceID, UserAgent: c.UserAgent, IPAddress: c.IPAddress}
+}
+
+type Authenticate struct {
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+	Password    string `json:"password"`
+	DeviceID    string `json:"device_id"`
+	IPAddress   string `json:"ip_address"`
+	UserAgent   string `json:"user_agent"`
+}
+
+// Device returns the device the login comes from.
+func (a *Authenticate) Device() model.Device {
+	return model.Device{ID: a.DeviceID, UserAgent: a.UserAgent, IPAddress: a.IPAddress}
+}
+
+// Identifier returns the value the user logged in with, password logins are by email only.
+func (a *Authenticate) Identifier() string {
+	return a.Email
+}
+
+// Attempt returns the login attempt written to the login history.
+func (a *Authenticate) Attempt() model.LoginAttempt {
+	return model.LoginAttempt{Identifier: a.Identifier(), Device: a.Device()}
+}
+
+type VerifyMFALogin struct {
+	MFAToken  string `json:"mfa_token"`
+	Code      string `json:"code"`
+	DeviceID  string `json:"device_id"`
+	IPAddress string `json:"ip_address"`
+	UserAgent string `json:"user_agent"`
+}
+
+type AuthenticateOIDC struct {
+	Provider  string `json:"provider"`
+	IDToken   string `json:"id_token"`
+	Nonce     string `json:"nonce"`
+	DeviceID  string `json:"device_id"`
+	IPAddress string `json:"ip_address"`
+	UserAgent string `json:"user_agent"`
+}
+
+// UpdateProfile changes the fields that are set, an empty value clears an optional field.
+type UpdateProfile struct {
+	UserUID   string   `json:"user_uid"`
+	Name      *string  `json:"name"`
+	Birthdate *string  `json:"birthdate"`
+	Gender    *string  `json:"gender"`
+	Bio       *string  `json:"bio"`
+	JobTitle  *string  `json:"job_title"`
+	Company   *string  `json:"company"`
+	Education *string  `json:"education"`
+	HeightCM  *int     `json:"height_cm"`
+	Interests []string `json:"interests"`
 }
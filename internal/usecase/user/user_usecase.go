@@ -2,41 +2,69 @@ package userusecase
 
 import (
 	"context"
+	"date-apps-be/internal/constant"
 	"date-apps-be/internal/model"
 	userrepo "date-apps-be/internal/repository/user"
+	useridentityrepo "date-apps-be/internal/repository/user_identity"
 	userpackagerepo "date-apps-be/internal/repository/user_premium"
 	authservice "date-apps-be/internal/service/auth"
+	oidcservice "date-apps-be/internal/service/oidc"
+	accountusecase "date-apps-be/internal/usecase/account"
+	loginattemptusecase "date-apps-be/internal/usecase/login_attempt"
+	mfausecase "date-apps-be/internal/usecase/mfa"
 	"date-apps-be/internal/usecase/user/dto"
+	"date-apps-be/pkg/datatype"
 	"date-apps-be/pkg/derrors"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/segmentio/ksuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// dummyPasswordHash is compared against when the login identifier is unknown,
+// so the response time does not reveal whether an account exists.
+const dummyPasswordHash = "$2a$10$1997P4BudLHMBI2yrBsTjOd77VK6kmoP819nZ9mAu6NUW.rUOwkCS"
+
 type (
 	UserUsecase interface {
-		CreateUser(ctx context.Context, user *dto.CreateUser) (token string, err error)
+		CreateUser(ctx context.Context, user *dto.CreateUser) (token *model.AuthToken, err error)
+		Authenticate(ctx context.Context, d dto.Authenticate) (token *model.AuthToken, err error)
+		VerifyMFALogin(ctx context.Context, d dto.VerifyMFALogin) (token *model.AuthToken, err error)
+		AuthenticateOIDC(ctx context.Context, d dto.AuthenticateOIDC) (token *model.AuthToken, err error)
 		GetUser(ctx context.Context, userUID string) (user *model.User, err error)
 		GetUserByEmailOrPhoneNumber(ctx context.Context, email, phoneNumber string) (user *model.User, err error)
 		GetUserPackage(ctx context.Context, userUID string) (userPackage *model.UserPackage, err error)
+		UpdateProfile(ctx context.Context, d dto.UpdateProfile) (user *model.User, err error)
 	}
 
 	userUsecase struct {
-		authService authservice.AuthService
-		userRepo    userrepo.UserRepository
-		userPackage userpackagerepo.UserPremiumRepository
+		authService      authservice.AuthService
+		userRepo         userrepo.UserRepository
+		userPackage      userpackagerepo.UserPremiumRepository
+		loginAttempt     loginattemptusecase.LoginAttemptUsecase
+		accountUsecase   accountusecase.AccountUsecase
+		mfaUsecase       mfausecase.MFAUsecase
+		userIdentityRepo useridentityrepo.UserIdentityRepository
+		oidcService      oidcservice.OIDCService
 	}
 )
 
-func NewUserUsecase(userRepo userrepo.UserRepository, authService authservice.AuthService, userPackage userpackagerepo.UserPremiumRepository) UserUsecase {
+func NewUserUsecase(userRepo userrepo.UserRepository, authService authservice.AuthService, userPackage userpackagerepo.UserPremiumRepository, loginAttempt loginattemptusecase.LoginAttemptUsecase, accountUsecase accountusecase.AccountUsecase, mfaUsecase mfausecase.MFAUsecase, userIdentityRepo useridentityrepo.UserIdentityRepository, oidcService oidcservice.OIDCService) UserUsecase {
 	return &userUsecase{
-		userRepo:    userRepo,
-		authService: authService,
-		userPackage: userPackage,
+		userRepo:         userRepo,
+		authService:      authService,
+		userPackage:      userPackage,
+		loginAttempt:     loginAttempt,
+		accountUsecase:   accountUsecase,
+		mfaUsecase:       mfaUsecase,
+		userIdentityRepo: userIdentityRepo,
+		oidcService:      oidcService,
 	}
 }
 
-func (u *userUsecase) CreateUser(ctx context.Context, user *dto.CreateUser) (token string, err error) {
+func (u *userUsecase) CreateUser(ctx context.Context, user *dto.CreateUser) (token *model.AuthToken, err error) {
 	defer derrors.Wrap(&err, "CreateUser(%q)", user.Name)
 
 	uid := ksuid.New().String()
@@ -59,7 +87,11 @@ func (u *userUsecase) CreateUser(ctx context.Context, user *dto.CreateUser) (tok
 		return
 	}
 
-	token, err = u.authService.GenerateToken(uid)
+	// registration does not fail when the email can not be sent,
+	// the user can ask for a new verification email later
+	_ = u.accountUsecase.SendEmailVerification(ctx, userData)
+
+	token, err = u.authService.IssueToken(ctx, uid, user.Device())
 	if err != nil {
 		return
 	}
@@ -67,6 +99,280 @@ func (u *userUsecase) CreateUser(ctx context.Context, user *dto.CreateUser) (tok
 	return
 }
 
+// Authenticate verifies the user credentials and returns a token pair.
+// Failed attempts are counted per account and per IP address, an account
+// is locked for a while after too many consecutive failures.
+// When two-factor authentication is enabled only an MFA token is returned,
+// the login is recorded once the second factor is verified.
+func (u *userUsecase) Authenticate(ctx context.Context, d dto.Authenticate) (token *model.AuthToken, err error) {
+	defer derrors.Wrap(&err, "Authenticate(%q)", d.Identifier())
+
+	// looking up by both could match the email of one user and the phone number of another,
+	// phone numbers login with a one time code instead
+	if d.Email == "" || d.PhoneNumber != "" {
+		return nil, derrors.New(derrors.InvalidArgument, "Login with the email address only, phone numbers login with a one time code")
+	}
+
+	if err = u.loginAttempt.CheckIPAddress(ctx, d.IPAddress); err != nil {
+		return
+	}
+
+	user, err := u.userRepo.GetUserByEmailOrPhoneNumber(ctx, d.Email, "")
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		// keep the response time the same as a wrong password
+		_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(d.Password))
+		if err = u.loginAttempt.RecordLogin(ctx, d.Attempt(), nil, constant.LoginFailureUserNotFound); err != nil {
+			return
+		}
+		err = derrors.New(derrors.Unauthorized, "Invalid credentials")
+		return
+	}
+
+	if err = u.loginAttempt.CheckLocked(ctx, d.Attempt(), user); err != nil {
+		return
+	}
+
+	passwordHash := user.Password
+	if !user.HasPassword() {
+		// accounts created by social sign-in have no password, compare anyway to keep the response time
+		passwordHash = dummyPasswordHash
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(d.Password)) != nil || !user.HasPassword() {
+		if err = u.loginAttempt.RecordFailure(ctx, d.Attempt(), user, constant.LoginFailureInvalidPassword); err != nil {
+			return
+		}
+
+		return nil, derrors.New(derrors.Unauthorized, "Invalid credentials")
+	}
+
+	// only revealed once the password is verified
+	if err = u.checkUserStatus(ctx, d.Attempt(), user); err != nil {
+		return
+	}
+
+	token, err = u.mfaUsecase.IssueLoginToken(ctx, user.UID, d.Device())
+	if err != nil || token.MFARequired {
+		return
+	}
+
+	if err = u.loginAttempt.RecordLogin(ctx, d.Attempt(), user, ""); err != nil {
+		return
+	}
+
+	return token, nil
+}
+
+// VerifyMFALogin exchanges the MFA token from Authenticate and a TOTP or recovery code
+// for a token pair. Wrong codes count as failed logins of the account.
+func (u *userUsecase) VerifyMFALogin(ctx context.Context, d dto.VerifyMFALogin) (token *model.AuthToken, err error) {
+	defer derrors.Wrap(&err, "VerifyMFALogin")
+
+	if err = u.loginAttempt.CheckIPAddress(ctx, d.IPAddress); err != nil {
+		return
+	}
+
+	claims, err := u.authService.ParseMFAToken(ctx, d.MFAToken)
+	if err != nil {
+		return
+	}
+
+	user, err := u.userRepo.GetUserByUID(ctx, claims.UserUID)
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		return nil, derrors.New(derrors.Unauthorized, "Invalid token")
+	}
+
+	attempt := model.NewLoginAttempt(user, model.Device{ID: d.DeviceID, IPAddress: d.IPAddress, UserAgent: d.UserAgent})
+
+	if err = u.loginAttempt.CheckLocked(ctx, attempt, user); err != nil {
+		return
+	}
+
+	if err = u.checkUserStatus(ctx, attempt, user); err != nil {
+		return
+	}
+
+	valid, err := u.mfaUsecase.ValidateCode(ctx, user.UID, d.Code)
+	if err != nil {
+		return
+	}
+
+	if !valid {
+		if err = u.loginAttempt.RecordFailure(ctx, attempt, user, constant.LoginFailureInvalidMFACode); err != nil {
+			return
+		}
+
+		return nil, derrors.New(derrors.Unauthorized, "Invalid credentials")
+	}
+
+	// the MFA token can only be exchanged once
+	if err = u.authService.ConsumeMFAToken(ctx, claims); err != nil {
+		return
+	}
+
+	if err = u.loginAttempt.RecordLogin(ctx, attempt, user, ""); err != nil {
+		return
+	}
+
+	return u.authService.IssueToken(ctx, user.UID, attempt.Device)
+}
+
+// AuthenticateOIDC logs in with an ID token of an identity provider. The identity is linked
+// to the user with the same verified email, or a new user without password is created.
+func (u *userUsecase) AuthenticateOIDC(ctx context.Context, d dto.AuthenticateOIDC) (token *model.AuthToken, err error) {
+	defer derrors.Wrap(&err, "AuthenticateOIDC(%q)", d.Provider)
+
+	identity, err := u.oidcService.VerifyIDToken(ctx, d.Provider, d.IDToken, d.Nonce)
+	if err != nil {
+		return
+	}
+
+	user, err := u.getOrCreateOIDCUser(ctx, identity)
+	if err != nil {
+		return
+	}
+
+	attempt := model.LoginAttempt{
+		Identifier: identity.Email,
+		Device:     model.Device{ID: d.DeviceID, IPAddress: d.IPAddress, UserAgent: d.UserAgent},
+	}
+	if attempt.Identifier == "" {
+		attempt.Identifier = identity.Provider + ":" + identity.Subject
+	}
+
+	if err = u.loginAttempt.CheckLocked(ctx, attempt, user); err != nil {
+		return
+	}
+
+	if err = u.checkUserStatus(ctx, attempt, user); err != nil {
+		return
+	}
+
+	token, err = u.mfaUsecase.IssueLoginToken(ctx, user.UID, attempt.Device)
+	if err != nil || token.MFARequired {
+		return
+	}
+
+	if err = u.loginAttempt.RecordLogin(ctx, attempt, user, ""); err != nil {
+		return
+	}
+
+	return token, nil
+}
+
+func (u *userUsecase) getOrCreateOIDCUser(ctx context.Context, identity *oidcservice.Identity) (user *model.User, err error) {
+	userIdentity, err := u.userIdentityRepo.GetUserIdentity(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return
+	}
+
+	if userIdentity != nil {
+		user, err = u.userRepo.GetUserByUID(ctx, userIdentity.UserUID)
+		if err != nil {
+			return
+		}
+		if user == nil {
+			return nil, derrors.New(derrors.Unauthorized, "Invalid credentials")
+		}
+		return user, nil
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, derrors.New(derrors.InvalidArgument, "The identity provider did not share a verified email address")
+	}
+
+	user, err = u.userRepo.GetUserByEmailOrPhoneNumber(ctx, identity.Email, "")
+	if err != nil {
+		return
+	}
+
+	// linking to an unverified email would hand the account to whoever registered it first
+	if user != nil && !user.IsEmailVerified() {
+		return nil, derrors.New(derrors.Duplicate, "Email already registered, please login with your password and verify your email first")
+	}
+
+	isNewUser := user == nil
+	if isNewUser {
+		user = &model.User{
+			UID:   ksuid.New().String(),
+			Name:  identity.Name,
+			Email: &identity.Email,
+		}
+		if user.Name == "" {
+			user.Name, _, _ = strings.Cut(identity.Email, "@")
+		}
+	}
+
+	if err = u.linkOIDCIdentity(ctx, user, isNewUser, identity); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// linkOIDCIdentity stores the identity of user, a new user is created together with
+// a verified email because the identity provider already verified it.
+func (u *userUsecase) linkOIDCIdentity(ctx context.Context, user *model.User, isNewUser bool, identity *oidcservice.Identity) (err error) {
+	tx, err := u.userRepo.Begin()
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = u.userRepo.Rollback(tx)
+		}
+	}()
+
+	if isNewUser {
+		if _, err = u.userRepo.CreateUser(ctx, tx, user); err != nil {
+			return
+		}
+
+		if err = u.userRepo.UpdateEmailVerifiedAt(ctx, tx, user.UID, datatype.NewTimeNow()); err != nil {
+			return
+		}
+	}
+
+	err = u.userIdentityRepo.CreateUserIdentity(ctx, tx, &model.UserIdentity{
+		UID:      ksuid.New().String(),
+		UserUID:  user.UID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    &identity.Email,
+	})
+	if err != nil {
+		return
+	}
+
+	if err = u.userRepo.Commit(tx); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return nil
+}
+
+// checkUserStatus records a failed login and rejects the user when the account is suspended or banned.
+func (u *userUsecase) checkUserStatus(ctx context.Context, attempt model.LoginAttempt, user *model.User) (err error) {
+	statusErr := authservice.UserStatusError(user)
+	if statusErr == nil {
+		return nil
+	}
+
+	if err = u.loginAttempt.RecordLogin(ctx, attempt, user, constant.LoginFailureAccountSuspended); err != nil {
+		return
+	}
+
+	return statusErr
+}
+
 func (u *userUsecase) GetUser(ctx context.Context, userUID string) (user *model.User, err error) {
 	defer derrors.Wrap(&err, "GetUser(%q)", userUID)
 
@@ -86,6 +392,127 @@ func (u *userUsecase) GetUser(ctx context.Context, userUID string) (user *model.
 	return
 }
 
+// UpdateProfile validates and saves the fields of the dating profile that are set.
+func (u *userUsecase) UpdateProfile(ctx context.Context, d dto.UpdateProfile) (user *model.User, err error) {
+	defer derrors.Wrap(&err, "UpdateProfile(%q)", d.UserUID)
+
+	user, err = u.userRepo.GetUserByUID(ctx, d.UserUID)
+	if err != nil {
+		return
+	}
+
+	if user == nil {
+		return nil, derrors.New(derrors.NotFound, "User not found")
+	}
+
+	if err = applyProfile(user, d, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err = u.userRepo.UpdateUserProfile(ctx, nil, user); err != nil {
+		return
+	}
+
+	return user, nil
+}
+
+// applyProfile validates every field of the update and copies it to the user.
+func applyProfile(user *model.User, d dto.UpdateProfile, now time.Time) error {
+	if d.Name != nil {
+		name := strings.TrimSpace(*d.Name)
+		if name == "" || utf8.RuneCountInString(name) > constant.MaxNameLength {
+			return derrors.New(derrors.InvalidArgument, "Name must be between 1 and %d characters", constant.MaxNameLength)
+		}
+		user.Name = name
+	}
+
+	if d.Birthdate != nil {
+		user.Birthdate = datatype.Date{}
+		if *d.Birthdate != "" {
+			birthdate, err := datatype.ParseDate(*d.Birthdate, "UTC")
+			if err != nil {
+				return derrors.New(derrors.InvalidArgument, "Birthdate must be formatted as YYYY-MM-DD")
+			}
+			user.Birthdate = birthdate
+		}
+
+		if age := user.Age(now); !user.Birthdate.IsNil() && (age < constant.MinAge || age > constant.MaxAge) {
+			return derrors.New(derrors.InvalidArgument, "Age must be between %d and %d", constant.MinAge, constant.MaxAge)
+		}
+	}
+
+	if d.Gender != nil {
+		user.Gender = nil
+		if *d.Gender != "" {
+			gender, err := constant.ParseGender(*d.Gender)
+			if err != nil {
+				return derrors.New(derrors.InvalidArgument, "Gender must be one of %s", strings.Join(constant.GenderNames(), ", "))
+			}
+			user.Gender = &gender
+		}
+	}
+
+	texts := []struct {
+		field     string
+		value     *string
+		dest      **string
+		maxLength int
+	}{
+		{"Bio", d.Bio, &user.Bio, constant.MaxBioLength},
+		{"Job title", d.JobTitle, &user.JobTitle, constant.MaxJobLength},
+		{"Company", d.Company, &user.Company, constant.MaxJobLength},
+		{"Education", d.Education, &user.Education, constant.MaxEducationLength},
+	}
+	for _, text := range texts {
+		if text.value == nil {
+			continue
+		}
+
+		value := strings.TrimSpace(*text.value)
+		if utf8.RuneCountInString(value) > text.maxLength {
+			return derrors.New(derrors.InvalidArgument, "%s must be at most %d characters", text.field, text.maxLength)
+		}
+
+		*text.dest = nil
+		if value != "" {
+			*text.dest = &value
+		}
+	}
+
+	if d.HeightCM != nil {
+		user.HeightCM = nil
+		if height := *d.HeightCM; height != 0 {
+			if height < constant.MinHeightCM || height > constant.MaxHeightCM {
+				return derrors.New(derrors.InvalidArgument, "Height must be between %d and %d cm", constant.MinHeightCM, constant.MaxHeightCM)
+			}
+			user.HeightCM = &height
+		}
+	}
+
+	if d.Interests != nil {
+		interests := datatype.StringList{}
+		seen := map[string]bool{}
+		for _, interest := range d.Interests {
+			interest = strings.ToLower(strings.TrimSpace(interest))
+			if interest == "" || seen[interest] {
+				continue
+			}
+			if utf8.RuneCountInString(interest) > constant.MaxInterestLength {
+				return derrors.New(derrors.InvalidArgument, "An interest must be at most %d characters", constant.MaxInterestLength)
+			}
+			seen[interest] = true
+			interests = append(interests, interest)
+		}
+
+		if len(interests) > constant.MaxInterests {
+			return derrors.New(derrors.InvalidArgument, "At most %d interests are allowed", constant.MaxInterests)
+		}
+		user.Interests = interests
+	}
+
+	return nil
+}
+
 func (u *userUsecase) GetUserByEmailOrPhoneNumber(ctx context.Context, email, phoneNumber string) (user *model.User, err error) {
 	defer derrors.Wrap(&err, "GetUserByEmailOrPhoneNumber(%q , %q)", email, phoneNumber)
 
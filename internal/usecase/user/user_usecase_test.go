@@ -4,21 +4,30 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"date-apps-be/internal/constant"
 	"date-apps-be/internal/model"
+	oidcservice "date-apps-be/internal/service/oidc"
 	"date-apps-be/internal/test"
+	loginattemptusecase "date-apps-be/internal/usecase/login_attempt"
 	userusecase "date-apps-be/internal/usecase/user"
 	"date-apps-be/internal/usecase/user/dto"
 	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type params struct {
 	Result            *model.User
 	UserPackageResult *model.UserPackage
 	CreateUser        *dto.CreateUser
+	Authenticate      dto.Authenticate
+	VerifyMFALogin    dto.VerifyMFALogin
+	Claims            *model.JWTClaims
 	UserUID           string
 	Email             string
 	PhoneNumber       string
@@ -27,13 +36,13 @@ type params struct {
 func TestCreateUser(t *testing.T) {
 	mc := test.InitMockComponent(t)
 	ctx := context.Background()
-	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository)
+	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), mc.AccountUsecase, mc.MFAUsecase, mc.UserIdentityRepository, mc.OIDCService)
 
 	var testCases = []struct {
 		caseName     string
 		params       params
 		expectations func(params)
-		results      func(token string, err error)
+		results      func(token *model.AuthToken, err error)
 	}{
 		{
 			caseName: "CreateUser_Success",
@@ -48,14 +57,18 @@ func TestCreateUser(t *testing.T) {
 			expectations: func(params params) {
 				mc.UserRepository.On("CreateUser", mock.Anything, mock.Anything, mock.Anything).
 					Return(int64(1), nil)
-				mc.AuthService.On("GenerateToken", mock.Anything).
-					Return("test_token", nil)
+				mc.AccountUsecase.On("SendEmailVerification", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return *u.Email == params.CreateUser.Email
+				})).Return(nil)
+				mc.AuthService.On("IssueToken", mock.Anything, mock.Anything, mock.Anything).
+					Return(&model.AuthToken{Token: "test_token", RefreshToken: "test_refresh_token"}, nil)
 			},
-			results: func(token string, err error) {
+			results: func(token *model.AuthToken, err error) {
 				assert.NoError(t, err)
-				assert.Equal(t, "test_token", token)
+				assert.Equal(t, "test_token", token.Token)
+				assert.Equal(t, "test_refresh_token", token.RefreshToken)
 				mc.UserRepository.AssertCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
-				mc.AuthService.AssertCalled(t, "GenerateToken", mock.Anything)
+				mc.AuthService.AssertCalled(t, "IssueToken", mock.Anything, mock.Anything, mock.Anything)
 			},
 		},
 	}
@@ -69,10 +82,444 @@ func TestCreateUser(t *testing.T) {
 	}
 }
 
+func TestAuthenticate(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), mc.AccountUsecase, mc.MFAUsecase, mc.UserIdentityRepository, mc.OIDCService)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	lockedUntil := time.Now().Add(time.Hour)
+
+	var testCases = []struct {
+		caseName     string
+		params       params
+		expectations func(params)
+		results      func(token *model.AuthToken, err error)
+	}{
+		{
+			caseName: "Authenticate_Success",
+			params: params{
+				Authenticate: dto.Authenticate{Email: "john@example.com", Password: "password123", DeviceID: "device_1", IPAddress: "10.0.0.1"},
+				Result:       &model.User{UID: "user_1", Password: string(hashedPassword)},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.0.1", constant.FailedLoginWindow).Return(0, nil)
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "john@example.com", "").Return(params.Result, nil)
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return l.IsSuccess && *l.UserUID == "user_1"
+				})).Return(nil).Once()
+				mc.MFAUsecase.On("IssueLoginToken", mock.Anything, "user_1", model.Device{ID: "device_1", IPAddress: "10.0.0.1"}).Return(&model.AuthToken{Token: "test_token"}, nil)
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "test_token", token.Token)
+			},
+		},
+		{
+			caseName: "Authenticate_MFARequired",
+			params: params{
+				Authenticate: dto.Authenticate{Email: "mfa@example.com", Password: "password123", IPAddress: "10.0.0.7"},
+				Result:       &model.User{UID: "user_7", Password: string(hashedPassword)},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.0.7", constant.FailedLoginWindow).Return(0, nil)
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "mfa@example.com", "").Return(params.Result, nil)
+				mc.MFAUsecase.On("IssueLoginToken", mock.Anything, "user_7", mock.Anything).Return(&model.AuthToken{MFARequired: true, MFAToken: "mfa_token"}, nil)
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.NoError(t, err)
+				assert.True(t, token.MFARequired)
+				assert.Empty(t, token.Token)
+				// the login is recorded once the second factor is verified
+				mc.LoginHistoryRepository.AssertNotCalled(t, "CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return l.UserUID != nil && *l.UserUID == "user_7"
+				}))
+			},
+		},
+		{
+			caseName: "Authenticate_InvalidPassword",
+			params: params{
+				Authenticate: dto.Authenticate{Email: "jane@example.com", Password: "wrong", IPAddress: "10.0.0.2"},
+				Result:       &model.User{UID: "user_2", Password: string(hashedPassword)},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.0.2", constant.FailedLoginWindow).Return(0, nil)
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "jane@example.com", "").Return(params.Result, nil)
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && *l.FailureReason == constant.LoginFailureInvalidPassword
+				})).Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user_2", constant.FailedLoginWindow).Return(1, nil)
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, token)
+			},
+		},
+		{
+			caseName: "Authenticate_InvalidPasswordLocksAccount",
+			params: params{
+				Authenticate: dto.Authenticate{Email: "jack@example.com", Password: "wrong", IPAddress: "10.0.0.3"},
+				Result:       &model.User{UID: "user_3", Password: string(hashedPassword)},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.0.3", constant.FailedLoginWindow).Return(0, nil)
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "jack@example.com", "").Return(params.Result, nil)
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user_3", constant.FailedLoginWindow).Return(constant.MaxFailedLoginPerAccount, nil)
+				mc.UserRepository.On("UpdateLockedUntil", mock.Anything, mock.Anything, "user_3", mock.Anything).Return(nil)
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+				assert.Nil(t, token)
+			},
+		},
+		{
+			caseName: "Authenticate_AccountLocked",
+			params: params{
+				Authenticate: dto.Authenticate{Email: "jill@example.com", Password: "password123", IPAddress: "10.0.0.4"},
+				Result:       &model.User{UID: "user_4", Password: string(hashedPassword), LockedUntil: datatype.NewTime(&lockedUntil)},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.0.4", constant.FailedLoginWindow).Return(0, nil)
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "jill@example.com", "").Return(params.Result, nil)
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && *l.FailureReason == constant.LoginFailureAccountLocked
+				})).Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+				assert.Nil(t, token)
+			},
+		},
+		{
+			caseName: "Authenticate_AccountBanned",
+			params: params{
+				Authenticate: dto.Authenticate{Email: "joe@example.com", Password: "password123", IPAddress: "10.0.0.8"},
+				Result:       &model.User{UID: "user_8", Password: string(hashedPassword), Status: constant.UserStatusBanned},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.0.8", constant.FailedLoginWindow).Return(0, nil)
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "joe@example.com", "").Return(params.Result, nil)
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && *l.FailureReason == constant.LoginFailureAccountSuspended
+				})).Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				assert.Nil(t, token)
+			},
+		},
+		{
+			caseName: "Authenticate_UserNotFound",
+			params: params{
+				Authenticate: dto.Authenticate{Email: "nobody@example.com", Password: "password123", IPAddress: "10.0.0.5"},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.0.5", constant.FailedLoginWindow).Return(0, nil)
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "nobody@example.com", "").Return(nil, nil)
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return l.UserUID == nil && *l.FailureReason == constant.LoginFailureUserNotFound
+				})).Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, token)
+			},
+		},
+		{
+			caseName: "Authenticate_EmailAndPhoneNumber",
+			params: params{
+				Authenticate: dto.Authenticate{Email: "john@example.com", PhoneNumber: "6281100000001", Password: "password123", IPAddress: "10.0.0.9"},
+			},
+			expectations: func(params params) {},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, token)
+				mc.UserRepository.AssertNotCalled(t, "GetUserByEmailOrPhoneNumber", mock.Anything, "john@example.com", "6281100000001")
+			},
+		},
+		{
+			caseName: "Authenticate_PhoneNumberOnly",
+			params: params{
+				Authenticate: dto.Authenticate{PhoneNumber: "6281100000001", Password: "password123", IPAddress: "10.0.0.9"},
+			},
+			expectations: func(params params) {},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, token)
+				mc.UserRepository.AssertNotCalled(t, "GetUserByEmailOrPhoneNumber", mock.Anything, "", "6281100000001")
+			},
+		},
+		{
+			caseName: "Authenticate_TooManyFailuresFromIP",
+			params: params{
+				Authenticate: dto.Authenticate{Email: "john@example.com", Password: "password123", IPAddress: "10.0.0.6"},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.0.6", constant.FailedLoginWindow).Return(constant.MaxFailedLoginPerIP, nil)
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.TooManyRequests))
+				assert.Nil(t, token)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations(testCase.params)
+			token, err := testUsecase.Authenticate(ctx, testCase.params.Authenticate)
+			testCase.results(token, err)
+		})
+	}
+}
+
+func TestVerifyMFALogin(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), mc.AccountUsecase, mc.MFAUsecase, mc.UserIdentityRepository, mc.OIDCService)
+
+	lockedUntil := time.Now().Add(time.Hour)
+
+	var testCases = []struct {
+		caseName     string
+		params       params
+		expectations func(params)
+		results      func(token *model.AuthToken, err error)
+	}{
+		{
+			caseName: "VerifyMFALogin_Success",
+			params: params{
+				VerifyMFALogin: dto.VerifyMFALogin{MFAToken: "mfa_token_1", Code: "123456", DeviceID: "device_2", IPAddress: "10.0.1.1"},
+				Claims:         &model.JWTClaims{UserUID: "user_1", Scope: constant.TokenScopeMFAPending},
+				Result:         &model.User{UID: "user_1", Email: ptr("john@example.com")},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.1.1", constant.FailedLoginWindow).Return(0, nil)
+				mc.AuthService.On("ParseMFAToken", mock.Anything, "mfa_token_1").Return(params.Claims, nil)
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "user_1").Return(params.Result, nil)
+				mc.MFAUsecase.On("ValidateCode", mock.Anything, "user_1", "123456").Return(true, nil)
+				mc.AuthService.On("ConsumeMFAToken", mock.Anything, params.Claims).Return(nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return l.IsSuccess && *l.UserUID == "user_1" && l.Identifier == "john@example.com"
+				})).Return(nil).Once()
+				mc.AuthService.On("IssueToken", mock.Anything, "user_1", model.Device{ID: "device_2", IPAddress: "10.0.1.1"}).Return(&model.AuthToken{Token: "test_token"}, nil)
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "test_token", token.Token)
+			},
+		},
+		{
+			caseName: "VerifyMFALogin_InvalidCode",
+			params: params{
+				VerifyMFALogin: dto.VerifyMFALogin{MFAToken: "mfa_token_2", Code: "000000", IPAddress: "10.0.1.2"},
+				Claims:         &model.JWTClaims{UserUID: "user_2", Scope: constant.TokenScopeMFAPending},
+				Result:         &model.User{UID: "user_2", Email: ptr("jane@example.com")},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.1.2", constant.FailedLoginWindow).Return(0, nil)
+				mc.AuthService.On("ParseMFAToken", mock.Anything, "mfa_token_2").Return(params.Claims, nil)
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "user_2").Return(params.Result, nil)
+				mc.MFAUsecase.On("ValidateCode", mock.Anything, "user_2", "000000").Return(false, nil)
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && *l.FailureReason == constant.LoginFailureInvalidMFACode
+				})).Return(nil).Once()
+				mc.LoginHistoryRepository.On("CountFailedLoginByUserUID", mock.Anything, "user_2", constant.FailedLoginWindow).Return(1, nil)
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, token)
+				mc.AuthService.AssertNotCalled(t, "IssueToken", mock.Anything, "user_2", mock.Anything)
+			},
+		},
+		{
+			caseName: "VerifyMFALogin_AccountLocked",
+			params: params{
+				VerifyMFALogin: dto.VerifyMFALogin{MFAToken: "mfa_token_3", Code: "123456", IPAddress: "10.0.1.3"},
+				Claims:         &model.JWTClaims{UserUID: "user_3", Scope: constant.TokenScopeMFAPending},
+				Result:         &model.User{UID: "user_3", Email: ptr("jack@example.com"), LockedUntil: datatype.NewTime(&lockedUntil)},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.1.3", constant.FailedLoginWindow).Return(0, nil)
+				mc.AuthService.On("ParseMFAToken", mock.Anything, "mfa_token_3").Return(params.Claims, nil)
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "user_3").Return(params.Result, nil)
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return !l.IsSuccess && *l.FailureReason == constant.LoginFailureAccountLocked
+				})).Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Locked))
+				assert.Nil(t, token)
+			},
+		},
+		{
+			caseName: "VerifyMFALogin_TokenAlreadyUsed",
+			params: params{
+				VerifyMFALogin: dto.VerifyMFALogin{MFAToken: "mfa_token_5", Code: "123456", IPAddress: "10.0.1.5"},
+				Claims:         &model.JWTClaims{UserUID: "user_5", Scope: constant.TokenScopeMFAPending},
+				Result:         &model.User{UID: "user_5", Email: ptr("jill@example.com")},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.1.5", constant.FailedLoginWindow).Return(0, nil)
+				mc.AuthService.On("ParseMFAToken", mock.Anything, "mfa_token_5").Return(params.Claims, nil)
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "user_5").Return(params.Result, nil)
+				mc.MFAUsecase.On("ValidateCode", mock.Anything, "user_5", "123456").Return(true, nil)
+				// a concurrent request exchanged the token first
+				mc.AuthService.On("ConsumeMFAToken", mock.Anything, params.Claims).Return(derrors.New(derrors.Unauthorized, "Token has been revoked")).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, token)
+				mc.AuthService.AssertNotCalled(t, "IssueToken", mock.Anything, "user_5", mock.Anything)
+			},
+		},
+		{
+			caseName: "VerifyMFALogin_InvalidToken",
+			params: params{
+				VerifyMFALogin: dto.VerifyMFALogin{MFAToken: "access_token", Code: "123456", IPAddress: "10.0.1.4"},
+			},
+			expectations: func(params params) {
+				mc.LoginHistoryRepository.On("CountFailedLoginByIPAddress", mock.Anything, "10.0.1.4", constant.FailedLoginWindow).Return(0, nil)
+				mc.AuthService.On("ParseMFAToken", mock.Anything, "access_token").Return(nil, derrors.New(derrors.Unauthorized, "Invalid token"))
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Unauthorized))
+				assert.Nil(t, token)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations(testCase.params)
+			token, err := testUsecase.VerifyMFALogin(ctx, testCase.params.VerifyMFALogin)
+			testCase.results(token, err)
+		})
+	}
+}
+
+func TestAuthenticateOIDC(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), mc.AccountUsecase, mc.MFAUsecase, mc.UserIdentityRepository, mc.OIDCService)
+
+	var testCases = []struct {
+		caseName     string
+		idToken      string
+		expectations func()
+		results      func(token *model.AuthToken, err error)
+	}{
+		{
+			caseName: "AuthenticateOIDC_LinkedIdentity",
+			idToken:  "token_1",
+			expectations: func() {
+				mc.OIDCService.On("VerifyIDToken", mock.Anything, "google", "token_1", "").
+					Return(&oidcservice.Identity{Provider: "google", Subject: "sub_1", Email: "john@example.com", EmailVerified: true}, nil)
+				mc.UserIdentityRepository.On("GetUserIdentity", mock.Anything, "google", "sub_1").Return(&model.UserIdentity{UserUID: "user_1"}, nil)
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "user_1").Return(&model.User{UID: "user_1"}, nil)
+				mc.MFAUsecase.On("IssueLoginToken", mock.Anything, "user_1", mock.Anything).Return(&model.AuthToken{Token: "token_user_1"}, nil)
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return l.IsSuccess && *l.UserUID == "user_1"
+				})).Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "token_user_1", token.Token)
+			},
+		},
+		{
+			caseName: "AuthenticateOIDC_LinkByVerifiedEmail",
+			idToken:  "token_2",
+			expectations: func() {
+				mc.OIDCService.On("VerifyIDToken", mock.Anything, "google", "token_2", "").
+					Return(&oidcservice.Identity{Provider: "google", Subject: "sub_2", Email: "jane@example.com", EmailVerified: true}, nil)
+				mc.UserIdentityRepository.On("GetUserIdentity", mock.Anything, "google", "sub_2").Return(nil, nil)
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "jane@example.com", "").
+					Return(&model.User{UID: "user_2", EmailVerifiedAt: datatype.NewTimeNow()}, nil)
+				mc.UserRepository.On("Begin").Return(nil, nil).Once()
+				mc.UserIdentityRepository.On("CreateUserIdentity", mock.Anything, mock.Anything, mock.MatchedBy(func(i *model.UserIdentity) bool {
+					return i.UserUID == "user_2" && i.Subject == "sub_2"
+				})).Return(nil).Once()
+				mc.UserRepository.On("Commit", mock.Anything).Return(nil).Once()
+				mc.MFAUsecase.On("IssueLoginToken", mock.Anything, "user_2", mock.Anything).Return(&model.AuthToken{Token: "token_user_2"}, nil)
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.MatchedBy(func(l *model.LoginHistory) bool {
+					return l.IsSuccess && *l.UserUID == "user_2"
+				})).Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "token_user_2", token.Token)
+				mc.UserRepository.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			caseName: "AuthenticateOIDC_UnverifiedLocalEmail",
+			idToken:  "token_3",
+			expectations: func() {
+				mc.OIDCService.On("VerifyIDToken", mock.Anything, "google", "token_3", "").
+					Return(&oidcservice.Identity{Provider: "google", Subject: "sub_3", Email: "jack@example.com", EmailVerified: true}, nil)
+				mc.UserIdentityRepository.On("GetUserIdentity", mock.Anything, "google", "sub_3").Return(nil, nil)
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "jack@example.com", "").Return(&model.User{UID: "user_3"}, nil)
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Duplicate))
+				assert.Nil(t, token)
+			},
+		},
+		{
+			caseName: "AuthenticateOIDC_UnverifiedProviderEmail",
+			idToken:  "token_4",
+			expectations: func() {
+				mc.OIDCService.On("VerifyIDToken", mock.Anything, "google", "token_4", "").
+					Return(&oidcservice.Identity{Provider: "google", Subject: "sub_4", Email: "jill@example.com"}, nil)
+				mc.UserIdentityRepository.On("GetUserIdentity", mock.Anything, "google", "sub_4").Return(nil, nil)
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, token)
+			},
+		},
+		{
+			caseName: "AuthenticateOIDC_CreateUser",
+			idToken:  "token_5",
+			expectations: func() {
+				mc.OIDCService.On("VerifyIDToken", mock.Anything, "google", "token_5", "").
+					Return(&oidcservice.Identity{Provider: "google", Subject: "sub_5", Email: "new.user@example.com", EmailVerified: true}, nil)
+				mc.UserIdentityRepository.On("GetUserIdentity", mock.Anything, "google", "sub_5").Return(nil, nil)
+				mc.UserRepository.On("GetUserByEmailOrPhoneNumber", mock.Anything, "new.user@example.com", "").Return(nil, nil)
+				mc.UserRepository.On("Begin").Return(nil, nil).Once()
+				mc.UserRepository.On("CreateUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return u.Name == "new.user" && u.Password == "" && *u.Email == "new.user@example.com"
+				})).Return(int64(1), nil).Once()
+				mc.UserRepository.On("UpdateEmailVerifiedAt", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				mc.UserIdentityRepository.On("CreateUserIdentity", mock.Anything, mock.Anything, mock.MatchedBy(func(i *model.UserIdentity) bool {
+					return i.Subject == "sub_5"
+				})).Return(nil).Once()
+				mc.UserRepository.On("Commit", mock.Anything).Return(nil).Once()
+				mc.MFAUsecase.On("IssueLoginToken", mock.Anything, mock.Anything, mock.Anything).Return(&model.AuthToken{Token: "token_new_user"}, nil).Once()
+				mc.LoginHistoryRepository.On("CreateLoginHistory", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			results: func(token *model.AuthToken, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "token_new_user", token.Token)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			token, err := testUsecase.AuthenticateOIDC(ctx, dto.AuthenticateOIDC{Provider: "google", IDToken: testCase.idToken, IPAddress: "10.0.2.1"})
+			testCase.results(token, err)
+		})
+	}
+}
+
 func TestGetUser(t *testing.T) {
 	mc := test.InitMockComponent(t)
 	ctx := context.Background()
-	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository)
+	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), mc.AccountUsecase, mc.MFAUsecase, mc.UserIdentityRepository, mc.OIDCService)
 
 	var testCases = []struct {
 		caseName     string
@@ -128,7 +575,7 @@ func TestGetUser(t *testing.T) {
 func TestGetUserByEmailOrPhoneNumber(t *testing.T) {
 	mc := test.InitMockComponent(t)
 	ctx := context.Background()
-	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository)
+	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), mc.AccountUsecase, mc.MFAUsecase, mc.UserIdentityRepository, mc.OIDCService)
 
 	var testCases = []struct {
 		caseName     string
@@ -170,7 +617,7 @@ func TestGetUserByEmailOrPhoneNumber(t *testing.T) {
 func TestGetUserPackage(t *testing.T) {
 	mc := test.InitMockComponent(t)
 	ctx := context.Background()
-	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository)
+	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), mc.AccountUsecase, mc.MFAUsecase, mc.UserIdentityRepository, mc.OIDCService)
 
 	var testCases = []struct {
 		caseName     string
@@ -229,6 +676,153 @@ func TestGetUserPackage(t *testing.T) {
 	}
 }
 
+func TestUpdateProfile(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := userusecase.NewUserUsecase(mc.UserRepository, mc.AuthService, mc.UserPremiumRepository, loginattemptusecase.NewLoginAttemptUsecase(mc.LoginHistoryRepository, mc.UserRepository), mc.AccountUsecase, mc.MFAUsecase, mc.UserIdentityRepository, mc.OIDCService)
+
+	adultBirthdate := time.Now().AddDate(-30, 0, 0).Format("2006-01-02")
+	minorBirthdate := time.Now().AddDate(-17, 0, 0).Format("2006-01-02")
+
+	var testCases = []struct {
+		caseName     string
+		params       dto.UpdateProfile
+		expectations func()
+		results      func(user *model.User, err error)
+	}{
+		{
+			caseName: "UpdateProfile_Success",
+			params: dto.UpdateProfile{
+				UserUID:   "profile_1",
+				Name:      ptr(" Jane "),
+				Birthdate: ptr(adultBirthdate),
+				Gender:    ptr("female"),
+				Bio:       ptr("Coffee first"),
+				HeightCM:  intPtr(170),
+				Interests: []string{"Hiking", " hiking", "music", ""},
+			},
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "profile_1").Return(&model.User{UID: "profile_1", Name: "Jane Doe", JobTitle: ptr("Engineer")}, nil).Once()
+				mc.UserRepository.On("UpdateUserProfile", mock.Anything, mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return u.UID == "profile_1"
+				})).Return(nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Jane", user.Name)
+				assert.Equal(t, 30, user.Age(time.Now()))
+				assert.Equal(t, constant.GenderFemale, *user.Gender)
+				assert.Equal(t, "Coffee first", *user.Bio)
+				assert.Equal(t, "Engineer", *user.JobTitle, "fields that are not set are unchanged")
+				assert.Equal(t, 170, *user.HeightCM)
+				assert.Equal(t, datatype.StringList{"hiking", "music"}, user.Interests)
+			},
+		},
+		{
+			caseName: "UpdateProfile_ClearFields",
+			params: dto.UpdateProfile{
+				UserUID:   "profile_2",
+				Bio:       ptr(""),
+				HeightCM:  intPtr(0),
+				Interests: []string{},
+			},
+			expectations: func() {
+				height := 180
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "profile_2").Return(&model.User{
+					UID: "profile_2", Bio: ptr("Old bio"), HeightCM: &height, Interests: datatype.StringList{"chess"},
+				}, nil).Once()
+				mc.UserRepository.On("UpdateUserProfile", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, user.Bio)
+				assert.Nil(t, user.HeightCM)
+				assert.Empty(t, user.Interests)
+			},
+		},
+		{
+			caseName: "UpdateProfile_Underage",
+			params:   dto.UpdateProfile{UserUID: "profile_3", Birthdate: ptr(minorBirthdate)},
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "profile_3").Return(&model.User{UID: "profile_3"}, nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, user)
+			},
+		},
+		{
+			caseName: "UpdateProfile_InvalidBirthdate",
+			params:   dto.UpdateProfile{UserUID: "profile_3", Birthdate: ptr("23/04/1995")},
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "profile_3").Return(&model.User{UID: "profile_3"}, nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, user)
+			},
+		},
+		{
+			caseName: "UpdateProfile_InvalidGender",
+			params:   dto.UpdateProfile{UserUID: "profile_3", Gender: ptr("robot")},
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "profile_3").Return(&model.User{UID: "profile_3"}, nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, user)
+			},
+		},
+		{
+			caseName: "UpdateProfile_HeightOutOfRange",
+			params:   dto.UpdateProfile{UserUID: "profile_3", HeightCM: intPtr(300)},
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "profile_3").Return(&model.User{UID: "profile_3"}, nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, user)
+			},
+		},
+		{
+			caseName: "UpdateProfile_TooManyInterests",
+			params: dto.UpdateProfile{UserUID: "profile_3", Interests: []string{
+				"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k",
+			}},
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "profile_3").Return(&model.User{UID: "profile_3"}, nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, user)
+			},
+		},
+		{
+			caseName: "UpdateProfile_EmptyName",
+			params:   dto.UpdateProfile{UserUID: "profile_3", Name: ptr("  ")},
+			expectations: func() {
+				mc.UserRepository.On("GetUserByUID", mock.Anything, "profile_3").Return(&model.User{UID: "profile_3"}, nil).Once()
+			},
+			results: func(user *model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+				assert.Nil(t, user)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations()
+			user, err := testUsecase.UpdateProfile(ctx, testCase.params)
+			testCase.results(user, err)
+		})
+	}
+}
+
 func ptr(s string) *string {
 	return &s
 }
+
+func intPtr(i int) *int {
+	return &i
+}
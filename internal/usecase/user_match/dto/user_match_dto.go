@@ -1,6 +1,9 @@
 package dto
 
-import "date-apps-be/internal/constant"
+import (
+	"date-apps-be/internal/constant"
+	"date-apps-be/internal/model"
+)
 
 type GetUserMatches struct {
 	UserUID   string                 `json:"user_uid"`
@@ -8,3 +11,13 @@ type GetUserMatches struct {
 	Limit     uint64                 `json:"limit"`
 	MatchType constant.UserMatchType `json:"match_type"`
 }
+
+// GetAvailableUsers pages through the discovery deck, Origin and MaxDistanceKM narrow the
+// search down to the geohash cells around the user when both are set.
+type GetAvailableUsers struct {
+	UserUID       string
+	Page          uint64
+	Limit         uint64
+	Origin        *model.UserLocation
+	MaxDistanceKM *int
+}
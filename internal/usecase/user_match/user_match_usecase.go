@@ -2,71 +2,462 @@ package usermatchusecase
 
 import (
 	"context"
+	"database/sql"
+	"date-apps-be/infrastructure/config"
 	"date-apps-be/internal/constant"
 	"date-apps-be/internal/model"
+	hiddenpairrepo "date-apps-be/internal/repository/hidden_pair"
+	mutualmatchrepo "date-apps-be/internal/repository/mutual_match"
+	userrepo "date-apps-be/internal/repository/user"
+	userlocationrepo "date-apps-be/internal/repository/user_location"
 	userMatchRepo "date-apps-be/internal/repository/user_match"
+	userpreferencerepo "date-apps-be/internal/repository/user_preference"
+	userrewindrepo "date-apps-be/internal/repository/user_rewind"
+	mailservice "date-apps-be/internal/service/mail"
+	photousecase "date-apps-be/internal/usecase/photo"
 	userusecase "date-apps-be/internal/usecase/user"
 	"date-apps-be/internal/usecase/user_match/dto"
+	"date-apps-be/pkg/datatype"
 	"date-apps-be/pkg/derrors"
+	"fmt"
+
+	"github.com/segmentio/ksuid"
 )
 
 type (
 	UserMatchUsecase interface {
-		CreateUserMatch(ctx context.Context, userMatch *model.UserMatch) (err error)
+		CreateUserMatch(ctx context.Context, userMatch *model.UserMatch) (mutualMatch *model.MutualMatch, err error)
+		GetMutualMatches(ctx context.Context, userUID string, page, limit uint64) (mutualMatches []*model.MutualMatch, err error)
+		RewindUserMatch(ctx context.Context, userUID string) (rewind *model.UserRewind, rewindsLeft int, err error)
+		GetLikesReceived(ctx context.Context, userUID string, page, limit uint64) (users []*model.User, total int, locked bool, err error)
+		Unmatch(ctx context.Context, userUID, matchUID string) (err error)
 		GetUserMatches(ctx context.Context, d dto.GetUserMatches) (userMatches []*model.UserMatch, err error)
 		GetAvailableUsers(ctx context.Context, userUID string, page, limit uint64) (users []*model.User, quotaLeft int, err error)
 		GetUserMatchTodayByUserUIDAndMatchUID(ctx context.Context, userUID, matchUID string) (userMatch *model.UserMatch, err error)
 	}
 
 	userMatchUsecase struct {
-		repo        userMatchRepo.UserMatchRepository
-		userUsecase userusecase.UserUsecase
+		conf         *config.Config
+		repo         userMatchRepo.UserMatchRepository
+		userUsecase  userusecase.UserUsecase
+		photoUsecase photousecase.PhotoUsecase
+
+		userRepo           userrepo.UserRepository
+		userLocationRepo   userlocationrepo.UserLocationRepository
+		userPreferenceRepo userpreferencerepo.UserPreferenceRepository
+		mutualMatchRepo    mutualmatchrepo.MutualMatchRepository
+		userRewindRepo     userrewindrepo.UserRewindRepository
+		hiddenPairRepo     hiddenpairrepo.HiddenPairRepository
+		mailer             mailservice.Mailer
 	}
 )
 
-func NewUserMatchUsecase(repo userMatchRepo.UserMatchRepository, userUsecase userusecase.UserUsecase) UserMatchUsecase {
+// Dependencies are what NewUserMatchUsecase needs, every field is required.
+type Dependencies struct {
+	Conf               *config.Config
+	UserMatchRepo      userMatchRepo.UserMatchRepository
+	UserUsecase        userusecase.UserUsecase
+	PhotoUsecase       photousecase.PhotoUsecase
+	UserRepo           userrepo.UserRepository
+	UserLocationRepo   userlocationrepo.UserLocationRepository
+	UserPreferenceRepo userpreferencerepo.UserPreferenceRepository
+	MutualMatchRepo    mutualmatchrepo.MutualMatchRepository
+	UserRewindRepo     userrewindrepo.UserRewindRepository
+	HiddenPairRepo     hiddenpairrepo.HiddenPairRepository
+	Mailer             mailservice.Mailer
+}
+
+func NewUserMatchUsecase(deps Dependencies) UserMatchUsecase {
 	return &userMatchUsecase{
-		repo:        repo,
-		userUsecase: userUsecase,
+		conf:               deps.Conf,
+		repo:               deps.UserMatchRepo,
+		userUsecase:        deps.UserUsecase,
+		photoUsecase:       deps.PhotoUsecase,
+		userRepo:           deps.UserRepo,
+		userLocationRepo:   deps.UserLocationRepo,
+		userPreferenceRepo: deps.UserPreferenceRepo,
+		mutualMatchRepo:    deps.MutualMatchRepo,
+		userRewindRepo:     deps.UserRewindRepo,
+		hiddenPairRepo:     deps.HiddenPairRepo,
+		mailer:             deps.Mailer,
 	}
 }
 
-// CreateUserMatch creates a new user match record in the database.
-func (u *userMatchUsecase) CreateUserMatch(ctx context.Context, userMatch *model.UserMatch) (err error) {
+// CreateUserMatch creates a new user match record in the database, mutualMatch is set
+// when the swipe is a like and the other user liked the user before.
+// A super-like uses its own quota and notifies the other user.
+func (u *userMatchUsecase) CreateUserMatch(ctx context.Context, userMatch *model.UserMatch) (mutualMatch *model.MutualMatch, err error) {
 	defer derrors.Wrap(&err, "CreateUserMatch(%q)", userMatch.UserUID)
 
-	maxMatchPerDay := constant.MaxMatchPerDay
+	if userMatch.UserUID == userMatch.MatchUID {
+		return nil, derrors.New(derrors.InvalidArgument, "You can not match with yourself")
+	}
 
 	userPackage, err := u.userUsecase.GetUserPackage(ctx, userMatch.UserUID)
 	if err != nil {
 		return
 	}
 
+	mutualMatch, err = u.createUserMatch(ctx, userMatch, userPackage)
+	if err != nil {
+		return
+	}
+
+	if userMatch.IsSuperLike() {
+		// the swipe is saved already, the notice is best effort
+		_ = u.notifySuperLike(ctx, userMatch.MatchUID)
+	}
+
+	return mutualMatch, nil
+}
+
+// checkMatchQuota counts in tx, the swipe pair must be locked so concurrent swipes of the user can not exceed the quota.
+func (u *userMatchUsecase) checkMatchQuota(ctx context.Context, tx *sql.Tx, userUID string, userPackage *model.UserPackage) (err error) {
+	maxMatchPerDay := constant.MaxMatchPerDay
+
+	if userPackage != nil && !userPackage.IsExpiredPackage() {
+		if userPackage.Quota == 0 {
+			// unlimited matches for premium users with quota=0
+			return nil
+		}
+		maxMatchPerDay = int(userPackage.Quota)
+	}
+
+	// get total matches today for user
+	total, err := u.repo.GetTotalUserMatchToday(ctx, tx, userUID)
+	if err != nil {
+		return
+	}
+
+	if total >= maxMatchPerDay {
+		return derrors.New(derrors.Forbidden, "Quota match per day reached")
+	}
+	return nil
+}
+
+// checkSuperLikeQuota counts in tx like checkMatchQuota.
+func (u *userMatchUsecase) checkSuperLikeQuota(ctx context.Context, tx *sql.Tx, userUID string, userPackage *model.UserPackage) (err error) {
+	maxSuperLikePerDay := constant.MaxSuperLikePerDay
+
+	if userPackage != nil && !userPackage.IsExpiredPackage() && userPackage.PremiumConfig != nil && userPackage.PremiumConfig.SuperLikeQuota > 0 {
+		maxSuperLikePerDay = int(userPackage.PremiumConfig.SuperLikeQuota)
+	}
+
+	total, err := u.repo.GetTotalSuperLikeToday(ctx, tx, userUID)
+	if err != nil {
+		return
+	}
+
+	if total >= maxSuperLikePerDay {
+		return derrors.New(derrors.Forbidden, "Quota super like per day reached")
+	}
+	return nil
+}
+
+// notifySuperLike tells the user that someone super-liked them, the super-liker is revealed in the discovery deck.
+// Banned, suspended and deleted users are not notified.
+func (u *userMatchUsecase) notifySuperLike(ctx context.Context, userUID string) (err error) {
+	user, err := u.userUsecase.GetUser(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if user.Email == nil || user.IsBanned() || user.IsSuspended() || user.IsDeleted() {
+		return nil
+	}
+
+	return u.mailer.Send(ctx, mailservice.Mail{
+		To:      *user.Email,
+		Subject: "Someone super liked you",
+		Body:    fmt.Sprintf("Hi %s,\n\nSomeone super liked you! They are waiting at the front of your discovery deck.", user.Name),
+	})
+}
+
+func (u *userMatchUsecase) createUserMatch(ctx context.Context, userMatch *model.UserMatch, userPackage *model.UserPackage) (mutualMatch *model.MutualMatch, err error) {
+	tx, err := u.repo.Begin()
+	if err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = u.repo.Rollback(tx)
+		}
+	}()
+
+	if err = u.repo.LockSwipePair(ctx, tx, userMatch.UserUID, userMatch.MatchUID); err != nil {
+		return
+	}
+
+	if err = u.checkMatchTarget(ctx, tx, userMatch.MatchUID); err != nil {
+		return
+	}
+
+	if userMatch.IsSuperLike() {
+		err = u.checkSuperLikeQuota(ctx, tx, userMatch.UserUID, userPackage)
+	} else {
+		err = u.checkMatchQuota(ctx, tx, userMatch.UserUID, userPackage)
+	}
+	if err != nil {
+		return
+	}
+
+	hidden, err := u.hiddenPairRepo.IsPairHidden(ctx, tx, userMatch.UserUID, userMatch.MatchUID)
+	if err != nil {
+		return
+	}
+
+	if hidden {
+		return nil, derrors.New(derrors.Forbidden, "You can no longer match with this user")
+	}
+
+	if err = u.repo.CreateUserMatch(ctx, tx, userMatch); err != nil {
+		return
+	}
+
+	if userMatch.IsLike() {
+		mutualMatch, err = u.createMutualMatch(ctx, tx, userMatch)
+		if err != nil {
+			return
+		}
+	}
+
+	if err = u.repo.Commit(tx); err != nil {
+		return nil, derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return mutualMatch, nil
+}
+
+// checkMatchTarget rejects swipes on users that do not exist or are hidden from discovery,
+// the user is read in tx after the swipe pair is locked so a concurrent ban or deletion is seen.
+func (u *userMatchUsecase) checkMatchTarget(ctx context.Context, tx *sql.Tx, matchUID string) (err error) {
+	target, err := u.userRepo.GetUserForUpdate(ctx, tx, matchUID)
+	if err != nil {
+		return
+	}
+
+	if target == nil || target.IsDeleted() || target.IsBanned() || target.IsSuspended() {
+		return derrors.New(derrors.NotFound, "User not found")
+	}
+
+	return nil
+}
+
+// createMutualMatch matches the pair when the latest swipe of the other user is a like,
+// mutualMatch is nil when the pair did not match now.
+func (u *userMatchUsecase) createMutualMatch(ctx context.Context, tx *sql.Tx, userMatch *model.UserMatch) (mutualMatch *model.MutualMatch, err error) {
+	reciprocal, err := u.repo.GetLatestUserMatch(ctx, tx, userMatch.MatchUID, userMatch.UserUID)
+	if err != nil || reciprocal == nil || !reciprocal.IsLike() {
+		return nil, err
+	}
+
+	existing, err := u.mutualMatchRepo.GetMutualMatch(ctx, tx, userMatch.UserUID, userMatch.MatchUID)
+	if err != nil || existing != nil {
+		return nil, err
+	}
+
+	mutualMatch = &model.MutualMatch{
+		UID:       ksuid.New().String(),
+		UserUID:   userMatch.UserUID,
+		MatchUID:  userMatch.MatchUID,
+		CreatedAt: datatype.NewTimeNow(),
+	}
+
+	if err = u.mutualMatchRepo.CreateMutualMatch(ctx, tx, mutualMatch); err != nil {
+		return nil, err
+	}
+
+	return mutualMatch, nil
+}
+
+// RewindUserMatch deletes the most recent swipe of the user, which gives its quota back.
+// A rewound super-like keeps using its quota since the other user was notified already.
+// Only packages with a rewind quota include it, the swipe must be inside RewindWindow
+// and a like that turned into a mutual match cannot be rewound.
+func (u *userMatchUsecase) RewindUserMatch(ctx context.Context, userUID string) (rewind *model.UserRewind, rewindsLeft int, err error) {
+	defer derrors.Wrap(&err, "RewindUserMatch(%q)", userUID)
+
+	userPackage, err := u.userUsecase.GetUserPackage(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if userPackage == nil || userPackage.IsExpiredPackage() || userPackage.PremiumConfig == nil || userPackage.PremiumConfig.RewindQuota == 0 {
+		return nil, 0, derrors.New(derrors.Forbidden, "Your package does not include rewind")
+	}
+	maxRewindPerDay := int(userPackage.PremiumConfig.RewindQuota)
+
+	tx, err := u.repo.Begin()
+	if err != nil {
+		return nil, 0, derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
+		if err != nil {
+			_ = u.repo.Rollback(tx)
+		}
+	}()
+
+	if err = u.repo.LockUser(ctx, tx, userUID); err != nil {
+		return
+	}
+
+	total, err := u.userRewindRepo.GetTotalUserRewindToday(ctx, tx, userUID)
+	if err != nil {
+		return
+	}
+
+	if total >= maxRewindPerDay {
+		return nil, 0, derrors.New(derrors.Forbidden, "Quota rewind per day reached")
+	}
+
+	userMatch, err := u.repo.GetRewindableUserMatch(ctx, tx, userUID, constant.RewindWindow)
+	if err != nil {
+		return
+	}
+
+	if userMatch == nil {
+		return nil, 0, derrors.New(derrors.NotFound, "There is no recent swipe to rewind")
+	}
+
+	if userMatch.IsLike() {
+		mutualMatch, err := u.mutualMatchRepo.GetMutualMatch(ctx, tx, userUID, userMatch.MatchUID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if mutualMatch != nil {
+			return nil, 0, derrors.New(derrors.Forbidden, "You already matched with this user")
+		}
+	}
+
+	if err = u.repo.DeleteUserMatch(ctx, tx, userMatch.ID); err != nil {
+		return
+	}
+
+	rewind = &model.UserRewind{
+		UserUID:   userUID,
+		MatchUID:  userMatch.MatchUID,
+		MatchType: userMatch.MatchType,
+		SwipedAt:  userMatch.CreatedAt,
+	}
+
+	if err = u.userRewindRepo.CreateUserRewind(ctx, tx, rewind); err != nil {
+		return
+	}
+
+	if err = u.repo.Commit(tx); err != nil {
+		return nil, 0, derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return rewind, maxRewindPerDay - total - 1, nil
+}
+
+// GetLikesReceived returns the users who liked the user and the user has not swiped on yet.
+// Only users with an active package see who they are, for everyone else locked is true and users is nil.
+func (u *userMatchUsecase) GetLikesReceived(ctx context.Context, userUID string, page, limit uint64) (users []*model.User, total int, locked bool, err error) {
+	defer derrors.Wrap(&err, "GetLikesReceived(%q)", userUID)
+
+	userPackage, err := u.userUsecase.GetUserPackage(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	total, err = u.repo.GetTotalLikesReceived(ctx, userUID)
+	if err != nil {
+		return
+	}
+
 	if userPackage == nil || userPackage.IsExpiredPackage() {
-		// get total matches today for user
-		total, err := u.repo.GetTotalUserMatchToday(ctx, userMatch.UserUID)
+		return nil, total, true, nil
+	}
+
+	users, err = u.repo.GetLikesReceived(ctx, userUID, page, limit)
+	if err != nil {
+		return
+	}
+
+	if err = u.photoUsecase.AttachPhotos(ctx, users); err != nil {
+		return nil, 0, false, err
+	}
+
+	return users, total, false, nil
+}
+
+// Unmatch removes the mutual match of the pair and hides them from each other for good.
+// Unmatching a pair that is hidden already succeeds so the request can be retried.
+func (u *userMatchUsecase) Unmatch(ctx context.Context, userUID, matchUID string) (err error) {
+	defer derrors.Wrap(&err, "Unmatch(%q, %q)", userUID, matchUID)
+
+	if userUID == matchUID {
+		return derrors.New(derrors.InvalidArgument, "You can not unmatch yourself")
+	}
+
+	tx, err := u.repo.Begin()
+	if err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Begin")
+	}
+	defer func() {
 		if err != nil {
-			return err
+			_ = u.repo.Rollback(tx)
 		}
+	}()
 
-		if total >= maxMatchPerDay {
-			err = derrors.New(derrors.Forbidden, "Quota match per day reached")
-			return err
+	// a like of the other user can not match the pair again while it is being removed
+	if err = u.repo.LockSwipePair(ctx, tx, userUID, matchUID); err != nil {
+		return
+	}
+
+	deleted, err := u.mutualMatchRepo.DeleteMutualMatch(ctx, tx, userUID, matchUID)
+	if err != nil {
+		return
+	}
+
+	if deleted {
+		err = u.hiddenPairRepo.CreateHiddenPair(ctx, tx, &model.HiddenPair{
+			UserUID:  userUID,
+			MatchUID: matchUID,
+			HiddenBy: userUID,
+		})
+		if err != nil {
+			return
 		}
-	} else if userPackage.Quota > 0 {
-		maxMatchPerDay = int(userPackage.Quota)
-		total, err := u.repo.GetTotalUserMatchToday(ctx, userMatch.UserUID)
+	} else {
+		hidden, err := u.hiddenPairRepo.IsPairHidden(ctx, tx, userUID, matchUID)
 		if err != nil {
 			return err
 		}
 
-		if total >= maxMatchPerDay {
-			err = derrors.New(derrors.Forbidden, "Quota match per day reached")
-			return err
+		if !hidden {
+			return derrors.New(derrors.NotFound, "You have not matched with this user")
 		}
 	}
 
-	return u.repo.CreateUserMatch(ctx, userMatch)
+	if err = u.repo.Commit(tx); err != nil {
+		return derrors.WrapStack(err, derrors.Unknown, "Commit")
+	}
+
+	return nil
+}
+
+// GetMutualMatches returns the users the user matched with, newest first.
+func (u *userMatchUsecase) GetMutualMatches(ctx context.Context, userUID string, page, limit uint64) (mutualMatches []*model.MutualMatch, err error) {
+	defer derrors.Wrap(&err, "GetMutualMatches(%q)", userUID)
+
+	mutualMatches, err = u.mutualMatchRepo.GetMutualMatches(ctx, userUID, page, limit)
+	if err != nil {
+		return
+	}
+
+	users := make([]*model.User, 0, len(mutualMatches))
+	for _, mutualMatch := range mutualMatches {
+		users = append(users, mutualMatch.Match)
+	}
+
+	if err = u.photoUsecase.AttachPhotos(ctx, users); err != nil {
+		return nil, err
+	}
+
+	return mutualMatches, nil
 }
 
 // GetUserMatches retrieves a list of user matches
@@ -77,12 +468,23 @@ func (u *userMatchUsecase) GetUserMatches(ctx context.Context, d dto.GetUserMatc
 
 // GetAvailableUsers retrieves a list of users that the current user has not matched with today
 func (u *userMatchUsecase) GetAvailableUsers(ctx context.Context, userUID string, page, limit uint64) (users []*model.User, quotaLeft int, err error) {
+	if u.conf.RequireEmailVerification {
+		user, err := u.userUsecase.GetUser(ctx, userUID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if !user.IsEmailVerified() {
+			return nil, 0, derrors.New(derrors.Forbidden, "Please verify your email to start matching")
+		}
+	}
+
 	userPackage, err := u.userUsecase.GetUserPackage(ctx, userUID)
 	if err != nil {
 		return
 	}
 
-	total, err := u.repo.GetTotalUserMatchToday(ctx, userUID)
+	total, err := u.repo.GetTotalUserMatchToday(ctx, nil, userUID)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -93,7 +495,7 @@ func (u *userMatchUsecase) GetAvailableUsers(ctx context.Context, userUID string
 	if userPackage != nil && !userPackage.IsExpiredPackage() {
 		if userPackage.Quota == 0 {
 			// Unlimited matches for premium users with quota=0
-			users, err = u.repo.GetAvailableUsers(ctx, userUID, page, limit)
+			users, err = u.getAvailableUsers(ctx, userUID, page, limit)
 			if err != nil {
 				return
 			}
@@ -109,13 +511,49 @@ func (u *userMatchUsecase) GetAvailableUsers(ctx context.Context, userUID string
 		return nil, 0, err
 	}
 
-	users, err = u.repo.GetAvailableUsers(ctx, userUID, page, limit)
+	users, err = u.getAvailableUsers(ctx, userUID, page, limit)
 	if err != nil {
 		return
 	}
 	return
 }
 
+// getAvailableUsers returns a page of the discovery deck with the photos of every user.
+func (u *userMatchUsecase) getAvailableUsers(ctx context.Context, userUID string, page, limit uint64) (users []*model.User, err error) {
+	d := dto.GetAvailableUsers{
+		UserUID: userUID,
+		Page:    page,
+		Limit:   limit,
+	}
+
+	// the distance itself is filtered in the query, the origin only narrows the search down
+	d.Origin, err = u.userLocationRepo.GetUserLocation(ctx, userUID)
+	if err != nil {
+		return
+	}
+
+	if d.Origin != nil {
+		preference, err := u.userPreferenceRepo.GetUserPreference(ctx, userUID)
+		if err != nil {
+			return nil, err
+		}
+
+		if preference != nil {
+			d.MaxDistanceKM = preference.MaxDistanceKM
+		}
+	}
+
+	users, err = u.repo.GetAvailableUsers(ctx, d)
+	if err != nil {
+		return
+	}
+
+	if err = u.photoUsecase.AttachPhotos(ctx, users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (u *userMatchUsecase) GetUserMatchTodayByUserUIDAndMatchUID(ctx context.Context, userUID, matchUID string) (userMatch *model.UserMatch, err error) {
 	return u.repo.GetUserMatchTodayByUserUIDAndMatchUID(ctx, userUID, matchUID)
 }
@@ -2,18 +2,40 @@ package usermatchusecase_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"date-apps-be/internal/constant"
 	"date-apps-be/internal/model"
+	mailservice "date-apps-be/internal/service/mail"
 	"date-apps-be/internal/test"
 	usermatchusecase "date-apps-be/internal/usecase/user_match"
+	"date-apps-be/internal/usecase/user_match/dto"
+	"date-apps-be/pkg/datatype"
+	"date-apps-be/pkg/derrors"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+func newUserMatchUsecase(mc *test.MockComponent, mailer mailservice.Mailer) usermatchusecase.UserMatchUsecase {
+	return usermatchusecase.NewUserMatchUsecase(usermatchusecase.Dependencies{
+		Conf:               mc.Config,
+		UserMatchRepo:      mc.UserMatchRepository,
+		UserUsecase:        mc.UserUsecase,
+		PhotoUsecase:       mc.PhotoUsecase,
+		UserRepo:           mc.UserRepository,
+		UserLocationRepo:   mc.UserLocationRepository,
+		UserPreferenceRepo: mc.UserPreferenceRepository,
+		MutualMatchRepo:    mc.MutualMatchRepository,
+		UserRewindRepo:     mc.UserRewindRepository,
+		HiddenPairRepo:     mc.HiddenPairRepository,
+		Mailer:             mailer,
+	})
+}
+
 type params struct {
 	UserMatch   *model.UserMatch
 	UserPackage *model.UserPackage
@@ -24,20 +46,21 @@ type params struct {
 func TestCreateUserMatch(t *testing.T) {
 	mc := test.InitMockComponent(t)
 	ctx := context.Background()
-	testUsecase := usermatchusecase.NewUserMatchUsecase(mc.UserMatchRepository, mc.UserUsecase)
+	testUsecase := newUserMatchUsecase(mc, mailservice.NewMemoryMailer())
 
 	var testCases = []struct {
 		caseName     string
 		params       params
 		expectations func(params)
-		results      func(err error)
+		results      func(mutualMatch *model.MutualMatch, err error)
 	}{
 		{
 			caseName: "CreateUserMatch_Success",
 			params: params{
 				UserMatch: &model.UserMatch{
-					UserUID:  "user123",
-					MatchUID: "match123",
+					UserUID:   "user123",
+					MatchUID:  "match123",
+					MatchType: constant.UserMatchTypePass,
 				},
 				UserPackage: &model.UserPackage{
 					UserUID: "user123",
@@ -45,12 +68,128 @@ func TestCreateUserMatch(t *testing.T) {
 				},
 			},
 			expectations: func(params params) {
-				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserMatch.UserUID).Return(params.UserPackage, nil)
-				mc.UserMatchRepository.On("GetTotalUserMatchToday", mock.Anything, params.UserMatch.UserUID).Return(0, nil)
-				mc.UserMatchRepository.On("CreateUserMatch", mock.Anything, mock.Anything).Return(nil)
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserMatch.UserUID).Return(params.UserPackage, nil).Once()
+				mc.UserMatchRepository.On("GetTotalUserMatchToday", mock.Anything, mock.Anything, params.UserMatch.UserUID).Return(0, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, "user123", "match123").Return(nil).Once()
+				mc.UserRepository.On("GetUserForUpdate", mock.Anything, mock.Anything, "match123").Return(&model.User{UID: "match123"}, nil).Once()
+				mc.HiddenPairRepository.On("IsPairHidden", mock.Anything, mock.Anything, "user123", "match123").Return(false, nil).Once()
+				mc.UserMatchRepository.On("CreateUserMatch", mock.Anything, mock.Anything, params.UserMatch).Return(nil).Once()
+				mc.UserMatchRepository.On("Commit", mock.Anything).Return(nil).Once()
 			},
-			results: func(err error) {
-				assert.Nil(t, err)
+			results: func(mutualMatch *model.MutualMatch, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, mutualMatch)
+				mc.UserMatchRepository.AssertNotCalled(t, "GetLatestUserMatch", mock.Anything, mock.Anything, "match123", "user123")
+			},
+		},
+		{
+			caseName: "CreateUserMatch_LikeNotReciprocated",
+			params: params{
+				UserMatch: &model.UserMatch{
+					UserUID:   "user123",
+					MatchUID:  "match456",
+					MatchType: constant.UserMatchTypeLike,
+				},
+			},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserMatch.UserUID).Return(nil, nil).Once()
+				mc.UserMatchRepository.On("GetTotalUserMatchToday", mock.Anything, mock.Anything, params.UserMatch.UserUID).Return(0, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, "user123", "match456").Return(nil).Once()
+				mc.UserRepository.On("GetUserForUpdate", mock.Anything, mock.Anything, "match456").Return(&model.User{UID: "match456"}, nil).Once()
+				mc.HiddenPairRepository.On("IsPairHidden", mock.Anything, mock.Anything, "user123", "match456").Return(false, nil).Once()
+				mc.UserMatchRepository.On("CreateUserMatch", mock.Anything, mock.Anything, params.UserMatch).Return(nil).Once()
+				mc.UserMatchRepository.On("GetLatestUserMatch", mock.Anything, mock.Anything, "match456", "user123").
+					Return(&model.UserMatch{UserUID: "match456", MatchUID: "user123", MatchType: constant.UserMatchTypePass}, nil).Once()
+				mc.UserMatchRepository.On("Commit", mock.Anything).Return(nil).Once()
+			},
+			results: func(mutualMatch *model.MutualMatch, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, mutualMatch)
+			},
+		},
+		{
+			caseName: "CreateUserMatch_Mutual",
+			params: params{
+				UserMatch: &model.UserMatch{
+					UserUID:   "user123",
+					MatchUID:  "match789",
+					MatchType: constant.UserMatchTypeLike,
+				},
+			},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserMatch.UserUID).Return(nil, nil).Once()
+				mc.UserMatchRepository.On("GetTotalUserMatchToday", mock.Anything, mock.Anything, params.UserMatch.UserUID).Return(0, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, "user123", "match789").Return(nil).Once()
+				mc.UserRepository.On("GetUserForUpdate", mock.Anything, mock.Anything, "match789").Return(&model.User{UID: "match789"}, nil).Once()
+				mc.HiddenPairRepository.On("IsPairHidden", mock.Anything, mock.Anything, "user123", "match789").Return(false, nil).Once()
+				mc.UserMatchRepository.On("CreateUserMatch", mock.Anything, mock.Anything, params.UserMatch).Return(nil).Once()
+				mc.UserMatchRepository.On("GetLatestUserMatch", mock.Anything, mock.Anything, "match789", "user123").
+					Return(&model.UserMatch{UserUID: "match789", MatchUID: "user123", MatchType: constant.UserMatchTypeLike}, nil).Once()
+				mc.MutualMatchRepository.On("GetMutualMatch", mock.Anything, mock.Anything, "user123", "match789").Return(nil, nil).Once()
+				mc.MutualMatchRepository.On("CreateMutualMatch", mock.Anything, mock.Anything, mock.MatchedBy(func(m *model.MutualMatch) bool {
+					return m.UID != "" && m.UserUID == "user123" && m.MatchUID == "match789"
+				})).Return(nil).Once()
+				mc.UserMatchRepository.On("Commit", mock.Anything).Return(nil).Once()
+			},
+			results: func(mutualMatch *model.MutualMatch, err error) {
+				assert.NoError(t, err)
+				if assert.NotNil(t, mutualMatch) {
+					assert.Equal(t, "match789", mutualMatch.OtherUID("user123"))
+				}
+			},
+		},
+		{
+			caseName: "CreateUserMatch_AlreadyMatched",
+			params: params{
+				UserMatch: &model.UserMatch{
+					UserUID:   "user123",
+					MatchUID:  "match000",
+					MatchType: constant.UserMatchTypeLike,
+				},
+			},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserMatch.UserUID).Return(nil, nil).Once()
+				mc.UserMatchRepository.On("GetTotalUserMatchToday", mock.Anything, mock.Anything, params.UserMatch.UserUID).Return(0, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, "user123", "match000").Return(nil).Once()
+				mc.UserRepository.On("GetUserForUpdate", mock.Anything, mock.Anything, "match000").Return(&model.User{UID: "match000"}, nil).Once()
+				mc.HiddenPairRepository.On("IsPairHidden", mock.Anything, mock.Anything, "user123", "match000").Return(false, nil).Once()
+				mc.UserMatchRepository.On("CreateUserMatch", mock.Anything, mock.Anything, params.UserMatch).Return(nil).Once()
+				mc.UserMatchRepository.On("GetLatestUserMatch", mock.Anything, mock.Anything, "match000", "user123").
+					Return(&model.UserMatch{UserUID: "match000", MatchUID: "user123", MatchType: constant.UserMatchTypeLike}, nil).Once()
+				mc.MutualMatchRepository.On("GetMutualMatch", mock.Anything, mock.Anything, "user123", "match000").
+					Return(&model.MutualMatch{UID: "existing", UserUID: "match000", MatchUID: "user123"}, nil).Once()
+				mc.UserMatchRepository.On("Commit", mock.Anything).Return(nil).Once()
+			},
+			results: func(mutualMatch *model.MutualMatch, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, mutualMatch)
+			},
+		},
+		{
+			caseName: "CreateUserMatch_Unmatched",
+			params: params{
+				UserMatch: &model.UserMatch{
+					UserUID:   "user123",
+					MatchUID:  "hidden123",
+					MatchType: constant.UserMatchTypeLike,
+				},
+			},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserMatch.UserUID).Return(nil, nil).Once()
+				mc.UserMatchRepository.On("GetTotalUserMatchToday", mock.Anything, mock.Anything, params.UserMatch.UserUID).Return(0, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, "user123", "hidden123").Return(nil).Once()
+				mc.UserRepository.On("GetUserForUpdate", mock.Anything, mock.Anything, "hidden123").Return(&model.User{UID: "hidden123"}, nil).Once()
+				mc.HiddenPairRepository.On("IsPairHidden", mock.Anything, mock.Anything, "user123", "hidden123").Return(true, nil).Once()
+				mc.UserMatchRepository.On("Rollback", mock.Anything).Return(nil).Once()
+			},
+			results: func(mutualMatch *model.MutualMatch, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				assert.Nil(t, mutualMatch)
 			},
 		},
 		{
@@ -60,17 +199,417 @@ func TestCreateUserMatch(t *testing.T) {
 					UserUID:  "user123",
 					MatchUID: "match123",
 				},
+			},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserMatch.UserUID).Return(nil, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, "user123", "match123").Return(nil).Once()
+				mc.UserRepository.On("GetUserForUpdate", mock.Anything, mock.Anything, "match123").Return(&model.User{UID: "match123"}, nil).Once()
+				mc.UserMatchRepository.On("GetTotalUserMatchToday", mock.Anything, mock.Anything, params.UserMatch.UserUID).Return(constant.MaxMatchPerDay, nil).Once()
+				mc.UserMatchRepository.On("Rollback", mock.Anything).Return(nil).Once()
+			},
+			results: func(mutualMatch *model.MutualMatch, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				assert.Nil(t, mutualMatch)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations(testCase.params)
+			mutualMatch, err := testUsecase.CreateUserMatch(ctx, testCase.params.UserMatch)
+			testCase.results(mutualMatch, err)
+		})
+	}
+}
+
+func TestCreateUserMatch_Target(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := newUserMatchUsecase(mc, mailservice.NewMemoryMailer())
+
+	suspendedUntil := time.Now().Add(24 * time.Hour)
+
+	var testCases = []struct {
+		caseName string
+		matchUID string
+		target   *model.User
+	}{
+		{
+			caseName: "Target_Missing",
+			matchUID: "missing123",
+		},
+		{
+			caseName: "Target_Deleted",
+			matchUID: "deleted123",
+			target:   &model.User{UID: "deleted123", Status: constant.UserStatusActive, DeletedAt: datatype.NewTimeNow()},
+		},
+		{
+			caseName: "Target_Banned",
+			matchUID: "banned123",
+			target:   &model.User{UID: "banned123", Status: constant.UserStatusBanned},
+		},
+		{
+			caseName: "Target_Suspended",
+			matchUID: "suspended123",
+			target:   &model.User{UID: "suspended123", Status: constant.UserStatusSuspended, SuspendedUntil: datatype.NewTime(&suspendedUntil)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			userMatch := &model.UserMatch{UserUID: "user123", MatchUID: testCase.matchUID, MatchType: constant.UserMatchTypeLike}
+
+			mc.UserUsecase.On("GetUserPackage", mock.Anything, "user123").Return(nil, nil).Once()
+			mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+			mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, "user123", testCase.matchUID).Return(nil).Once()
+			mc.UserRepository.On("GetUserForUpdate", mock.Anything, mock.Anything, testCase.matchUID).Return(testCase.target, nil).Once()
+			mc.UserMatchRepository.On("Rollback", mock.Anything).Return(nil).Once()
+
+			mutualMatch, err := testUsecase.CreateUserMatch(ctx, userMatch)
+			assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+			assert.Nil(t, mutualMatch)
+			mc.UserMatchRepository.AssertNotCalled(t, "CreateUserMatch", mock.Anything, mock.Anything, userMatch)
+		})
+	}
+
+	t.Run("Target_Self", func(t *testing.T) {
+		userMatch := &model.UserMatch{UserUID: "user123", MatchUID: "user123", MatchType: constant.UserMatchTypeLike}
+
+		mutualMatch, err := testUsecase.CreateUserMatch(ctx, userMatch)
+		assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+		assert.Nil(t, mutualMatch)
+		mc.UserMatchRepository.AssertNotCalled(t, "LockSwipePair", mock.Anything, mock.Anything, "user123", "user123")
+	})
+}
+
+func TestCreateUserMatch_SuperLike(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	mailer := mailservice.NewMemoryMailer()
+	testUsecase := newUserMatchUsecase(mc, mailer)
+
+	today := datatype.NewDateNow()
+	endedAt := today.AddDate(0, 0, 30)
+	email := "match@example.com"
+
+	var testCases = []struct {
+		caseName     string
+		params       params
+		expectations func(params)
+		results      func(err error)
+	}{
+		{
+			caseName: "SuperLike_Free",
+			params: params{
+				UserMatch: &model.UserMatch{UserUID: "user123", MatchUID: "match123", MatchType: constant.UserMatchTypeSuperLike},
+			},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, "user123").Return(nil, nil).Once()
+				mc.UserMatchRepository.On("GetTotalSuperLikeToday", mock.Anything, mock.Anything, "user123").Return(0, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, "user123", "match123").Return(nil).Once()
+				mc.UserRepository.On("GetUserForUpdate", mock.Anything, mock.Anything, "match123").Return(&model.User{UID: "match123"}, nil).Once()
+				mc.HiddenPairRepository.On("IsPairHidden", mock.Anything, mock.Anything, "user123", "match123").Return(false, nil).Once()
+				mc.UserMatchRepository.On("CreateUserMatch", mock.Anything, mock.Anything, params.UserMatch).Return(nil).Once()
+				mc.UserMatchRepository.On("GetLatestUserMatch", mock.Anything, mock.Anything, "match123", "user123").Return(nil, nil).Once()
+				mc.UserMatchRepository.On("Commit", mock.Anything).Return(nil).Once()
+				mc.UserUsecase.On("GetUser", mock.Anything, "match123").Return(&model.User{UID: "match123", Name: "Match", Email: &email}, nil).Once()
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+				mc.UserMatchRepository.AssertNotCalled(t, "GetTotalUserMatchToday", mock.Anything, mock.Anything, "user123")
+				if mails := mailer.Mails(); assert.Len(t, mails, 1) {
+					assert.Equal(t, email, mails[0].To)
+				}
+			},
+		},
+		{
+			caseName: "SuperLike_TargetSuspended",
+			params: params{
+				UserMatch: &model.UserMatch{UserUID: "user321", MatchUID: "suspended123", MatchType: constant.UserMatchTypeSuperLike},
+			},
+			expectations: func(params params) {
+				suspendedUntil := time.Now().Add(time.Hour)
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, "user321").Return(nil, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, "user321", "suspended123").Return(nil).Once()
+				mc.UserRepository.On("GetUserForUpdate", mock.Anything, mock.Anything, "suspended123").Return(&model.User{UID: "suspended123"}, nil).Once()
+				mc.UserMatchRepository.On("GetTotalSuperLikeToday", mock.Anything, mock.Anything, "user321").Return(0, nil).Once()
+				mc.HiddenPairRepository.On("IsPairHidden", mock.Anything, mock.Anything, "user321", "suspended123").Return(false, nil).Once()
+				mc.UserMatchRepository.On("CreateUserMatch", mock.Anything, mock.Anything, params.UserMatch).Return(nil).Once()
+				mc.UserMatchRepository.On("GetLatestUserMatch", mock.Anything, mock.Anything, "suspended123", "user321").Return(nil, nil).Once()
+				mc.UserMatchRepository.On("Commit", mock.Anything).Return(nil).Once()
+				mc.UserUsecase.On("GetUser", mock.Anything, "suspended123").Return(&model.User{
+					UID: "suspended123", Email: &email, Status: constant.UserStatusSuspended, SuspendedUntil: datatype.NewTime(&suspendedUntil),
+				}, nil).Once()
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+				// only the mail of SuperLike_Free
+				assert.Len(t, mailer.Mails(), 1)
+			},
+		},
+		{
+			caseName: "SuperLike_FreeQuotaReached",
+			params: params{
+				UserMatch: &model.UserMatch{UserUID: "user456", MatchUID: "match123", MatchType: constant.UserMatchTypeSuperLike},
+			},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, "user456").Return(nil, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, "user456", "match123").Return(nil).Once()
+				mc.UserRepository.On("GetUserForUpdate", mock.Anything, mock.Anything, "match123").Return(&model.User{UID: "match123"}, nil).Once()
+				mc.UserMatchRepository.On("GetTotalSuperLikeToday", mock.Anything, mock.Anything, "user456").Return(constant.MaxSuperLikePerDay, nil).Once()
+				mc.UserMatchRepository.On("Rollback", mock.Anything).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+			},
+		},
+		{
+			caseName: "SuperLike_PackageQuota",
+			params: params{
+				UserMatch: &model.UserMatch{UserUID: "user789", MatchUID: "match456", MatchType: constant.UserMatchTypeSuperLike},
 				UserPackage: &model.UserPackage{
-					UserUID: "user123",
-					Quota:   0,
+					UserUID:       "user789",
+					EndedAt:       &endedAt,
+					PremiumConfig: &model.PremiumConfig{SuperLikeQuota: 5},
 				},
 			},
 			expectations: func(params params) {
-				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserMatch.UserUID).Return(params.UserPackage, nil)
-				mc.UserMatchRepository.On("GetTotalUserMatchToday", mock.Anything, params.UserMatch.UserUID).Return(constant.MaxMatchPerDay, nil)
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, "user789").Return(params.UserPackage, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, "user789", "match456").Return(nil).Once()
+				mc.UserRepository.On("GetUserForUpdate", mock.Anything, mock.Anything, "match456").Return(&model.User{UID: "match456"}, nil).Once()
+				mc.UserMatchRepository.On("GetTotalSuperLikeToday", mock.Anything, mock.Anything, "user789").Return(5, nil).Once()
+				mc.UserMatchRepository.On("Rollback", mock.Anything).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations(testCase.params)
+			_, err := testUsecase.CreateUserMatch(ctx, testCase.params.UserMatch)
+			testCase.results(err)
+		})
+	}
+}
+
+func TestRewindUserMatch(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := newUserMatchUsecase(mc, mailservice.NewMemoryMailer())
+
+	today := datatype.NewDateNow()
+	endedAt := today.AddDate(0, 0, 30)
+	withRewind := &model.UserPackage{EndedAt: &endedAt, PremiumConfig: &model.PremiumConfig{RewindQuota: 3}}
+
+	var testCases = []struct {
+		caseName     string
+		params       params
+		expectations func(params)
+		results      func(rewind *model.UserRewind, rewindsLeft int, err error)
+	}{
+		{
+			caseName: "Rewind_NotIncluded",
+			params: params{
+				UserUID:     "user123",
+				UserPackage: &model.UserPackage{EndedAt: &endedAt, PremiumConfig: &model.PremiumConfig{}},
+			},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserUID).Return(params.UserPackage, nil).Once()
+			},
+			results: func(rewind *model.UserRewind, rewindsLeft int, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				mc.UserMatchRepository.AssertNotCalled(t, "LockUser", mock.Anything, mock.Anything, "user123")
+			},
+		},
+		{
+			caseName: "Rewind_QuotaReached",
+			params:   params{UserUID: "user456", UserPackage: withRewind},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserUID).Return(params.UserPackage, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockUser", mock.Anything, mock.Anything, params.UserUID).Return(nil).Once()
+				mc.UserRewindRepository.On("GetTotalUserRewindToday", mock.Anything, mock.Anything, params.UserUID).Return(3, nil).Once()
+				mc.UserMatchRepository.On("Rollback", mock.Anything).Return(nil).Once()
+			},
+			results: func(rewind *model.UserRewind, rewindsLeft int, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				assert.Nil(t, rewind)
+			},
+		},
+		{
+			caseName: "Rewind_NoRecentSwipe",
+			params:   params{UserUID: "user789", UserPackage: withRewind},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserUID).Return(params.UserPackage, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockUser", mock.Anything, mock.Anything, params.UserUID).Return(nil).Once()
+				mc.UserRewindRepository.On("GetTotalUserRewindToday", mock.Anything, mock.Anything, params.UserUID).Return(0, nil).Once()
+				mc.UserMatchRepository.On("GetRewindableUserMatch", mock.Anything, mock.Anything, params.UserUID, constant.RewindWindow).Return(nil, nil).Once()
+				mc.UserMatchRepository.On("Rollback", mock.Anything).Return(nil).Once()
+			},
+			results: func(rewind *model.UserRewind, rewindsLeft int, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
+			},
+		},
+		{
+			caseName: "Rewind_AlreadyMatched",
+			params:   params{UserUID: "user000", UserPackage: withRewind},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserUID).Return(params.UserPackage, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockUser", mock.Anything, mock.Anything, params.UserUID).Return(nil).Once()
+				mc.UserRewindRepository.On("GetTotalUserRewindToday", mock.Anything, mock.Anything, params.UserUID).Return(0, nil).Once()
+				mc.UserMatchRepository.On("GetRewindableUserMatch", mock.Anything, mock.Anything, params.UserUID, constant.RewindWindow).
+					Return(&model.UserMatch{ID: 7, UserUID: params.UserUID, MatchUID: "match000", MatchType: constant.UserMatchTypeLike}, nil).Once()
+				mc.MutualMatchRepository.On("GetMutualMatch", mock.Anything, mock.Anything, params.UserUID, "match000").
+					Return(&model.MutualMatch{UID: "mutual000", UserUID: "match000", MatchUID: params.UserUID}, nil).Once()
+				mc.UserMatchRepository.On("Rollback", mock.Anything).Return(nil).Once()
+			},
+			results: func(rewind *model.UserRewind, rewindsLeft int, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				mc.UserMatchRepository.AssertNotCalled(t, "DeleteUserMatch", mock.Anything, mock.Anything, int64(7))
+			},
+		},
+		{
+			caseName: "Rewind_Success",
+			params:   params{UserUID: "user111", UserPackage: withRewind},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserUID).Return(params.UserPackage, nil).Once()
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockUser", mock.Anything, mock.Anything, params.UserUID).Return(nil).Once()
+				mc.UserRewindRepository.On("GetTotalUserRewindToday", mock.Anything, mock.Anything, params.UserUID).Return(1, nil).Once()
+				mc.UserMatchRepository.On("GetRewindableUserMatch", mock.Anything, mock.Anything, params.UserUID, constant.RewindWindow).
+					Return(&model.UserMatch{ID: 8, UserUID: params.UserUID, MatchUID: "match111", MatchType: constant.UserMatchTypePass}, nil).Once()
+				mc.UserMatchRepository.On("DeleteUserMatch", mock.Anything, mock.Anything, int64(8)).Return(nil).Once()
+				mc.UserRewindRepository.On("CreateUserRewind", mock.Anything, mock.Anything, mock.MatchedBy(func(rewind *model.UserRewind) bool {
+					return rewind.UserUID == "user111" && rewind.MatchUID == "match111" && rewind.MatchType == constant.UserMatchTypePass
+				})).Return(nil).Once()
+				mc.UserMatchRepository.On("Commit", mock.Anything).Return(nil).Once()
+			},
+			results: func(rewind *model.UserRewind, rewindsLeft int, err error) {
+				assert.NoError(t, err)
+				if assert.NotNil(t, rewind) {
+					assert.Equal(t, "match111", rewind.MatchUID)
+				}
+				assert.Equal(t, 1, rewindsLeft)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations(testCase.params)
+			rewind, rewindsLeft, err := testUsecase.RewindUserMatch(ctx, testCase.params.UserUID)
+			testCase.results(rewind, rewindsLeft, err)
+		})
+	}
+}
+
+func TestGetLikesReceived(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := newUserMatchUsecase(mc, mailservice.NewMemoryMailer())
+
+	today := datatype.NewDateNow()
+	endedAt := today.AddDate(0, 0, 30)
+
+	t.Run("GetLikesReceived_Free", func(t *testing.T) {
+		mc.UserUsecase.On("GetUserPackage", mock.Anything, "free123").Return(nil, nil).Once()
+		mc.UserMatchRepository.On("GetTotalLikesReceived", mock.Anything, "free123").Return(4, nil).Once()
+
+		users, total, locked, err := testUsecase.GetLikesReceived(ctx, "free123", 1, 10)
+		assert.NoError(t, err)
+		assert.Nil(t, users)
+		assert.Equal(t, 4, total)
+		assert.True(t, locked)
+		mc.UserMatchRepository.AssertNotCalled(t, "GetLikesReceived", mock.Anything, "free123", mock.Anything, mock.Anything)
+	})
+
+	t.Run("GetLikesReceived_Premium", func(t *testing.T) {
+		likers := []*model.User{{UID: "liker123"}}
+		mc.UserUsecase.On("GetUserPackage", mock.Anything, "premium123").Return(&model.UserPackage{EndedAt: &endedAt}, nil).Once()
+		mc.UserMatchRepository.On("GetTotalLikesReceived", mock.Anything, "premium123").Return(1, nil).Once()
+		mc.UserMatchRepository.On("GetLikesReceived", mock.Anything, "premium123", uint64(1), uint64(10)).Return(likers, nil).Once()
+		mc.PhotoUsecase.On("AttachPhotos", mock.Anything, likers).Return(nil).Once()
+
+		users, total, locked, err := testUsecase.GetLikesReceived(ctx, "premium123", 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, likers, users)
+		assert.Equal(t, 1, total)
+		assert.False(t, locked)
+	})
+}
+
+func TestUnmatch(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := newUserMatchUsecase(mc, mailservice.NewMemoryMailer())
+
+	var testCases = []struct {
+		caseName     string
+		params       params
+		expectations func(params)
+		results      func(err error)
+	}{
+		{
+			caseName:     "Unmatch_Self",
+			params:       params{UserUID: "user123", MatchUID: "user123"},
+			expectations: func(params params) {},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.InvalidArgument))
+			},
+		},
+		{
+			caseName: "Unmatch_Success",
+			params:   params{UserUID: "user123", MatchUID: "match123"},
+			expectations: func(params params) {
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, params.UserUID, params.MatchUID).Return(nil).Once()
+				mc.MutualMatchRepository.On("DeleteMutualMatch", mock.Anything, mock.Anything, params.UserUID, params.MatchUID).Return(true, nil).Once()
+				mc.HiddenPairRepository.On("CreateHiddenPair", mock.Anything, mock.Anything, &model.HiddenPair{
+					UserUID: params.UserUID, MatchUID: params.MatchUID, HiddenBy: params.UserUID,
+				}).Return(nil).Once()
+				mc.UserMatchRepository.On("Commit", mock.Anything).Return(nil).Once()
 			},
 			results: func(err error) {
-				assert.Nil(t, err)
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName: "Unmatch_Again",
+			params:   params{UserUID: "match123", MatchUID: "user123"},
+			expectations: func(params params) {
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, params.UserUID, params.MatchUID).Return(nil).Once()
+				mc.MutualMatchRepository.On("DeleteMutualMatch", mock.Anything, mock.Anything, params.UserUID, params.MatchUID).Return(false, nil).Once()
+				mc.HiddenPairRepository.On("IsPairHidden", mock.Anything, mock.Anything, params.UserUID, params.MatchUID).Return(true, nil).Once()
+				mc.UserMatchRepository.On("Commit", mock.Anything).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			caseName: "Unmatch_NotMatched",
+			params:   params{UserUID: "user123", MatchUID: "stranger123"},
+			expectations: func(params params) {
+				mc.UserMatchRepository.On("Begin").Return((*sql.Tx)(nil), nil).Once()
+				mc.UserMatchRepository.On("LockSwipePair", mock.Anything, mock.Anything, params.UserUID, params.MatchUID).Return(nil).Once()
+				mc.MutualMatchRepository.On("DeleteMutualMatch", mock.Anything, mock.Anything, params.UserUID, params.MatchUID).Return(false, nil).Once()
+				mc.HiddenPairRepository.On("IsPairHidden", mock.Anything, mock.Anything, params.UserUID, params.MatchUID).Return(false, nil).Once()
+				mc.UserMatchRepository.On("Rollback", mock.Anything).Return(nil).Once()
+			},
+			results: func(err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.NotFound))
 			},
 		},
 	}
@@ -78,16 +617,31 @@ func TestCreateUserMatch(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.caseName, func(t *testing.T) {
 			testCase.expectations(testCase.params)
-			err := testUsecase.CreateUserMatch(ctx, testCase.params.UserMatch)
+			err := testUsecase.Unmatch(ctx, testCase.params.UserUID, testCase.params.MatchUID)
 			testCase.results(err)
 		})
 	}
 }
 
+func TestGetMutualMatches(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	ctx := context.Background()
+	testUsecase := newUserMatchUsecase(mc, mailservice.NewMemoryMailer())
+
+	match := &model.User{UID: "match123"}
+	mc.MutualMatchRepository.On("GetMutualMatches", mock.Anything, "user123", uint64(1), uint64(10)).
+		Return([]*model.MutualMatch{{UID: "mutual123", UserUID: "match123", MatchUID: "user123", Match: match}}, nil).Once()
+	mc.PhotoUsecase.On("AttachPhotos", mock.Anything, []*model.User{match}).Return(nil).Once()
+
+	mutualMatches, err := testUsecase.GetMutualMatches(ctx, "user123", 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, mutualMatches, 1)
+}
+
 func TestGetUserMatchTodayByUserUIDAndMatchUID(t *testing.T) {
 	mc := test.InitMockComponent(t)
 	ctx := context.Background()
-	testUsecase := usermatchusecase.NewUserMatchUsecase(mc.UserMatchRepository, mc.UserUsecase)
+	testUsecase := newUserMatchUsecase(mc, mailservice.NewMemoryMailer())
 
 	var testCases = []struct {
 		caseName     string
@@ -139,3 +693,80 @@ func TestGetUserMatchTodayByUserUIDAndMatchUID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAvailableUsers_RequireEmailVerification(t *testing.T) {
+	mc := test.InitMockComponent(t)
+	mc.Config.RequireEmailVerification = true
+	ctx := context.Background()
+	testUsecase := newUserMatchUsecase(mc, mailservice.NewMemoryMailer())
+
+	var testCases = []struct {
+		caseName     string
+		params       params
+		expectations func(params)
+		results      func(users []*model.User, err error)
+	}{
+		{
+			caseName: "GetAvailableUsers_Unverified",
+			params:   params{UserUID: "user123"},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUser", mock.Anything, params.UserUID).Return(&model.User{UID: params.UserUID}, nil).Once()
+			},
+			results: func(users []*model.User, err error) {
+				assert.True(t, derrors.IsErrCode(err, derrors.Forbidden))
+				assert.Nil(t, users)
+				mc.UserMatchRepository.AssertNotCalled(t, "GetAvailableUsers", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			caseName: "GetAvailableUsers_Verified",
+			params:   params{UserUID: "user456"},
+			expectations: func(params params) {
+				mc.UserUsecase.On("GetUser", mock.Anything, params.UserUID).
+					Return(&model.User{UID: params.UserUID, EmailVerifiedAt: datatype.NewTimeNow()}, nil).Once()
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserUID).Return(nil, nil).Once()
+				mc.UserMatchRepository.On("GetTotalUserMatchToday", mock.Anything, mock.Anything, params.UserUID).Return(0, nil).Once()
+				mc.UserLocationRepository.On("GetUserLocation", mock.Anything, params.UserUID).Return(nil, nil).Once()
+				mc.UserMatchRepository.On("GetAvailableUsers", mock.Anything, dto.GetAvailableUsers{UserUID: params.UserUID, Page: 1, Limit: 10}).
+					Return([]*model.User{{UID: "match123"}}, nil).Once()
+				mc.PhotoUsecase.On("AttachPhotos", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			results: func(users []*model.User, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, users, 1)
+			},
+		},
+		{
+			caseName: "GetAvailableUsers_Nearby",
+			params:   params{UserUID: "user789"},
+			expectations: func(params params) {
+				location := &model.UserLocation{UserUID: params.UserUID, Latitude: -6.2088, Longitude: 106.8456}
+				maxDistanceKM := 25
+
+				mc.UserUsecase.On("GetUser", mock.Anything, params.UserUID).
+					Return(&model.User{UID: params.UserUID, EmailVerifiedAt: datatype.NewTimeNow()}, nil).Once()
+				mc.UserUsecase.On("GetUserPackage", mock.Anything, params.UserUID).Return(nil, nil).Once()
+				mc.UserMatchRepository.On("GetTotalUserMatchToday", mock.Anything, mock.Anything, params.UserUID).Return(0, nil).Once()
+				mc.UserLocationRepository.On("GetUserLocation", mock.Anything, params.UserUID).Return(location, nil).Once()
+				mc.UserPreferenceRepository.On("GetUserPreference", mock.Anything, params.UserUID).
+					Return(&model.UserPreference{UserUID: params.UserUID, MaxDistanceKM: &maxDistanceKM}, nil).Once()
+				mc.UserMatchRepository.On("GetAvailableUsers", mock.Anything, dto.GetAvailableUsers{
+					UserUID: params.UserUID, Page: 1, Limit: 10, Origin: location, MaxDistanceKM: &maxDistanceKM,
+				}).Return([]*model.User{}, nil).Once()
+				mc.PhotoUsecase.On("AttachPhotos", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			results: func(users []*model.User, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, users)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.caseName, func(t *testing.T) {
+			testCase.expectations(testCase.params)
+			users, _, err := testUsecase.GetAvailableUsers(ctx, testCase.params.UserUID, 1, 10)
+			testCase.results(users, err)
+		})
+	}
+}
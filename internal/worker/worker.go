@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// Worker runs background jobs periodically until its context is cancelled.
+	Worker struct {
+		log  *zap.Logger
+		jobs []job
+		wg   sync.WaitGroup
+	}
+
+	job struct {
+		name     string
+		interval time.Duration
+		run      func(ctx context.Context) error
+	}
+)
+
+func New(log *zap.Logger) *Worker {
+	return &Worker{
+		log: log,
+	}
+}
+
+// Register adds a job that runs every interval, it must be called before Start.
+func (w *Worker) Register(name string, interval time.Duration, run func(ctx context.Context) error) {
+	w.jobs = append(w.jobs, job{
+		name:     name,
+		interval: interval,
+		run:      run,
+	})
+}
+
+// Start runs every registered job in its own goroutine.
+func (w *Worker) Start(ctx context.Context) {
+	for _, j := range w.jobs {
+		w.wg.Add(1)
+		go w.loop(ctx, j)
+	}
+}
+
+// Wait blocks until every job stopped after the context passed to Start is cancelled.
+func (w *Worker) Wait() {
+	w.wg.Wait()
+}
+
+func (w *Worker) loop(ctx context.Context, j job) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.run(ctx); err != nil {
+				w.log.Error("worker job failed", zap.String("job", j.name), zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package datatype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// StringList is a list of strings stored as a JSON array in a single column.
+type StringList []string
+
+// Scan implements the Scanner interface.
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("datatype.StringList: unsupported scan source type")
+	}
+
+	return json.Unmarshal(b, l)
+}
+
+// Value implements the driver Valuer interface, an empty list is stored as NULL.
+func (l StringList) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
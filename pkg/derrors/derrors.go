@@ -27,6 +27,8 @@ const (
 	Duplicate
 	Unauthorized
 	Forbidden
+	Locked
+	TooManyRequests
 )
 
 var codes = []struct {
@@ -39,6 +41,8 @@ var codes = []struct {
 	{Duplicate, http.StatusBadRequest},
 	{Unauthorized, http.StatusUnauthorized},
 	{Forbidden, http.StatusForbidden},
+	{Locked, http.StatusLocked},
+	{TooManyRequests, http.StatusTooManyRequests},
 }
 
 // ToStatus returns a status code corresponding to err.
@@ -0,0 +1,157 @@
+// Package geo implements the great-circle distance between coordinates and
+// geohash cells to look up nearby points with a prefix index.
+package geo
+
+import (
+	"math"
+	"strings"
+)
+
+// EarthRadiusKM is the mean radius of the earth used for distances.
+const EarthRadiusKM = 6371.0
+
+const kmPerDegree = EarthRadiusKM * math.Pi / 180
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// distanceBuckets are the upper bounds distances are rounded up to,
+// larger distances are rounded up to a multiple of the last bucket.
+var distanceBuckets = []int{5, 10, 15, 25, 50, 100, 250, 500}
+
+// IsValid reports whether the coordinates are a point on earth.
+func IsValid(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// Distance returns the great-circle distance in kilometers with the haversine formula.
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+
+	a := math.Pow(math.Sin(dLat/2), 2) + math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Pow(math.Sin(dLng/2), 2)
+	return EarthRadiusKM * 2 * math.Asin(math.Sqrt(math.Min(1, a)))
+}
+
+// RoundDistance rounds a distance up to a bucket so the exact location of a user
+// cannot be derived from the distances shown to others.
+func RoundDistance(km float64) int {
+	for _, bucket := range distanceBuckets {
+		if km <= float64(bucket) {
+			return bucket
+		}
+	}
+
+	last := distanceBuckets[len(distanceBuckets)-1]
+	return int(math.Ceil(km/float64(last))) * last
+}
+
+// Snap moves the coordinates to the center of the grid cell of about cellKM by cellKM containing them,
+// every point in a cell is stored as the same point so distances from it reveal nothing finer than the cell.
+func Snap(latitude, longitude, cellKM float64) (float64, float64) {
+	latStep := cellKM / kmPerDegree
+	latitude = math.Max(-90, math.Min(90, (math.Floor(latitude/latStep)+0.5)*latStep))
+
+	// cells keep their width in kilometers, so they span more degrees of longitude closer to a pole
+	lngStep := 360.0
+	if cos := math.Cos(radians(latitude)); cos > 0 {
+		lngStep = math.Min(360, latStep/cos)
+	}
+	longitude = math.Min(180, (math.Floor((longitude+180)/lngStep)+0.5)*lngStep-180)
+
+	return latitude, longitude
+}
+
+// Encode returns the geohash of the coordinates with the given number of characters.
+func Encode(latitude, longitude float64, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+
+	var hash strings.Builder
+	bit, ch, even := 0, 0, true
+	for hash.Len() < precision {
+		if even {
+			mid := (minLng + maxLng) / 2
+			if longitude >= mid {
+				ch |= 1 << (4 - bit)
+				minLng = mid
+			} else {
+				maxLng = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if latitude >= mid {
+				ch |= 1 << (4 - bit)
+				minLat = mid
+			} else {
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		if bit < 4 {
+			bit++
+			continue
+		}
+
+		hash.WriteByte(base32[ch])
+		bit, ch = 0, 0
+	}
+
+	return hash.String()
+}
+
+// cellSize returns the height and width in degrees of a geohash cell with the given number of characters.
+func cellSize(precision int) (latDegrees, lngDegrees float64) {
+	bits := 5 * precision
+	lngBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / math.Pow(2, float64(latBits)), 360 / math.Pow(2, float64(lngBits))
+}
+
+// Cover returns the geohash prefixes of the cell of the point and its neighbours, every point
+// within radiusKM of the point has a geohash starting with one of them. Cover returns nil when
+// the radius is too large to narrow down the search.
+func Cover(latitude, longitude, radiusKM float64) []string {
+	// cells are narrowest on the side closest to a pole
+	farthestLat := math.Min(90, math.Abs(latitude)+radiusKM/kmPerDegree)
+	lngKMPerDegree := kmPerDegree * math.Cos(radians(farthestLat))
+
+	precision := 0
+	for p := 1; p <= 12; p++ {
+		latDegrees, lngDegrees := cellSize(p)
+		if latDegrees*kmPerDegree < radiusKM || lngDegrees*lngKMPerDegree < radiusKM {
+			break
+		}
+		precision = p
+	}
+
+	if precision == 0 {
+		return nil
+	}
+
+	latDegrees, lngDegrees := cellSize(precision)
+	seen := map[string]bool{}
+	var cells []string
+	for _, dLat := range []float64{-latDegrees, 0, latDegrees} {
+		lat := latitude + dLat
+		if lat < -90 || lat > 90 {
+			continue
+		}
+
+		for _, dLng := range []float64{-lngDegrees, 0, lngDegrees} {
+			lng := math.Mod(longitude+dLng+540, 360) - 180
+
+			cell := Encode(lat, lng, precision)
+			if !seen[cell] {
+				seen[cell] = true
+				cells = append(cells, cell)
+			}
+		}
+	}
+
+	return cells
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
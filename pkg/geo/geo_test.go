@@ -0,0 +1,127 @@
+package geo_test
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"date-apps-be/pkg/geo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// destination returns the point at distanceKM from the origin in the direction of bearing degrees.
+func destination(latitude, longitude, bearing, distanceKM float64) (float64, float64) {
+	d := distanceKM / geo.EarthRadiusKM
+	lat1, lng1, b := latitude*math.Pi/180, longitude*math.Pi/180, bearing*math.Pi/180
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(b))
+	lng2 := lng1 + math.Atan2(math.Sin(b)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+
+	return lat2 * 180 / math.Pi, math.Mod(lng2*180/math.Pi+540, 360) - 180
+}
+
+func TestDistance(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		lat1, lng1, lat2, lng2 float64
+		km                     float64
+	}{
+		{"SamePoint", -6.2088, 106.8456, -6.2088, 106.8456, 0},
+		{"JakartaBandung", -6.2088, 106.8456, -6.9175, 107.6191, 116.4},
+		{"LondonParis", 51.5074, -0.1278, 48.8566, 2.3522, 343.6},
+		{"AcrossAntimeridian", 0, 179.5, 0, -179.5, 111.2},
+		{"Antipodes", 0, 0, 0, 180, math.Pi * geo.EarthRadiusKM},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.km, geo.Distance(tc.lat1, tc.lng1, tc.lat2, tc.lng2), 0.5)
+			assert.InDelta(t, tc.km, geo.Distance(tc.lat2, tc.lng2, tc.lat1, tc.lng1), 0.5, "distance is symmetric")
+		})
+	}
+}
+
+func TestRoundDistance(t *testing.T) {
+	testCases := []struct {
+		km       float64
+		expected int
+	}{
+		{0, 5},
+		{0.3, 5},
+		{1.01, 5},
+		{5, 5},
+		{7.9, 10},
+		{42, 50},
+		{499, 500},
+		{501, 1000},
+		{1730, 2000},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, geo.RoundDistance(tc.km), tc.km)
+	}
+}
+
+func TestSnap(t *testing.T) {
+	points := [][2]float64{
+		{-6.2088, 106.8456},
+		{51.5074, -0.1278},
+		{0, 179.999},
+		{89.999, -179.999},
+		{-90, 180},
+	}
+
+	for _, point := range points {
+		lat, lng := geo.Snap(point[0], point[1], 1)
+		assert.True(t, geo.IsValid(lat, lng), point)
+		assert.LessOrEqual(t, geo.Distance(point[0], point[1], lat, lng), 1.0, point)
+	}
+
+	// points in the same cell are stored as the same point
+	lat1, lng1 := geo.Snap(-6.2088, 106.8456, 1)
+	lat2, lng2 := geo.Snap(-6.2089, 106.8457, 1)
+	assert.Equal(t, lat1, lat2)
+	assert.Equal(t, lng1, lng2)
+}
+
+func TestEncode(t *testing.T) {
+	assert.Equal(t, "u4pruydqqvj", geo.Encode(57.64911, 10.40744, 11))
+	assert.Equal(t, "s0000", geo.Encode(0, 0, 5))
+	assert.Equal(t, "zzzzz", geo.Encode(90, 180, 5))
+}
+
+func TestCover(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	origins := [][2]float64{
+		{-6.2088, 106.8456},
+		{51.5074, -0.1278},
+		{0, 179.99},
+		{-0.001, -0.001},
+		{69.6496, 18.956},
+	}
+
+	for _, origin := range origins {
+		for _, radiusKM := range []float64{0.5, 5, 25, 100, 500} {
+			cells := geo.Cover(origin[0], origin[1], radiusKM)
+			if !assert.NotEmpty(t, cells, "%v %v", origin, radiusKM) {
+				continue
+			}
+			assert.LessOrEqual(t, len(cells), 9)
+
+			for i := 0; i < 200; i++ {
+				lat, lng := destination(origin[0], origin[1], random.Float64()*360, random.Float64()*radiusKM)
+				hash := geo.Encode(lat, lng, 12)
+
+				covered := false
+				for _, cell := range cells {
+					covered = covered || strings.HasPrefix(hash, cell)
+				}
+				assert.True(t, covered, "%v within %v km of %v is not covered by %v", [2]float64{lat, lng}, radiusKM, origin, cells)
+			}
+		}
+	}
+
+	assert.Nil(t, geo.Cover(0, 0, 5000), "no prefix narrows down a search across continents")
+}
@@ -0,0 +1,69 @@
+package hmacauth_test
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"date-apps-be/pkg/hmacauth"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("shared-secret")
+	body := []byte(`{"user_uid":"user_1"}`)
+
+	signature := hmacauth.Sign(secret, "POST", "/internal/users?fields=all", 1760778000, body)
+	assert.Len(t, signature, 64)
+
+	assert.True(t, hmacauth.Verify(secret, "post", "/internal/users?fields=all", 1760778000, body, signature))
+	assert.True(t, hmacauth.Verify(secret, "POST", "/internal/users?fields=all", 1760778000, body, strings.ToUpper(signature)))
+
+	assert.False(t, hmacauth.Verify([]byte("other-secret"), "POST", "/internal/users?fields=all", 1760778000, body, signature), "wrong secret")
+	assert.False(t, hmacauth.Verify(secret, "GET", "/internal/users?fields=all", 1760778000, body, signature), "method changed")
+	assert.False(t, hmacauth.Verify(secret, "POST", "/internal/users?fields=none", 1760778000, body, signature), "query changed")
+	assert.False(t, hmacauth.Verify(secret, "POST", "/internal/users?fields=all", 1760778001, body, signature), "timestamp changed")
+	assert.False(t, hmacauth.Verify(secret, "POST", "/internal/users?fields=all", 1760778000, []byte(`{}`), signature), "body changed")
+}
+
+func TestParseAuthorization(t *testing.T) {
+	clientID, signature, err := hmacauth.ParseAuthorization(hmacauth.Authorization("billing", "abc123"))
+	assert.NoError(t, err)
+	assert.Equal(t, "billing", clientID)
+	assert.Equal(t, "abc123", signature)
+
+	for _, header := range []string{
+		"",
+		"Bearer token",
+		"HMAC-SHA256 Credential=billing",
+		"HMAC-SHA256 Signature=abc123",
+		"HMAC-SHA256 Credential",
+	} {
+		_, _, err = hmacauth.ParseAuthorization(header)
+		assert.ErrorIs(t, err, hmacauth.ErrMalformedAuthorization, header)
+	}
+}
+
+func TestSignRequest(t *testing.T) {
+	secret := []byte("shared-secret")
+	now := time.Unix(1760778000, 0)
+
+	req, err := http.NewRequest(http.MethodPost, "http://date-apps/v1/internal/users?x=1", strings.NewReader("payload"))
+	assert.NoError(t, err)
+	assert.NoError(t, hmacauth.SignRequest(req, "billing", secret, now))
+
+	body, err := io.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "payload", string(body), "the body can still be sent")
+
+	assert.Equal(t, strconv.FormatInt(now.Unix(), 10), req.Header.Get(hmacauth.HeaderTimestamp))
+
+	clientID, signature, err := hmacauth.ParseAuthorization(req.Header.Get(hmacauth.HeaderAuthorization))
+	assert.NoError(t, err)
+	assert.Equal(t, "billing", clientID)
+	assert.True(t, hmacauth.Verify(secret, http.MethodPost, "/v1/internal/users?x=1", now.Unix(), body, signature))
+}
@@ -0,0 +1,34 @@
+package jwk_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"date-apps-be/pkg/jwk"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRSAKeyRoundTrip(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	set := jwk.Set{Keys: []jwk.Key{jwk.NewRSAKey("key-1", &privateKey.PublicKey)}}
+
+	key, ok := set.Key("key-1")
+	assert.True(t, ok)
+	assert.Equal(t, "AQAB", key.E)
+
+	pub, err := key.RSAPublicKey()
+	assert.NoError(t, err)
+	assert.True(t, privateKey.PublicKey.Equal(pub))
+
+	_, ok = set.Key("unknown")
+	assert.False(t, ok)
+}
+
+func TestRSAPublicKeyUnsupportedType(t *testing.T) {
+	_, err := jwk.Key{Kty: "EC", Kid: "key-1"}.RSAPublicKey()
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+// Package totp implements time-based one-time passwords as described in RFC 6238,
+// on top of the HOTP algorithm of RFC 4226.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Algorithm is the HMAC hash function used to generate codes.
+type Algorithm string
+
+const (
+	SHA1   Algorithm = "SHA1"
+	SHA256 Algorithm = "SHA256"
+	SHA512 Algorithm = "SHA512"
+)
+
+func (a Algorithm) hash() func() hash.Hash {
+	switch a {
+	case SHA256:
+		return sha256.New
+	case SHA512:
+		return sha512.New
+	default:
+		return sha1.New
+	}
+}
+
+// Options configures code generation. The zero value is replaced by the
+// defaults most authenticator apps expect: 30 seconds, 6 digits and SHA1.
+type Options struct {
+	Period    time.Duration
+	Digits    int
+	Algorithm Algorithm
+}
+
+func (o Options) withDefaults() Options {
+	if o.Period <= 0 {
+		o.Period = 30 * time.Second
+	}
+	if o.Digits <= 0 {
+		o.Digits = 6
+	}
+	if o.Algorithm == "" {
+		o.Algorithm = SHA1
+	}
+	return o
+}
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random base32 encoded secret of n bytes.
+func GenerateSecret(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// DecodeSecret decodes a base32 secret, case and padding are ignored.
+func DecodeSecret(secret string) ([]byte, error) {
+	secret = strings.TrimRight(strings.ToUpper(strings.ReplaceAll(secret, " ", "")), "=")
+	return encoding.DecodeString(secret)
+}
+
+// Step returns the time step t falls in.
+func Step(t time.Time, opts Options) int64 {
+	opts = opts.withDefaults()
+	return t.Unix() / int64(opts.Period/time.Second)
+}
+
+// GenerateCode returns the code of key at time t.
+func GenerateCode(key []byte, t time.Time, opts Options) string {
+	opts = opts.withDefaults()
+	return hotp(key, Step(t, opts), opts)
+}
+
+// Validate checks code against the steps around t, skew is the number of steps
+// accepted before and after to tolerate clock drift. The matching step is returned,
+// callers should reject steps that were already used to prevent replays.
+func Validate(key []byte, code string, t time.Time, skew int, opts Options) (step int64, ok bool) {
+	opts = opts.withDefaults()
+	if len(code) != opts.Digits {
+		return 0, false
+	}
+
+	current := Step(t, opts)
+	for i := -int64(skew); i <= int64(skew); i++ {
+		expected := hotp(key, current+i, opts)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return current + i, true
+		}
+	}
+	return 0, false
+}
+
+// URI returns the otpauth URI shown as a QR code to enroll authenticator apps.
+func URI(issuer, account, secret string, opts Options) string {
+	opts = opts.withDefaults()
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", string(opts.Algorithm))
+	query.Set("digits", strconv.Itoa(opts.Digits))
+	query.Set("period", strconv.Itoa(int(opts.Period/time.Second)))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// hotp implements RFC 4226 section 5.3 with dynamic truncation.
+func hotp(key []byte, counter int64, opts Options) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(opts.Algorithm.hash(), key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	binCode := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < opts.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", opts.Digits, binCode%mod)
+}
@@ -0,0 +1,96 @@
+package totp_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"date-apps-be/pkg/totp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test vectors from RFC 6238 Appendix B, every algorithm uses its own seed length.
+var (
+	seedSHA1   = []byte("12345678901234567890")
+	seedSHA256 = []byte("12345678901234567890123456789012")
+	seedSHA512 = []byte("1234567890123456789012345678901234567890123456789012345678901234")
+)
+
+func TestGenerateCode_RFC6238(t *testing.T) {
+	var testCases = []struct {
+		unix      int64
+		algorithm totp.Algorithm
+		seed      []byte
+		code      string
+	}{
+		{59, totp.SHA1, seedSHA1, "94287082"},
+		{59, totp.SHA256, seedSHA256, "46119246"},
+		{59, totp.SHA512, seedSHA512, "90693936"},
+		{1111111109, totp.SHA1, seedSHA1, "07081804"},
+		{1111111109, totp.SHA256, seedSHA256, "68084774"},
+		{1111111109, totp.SHA512, seedSHA512, "25091201"},
+		{1111111111, totp.SHA1, seedSHA1, "14050471"},
+		{1111111111, totp.SHA256, seedSHA256, "67062674"},
+		{1111111111, totp.SHA512, seedSHA512, "99943326"},
+		{1234567890, totp.SHA1, seedSHA1, "89005924"},
+		{1234567890, totp.SHA256, seedSHA256, "91819424"},
+		{1234567890, totp.SHA512, seedSHA512, "93441116"},
+		{2000000000, totp.SHA1, seedSHA1, "69279037"},
+		{2000000000, totp.SHA256, seedSHA256, "90698825"},
+		{2000000000, totp.SHA512, seedSHA512, "38618901"},
+		{20000000000, totp.SHA1, seedSHA1, "65353130"},
+		{20000000000, totp.SHA256, seedSHA256, "77737706"},
+		{20000000000, totp.SHA512, seedSHA512, "47863826"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.algorithm)+"_"+time.Unix(tc.unix, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+			opts := totp.Options{Digits: 8, Algorithm: tc.algorithm}
+			assert.Equal(t, tc.code, totp.GenerateCode(tc.seed, time.Unix(tc.unix, 0), opts))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	opts := totp.Options{}
+	code := totp.GenerateCode(seedSHA1, now, opts)
+
+	step, ok := totp.Validate(seedSHA1, code, now, 1, opts)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now, opts), step)
+
+	// the previous step is accepted to tolerate clock drift
+	step, ok = totp.Validate(seedSHA1, code, now.Add(30*time.Second), 1, opts)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now, opts), step)
+
+	_, ok = totp.Validate(seedSHA1, code, now.Add(90*time.Second), 1, opts)
+	assert.False(t, ok)
+
+	_, ok = totp.Validate(seedSHA1, "12345", now, 1, opts)
+	assert.False(t, ok)
+}
+
+func TestSecretAndURI(t *testing.T) {
+	secret, err := totp.GenerateSecret(20)
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	key, err := totp.DecodeSecret(strings.ToLower(secret))
+	require.NoError(t, err)
+	assert.Len(t, key, 20)
+
+	uri, err := url.Parse(totp.URI("Date Apps", "john@example.com", secret, totp.Options{}))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Date Apps:john@example.com", uri.Path)
+	assert.Equal(t, secret, uri.Query().Get("secret"))
+	assert.Equal(t, "Date Apps", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
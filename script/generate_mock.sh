@@ -6,11 +6,41 @@ mockery --name=UserRepository --dir=internal/repository/user --output=internal/t
 mockery --name=UserMatchRepository --dir=internal/repository/user_match --output=internal/test/mockrepository --outpkg=mockrepository
 mockery --name=UserPremiumRepository --dir=internal/repository/user_premium --output=internal/test/mockrepository --outpkg=mockrepository
 mockery --name=PremiumConfigRepository --dir=internal/repository/premium_config --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=LoginHistoryRepository --dir=internal/repository/login_history --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=RefreshTokenRepository --dir=internal/repository/refresh_token --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=RevokedTokenRepository --dir=internal/repository/revoked_token --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=OTPCodeRepository --dir=internal/repository/otp_code --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=PasswordResetRepository --dir=internal/repository/password_reset --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=UserMFARepository --dir=internal/repository/user_mfa --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=UserIdentityRepository --dir=internal/repository/user_identity --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=UserRoleRepository --dir=internal/repository/user_role --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=UserSessionRepository --dir=internal/repository/user_session --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=ServiceSignatureRepository --dir=internal/repository/service_signature --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=DataExportRepository --dir=internal/repository/data_export --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=UserPhotoRepository --dir=internal/repository/user_photo --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=UserPreferenceRepository --dir=internal/repository/user_preference --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=UserLocationRepository --dir=internal/repository/user_location --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=MutualMatchRepository --dir=internal/repository/mutual_match --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=UserRewindRepository --dir=internal/repository/user_rewind --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=HiddenPairRepository --dir=internal/repository/hidden_pair --output=internal/test/mockrepository --outpkg=mockrepository
+mockery --name=UserStatusAuditRepository --dir=internal/repository/user_status_audit --output=internal/test/mockrepository --outpkg=mockrepository
 
 # Generate mocks for service interfaces
 mockery --name=AuthService --dir=internal/service/auth --output=internal/test/mockservice --outpkg=mockservice
+mockery --name=OIDCService --dir=internal/service/oidc --output=internal/test/mockservice --outpkg=mockservice
+mockery --name=HMACService --dir=internal/service/hmac --output=internal/test/mockservice --outpkg=mockservice
 
 # Generate mocks for usecase interfaces
 mockery --name=UserUsecase --dir=internal/usecase/user --output=internal/test/mockusecase --outpkg=mockusecase
 mockery --name=PremiumConfigUsecase --dir=internal/usecase/premium_config --output=internal/test/mockusecase --outpkg=mockusecase
-mockery --name=UserMatchUsecase --dir=internal/usecase/user_match --output=internal/test/mockusecase --outpkg=mockusecase
\ No newline at end of file
+mockery --name=UserMatchUsecase --dir=internal/usecase/user_match --output=internal/test/mockusecase --outpkg=mockusecase
+mockery --name=OTPUsecase --dir=internal/usecase/otp --output=internal/test/mockusecase --outpkg=mockusecase
+mockery --name=AccountUsecase --dir=internal/usecase/account --output=internal/test/mockusecase --outpkg=mockusecase
+mockery --name=MFAUsecase --dir=internal/usecase/mfa --output=internal/test/mockusecase --outpkg=mockusecase
+mockery --name=RoleUsecase --dir=internal/usecase/role --output=internal/test/mockusecase --outpkg=mockusecase
+mockery --name=SessionUsecase --dir=internal/usecase/session --output=internal/test/mockusecase --outpkg=mockusecase
+mockery --name=DataExportUsecase --dir=internal/usecase/data_export --output=internal/test/mockusecase --outpkg=mockusecase
+mockery --name=ModerationUsecase --dir=internal/usecase/moderation --output=internal/test/mockusecase --outpkg=mockusecase
+mockery --name=PhotoUsecase --dir=internal/usecase/photo --output=internal/test/mockusecase --outpkg=mockusecase
+mockery --name=PreferenceUsecase --dir=internal/usecase/preference --output=internal/test/mockusecase --outpkg=mockusecase
+mockery --name=LocationUsecase --dir=internal/usecase/location --output=internal/test/mockusecase --outpkg=mockusecase
\ No newline at end of file